@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"github.com/lindell/multi-gitter/internal/multigitter"
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+)
+
+func configureChangeRequest(cmd *cobra.Command) {
+	cmd.Flags().StringSliceP("production-org", "", nil, `Organizations, or groups together with their subgroups, where a valid change request is required before any change is made. Wildcards, like "prod-*", can be used.`)
+	cmd.Flags().StringP("change-request", "", "", "The ID of the change request, like a ServiceNow change request, that approves the changes in production organizations.")
+	cmd.Flags().StringP("change-request-url", "", "", `The url used to validate the change request, where {{.ChangeRequest}} is replaced with its ID. The change request is valid if the url responds with a 2xx status code.`)
+	cmd.Flags().StringArrayP("change-request-header", "", nil, `A header, in the format "Name: value", that is sent when validating the change request. Can be used multiple times.`)
+}
+
+func getChangeRequestGate(flag *flag.FlagSet) (*multigitter.ChangeRequestGate, error) {
+	productionOrgs, _ := flag.GetStringSlice("production-org")
+	changeRequest, _ := flag.GetString("change-request")
+	validationURL, _ := flag.GetString("change-request-url")
+	headers, _ := flag.GetStringArray("change-request-header")
+
+	if len(productionOrgs) == 0 {
+		return nil, nil
+	}
+
+	return multigitter.NewChangeRequestGate(productionOrgs, changeRequest, validationURL, headers)
+}
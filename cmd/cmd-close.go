@@ -19,10 +19,13 @@ func CloseCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringP("branch", "B", "multi-gitter-branch", "The name of the branch where changes are committed.")
+	cmd.Flags().BoolP("delete-forks", "", false, "Delete the forks that the closed and merged pull requests were made from. Forks with any other branches than the default branch are kept (GitHub/GitLab/Gitea).")
+	cmd.Flags().BoolP("keep-branch", "", false, "Keep the branches of the closed pull requests, instead of deleting them, so that they can be inspected or reopened later.")
 	configurePlatform(cmd)
 	configureRunPlatform(cmd, false)
 	configureLogging(cmd, "-")
 	configureConfig(cmd)
+	cmd.Flags().AddFlagSet(planFlag())
 
 	return cmd
 }
@@ -31,6 +34,18 @@ func closeCMD(cmd *cobra.Command, _ []string) error {
 	flag := cmd.Flags()
 
 	branchName, _ := flag.GetString("branch")
+	deleteForks, _ := flag.GetBool("delete-forks")
+	keepBranch, _ := flag.GetBool("keep-branch")
+
+	encryptionKey, err := getEncryptionKey(flag)
+	if err != nil {
+		return err
+	}
+
+	planRepositories, err := getPlanRepositories(flag, branchName, encryptionKey)
+	if err != nil {
+		return err
+	}
 
 	vc, err := getVersionController(flag, true, false)
 	if err != nil {
@@ -41,6 +56,11 @@ func closeCMD(cmd *cobra.Command, _ []string) error {
 		VersionController: vc,
 
 		FeatureBranch: branchName,
+
+		PlanRepositories: planRepositories,
+
+		DeleteForks: deleteForks,
+		KeepBranch:  keepBranch,
 	}
 
 	err = statuser.Close(context.Background())
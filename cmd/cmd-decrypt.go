@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/lindell/multi-gitter/internal/encryption"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// DecryptCmd decrypts files encrypted by multi-gitter
+func DecryptCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "decrypt [file]",
+		Short: "Decrypt an output or plan file encrypted with --encryption-key-file.",
+		Long:  "Decrypt an output or plan file encrypted with --encryption-key-file, or the MULTI_GITTER_ENCRYPTION_PASSPHRASE environment variable.",
+		Args:  cobra.ExactArgs(1),
+		RunE:  decrypt,
+	}
+
+	configureConfig(cmd)
+	cmd.Flags().AddFlagSet(outputFlag())
+
+	return cmd
+}
+
+func decrypt(cmd *cobra.Command, args []string) error {
+	flag := cmd.Flags()
+
+	strOutput, _ := flag.GetString("output")
+
+	key, err := getEncryptionKey(flag)
+	if err != nil {
+		return err
+	}
+	if key == nil {
+		return errors.New("either the --encryption-key-file flag or the MULTI_GITTER_ENCRYPTION_PASSPHRASE environment variable has to be set")
+	}
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return errors.Wrapf(err, "could not read file %s", args[0])
+	}
+
+	plaintext, err := encryption.Decrypt(data, key)
+	if err != nil {
+		return err
+	}
+
+	// The decrypted content is never encrypted again
+	output, err := fileOutput(strOutput, os.Stdout, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := output.Write(plaintext); err != nil {
+		return err
+	}
+	return closeOutput(output)
+}
@@ -2,8 +2,11 @@ package cmd
 
 import (
 	"context"
+	"path"
+	"strings"
 
 	"github.com/lindell/multi-gitter/internal/multigitter"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -20,11 +23,32 @@ func MergeCmd() *cobra.Command {
 
 	cmd.Flags().StringP("branch", "B", "multi-gitter-branch", "The name of the branch where changes are committed.")
 	cmd.Flags().StringSliceP("merge-type", "", []string{"merge", "squash", "rebase"},
-		"The type of merge that should be done (GitHub). Multiple types can be used as backup strategies if the first one is not allowed.")
+		"The type of merge that should be done (GitHub/Gitea). Multiple types can be used as backup strategies if the first one is not allowed. "+
+			"If a repository allows none of them, the first type it allows, in the order merge, squash and rebase, is used instead.")
+	cmd.Flags().StringArrayP("merge-group", "", nil, `A comma separated list of repositories, in the format "ownerName/repoName", that should be merged together. `+
+		`Can be used multiple times, and the groups are merged in the order they are defined. A group is only merged once all pull requests of the previous groups are merged. `+
+		`Pull requests of repositories not in any group are merged last. Wildcards, like "ownerName/lib-*", can be used.`)
+	cmd.Flags().BoolP("serial", "", false, "Merge the pull requests one by one in the order they are found, and stop at the first pull request that can not be merged.")
+	cmd.Flags().DurationP("wait", "", 0, `Wait this long, like "30m", for pull requests with pending checks, or required policies, to be ready before merging. Pull requests that are still pending are not merged.`)
+	cmd.Flags().BoolP("retry-conflicts", "", false, "If a pull request can not be merged, like when a pull request merged before it conflicts with it, "+
+		"update its branch with the base branch (GitHub/GitLab), wait for it to be ready again, and retry the merge. Requires --wait.")
+	cmd.Flags().StringP("verify-command", "", "", "A command that is run after each merged pull request, for example to wait for the pipeline of the base branch to succeed. "+
+		"If it exits with a non-zero exit code, no more pull requests are merged. The environment variables REPOSITORY and PULL_REQUEST are set.")
+	cmd.Flags().StringP("merge-title", "", "", `The title of the merge, or squashed, commit (GitHub/GitLab/Gitea). `+
+		`The value is a Go template, where {{.Repository}}, {{.PullRequest}} and {{.Branch}} can be used. The platform default is used if not set.`)
+	cmd.Flags().StringP("merge-message", "", "", "The body of the message of the merge, or squashed, commit, as a Go template. The platform default is used if not set.")
+	cmd.Flags().StringP("release-tag", "", "", `If set, a release with this tag is created on the merge commit of each merged pull request (GitHub/Gitea). `+
+		`The value is a Go template, where {{.Repository}}, {{.PullRequest}} and {{.Branch}} can be used.`)
+	cmd.Flags().StringP("release-name", "", "", "The name of created releases, as a Go template. Defaults to the tag.")
+	cmd.Flags().StringP("release-notes", "", "", "The notes of created releases, as a Go template.")
+	cmd.Flags().BoolP("tag-only", "", false, "Only create the tag of --release-tag, without any release.")
+	configureJira(cmd, true)
+	configureChangeRequest(cmd)
 	configurePlatform(cmd)
 	configureRunPlatform(cmd, false)
 	configureLogging(cmd, "-")
 	configureConfig(cmd)
+	cmd.Flags().AddFlagSet(planFlag())
 
 	return cmd
 }
@@ -33,6 +57,88 @@ func merge(cmd *cobra.Command, _ []string) error {
 	flag := cmd.Flags()
 
 	branchName, _ := flag.GetString("branch")
+	mergeGroups, _ := flag.GetStringArray("merge-group")
+	serial, _ := flag.GetBool("serial")
+	wait, _ := flag.GetDuration("wait")
+	retryConflicts, _ := flag.GetBool("retry-conflicts")
+	verifyCommand, _ := flag.GetString("verify-command")
+	releaseTag, _ := flag.GetString("release-tag")
+	releaseName, _ := flag.GetString("release-name")
+	releaseNotes, _ := flag.GetString("release-notes")
+	tagOnly, _ := flag.GetBool("tag-only")
+	mergeTitle, _ := flag.GetString("merge-title")
+	mergeMessage, _ := flag.GetString("merge-message")
+
+	groups := make([][]string, 0, len(mergeGroups))
+	for _, mergeGroup := range mergeGroups {
+		patterns := strings.Split(mergeGroup, ",")
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return errors.Errorf("invalid merge group pattern %q", pattern)
+			}
+		}
+		groups = append(groups, patterns)
+	}
+
+	if retryConflicts && wait == 0 {
+		return errors.New("--wait has to be set to retry conflicting merges")
+	}
+
+	var verifyPath string
+	var verifyArguments []string
+	if verifyCommand != "" {
+		var err error
+		verifyPath, verifyArguments, err = parseCommand(verifyCommand)
+		if err != nil {
+			return err
+		}
+	}
+
+	var message *multigitter.MergeMessageTemplate
+	if mergeTitle != "" || mergeMessage != "" {
+		var err error
+		message, err = multigitter.ParseMergeMessageTemplate(mergeTitle, mergeMessage)
+		if err != nil {
+			return err
+		}
+	}
+
+	var release *multigitter.ReleaseTemplate
+	if releaseTag != "" {
+		var err error
+		release, err = multigitter.ParseReleaseTemplate(releaseTag, releaseName, releaseNotes, tagOnly)
+		if err != nil {
+			return err
+		}
+	} else if releaseName != "" || releaseNotes != "" || tagOnly {
+		return errors.New("--release-tag has to be set to create releases")
+	}
+
+	jira, err := getJira(flag)
+	if err != nil {
+		return err
+	}
+	jiraIssue, _ := flag.GetString("jira-issue")
+	jiraPerRepository, _ := flag.GetBool("jira-per-repository")
+	jiraTransition, _ := flag.GetString("jira-transition")
+	if jiraTransition != "" && jira == nil {
+		return errors.New("--jira-url has to be set to transition Jira issues")
+	}
+
+	changeRequestGate, err := getChangeRequestGate(flag)
+	if err != nil {
+		return err
+	}
+
+	encryptionKey, err := getEncryptionKey(flag)
+	if err != nil {
+		return err
+	}
+
+	planRepositories, err := getPlanRepositories(flag, branchName, encryptionKey)
+	if err != nil {
+		return err
+	}
 
 	vc, err := getVersionController(flag, true, false)
 	if err != nil {
@@ -43,6 +149,26 @@ func merge(cmd *cobra.Command, _ []string) error {
 		VersionController: vc,
 
 		FeatureBranch: branchName,
+
+		PlanRepositories: planRepositories,
+
+		Groups:         groups,
+		Serial:         serial,
+		Wait:           wait,
+		RetryConflicts: retryConflicts,
+
+		VerifyPath:      verifyPath,
+		VerifyArguments: verifyArguments,
+
+		Message: message,
+		Release: release,
+
+		Jira:                   jira,
+		JiraIssue:              jiraIssue,
+		JiraIssuePerRepository: jiraPerRepository,
+		JiraTransition:         jiraTransition,
+
+		ChangeRequestGate: changeRequestGate,
 	}
 
 	err = statuser.Merge(context.Background())
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lindell/multi-gitter/internal/multigitter"
+	"github.com/spf13/cobra"
+)
+
+//nolint:lll
+const planHelp = `
+This command takes the same flags as the run command, but does not clone or change any repository. Instead, it prints which repositories would be used, which branch the changes would be based on, the values of each pull request, and anything that would stop the run, like values rejected by the platform or already existing pull requests.
+
+The plan can be saved with --plan-file, and later used with "run --from-plan" to only run on the repositories of the plan that had no blockers.
+`
+
+// PlanCmd prints what a run would do, without making any changes
+func PlanCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "plan",
+		Short:   "Shows which repositories a run would change, and what would stop it, without making any changes.",
+		Long:    planHelp,
+		Args:    cobra.NoArgs,
+		PreRunE: logFlagInit,
+		RunE:    planCMD,
+	}
+
+	configureRunFlags(cmd)
+	cmd.Flags().StringP("plan-file", "", "", "Save the plan as json to this file, to be used with run --from-plan.")
+
+	return cmd
+}
+
+func planCMD(cmd *cobra.Command, _ []string) error {
+	flag := cmd.Flags()
+
+	planPath, _ := flag.GetString("plan-file")
+
+	runner, err := runnerFromFlags(flag)
+	if err != nil {
+		return err
+	}
+
+	plan, err := runner.Plan(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if planPath != "" {
+		// The plan is encrypted with the same key as the checkpoint, which is read once with the other flags
+		if err := multigitter.WritePlan(planPath, plan, runner.CheckpointKey); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprint(runner.Output, plan.String())
+	return closeOutput(runner.Output)
+}
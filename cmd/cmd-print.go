@@ -19,6 +19,8 @@ This command will clone down multiple repositories. For each of those repositori
 
 When the script is invoked, these environment variables are set:
 - REPOSITORY will be set to the name of the repository currently being executed
+
+By default, only a limited set of environment variables, such as PATH and HOME, are passed on to the script, to avoid leaking credentials like the token. Other environment variables can be forwarded or set with the --env flag.
 `
 
 // PrintCmd is the main command that runs a script for multiple repositories and print the output of each run
@@ -40,6 +42,7 @@ func PrintCmd() *cobra.Command {
 	configureLogging(cmd, "")
 	configureConfig(cmd)
 	cmd.Flags().AddFlagSet(outputFlag())
+	cmd.Flags().AddFlagSet(scriptEnvFlag())
 
 	return cmd
 }
@@ -56,12 +59,17 @@ func printCMD(cmd *cobra.Command, _ []string) error {
 		return errors.New("concurrent runs can't be less than one")
 	}
 
-	output, err := fileOutput(strOutput, os.Stdout)
+	encryptionKey, err := getEncryptionKey(flag)
+	if err != nil {
+		return err
+	}
+
+	output, err := fileOutput(strOutput, os.Stdout, encryptionKey)
 	if err != nil {
 		return err
 	}
 
-	errOutput, err := fileOutput(strErrOutput, os.Stderr)
+	errOutput, err := fileOutput(strErrOutput, os.Stderr, encryptionKey)
 	if err != nil {
 		return err
 	}
@@ -81,6 +89,11 @@ func printCMD(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
+	scriptEnv, err := getScriptEnv(flag)
+	if err != nil {
+		return err
+	}
+
 	// Set up signal listening to cancel the context and let started runs finish gracefully
 	ctx, cancel := context.WithCancel(context.Background())
 	c := make(chan os.Signal, 1)
@@ -96,6 +109,7 @@ func printCMD(cmd *cobra.Command, _ []string) error {
 	printer := multigitter.Printer{
 		ScriptPath: executablePath,
 		Arguments:  arguments,
+		ScriptEnv:  scriptEnv,
 
 		VersionController: vc,
 
@@ -109,6 +123,12 @@ func printCMD(cmd *cobra.Command, _ []string) error {
 	}
 
 	err = printer.Print(ctx)
+	if closeErr := closeOutput(output); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	if closeErr := closeOutput(errOutput); closeErr != nil && err == nil {
+		err = closeErr
+	}
 	if err != nil {
 		fmt.Println(err.Error())
 		os.Exit(1)
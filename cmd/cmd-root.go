@@ -4,6 +4,8 @@ import (
 	"math/rand"
 	"time"
 
+	"github.com/lindell/multi-gitter/internal/http"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -13,20 +15,73 @@ func RootCmd() *cobra.Command {
 		Use:   "multi-gitter",
 		Short: "Multi gitter is a tool for making changes into multiple git repositories.",
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := initializeCassette(cmd); err != nil {
+				return err
+			}
+			if err := initializeFaultInjection(cmd); err != nil {
+				return err
+			}
+			initializeReadOnly(cmd)
 			return initializeConfig(cmd) // Bind configs that are not flags
 		},
 	}
 
+	// Recording and replaying of the requests made to the platforms, used to create and run hermetic tests
+	cmd.PersistentFlags().StringP("http-record", "", "", "Record all requests made to the platform to this file.")
+	cmd.PersistentFlags().StringP("http-replay", "", "", "Respond to all requests made to the platform with the responses recorded in this file.")
+	_ = cmd.PersistentFlags().MarkHidden("http-record")
+	_ = cmd.PersistentFlags().MarkHidden("http-replay")
+
+	// Random failures, used to verify that failures are handled gracefully
+	cmd.PersistentFlags().Float64P("fault-inject", "", 0, "The probability, between 0 and 1, of injecting failed and slow API responses, and rejected pushes.")
+	_ = cmd.PersistentFlags().MarkHidden("fault-inject")
+
+	cmd.PersistentFlags().BoolP("read-only", "", false, "Block every request to the platforms that could change something, and every git push. Event webhooks and digest emails are not sent. "+
+		"A stronger guarantee than --dry-run, to safely list, plan or report with credentials that could make changes.")
+
 	cmd.AddCommand(RunCmd())
+	cmd.AddCommand(PlanCmd())
 	cmd.AddCommand(StatusCmd())
 	cmd.AddCommand(MergeCmd())
 	cmd.AddCommand(CloseCmd())
+	cmd.AddCommand(UndraftCmd())
 	cmd.AddCommand(PrintCmd())
+	cmd.AddCommand(DecryptCmd())
 	cmd.AddCommand(VersionCmd())
 
 	return cmd
 }
 
+func initializeCassette(cmd *cobra.Command) error {
+	record, _ := cmd.Flags().GetString("http-record")
+	replay, _ := cmd.Flags().GetString("http-replay")
+
+	http.StopCassette()
+	switch {
+	case record != "" && replay != "":
+		return errors.New("--http-record and --http-replay can't be used at the same time")
+	case record != "":
+		http.Record(record)
+	case replay != "":
+		return http.Replay(replay)
+	}
+	return nil
+}
+
+func initializeFaultInjection(cmd *cobra.Command) error {
+	rate, _ := cmd.Flags().GetFloat64("fault-inject")
+	if rate < 0 || rate > 1 {
+		return errors.New("--fault-inject has to be between 0 and 1")
+	}
+	http.InjectFaults(rate)
+	return nil
+}
+
+func initializeReadOnly(cmd *cobra.Command) {
+	readOnly, _ := cmd.Flags().GetBool("read-only")
+	http.SetReadOnly(readOnly)
+}
+
 func init() {
 	rand.Seed(time.Now().UTC().UnixNano())
 }
@@ -3,17 +3,21 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"regexp"
 	"strings"
 	"syscall"
+	"text/template"
 
 	"github.com/lindell/multi-gitter/internal/git"
 
 	"github.com/lindell/multi-gitter/internal/multigitter"
 	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 //nolint:lll
@@ -23,6 +27,9 @@ This command will clone down multiple repositories. For each of those repositori
 When the script is invoked, these environment variables are set:
 - REPOSITORY will be set to the name of the repository currently being executed
 - DRY_RUN will be set =true, when running in with the --dry-run flag, otherwise it's absent
+- PULL_REQUEST_FILE will be set to the path of a file where the script can write json, like {"labels": ["go"], "reviewers": ["alice"]}, to add labels, reviewers, teamReviewers or assignees to the pull request of that repository, or text after its title (titleSuffix) or body (body)
+
+By default, only a limited set of environment variables, such as PATH and HOME, are passed on to the script, to avoid leaking credentials like the token. Other environment variables can be forwarded or set with the --env flag.
 `
 
 // RunCmd is the main command that runs a script for multiple repositories and creates PRs with the changes made
@@ -36,65 +43,224 @@ func RunCmd() *cobra.Command {
 		RunE:    run,
 	}
 
+	configureRunFlags(cmd)
+	cmd.Flags().StringP("from-plan", "", "", "Only run on the repositories, without any blockers, of a plan saved by the plan command.")
+	cmd.Flags().IntP("skip-exit-code", "", 0, "An exit code the script can use to signal that the repository is intentionally skipped, like when the change does not apply to it. Skipped repositories are reported separately from failed ones.")
+	cmd.Flags().BoolP("fail-on-error", "", false, "Exit with a non-zero exit code if the run failed on any repository. Repositories skipped by the script, or without any changes, are not failures.")
+	cmd.Flags().IntP("stop-after-failures", "", 0, "Stop the run, without starting any more repositories, once it has failed on this many repositories. Already started repositories are finished.")
+	cmd.Flags().DurationP("stop-after-duration", "", 0, `Stop the run, without starting any more repositories, once it has run for this long, like "2h". Already started repositories are finished.`)
+	cmd.Flags().StringP("checkpoint", "", "", "A file where the repositories that were never run, if the run is stopped early or canceled, are saved. The run can be continued on them with --from-plan.")
+	cmd.Flags().IntP("report-slowest", "", 0, "List this many of the repositories that took the longest in the report, with the time spent cloning, running the script and publishing each of them.")
+	cmd.Flags().BoolP("check-reviewer-ownership", "", false, "Warn about repositories where none of the reviewers, or team reviewers, own some of the changed files according to the CODEOWNERS file, since someone else would have to approve the pull request.")
+	cmd.Flags().StringP("check-run", "", "", "Add a check run with this name, the campaign and the hash of the script, to the last commit of every pull request, which other automation and branch protection can rely on. Requires the token of a GitHub App (GitHub).")
+	cmd.Flags().StringP("pr-status", "", "", `Set a successful status with this name, like "multi-gitter/run", on the last commit of every created or updated pull request, which status policies of the platform can rely on (GitHub/GitLab/Gitea).`)
+	cmd.Flags().StringP("pr-status-description", "", "", "The description of the status set by --pr-status.")
+	cmd.Flags().StringP("pr-status-url", "", "", "The link of the status set by --pr-status, like the page of the campaign.")
+	cmd.Flags().BoolP("trigger-pipeline", "", false, "Start the pipeline of every created or updated pull request, for projects where merge request pipelines are not started automatically, like for bot users (GitLab).")
+	cmd.Flags().DurationP("wait-for-pipeline", "", 0, `Wait this long, like "30m", for triggered pipelines to finish. Repositories where the pipeline does not pass in time are reported as failed.`)
+	cmd.Flags().BoolP("pr-summary-comment", "", false, "Add a comment with the script, the size of the changes and the version of multi-gitter to every created or updated pull request (GitHub/GitLab).")
+	cmd.Flags().BoolP("reopen-closed", "", false, "If the pull request of the branch was closed without being merged, reopen and update it instead of creating a new pull request (GitHub/GitLab/Gitea).")
+	cmd.Flags().BoolP("skip-adoption", "", false, "Do not look for an open pull request from the branch, made by other tooling or by hand, if the branch did not exist before the run. Such pull requests are otherwise updated instead of a new pull request being created next to them.")
+	cmd.Flags().BoolP("skip-retarget", "", false, "Do not retarget open pull requests whose base branch has been removed, like when the default branch is renamed from master to main. Retargeting costs one extra API call per open pull request, also on repositories where the script made no changes.")
+	cmd.Flags().StringArrayP("backport-branch", "", nil, `A branch, like "release/1.2", that the changes are also made against, with a pull request of its own from the branch "BRANCH-BACKPORT-BRANCH", after the regular run. `+
+		`Can be used multiple times. A pattern, like "release/*", makes the changes against every matching branch. Repositories where the branch does not exist are skipped. The backports are made even if the regular run failed on some repositories, `+
+		`but limits like --stop-after-failures and --max-prs-per-reviewer apply to all of them together.`)
+	configureEmail(cmd)
+
+	return cmd
+}
+
+// configureRunFlags adds the flags that define the changes of a run, shared by the run and plan commands
+func configureRunFlags(cmd *cobra.Command) {
 	cmd.Flags().StringP("branch", "B", "multi-gitter-branch", "The name of the branch where changes are committed.")
 	cmd.Flags().StringP("base-branch", "", "", "The branch which the changes will be based on.")
+	cmd.Flags().StringArrayP("base-branch-rule", "", nil, `A rule that picks the base branch per repository, in the format "[repository regex:]branch". Can be used multiple times. The branch of the first rule that applies to a repository, and exists in it, is used as base branch. For example, "develop" uses develop wherever it exists. Repositories without a matching rule use --base-branch, or the default branch.`)
+	cmd.Flags().StringP("base-ref", "", "", `A ref, like a tag or commit, that the changes are made on top of, instead of the tip of the base branch. Pull requests are still made to the base branch. It's a Go template, with the fields .Repository, .Owner, .Name and .BaseBranch, like "release-{{.Name}}". If it renders as empty, the tip of the base branch is used.`)
+	cmd.Flags().StringSliceP("paths", "", nil, "Limit the changes to these directories, relative to the root of the repositories, like \"deploy\". Only changes within them are committed, and they are mentioned in the title and body of the PR. With --git-type cmd, only they, and the files in the root, are checked out.")
 	cmd.Flags().StringP("pr-title", "t", "", "The title of the PR. Will default to the first line of the commit message if none is set.")
+	cmd.Flags().StringArrayP("pr-title-prefix", "", nil, `Text added before the title of the PR, in the format "[platform=]text", like "[automated]" or "gitlab=Draft:". Text with a platform is only added on that platform. Can be used multiple times.`)
+	cmd.Flags().StringArrayP("pr-title-suffix", "", nil, `Text added after the title of the PR, in the format "[platform=]text", like "github=(JIRA-123)". Text with a platform is only added on that platform. Can be used multiple times.`)
 	cmd.Flags().StringP("pr-body", "b", "", "The body of the commit message. Will default to everything but the first line of the commit message if none is set.")
+	cmd.Flags().StringP("pr-body-truncation-marker", "", "\n\n*The description was truncated to fit the length limit of the platform.*", "Text that ends PR bodies that are truncated since they exceed the length limit of the platform.")
+	cmd.Flags().BoolP("pr-full-body-comment", "", false, "Add the full text of truncated PR bodies as comments on the PR (GitHub/GitLab).")
 	cmd.Flags().StringP("commit-message", "m", "", "The commit message. Will default to title + body if none is set.")
+	cmd.Flags().StringP("campaign", "", "", "A name that identifies the change across runs. When set, a marker is added to the PR body, and an open PR with the same title and marker is updated instead of a new one being created, even if it was made from a differently named branch (GitHub/GitLab).")
+	cmd.Flags().BoolP("pr-diff-summary", "", false, "Append a summary of the changes, with changed files grouped by directory and the beginning of the diff, to the PR body.")
+	cmd.Flags().StringP("pr-overrides-dir", "", "", `Directory with markdown files that override the PR body for specific repositories. The file of "ownerName/repoName" should be placed at "ownerName/repoName.md" in the directory. The title and labels can be overridden with a yaml front matter.`)
 	cmd.Flags().StringSliceP("reviewers", "r", nil, "The username of the reviewers to be added on the pull request.")
 	cmd.Flags().StringSliceP("team-reviewers", "", nil, "Github team names of the reviewers, in format: 'org/team'")
 	cmd.Flags().StringSliceP("assignees", "a", nil, "The username of the assignees to be added on the pull request.")
+	cmd.Flags().BoolP("lenient-reviewers", "", false, "Warn about, and leave out, reviewers, team reviewers and assignees that can't be found on the platform, instead of stopping before any change is made.")
 	cmd.Flags().IntP("max-reviewers", "M", 0, "If this value is set, reviewers will be randomized.")
 	cmd.Flags().IntP("max-team-reviewers", "", 0, "If this value is set, team reviewers will be randomized")
+	cmd.Flags().IntP("max-prs-per-reviewer", "", 0, "If this value is set, reviewers are balanced so that the reviewers with the fewest pull requests are picked first, and no reviewer gets more than this number of pull requests.")
 	cmd.Flags().IntP("concurrent", "C", 1, "The maximum number of concurrent runs.")
 	cmd.Flags().BoolP("skip-pr", "", false, "Skip pull request and directly push to the branch.")
 	cmd.Flags().BoolP("push-only", "", false, "Skip pull request and only push the feature branch.")
 	cmd.Flags().StringSliceP("skip-repo", "s", nil, "Skip changes on specified repositories, the name is including the owner of repository in the format \"ownerName/repoName\".")
 	cmd.Flags().BoolP("interactive", "i", false, "Take manual decision before committing any change. Requires git to be installed.")
 	cmd.Flags().BoolP("dry-run", "d", false, "Run without pushing changes or creating pull requests.")
+	cmd.Flags().BoolP("progress", "", false, "Continuously display how many repositories are done, what the others are doing, and the estimated time left, on stderr. Best used together with --log-file.")
 	cmd.Flags().StringP("conflict-strategy", "", "skip", `What should happen if the branch already exist.
 Available values:
   skip: Skip making any changes to the existing branch and do not create a new pull request.
   replace: Replace the existing content of the branch by force pushing any new changes, then reuse any existing pull request, or create a new one if none exist.
 `)
 	cmd.Flags().BoolP("draft", "", false, "Create pull request(s) as draft.")
+	cmd.Flags().BoolP("rerequest-review", "", false, "When an already open pull request is updated, with the replace conflict strategy or because it was adopted or reopened, ask everyone who already approved it to review it again (GitHub/GitLab).")
+	cmd.Flags().BoolP("reset-approvals", "", false, "When an already open pull request is updated, with the replace conflict strategy or because it was adopted or reopened, withdraw its approvals, so that the new changes have to be approved again before it can be merged (GitHub/GitLab).")
+	cmd.Flags().BoolP("keep-reviewers", "", false, "When an already open pull request is updated, with the replace conflict strategy or because it was adopted or reopened, keep the reviewers that were added by someone else, like manually or by a branch policy, instead of removing everyone but the configured reviewers (GitHub/GitLab/Gitea).")
+	cmd.Flags().BoolP("close-obsolete", "", false, "Close any already open pull request on repositories where the script no longer makes any changes.")
+	cmd.Flags().BoolP("skip-conflicting-prs", "", false, "Skip repositories where another open pull request already changes any of the files changed by the script (GitHub/GitLab).")
 	_ = cmd.RegisterFlagCompletionFunc("conflict-strategy", func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
 		return []string{"skip", "replace"}, cobra.ShellCompDirectiveNoFileComp
 	})
 	cmd.Flags().StringSliceP("labels", "", nil, "Labels to be added to any created pull request.")
+	cmd.Flags().BoolP("size-labels", "", false, "Add a label, from size/XS to size/XXL, to pull requests based on the number of changed lines.")
+	cmd.Flags().IntSliceP("size-label-thresholds", "", multigitter.DefaultSizeLabelThresholds, "The number of changed lines where each of the size/S, size/M, size/L, size/XL and size/XXL labels start.")
+	cmd.Flags().IntP("split-diff-lines", "", 0, "If the changes to a repository exceed this number of lines, they are split by top-level directory into one pull request per directory. Each part is on its own branch, named after the --branch and the directory, and the pull requests list the branches of each other.")
 	cmd.Flags().StringP("author-name", "", "", "Name of the committer. If not set, the global git config setting will be used.")
 	cmd.Flags().StringP("author-email", "", "", "Email of the committer. If not set, the global git config setting will be used.")
-	cmd.Flags().StringP("clone-dir", "", "", "The temporary directory where the repositories will be cloned. If not set, the default os temporary directory will be used.")
+	cmd.Flags().StringArrayP("clone-dir", "", nil, "The temporary directory where the repositories will be cloned. If not set, the default os temporary directory will be used. Can be used multiple times, and each repository is then cloned into the directory with the most free space. Each repository is cloned into multi-gitter-<random>/<branch>/<owner>/<repo> in the directory, where the random part is unique to the run.")
+	cmd.Flags().IntP("script-output-limit", "", 0, "The maximum number of bytes of the output of the script, per repository, that is logged. Only the beginning and the end of longer outputs are logged.")
+	cmd.Flags().StringP("script-output-dir", "", "", "The directory where the full output of the script, for each repository, is written to ownerName/repoName.log.")
+	cmd.Flags().BoolP("keep-failed-clones", "", false, "Keep the clones of repositories where the run failed, together with the output of the script in a .log file next to each clone, so they can be inspected afterwards.")
+	cmd.Flags().IntP("disk-budget", "", 0, "The maximum disk space, in megabytes, that all clones may use at the same time. New clones wait for others to be removed when it's exceeded.")
+	cmd.Flags().IntP("min-free-disk", "", 0, "The disk space, in megabytes, that should be free in the clone directory before a new clone is started. New clones wait for others to be removed when less is free.")
+	cmd.Flags().StringP("tracking-issue", "", "", "The repository, in the format \"ownerName/repoName\", where an issue tracking the status of all pull requests should be created or updated (GitHub/GitLab).")
+	cmd.Flags().BoolP("issue-fallback", "", false, "Create an issue with the changes as a patch on repositories where pull requests are disabled, or not permitted to be created (GitHub/GitLab).")
+	cmd.Flags().BoolP("report-required-checks", "", false, "Report the status checks that are required to pass on the base branch of each pull request, including on dry runs (GitHub).")
+	cmd.Flags().StringP("team-mapping", "", "", `A file that maps repositories to the teams that own them, in the CODEOWNERS format but with repository names, like "my-org/web-* @my-org/frontend". When set, the report groups the repositories by team.`)
+	cmd.Flags().BoolP("teams-from-codeowners", "", false, "Group the repositories in the report by the default owners in their CODEOWNERS files. Repositories in the --team-mapping file use that team instead.")
+	cmd.Flags().StringP("patch-dir", "", "", "The directory where patches are written, on platforms where changes are submitted as emailed patches instead of pull requests (SourceHut). The patches can be sent with git send-email.")
+	cmd.Flags().StringP("event-webhook-url", "", "", "If set, a CloudEvent (structured json format) with the outcome of each repository will be posted to this url.")
 	cmd.Flags().StringP("repo-include", "", "", "Include repositories that match with a given Regular Expression")
 	cmd.Flags().StringP("repo-exclude", "", "", "Exclude repositories that match with a given Regular Expression")
+	cmd.Flags().StringP("shard", "", "", `Only use one part of the repositories, in the format "index/count". For example, "2/3" divides the repositories into three parts, and uses the second one. Every repository always ends up in the same part, so a run can be split over several machines.`)
+	configureJira(cmd, false)
+	configureChangeRequest(cmd)
 	configureGit(cmd)
 	configurePlatform(cmd)
 	configureRunPlatform(cmd, true)
 	configureLogging(cmd, "-")
 	configureConfig(cmd)
 	cmd.Flags().AddFlagSet(outputFlag())
-
-	return cmd
+	cmd.Flags().AddFlagSet(scriptEnvFlag())
 }
 
 func run(cmd *cobra.Command, _ []string) error {
 	flag := cmd.Flags()
 
+	runner, err := runnerFromFlags(flag)
+	if err != nil {
+		return err
+	}
+
+	runner.ScriptPath, runner.Arguments, err = parseCommand(flag.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	runner.ScriptEnv, err = getScriptEnv(flag)
+	if err != nil {
+		return err
+	}
+	runner.SkipExitCode, _ = flag.GetInt("skip-exit-code")
+	runner.FailOnError, _ = flag.GetBool("fail-on-error")
+	runner.StopAfterFailures, _ = flag.GetInt("stop-after-failures")
+	runner.StopAfterDuration, _ = flag.GetDuration("stop-after-duration")
+	runner.CheckpointPath, _ = flag.GetString("checkpoint")
+	runner.ReportSlowest, _ = flag.GetInt("report-slowest")
+	runner.CheckReviewerOwnership, _ = flag.GetBool("check-reviewer-ownership")
+	runner.CheckRunName, _ = flag.GetString("check-run")
+	runner.StatusContext, _ = flag.GetString("pr-status")
+	runner.StatusDescription, _ = flag.GetString("pr-status-description")
+	runner.StatusURL, _ = flag.GetString("pr-status-url")
+	if runner.StatusContext == "" && (runner.StatusDescription != "" || runner.StatusURL != "") {
+		return errors.New("--pr-status-description and --pr-status-url can only be used together with --pr-status")
+	}
+	runner.SummaryComment, _ = flag.GetBool("pr-summary-comment")
+	runner.Version = Version
+	runner.TriggerPipeline, _ = flag.GetBool("trigger-pipeline")
+	runner.PipelineTimeout, _ = flag.GetDuration("wait-for-pipeline")
+	runner.ReopenClosed, _ = flag.GetBool("reopen-closed")
+	runner.SkipAdoption, _ = flag.GetBool("skip-adoption")
+	runner.SkipRetarget, _ = flag.GetBool("skip-retarget")
+	runner.BackportBranches, _ = flag.GetStringArray("backport-branch")
+	if runner.PipelineTimeout != 0 && !runner.TriggerPipeline {
+		return errors.New("--wait-for-pipeline can only be used together with --trigger-pipeline")
+	}
+
+	runner.PlanRepositories, err = getPlanRepositories(flag, runner.FeatureBranch, runner.CheckpointKey)
+	if err != nil {
+		return err
+	}
+
+	// Set up signal listening to cancel the context and let started runs finish gracefully
+	ctx, cancel := context.WithCancel(context.Background())
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-c
+		fmt.Println("Finishing up ongoing runs. Press CTRL+C again to abort now.")
+		cancel()
+		<-c
+		os.Exit(1)
+	}()
+
+	emailSender, err := getEmailSender(flag)
+	if err != nil {
+		return err
+	}
+	output := runner.Output
+	digest := &strings.Builder{}
+	if emailSender != nil {
+		runner.Output = io.MultiWriter(output, digest)
+	}
+
+	err = runner.Run(ctx)
+	logAPIUsage()
+	if closeErr := closeOutput(output); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	sendDigest(emailSender, fmt.Sprintf("multi-gitter run of %s", runner.FeatureBranch), digest.String(), err)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	return nil
+}
+
+// runnerFromFlags creates a runner, without any script set, from the flags of the run command
+func runnerFromFlags(flag *pflag.FlagSet) (*multigitter.Runner, error) {
 	branchName, _ := flag.GetString("branch")
 	baseBranchName, _ := flag.GetString("base-branch")
+	baseBranchRuleStrs, _ := flag.GetStringArray("base-branch-rule")
+	baseRef, _ := flag.GetString("base-ref")
 	prTitle, _ := flag.GetString("pr-title")
+	prTitlePrefix := strings.Join(getPlatformValues(flag, "pr-title-prefix"), " ")
+	prTitleSuffix := strings.Join(getPlatformValues(flag, "pr-title-suffix"), " ")
 	prBody, _ := flag.GetString("pr-body")
+	prBodyTruncationMarker, _ := flag.GetString("pr-body-truncation-marker")
+	prFullBodyComment, _ := flag.GetBool("pr-full-body-comment")
 	commitMessage, _ := flag.GetString("commit-message")
+	prOverridesDir, _ := flag.GetString("pr-overrides-dir")
+	prDiffSummary, _ := flag.GetBool("pr-diff-summary")
+	campaign, _ := flag.GetString("campaign")
 	reviewers, _ := stringSlice(flag, "reviewers")
 	teamReviewers, _ := stringSlice(flag, "team-reviewers")
+	lenientReviewers, _ := flag.GetBool("lenient-reviewers")
 	maxReviewers, _ := flag.GetInt("max-reviewers")
 	maxTeamReviewers, _ := flag.GetInt("max-team-reviewers")
+	maxPRsPerReviewer, _ := flag.GetInt("max-prs-per-reviewer")
 	concurrent, _ := flag.GetInt("concurrent")
 	skipPullRequest, _ := flag.GetBool("skip-pr")
 	pushOnly, _ := flag.GetBool("push-only")
 	skipRepository, _ := flag.GetStringSlice("skip-repo")
 	interactive, _ := flag.GetBool("interactive")
 	dryRun, _ := flag.GetBool("dry-run")
+	showProgress, _ := flag.GetBool("progress")
 	forkMode, _ := flag.GetBool("fork")
 	forkOwner, _ := flag.GetString("fork-owner")
 	conflictStrategyStr, _ := flag.GetString("conflict-strategy")
@@ -103,23 +269,51 @@ func run(cmd *cobra.Command, _ []string) error {
 	strOutput, _ := flag.GetString("output")
 	assignees, _ := stringSlice(flag, "assignees")
 	draft, _ := flag.GetBool("draft")
-	cloneDir, _ := flag.GetString("clone-dir")
+	closeObsolete, _ := flag.GetBool("close-obsolete")
+	rerequestReview, _ := flag.GetBool("rerequest-review")
+	resetApprovals, _ := flag.GetBool("reset-approvals")
+	keepReviewers, _ := flag.GetBool("keep-reviewers")
+	skipConflictingPRs, _ := flag.GetBool("skip-conflicting-prs")
+	cloneDirs, _ := flag.GetStringArray("clone-dir")
+	keepFailedClones, _ := flag.GetBool("keep-failed-clones")
+	scriptOutputLimit, _ := flag.GetInt("script-output-limit")
+	scriptOutputDir, _ := flag.GetString("script-output-dir")
+	diskBudget, _ := flag.GetInt("disk-budget")
+	minFreeDisk, _ := flag.GetInt("min-free-disk")
 	labels, _ := stringSlice(flag, "labels")
+	sizeLabels, _ := flag.GetBool("size-labels")
+	sizeLabelThresholds, _ := flag.GetIntSlice("size-label-thresholds")
+	splitDiffLines, _ := flag.GetInt("split-diff-lines")
+	eventWebhookURL, _ := flag.GetString("event-webhook-url")
+	if readOnly, _ := flag.GetBool("read-only"); readOnly && eventWebhookURL != "" {
+		log.Info("No events will be sent to the event webhook since multi-gitter is in read-only mode")
+		eventWebhookURL = ""
+	}
+	trackingIssueRepo, _ := flag.GetString("tracking-issue")
+	issueFallback, _ := flag.GetBool("issue-fallback")
+	reportRequiredChecks, _ := flag.GetBool("report-required-checks")
+	patchDir, _ := flag.GetString("patch-dir")
 	repoInclude, _ := flag.GetString("repo-include")
 	repoExclude, _ := flag.GetString("repo-exclude")
+	shardStr, _ := flag.GetString("shard")
 
 	if concurrent < 1 {
-		return errors.New("concurrent runs can't be less than one")
+		return nil, errors.New("concurrent runs can't be less than one")
 	}
 
-	output, err := fileOutput(strOutput, os.Stdout)
+	encryptionKey, err := getEncryptionKey(flag)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	output, err := fileOutput(strOutput, os.Stdout, encryptionKey)
+	if err != nil {
+		return nil, err
 	}
 
 	// Set commit message based on pr title and body or the reverse
 	if commitMessage == "" && prTitle == "" {
-		return errors.New("pull request title or commit message must be set")
+		return nil, errors.New("pull request title or commit message must be set")
 	} else if commitMessage == "" {
 		commitMessage = prTitle
 		if prBody != "" {
@@ -133,27 +327,41 @@ func run(cmd *cobra.Command, _ []string) error {
 		}
 	}
 
+	// Without a platform, no pull requests can be created, only the feature branch can be pushed
+	if platform, _ := flag.GetString("platform"); platform == "none" {
+		if forkMode {
+			return nil, errors.New("--fork can't be used with the none platform")
+		}
+		if !skipPullRequest {
+			pushOnly = true
+		}
+	}
+
 	if pushOnly && forkMode {
-		return errors.New("--push-only and --fork can't be used at the same time")
+		return nil, errors.New("--push-only and --fork can't be used at the same time")
 	}
 
 	if skipPullRequest && pushOnly {
-		return errors.New("--push-only and --skip-pr can't be used at the same time")
+		return nil, errors.New("--push-only and --skip-pr can't be used at the same time")
 	}
 
 	if skipPullRequest && forkMode {
-		return errors.New("--fork and --skip-pr can't be used at the same time")
+		return nil, errors.New("--fork and --skip-pr can't be used at the same time")
+	}
+
+	if baseRef != "" && skipPullRequest {
+		return nil, errors.New("--base-ref and --skip-pr can't be used at the same time")
 	}
 
 	if concurrent > 1 && interactive {
-		return errors.New("--concurrent and --interactive can't be used at the same time")
+		return nil, errors.New("--concurrent and --interactive can't be used at the same time")
 	}
 
 	// Parse commit author data
 	var commitAuthor *git.CommitAuthor
 	if authorName != "" || authorEmail != "" {
 		if authorName == "" || authorEmail == "" {
-			return errors.New("both author-name and author-email has to be set if the other is set")
+			return nil, errors.New("both author-name and author-email has to be set if the other is set")
 		}
 		commitAuthor = &git.CommitAuthor{
 			Name:  authorName,
@@ -161,11 +369,22 @@ func run(cmd *cobra.Command, _ []string) error {
 		}
 	}
 
+	if scriptOutputLimit < 0 {
+		return nil, errors.New("script-output-limit cannot be negative")
+	}
+
+	if diskBudget < 0 || minFreeDisk < 0 {
+		return nil, errors.New("disk-budget and min-free-disk cannot be negative")
+	}
+
 	if maxReviewers < 0 {
-		return errors.New("max-reviewers cannot be negative")
+		return nil, errors.New("max-reviewers cannot be negative")
 	}
 	if maxTeamReviewers < 0 {
-		return errors.New("max-team-reviewers cannot be negative")
+		return nil, errors.New("max-team-reviewers cannot be negative")
+	}
+	if maxPRsPerReviewer < 0 {
+		return nil, errors.New("max-prs-per-reviewer cannot be negative")
 	}
 
 	var regExIncludeRepository *regexp.Regexp
@@ -173,93 +392,175 @@ func run(cmd *cobra.Command, _ []string) error {
 	if repoInclude != "" {
 		repoIncludeFilterCompile, err := regexp.Compile(repoInclude)
 		if err != nil {
-			return errors.WithMessage(err, "could not parse repo-include")
+			return nil, errors.WithMessage(err, "could not parse repo-include")
 		}
 		regExIncludeRepository = repoIncludeFilterCompile
 	}
 	if repoExclude != "" {
 		repoExcludeFilterCompile, err := regexp.Compile(repoExclude)
 		if err != nil {
-			return errors.WithMessage(err, "could not parse repo-exclude")
+			return nil, errors.WithMessage(err, "could not parse repo-exclude")
 		}
 		regExExcludeRepository = repoExcludeFilterCompile
 	}
 
+	if sizeLabels {
+		if err := multigitter.ValidateSizeLabelThresholds(sizeLabelThresholds); err != nil {
+			return nil, err
+		}
+	} else {
+		sizeLabelThresholds = nil
+	}
+
+	var baseRefTemplate *template.Template
+	if baseRef != "" {
+		baseRefTemplate, err = multigitter.ParseBaseRef(baseRef)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	baseBranchRules := make([]multigitter.BaseBranchRule, 0, len(baseBranchRuleStrs))
+	for _, ruleStr := range baseBranchRuleStrs {
+		rule, err := multigitter.ParseBaseBranchRule(ruleStr)
+		if err != nil {
+			return nil, err
+		}
+		baseBranchRules = append(baseBranchRules, rule)
+	}
+
+	var shard multigitter.Shard
+	if shardStr != "" {
+		shard, err = multigitter.ParseShard(shardStr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var prOverrides map[string]multigitter.PullRequestOverride
+	if prOverridesDir != "" {
+		prOverrides, err = multigitter.ReadPullRequestOverrides(prOverridesDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	vc, err := getVersionController(flag, true, false)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	gitCreator, err := getGitCreator(flag)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	paths, err := getPaths(flag)
+	if err != nil {
+		return nil, err
 	}
 
-	executablePath, arguments, err := parseCommand(flag.Arg(0))
+	jira, err := getJira(flag)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	jiraIssue, _ := flag.GetString("jira-issue")
+	jiraPerRepository, _ := flag.GetBool("jira-per-repository")
+
+	changeRequestGate, err := getChangeRequestGate(flag)
+	if err != nil {
+		return nil, err
 	}
 
+	teamMapping, err := getTeamMapping(flag)
+	if err != nil {
+		return nil, err
+	}
+	teamsFromCodeOwners, _ := flag.GetBool("teams-from-codeowners")
+
 	conflictStrategy, err := multigitter.ParseConflictStrategy(conflictStrategyStr)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Set up signal listening to cancel the context and let started runs finish gracefully
-	ctx, cancel := context.WithCancel(context.Background())
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-c
-		fmt.Println("Finishing up ongoing runs. Press CTRL+C again to abort now.")
-		cancel()
-		<-c
-		os.Exit(1)
-	}()
+	var progress io.Writer
+	if showProgress {
+		progress = os.Stderr
+	}
 
 	runner := &multigitter.Runner{
-		ScriptPath:    executablePath,
-		Arguments:     arguments,
 		FeatureBranch: branchName,
 
 		Output: output,
 
 		VersionController: vc,
 
-		CommitMessage:          commitMessage,
-		PullRequestTitle:       prTitle,
-		PullRequestBody:        prBody,
-		Reviewers:              reviewers,
-		TeamReviewers:          teamReviewers,
-		MaxReviewers:           maxReviewers,
-		MaxTeamReviewers:       maxTeamReviewers,
-		Interactive:            interactive,
-		DryRun:                 dryRun,
-		RegExIncludeRepository: regExIncludeRepository,
-		RegExExcludeRepository: regExExcludeRepository,
-		Fork:                   forkMode,
-		ForkOwner:              forkOwner,
-		SkipPullRequest:        skipPullRequest,
-		PushOnly:               pushOnly,
-		SkipRepository:         skipRepository,
-		CommitAuthor:           commitAuthor,
-		BaseBranch:             baseBranchName,
-		Assignees:              assignees,
-		ConflictStrategy:       conflictStrategy,
-		Draft:                  draft,
-		Labels:                 labels,
-		CloneDir:               cloneDir,
+		CheckpointKey: encryptionKey,
+
+		CommitMessage:               commitMessage,
+		PullRequestTitle:            prTitle,
+		PullRequestTitlePrefix:      prTitlePrefix,
+		PullRequestTitleSuffix:      prTitleSuffix,
+		PullRequestBody:             prBody,
+		Reviewers:                   reviewers,
+		TeamReviewers:               teamReviewers,
+		LenientReviewers:            lenientReviewers,
+		MaxReviewers:                maxReviewers,
+		MaxTeamReviewers:            maxTeamReviewers,
+		MaxPullRequestsPerReviewer:  maxPRsPerReviewer,
+		Interactive:                 interactive,
+		DryRun:                      dryRun,
+		Progress:                    progress,
+		RegExIncludeRepository:      regExIncludeRepository,
+		RegExExcludeRepository:      regExExcludeRepository,
+		Shard:                       shard,
+		Fork:                        forkMode,
+		ForkOwner:                   forkOwner,
+		SkipPullRequest:             skipPullRequest,
+		PushOnly:                    pushOnly,
+		SkipRepository:              skipRepository,
+		CommitAuthor:                commitAuthor,
+		BaseBranch:                  baseBranchName,
+		BaseBranchRules:             baseBranchRules,
+		BaseRef:                     baseRefTemplate,
+		Paths:                       paths,
+		Assignees:                   assignees,
+		ConflictStrategy:            conflictStrategy,
+		Draft:                       draft,
+		CloseObsolete:               closeObsolete,
+		RerequestReviews:            rerequestReview,
+		ResetApprovals:              resetApprovals,
+		KeepReviewers:               keepReviewers,
+		SkipConflictingPullRequests: skipConflictingPRs,
+		Labels:                      labels,
+		SizeLabelThresholds:         sizeLabelThresholds,
+		SplitDiffLines:              splitDiffLines,
+		PullRequestOverrides:        prOverrides,
+		PullRequestDiffSummary:      prDiffSummary,
+		Campaign:                    campaign,
+		CloneDirs:                   cloneDirs,
+		KeepFailedClones:            keepFailedClones,
+		ScriptOutputLimit:           scriptOutputLimit,
+		ScriptOutputDir:             scriptOutputDir,
+		DiskBudget:                  int64(diskBudget) * 1024 * 1024,
+		MinFreeDiskSpace:            int64(minFreeDisk) * 1024 * 1024,
+		EventWebhookURL:             eventWebhookURL,
+		TrackingIssueRepository:     trackingIssueRepo,
+		IssueFallback:               issueFallback,
+		Jira:                        jira,
+		JiraIssue:                   jiraIssue,
+		JiraIssuePerRepository:      jiraPerRepository,
+		ChangeRequestGate:           changeRequestGate,
+		TeamMapping:                 teamMapping,
+		TeamsFromCodeOwners:         teamsFromCodeOwners,
+		ReportRequiredChecks:        reportRequiredChecks,
+		BodyTruncationMarker:        prBodyTruncationMarker,
+		FullBodyComment:             prFullBodyComment,
+		PatchDir:                    patchDir,
 
 		Concurrent: concurrent,
 
 		CreateGit: gitCreator,
 	}
 
-	err = runner.Run(ctx)
-	if err != nil {
-		fmt.Println(err.Error())
-		os.Exit(1)
-	}
-
-	return nil
+	return runner, nil
 }
@@ -2,7 +2,10 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/lindell/multi-gitter/internal/multigitter"
 	"github.com/spf13/cobra"
@@ -20,11 +23,25 @@ func StatusCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringP("branch", "B", "multi-gitter-branch", "The name of the branch where changes are committed.")
+	cmd.Flags().StringP("tracking-issue", "", "", "The repository, in the format \"ownerName/repoName\", where an issue tracking the status of all pull requests should be created or updated (GitHub/GitLab).")
+	cmd.Flags().StringP("team-mapping", "", "", `A file that maps repositories to the teams that own them, in the CODEOWNERS format but with repository names, like "my-org/web-* @my-org/frontend". When set, the pull requests are grouped by team.`)
 	configurePlatform(cmd)
 	configureRunPlatform(cmd, false)
 	configureLogging(cmd, "-")
 	configureConfig(cmd)
+	cmd.Flags().StringP("output-format", "", "text", `The format of the statuses. Available values:
+  text: One line with the status of each pull request.
+  json: A list with the details of each pull request, like its url, checks, reviews and when it was created, in the same format on all platforms.
+  csv: The details of each pull request as csv, with a header row.
+  markdown: A markdown table with the pull requests.
+  gotemplate=FILE: The Go template in FILE, executed with the list of pull requests, with the same fields as the json format.
+`)
+	_ = cmd.RegisterFlagCompletionFunc("output-format", func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{"text", "json", "csv", "markdown", "gotemplate="}, cobra.ShellCompDirectiveNoFileComp
+	})
+	configureEmail(cmd)
 	cmd.Flags().AddFlagSet(outputFlag())
+	cmd.Flags().AddFlagSet(planFlag())
 
 	return cmd
 }
@@ -34,29 +51,72 @@ func status(cmd *cobra.Command, _ []string) error {
 
 	branchName, _ := flag.GetString("branch")
 	strOutput, _ := flag.GetString("output")
+	trackingIssueRepo, _ := flag.GetString("tracking-issue")
+	outputFormat, _ := flag.GetString("output-format")
+	platform, _ := flag.GetString("platform")
+
+	formatter, err := multigitter.ParseStatusFormatter(outputFormat)
+	if err != nil {
+		return err
+	}
+
+	teamMapping, err := getTeamMapping(flag)
+	if err != nil {
+		return err
+	}
+
+	encryptionKey, err := getEncryptionKey(flag)
+	if err != nil {
+		return err
+	}
+
+	planRepositories, err := getPlanRepositories(flag, branchName, encryptionKey)
+	if err != nil {
+		return err
+	}
 
 	vc, err := getVersionController(flag, true, false)
 	if err != nil {
 		return err
 	}
 
-	output, err := fileOutput(strOutput, os.Stdout)
+	output, err := fileOutput(strOutput, os.Stdout, encryptionKey)
+	if err != nil {
+		return err
+	}
+
+	emailSender, err := getEmailSender(flag)
 	if err != nil {
 		return err
 	}
+	digest := &strings.Builder{}
+	var statusOutput io.Writer = output
+	if emailSender != nil {
+		statusOutput = io.MultiWriter(output, digest)
+	}
 
 	statuser := multigitter.Statuser{
 		VersionController: vc,
 
-		Output: output,
+		Output: statusOutput,
 
 		FeatureBranch: branchName,
+
+		PlanRepositories: planRepositories,
+
+		TrackingIssueRepository: trackingIssueRepo,
+
+		TeamMapping: teamMapping,
+
+		Formatter: formatter,
+		Platform:  platform,
 	}
 
 	err = statuser.Statuses(context.Background())
+	sendDigest(emailSender, fmt.Sprintf("multi-gitter status of %s", branchName), digest.String(), err)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return closeOutput(output)
 }
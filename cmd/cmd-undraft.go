@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/lindell/multi-gitter/internal/multigitter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// UndraftCmd marks pull requests as ready for review, or converts them to drafts
+func UndraftCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "undraft",
+		Short:   "Mark draft pull requests as ready for review.",
+		Long:    "Mark draft pull requests with a specified branch name in an organization as ready for review, or convert them back to drafts with --to-draft.",
+		Args:    cobra.NoArgs,
+		PreRunE: logFlagInit,
+		RunE:    undraft,
+	}
+
+	cmd.Flags().StringP("branch", "B", "multi-gitter-branch", "The name of the branch where changes are committed.")
+	cmd.Flags().BoolP("to-draft", "", false, "Convert the pull requests to drafts instead of marking them as ready for review.")
+	cmd.Flags().BoolP("auto-merge", "", false, "Merge the pull requests that are marked as ready for review once all their requirements, like checks and approvals, are met (GitHub/GitLab).")
+	cmd.Flags().StringSliceP("merge-type", "", []string{"merge", "squash", "rebase"},
+		"The type of merge that auto-merged pull requests should use (GitHub). Multiple types can be used as backup strategies if the first one is not allowed. "+
+			"If a repository allows none of them, the first type it allows, in the order merge, squash and rebase, is used instead.")
+	configurePlatform(cmd)
+	configureRunPlatform(cmd, false)
+	configureLogging(cmd, "-")
+	configureConfig(cmd)
+
+	return cmd
+}
+
+func undraft(cmd *cobra.Command, _ []string) error {
+	flag := cmd.Flags()
+
+	branchName, _ := flag.GetString("branch")
+	toDraft, _ := flag.GetBool("to-draft")
+	autoMerge, _ := flag.GetBool("auto-merge")
+	if toDraft && autoMerge {
+		return errors.New("--auto-merge can not be used together with --to-draft")
+	}
+
+	vc, err := getVersionController(flag, true, false)
+	if err != nil {
+		return err
+	}
+
+	drafter := multigitter.Drafter{
+		VersionController: vc,
+
+		FeatureBranch: branchName,
+		Draft:         toDraft,
+		AutoMerge:     autoMerge,
+	}
+
+	err = drafter.SetDraft(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -54,7 +54,7 @@ func parseCommandLine(command string) ([]string, error) {
 
 		if currentState == stateQuotes {
 			if string(c) != quote {
-				current += string(c)
+				current += command[i : i+1]
 			} else {
 				args = append(args, current)
 				current = ""
@@ -64,7 +64,7 @@ func parseCommandLine(command string) ([]string, error) {
 		}
 
 		if escapeNext {
-			current += string(c)
+			current += command[i : i+1]
 			escapeNext = false
 			continue
 		}
@@ -86,14 +86,14 @@ func parseCommandLine(command string) ([]string, error) {
 				current = ""
 				currentState = stateStart
 			} else {
-				current += string(c)
+				current += command[i : i+1]
 			}
 			continue
 		}
 
 		if c != ' ' && c != '\t' {
 			currentState = stateArg
-			current += string(c)
+			current += command[i : i+1]
 		}
 	}
 
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/lindell/multi-gitter/internal/email"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+)
+
+func configureEmail(cmd *cobra.Command) {
+	cmd.Flags().StringSliceP("email-to", "", nil, "Email addresses that a digest of the results is sent to, through the --smtp-server. The digest is sent unencrypted, even if the output is encrypted with --encryption-key-file.")
+	cmd.Flags().StringP("email-from", "", "", "The sender address of digest emails.")
+	cmd.Flags().StringP("smtp-server", "", "", `The SMTP server, in the format "host:port", that digest emails are sent through.`)
+	cmd.Flags().StringP("smtp-user", "", "", "The user to authenticate with the SMTP server. The password can be set with the SMTP_PASSWORD environment variable.")
+}
+
+func getEmailSender(flag *flag.FlagSet) (*email.Sender, error) {
+	to, _ := flag.GetStringSlice("email-to")
+	from, _ := flag.GetString("email-from")
+	server, _ := flag.GetString("smtp-server")
+	user, _ := flag.GetString("smtp-user")
+
+	if len(to) == 0 {
+		return nil, nil
+	}
+	if readOnly, _ := flag.GetBool("read-only"); readOnly {
+		log.Info("No digest email will be sent since multi-gitter is in read-only mode")
+		return nil, nil
+	}
+	if server == "" {
+		return nil, errors.New("--smtp-server has to be set to send emails")
+	}
+	if from == "" {
+		return nil, errors.New("--email-from has to be set to send emails")
+	}
+
+	return &email.Sender{
+		Server:   server,
+		User:     user,
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     from,
+		To:       to,
+	}, nil
+}
+
+// sendDigest emails the digest, any failure is only logged since the digest is a notification of something that is already done
+func sendDigest(sender *email.Sender, subject, digest string, err error) {
+	if sender == nil {
+		return
+	}
+
+	if err != nil {
+		subject += " failed"
+		digest = err.Error() + "\n\n" + digest
+	}
+	if digest == "" {
+		digest = "Nothing to report.\n"
+	}
+
+	if err := sender.Send(subject, digest); err != nil {
+		log.Errorf("Could not send the digest email: %s", err)
+		return
+	}
+	log.Infof("Sent the digest email to %d recipients", len(sender.To))
+}
@@ -1,6 +1,12 @@
 package cmd
 
 import (
+	"context"
+	"math/rand"
+	"net/url"
+	"path"
+	"strings"
+
 	"github.com/lindell/multi-gitter/internal/git/cmdgit"
 	"github.com/lindell/multi-gitter/internal/git/gogit"
 	"github.com/lindell/multi-gitter/internal/multigitter"
@@ -19,18 +25,62 @@ Available values:
 	_ = cmd.RegisterFlagCompletionFunc("git-type", func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
 		return []string{"go", "cmd"}, cobra.ShellCompDirectiveDefault
 	})
+	cmd.Flags().StringP("git-ssh-command", "", "", `The command used instead of ssh to connect to ssh remotes, like core.sshCommand. For example "ssh -J user@jump-host" connects through a jump host. Requires --git-type cmd.`)
+	cmd.Flags().StringP("git-proxy", "", "", `The proxy used to connect to http(s) remotes, like "http://proxy.example.com:8080". With --git-type go, ssh remotes are connected to through the proxy as well, if it's a socks5 proxy.`)
 }
 
 func getGitCreator(flag *flag.FlagSet) (func(string) multigitter.Git, error) {
+	creator, err := getBaseGitCreator(flag)
+	if err != nil {
+		return nil, err
+	}
+
+	if readOnly, _ := flag.GetBool("read-only"); readOnly {
+		base := creator
+		creator = func(path string) multigitter.Git {
+			return readOnlyGit{Git: base(path)}
+		}
+	}
+
+	if faultRate, _ := flag.GetFloat64("fault-inject"); faultRate > 0 {
+		return func(path string) multigitter.Git {
+			return faultInjectingGit{
+				Git:  creator(path),
+				rate: faultRate,
+			}
+		}, nil
+	}
+
+	return creator, nil
+}
+
+func getBaseGitCreator(flag *flag.FlagSet) (func(string) multigitter.Git, error) {
 	fetchDepth, _ := flag.GetInt("fetch-depth")
 	gitType, _ := flag.GetString("git-type")
+	sshCommand, _ := flag.GetString("git-ssh-command")
+	proxy, _ := flag.GetString("git-proxy")
+	paths, err := getPaths(flag)
+	if err != nil {
+		return nil, err
+	}
+
+	if proxy != "" {
+		if _, err := url.Parse(proxy); err != nil {
+			return nil, errors.Wrap(err, "could not parse git-proxy")
+		}
+	}
 
 	switch gitType {
 	case "go":
+		if sshCommand != "" {
+			return nil, errors.New("git-ssh-command can only be used with the cmd git type, use git-proxy with a socks5 proxy to connect through another host with the go git type")
+		}
 		return func(path string) multigitter.Git {
 			return &gogit.Git{
 				Directory:  path,
 				FetchDepth: fetchDepth,
+				Proxy:      proxy,
+				Paths:      paths,
 			}
 		}, nil
 	case "cmd":
@@ -38,9 +88,49 @@ func getGitCreator(flag *flag.FlagSet) (func(string) multigitter.Git, error) {
 			return &cmdgit.Git{
 				Directory:  path,
 				FetchDepth: fetchDepth,
+				SSHCommand: sshCommand,
+				Proxy:      proxy,
+				Paths:      paths,
 			}
 		}, nil
 	}
 
 	return nil, errors.Errorf(`could not parse git type "%s"`, gitType)
 }
+
+// getPaths returns the paths, relative to the root of the repositories, that the changes are limited to
+func getPaths(flag *flag.FlagSet) ([]string, error) {
+	rawPaths, _ := flag.GetStringSlice("paths")
+
+	paths := make([]string, 0, len(rawPaths))
+	for _, p := range rawPaths {
+		cleaned := path.Clean(strings.Trim(p, "/"))
+		if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+			return nil, errors.Errorf("%q is not a path within the repository", p)
+		}
+		paths = append(paths, cleaned)
+	}
+	return paths, nil
+}
+
+// readOnlyGit blocks all pushes, in read-only mode
+type readOnlyGit struct {
+	multigitter.Git
+}
+
+func (g readOnlyGit) Push(_ context.Context, remoteName string, _ bool) error {
+	return errors.Errorf("the push to %s was blocked since multi-gitter is in read-only mode", remoteName)
+}
+
+// faultInjectingGit randomly rejects pushes, to be able to test how failures are handled
+type faultInjectingGit struct {
+	multigitter.Git
+	rate float64
+}
+
+func (g faultInjectingGit) Push(ctx context.Context, remoteName string, force bool) error {
+	if rand.Float64() < g.rate {
+		return errors.New("push rejected, fault injected by multi-gitter")
+	}
+	return g.Git.Push(ctx, remoteName, force)
+}
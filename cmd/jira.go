@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/lindell/multi-gitter/internal/jira"
+	"github.com/lindell/multi-gitter/internal/multigitter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+)
+
+func configureJira(cmd *cobra.Command, merge bool) {
+	cmd.Flags().StringP("jira-url", "", "", "The url of a Jira instance, like https://example.atlassian.net. If set, the changes are tracked in a Jira issue, which is referenced in the commits and pull requests.")
+	cmd.Flags().StringP("jira-project", "", "", "The key of the Jira project where issues are found and created.")
+	cmd.Flags().StringP("jira-issue", "", "", "The key of an existing Jira issue that tracks the changes. If not set, the issue labeled with the branch name is used, or created.")
+	cmd.Flags().StringP("jira-issue-type", "", "Task", "The type of created Jira issues.")
+	cmd.Flags().StringP("jira-user", "", "", "The user, usually an email, that the Jira token belongs to. If not set, the token is used as a personal access token.")
+	cmd.Flags().StringP("jira-token", "", "", "The Jira API token. Can also be set with the JIRA_TOKEN environment variable.")
+	cmd.Flags().BoolP("jira-per-repository", "", false, "Track the changes in every repository in its own Jira issue, instead of one issue for all repositories.")
+	if merge {
+		cmd.Flags().StringP("jira-transition", "", "", `The name of the transition, like "Done", that is done on the Jira issue once its pull requests are merged.`)
+	}
+}
+
+func getJira(flag *flag.FlagSet) (multigitter.Jira, error) {
+	jiraURL, _ := flag.GetString("jira-url")
+	project, _ := flag.GetString("jira-project")
+	issueType, _ := flag.GetString("jira-issue-type")
+	user, _ := flag.GetString("jira-user")
+	token, _ := flag.GetString("jira-token")
+
+	if jiraURL == "" {
+		if issue, _ := flag.GetString("jira-issue"); issue != "" {
+			return nil, errors.New("--jira-url has to be set to use a Jira issue")
+		}
+		return nil, nil
+	}
+
+	if token == "" {
+		token = os.Getenv("JIRA_TOKEN")
+	}
+	if token == "" {
+		return nil, errors.New("a Jira token has to be set with --jira-token or JIRA_TOKEN")
+	}
+
+	return jira.New(jiraURL, project, issueType, user, token), nil
+}
@@ -25,7 +25,7 @@ func configureLogging(cmd *cobra.Command, logFile string) {
 		return []string{"text", "json", "json-pretty"}, cobra.ShellCompDirectiveDefault
 	})
 
-	flags.StringP("log-file", "", logFile, `The file where all logs should be printed to. "-" means stdout.`)
+	flags.StringP("log-file", "", logFile, `The file where all logs should be printed to. "-" means stdout. The logs are never encrypted, not even when the output is encrypted with --encryption-key-file.`)
 
 	flags.BoolP("plain-output", "", false, `Don't use any terminal formatting when printing the output.`)
 }
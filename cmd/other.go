@@ -1,11 +1,18 @@
 package cmd
 
 import (
+	"fmt"
 	"io"
 	"os"
+	"time"
 
+	"github.com/lindell/multi-gitter/internal/encryption"
+	"github.com/lindell/multi-gitter/internal/http"
+	"github.com/lindell/multi-gitter/internal/multigitter"
 	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/lindell/multi-gitter/internal/tokensource"
 	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
 	flag "github.com/spf13/pflag"
 )
 
@@ -13,10 +20,72 @@ func outputFlag() *flag.FlagSet {
 	flags := flag.NewFlagSet("output", flag.ExitOnError)
 
 	flags.StringP("output", "o", "-", `The file that the output of the script should be outputted to. "-" means stdout.`)
+	flags.AddFlagSet(encryptionKeyFlag())
 
 	return flags
 }
 
+func encryptionKeyFlag() *flag.FlagSet {
+	flags := flag.NewFlagSet("encryption-key", flag.ExitOnError)
+
+	flags.StringP("encryption-key-file", "", "", `A file with a passphrase, or key, that output files and plan files are encrypted with. The passphrase can also be set with the MULTI_GITTER_ENCRYPTION_PASSPHRASE environment variable. Output to stdout, logs, and digest emails, are not encrypted. Encrypted files can be read with the decrypt command.`)
+
+	return flags
+}
+
+func planFlag() *flag.FlagSet {
+	flags := flag.NewFlagSet("plan", flag.ExitOnError)
+
+	flags.StringP("from-plan", "", "", "Only use the pull requests of the repositories, without any blockers, of a plan saved by the plan command. Repositories that have been renamed or moved since the plan was made are found by their id.")
+	flags.AddFlagSet(encryptionKeyFlag())
+
+	return flags
+}
+
+// getPlanRepositories reads the runnable repositories of the plan set with --from-plan. If none is set, nil is returned
+func getPlanRepositories(flag *flag.FlagSet, featureBranch string, encryptionKey []byte) ([]multigitter.PlannedRepository, error) {
+	planPath, _ := flag.GetString("from-plan")
+	if planPath == "" {
+		return nil, nil
+	}
+
+	plan, err := multigitter.ReadPlan(planPath, encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	if plan.FeatureBranch != featureBranch {
+		return nil, errors.Errorf("the plan was made for the branch %q, not %q", plan.FeatureBranch, featureBranch)
+	}
+	return plan.RunnableRepositories(), nil
+}
+
+// getEncryptionKey gets the passphrase, or key, used to encrypt files. If none is set, nil is returned
+func getEncryptionKey(flag *flag.FlagSet) ([]byte, error) {
+	keyFile, _ := flag.GetString("encryption-key-file")
+	if keyFile != "" {
+		return encryption.ReadKeyFile(keyFile)
+	}
+
+	if passphrase := os.Getenv("MULTI_GITTER_ENCRYPTION_PASSPHRASE"); passphrase != "" {
+		return []byte(passphrase), nil
+	}
+
+	return nil, nil
+}
+
+func scriptEnvFlag() *flag.FlagSet {
+	flags := flag.NewFlagSet("env", flag.ExitOnError)
+
+	flags.StringArrayP("env", "", nil, `Environment variables that should be passed to the script. Can either be in the format "NAME=value", or the name (or a pattern like "GO*") of an environment variable that should be forwarded. Use "*" to forward all environment variables.`)
+
+	return flags
+}
+
+func getScriptEnv(flag *flag.FlagSet) ([]string, error) {
+	forwarded, _ := flag.GetStringArray("env")
+	return multigitter.ScriptEnvironment(os.Environ(), forwarded)
+}
+
 func getToken(flag *flag.FlagSet) (string, error) {
 	if OverrideVersionController != nil {
 		return "", nil
@@ -24,6 +93,16 @@ func getToken(flag *flag.FlagSet) (string, error) {
 
 	token, _ := flag.GetString("token")
 
+	if source, _ := flag.GetString("token-source"); token == "" && source != "" {
+		token, err := tokensource.Read(source)
+		if err != nil {
+			return "", err
+		}
+		// Sources can only be read once, so the token is kept in the token flag for everything that needs it later
+		_ = flag.Set("token", token)
+		return token, nil
+	}
+
 	if token == "" {
 		if ght := os.Getenv("GITHUB_TOKEN"); ght != "" {
 			token = ght
@@ -31,13 +110,17 @@ func getToken(flag *flag.FlagSet) (string, error) {
 			token = ght
 		} else if ght := os.Getenv("GITEA_TOKEN"); ght != "" {
 			token = ght
+		} else if ght := os.Getenv("GITEE_TOKEN"); ght != "" {
+			token = ght
 		} else if ght := os.Getenv("BITBUCKET_SERVER_TOKEN"); ght != "" {
 			token = ght
+		} else if ght := os.Getenv("SOURCEHUT_TOKEN"); ght != "" {
+			token = ght
 		}
 	}
 
 	if token == "" {
-		return "", errors.New("either the --token flag or the GITHUB_TOKEN/GITLAB_TOKEN/GITEA_TOKEN/BITBUCKET_SERVER_TOKEN environment variable has to be set")
+		return "", errors.New("either the --token or --token-source flag, or the GITHUB_TOKEN/GITLAB_TOKEN/GITEA_TOKEN/GITEE_TOKEN/BITBUCKET_SERVER_TOKEN/SOURCEHUT_TOKEN environment variable has to be set")
 	}
 
 	return token, nil
@@ -59,6 +142,18 @@ func getMergeTypes(flag *flag.FlagSet) ([]scm.MergeType, error) {
 	return mergeTypes, nil
 }
 
+func getCloneProtocol(flag *flag.FlagSet) (scm.CloneProtocol, error) {
+	sshAuth, _ := flag.GetBool("ssh-auth")
+	cloneProtocolStr, _ := flag.GetString("clone-protocol")
+
+	// --ssh-auth is kept as a shorthand for --clone-protocol ssh
+	if sshAuth && !flag.Changed("clone-protocol") {
+		return scm.CloneProtocolSSH, nil
+	}
+
+	return scm.ParseCloneProtocol(cloneProtocolStr)
+}
+
 // nopWriter is a writer that does nothing
 type nopWriter struct{}
 
@@ -72,13 +167,58 @@ type nopCloser struct {
 
 func (nopCloser) Close() error { return nil }
 
-func fileOutput(value string, std io.Writer) (io.WriteCloser, error) {
+// fileOutput opens the output, if the output is a file and a key is set, it's encrypted when closed
+func fileOutput(value string, std io.Writer, key []byte) (io.WriteCloser, error) {
 	if value != "-" {
 		file, err := os.Create(value)
 		if err != nil {
 			return nil, errors.Wrapf(err, "could not open file %s", value)
 		}
+		if key != nil {
+			return encryption.NewWriter(file, key), nil
+		}
 		return file, nil
 	}
 	return nopCloser{std}, nil
 }
+
+// closeOutput closes an output opened with fileOutput
+func closeOutput(output io.Writer) error {
+	if closer, ok := output.(io.Closer); ok {
+		return errors.Wrap(closer.Close(), "could not write the output")
+	}
+	return nil
+}
+
+// logAPIUsage logs how many requests was made to each API, and if a follow-up run would fit within the rate limit
+func logAPIUsage() {
+	for _, usage := range http.Usage() {
+		api := usage.Host
+		if usage.Resource != "" {
+			api = fmt.Sprintf("%s (%s)", usage.Host, usage.Resource)
+		}
+
+		if !usage.HasRateLimit {
+			log.Infof("API usage of %s: %d requests, no rate limit was reported", api, usage.Requests)
+			continue
+		}
+
+		fits := "a follow-up run is estimated to fit in the current rate limit window"
+		if !usage.FitsInWindow() {
+			fits = "a follow-up run is estimated to exceed the rate limit"
+			if !usage.RateLimitReset.IsZero() {
+				fits += fmt.Sprintf(", which resets in %s", time.Until(usage.RateLimitReset).Round(time.Second))
+			}
+		}
+		log.Infof("API usage of %s: %d requests, %d of %d remaining, %s",
+			api, usage.Requests, usage.RateLimitRemaining, usage.RateLimit, fits)
+	}
+}
+
+func getTeamMapping(flag *flag.FlagSet) (multigitter.TeamMapping, error) {
+	mappingPath, _ := flag.GetString("team-mapping")
+	if mappingPath == "" {
+		return nil, nil
+	}
+	return multigitter.ReadTeamMapping(mappingPath)
+}
@@ -3,14 +3,19 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
+	"github.com/lindell/multi-gitter/internal/git/gogit"
 	"github.com/lindell/multi-gitter/internal/http"
 	"github.com/lindell/multi-gitter/internal/multigitter"
 	"github.com/lindell/multi-gitter/internal/scm/bitbucketserver"
 	"github.com/lindell/multi-gitter/internal/scm/gitea"
+	"github.com/lindell/multi-gitter/internal/scm/gitee"
 	"github.com/lindell/multi-gitter/internal/scm/github"
 	"github.com/lindell/multi-gitter/internal/scm/gitlab"
+	"github.com/lindell/multi-gitter/internal/scm/sourcehut"
+	"github.com/lindell/multi-gitter/internal/scm/static"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	flag "github.com/spf13/pflag"
@@ -19,26 +24,38 @@ import (
 func configurePlatform(cmd *cobra.Command) {
 	flags := cmd.Flags()
 
-	flags.StringP("base-url", "g", "", "Base URL of the target platform, needs to be changed for GitHub enterprise, a self-hosted GitLab instance, Gitea or BitBucket.")
+	flags.StringP("base-url", "g", "", "Base URL of the target platform, needs to be changed for GitHub enterprise, a self-hosted GitLab instance, Gitea, Gitee enterprise or BitBucket.")
 	flags.BoolP("insecure", "", false, "Insecure controls whether a client verifies the server certificate chain and host name. Used only for Bitbucket server.")
 	flags.StringP("username", "u", "", "The Bitbucket server username.")
-	flags.StringP("token", "T", "", "The personal access token for the targeting platform. Can also be set using the GITHUB_TOKEN/GITLAB_TOKEN/GITEA_TOKEN/BITBUCKET_SERVER_TOKEN environment variable.")
+	flags.StringP("token", "T", "", "The personal access token for the targeting platform. Can also be set using the GITHUB_TOKEN/GITLAB_TOKEN/GITEA_TOKEN/GITEE_TOKEN/BITBUCKET_SERVER_TOKEN/SOURCEHUT_TOKEN environment variable.")
+	flags.StringP("token-source", "", "", `Read the token from an inherited file descriptor, like "fd:3", from a unix socket that sends it to whoever connects, like "unix:/run/vault/token.sock", from a key of a secret in HashiCorp Vault, like "vault://secret/data/multi-gitter#token", with the address and token of Vault read from VAULT_ADDR and VAULT_TOKEN, from a secret in AWS Secrets Manager, like "aws-secretsmanager://multi-gitter/token" or "aws-secretsmanager://multi-gitter/tokens#github" for a key of a JSON secret, with the credentials read from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN and AWS_REGION, or from a secret in Azure Key Vault, like "azure-keyvault://my-vault/multi-gitter-token", with the service principal read from AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_CLIENT_SECRET, instead of from the environment. The token is read once, when the command starts, so a secret that is rotated during a run is not picked up.`)
 
 	flags.StringSliceP("org", "O", nil, "The name of a GitHub organization. All repositories in that organization will be used.")
 	flags.StringSliceP("group", "G", nil, "The name of a GitLab organization. All repositories in that group will be used.")
 	flags.StringSliceP("user", "U", nil, "The name of a user. All repositories owned by that user will be used.")
-	flags.StringSliceP("repo", "R", nil, "The name, including owner of a GitHub repository in the format \"ownerName/repoName\".")
+	flags.StringSliceP("repo", "R", nil, "The name, including owner of a GitHub repository in the format \"ownerName/repoName\". Segments may be URL-encoded.")
 	flags.StringP("repo-search", "", "", "Use a repository search to find repositories to target (GitHub only). Forks are NOT included by default, use `fork:true` to include them. See the GitHub documentation for full syntax: https://docs.github.com/en/search-github/searching-on-github/searching-for-repositories.")
 	flags.StringP("code-search", "", "", "Use a code search to find a set of repositories to target (GitHub only). Repeated results from a given repository will be ignored, forks are NOT included by default (use `fork:true` to include them). See the GitHub documentation for full syntax: https://docs.github.com/en/search-github/searching-on-github/searching-code.")
 	flags.StringSliceP("topic", "", nil, "The topic of a GitHub/GitLab/Gitea repository. All repositories having at least one matching topic are targeted.")
+	flags.StringSliceP("repo-url", "", nil, "The clone URL of a repository. Used with the \"none\" platform, where only git is used to interact with the repositories.")
+	flags.StringP("mailing-list", "", "", `The mailing list, in the format "~owner/list", patches are sent to. Defaults to the "~owner/repoName-devel" list of each repository. Used only for SourceHut.`)
 	flags.StringSliceP("project", "P", nil, "The name, including owner of a GitLab project in the format \"ownerName/repoName\".")
 	flags.BoolP("include-subgroups", "", false, "Include GitLab subgroups when using the --group flag.")
 	flags.BoolP("ssh-auth", "", false, `Use SSH cloning URL instead of HTTPS + token. This requires that a setup with ssh keys that have access to all repos and that the server is already in known_hosts.`)
+	flags.StringP("clone-protocol", "", "https", `The protocol used when cloning repositories.
+Available values:
+  https: Clone with HTTPS + token.
+  ssh: Clone with SSH, the same as using --ssh-auth.
+  auto: Try to clone with SSH, and fall back to HTTPS + token if that fails.
+`)
+	_ = cmd.RegisterFlagCompletionFunc("clone-protocol", func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{"https", "ssh", "auto"}, cobra.ShellCompDirectiveNoFileComp
+	})
 	flags.BoolP("skip-forks", "", false, `Skip repositories which are forks.`)
 
-	flags.StringP("platform", "p", "github", "The platform that is used. Available values: github, gitlab, gitea, bitbucket_server.")
+	flags.StringP("platform", "p", "github", "The platform that is used. Available values: github, gitlab, gitea, gitee, bitbucket_server, sourcehut, none. With none, only git is used and no pull requests are created.")
 	_ = cmd.RegisterFlagCompletionFunc("platform", func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
-		return []string{"github", "gitlab", "gitea", "bitbucket_server"}, cobra.ShellCompDirectiveDefault
+		return platforms, cobra.ShellCompDirectiveDefault
 	})
 
 	// Autocompletion for organizations
@@ -93,6 +110,29 @@ func configureRunPlatform(cmd *cobra.Command, prCreating bool) {
 	flags.StringP("fork-owner", "", "", forkOwnerDesc)
 }
 
+// platforms are all the available values of the platform flag
+var platforms = []string{"github", "gitlab", "gitea", "gitee", "bitbucket_server", "sourcehut", "none"}
+
+// getPlatformValues gets the values of a flag in the format "[platform=]value" that apply to the used platform.
+// Values without a platform apply to all platforms
+func getPlatformValues(flag *flag.FlagSet, name string) []string {
+	platform, _ := flag.GetString("platform")
+	values, _ := flag.GetStringArray(name)
+
+	ret := []string{}
+	for _, value := range values {
+		valuePlatform, platformValue, found := strings.Cut(value, "=")
+		if !found || !slices.Contains(platforms, valuePlatform) {
+			ret = append(ret, value)
+			continue
+		}
+		if valuePlatform == platform {
+			ret = append(ret, platformValue)
+		}
+	}
+	return ret
+}
+
 // OverrideVersionController can be set to force a specific version controller to be used
 // This is used to override the version controller with a mock, to be used during testing
 var OverrideVersionController multigitter.VersionController
@@ -112,8 +152,14 @@ func getVersionController(flag *flag.FlagSet, verifyFlags bool, readOnly bool) (
 		return createGitlabClient(flag, verifyFlags)
 	case "gitea":
 		return createGiteaClient(flag, verifyFlags)
+	case "gitee":
+		return createGiteeClient(flag, verifyFlags)
 	case "bitbucket_server":
 		return createBitbucketServerClient(flag, verifyFlags)
+	case "sourcehut":
+		return createSourceHutClient(flag, verifyFlags)
+	case "none":
+		return createStaticClient(flag, verifyFlags)
 	default:
 		return nil, fmt.Errorf("unknown platform: %s", platform)
 	}
@@ -129,7 +175,6 @@ func createGithubClient(flag *flag.FlagSet, verifyFlags bool, readOnly bool) (mu
 	topics, _ := flag.GetStringSlice("topic")
 	forkMode, _ := flag.GetBool("fork")
 	forkOwner, _ := flag.GetString("fork-owner")
-	sshAuth, _ := flag.GetBool("ssh-auth")
 	skipForks, _ := flag.GetBool("skip-forks")
 
 	if verifyFlags && len(orgs) == 0 && len(users) == 0 && len(repos) == 0 && repoSearch == "" && codeSearch == "" {
@@ -141,6 +186,11 @@ func createGithubClient(flag *flag.FlagSet, verifyFlags bool, readOnly bool) (mu
 		return nil, err
 	}
 
+	cloneProtocol, err := getCloneProtocol(flag)
+	if err != nil {
+		return nil, err
+	}
+
 	// Permissions returned from GitHub does not represent reality for some token types,
 	// see https://github.com/lindell/multi-gitter/issues/224 for more information.
 	// In those cases, we don't check permissions, and let errors occur if
@@ -179,7 +229,7 @@ func createGithubClient(flag *flag.FlagSet, verifyFlags bool, readOnly bool) (mu
 		MergeTypes:       mergeTypes,
 		ForkMode:         forkMode,
 		ForkOwner:        forkOwner,
-		SSHAuth:          sshAuth,
+		CloneProtocol:    cloneProtocol,
 		ReadOnly:         readOnly,
 		CheckPermissions: checkPermissions,
 	})
@@ -197,7 +247,6 @@ func createGitlabClient(flag *flag.FlagSet, verifyFlags bool) (multigitter.Versi
 	projects, _ := flag.GetStringSlice("project")
 	topics, _ := flag.GetStringSlice("topic")
 	includeSubgroups, _ := flag.GetBool("include-subgroups")
-	sshAuth, _ := flag.GetBool("ssh-auth")
 	skipForks, _ := flag.GetBool("skip-forks")
 
 	if verifyFlags && len(groups) == 0 && len(users) == 0 && len(projects) == 0 {
@@ -209,6 +258,11 @@ func createGitlabClient(flag *flag.FlagSet, verifyFlags bool) (multigitter.Versi
 		return nil, err
 	}
 
+	cloneProtocol, err := getCloneProtocol(flag)
+	if err != nil {
+		return nil, err
+	}
+
 	projRefs := make([]gitlab.ProjectReference, len(projects))
 	for i := range projects {
 		projRefs[i], err = gitlab.ParseProjectReference(projects[i])
@@ -225,7 +279,7 @@ func createGitlabClient(flag *flag.FlagSet, verifyFlags bool) (multigitter.Versi
 		SkipForks: skipForks,
 	}, gitlab.Config{
 		IncludeSubgroups: includeSubgroups,
-		SSHAuth:          sshAuth,
+		CloneProtocol:    cloneProtocol,
 	})
 	if err != nil {
 		return nil, err
@@ -240,7 +294,6 @@ func createGiteaClient(flag *flag.FlagSet, verifyFlags bool) (multigitter.Versio
 	users, _ := flag.GetStringSlice("user")
 	repos, _ := flag.GetStringSlice("repo")
 	topics, _ := flag.GetStringSlice("topic")
-	sshAuth, _ := flag.GetBool("ssh-auth")
 	skipForks, _ := flag.GetBool("skip-forks")
 
 	if verifyFlags && len(orgs) == 0 && len(users) == 0 && len(repos) == 0 {
@@ -256,6 +309,11 @@ func createGiteaClient(flag *flag.FlagSet, verifyFlags bool) (multigitter.Versio
 		return nil, err
 	}
 
+	cloneProtocol, err := getCloneProtocol(flag)
+	if err != nil {
+		return nil, err
+	}
+
 	repoRefs := make([]gitea.RepositoryReference, len(repos))
 	for i := range repos {
 		repoRefs[i], err = gitea.ParseRepositoryReference(repos[i])
@@ -275,7 +333,7 @@ func createGiteaClient(flag *flag.FlagSet, verifyFlags bool) (multigitter.Versio
 		Repositories:  repoRefs,
 		Topics:        topics,
 		SkipForks:     skipForks,
-	}, mergeTypes, sshAuth)
+	}, mergeTypes, cloneProtocol)
 	if err != nil {
 		return nil, err
 	}
@@ -283,6 +341,110 @@ func createGiteaClient(flag *flag.FlagSet, verifyFlags bool) (multigitter.Versio
 	return vc, nil
 }
 
+func createGiteeClient(flag *flag.FlagSet, verifyFlags bool) (multigitter.VersionController, error) {
+	giteeBaseURL, _ := flag.GetString("base-url")
+	orgs, _ := flag.GetStringSlice("org")
+	users, _ := flag.GetStringSlice("user")
+	repos, _ := flag.GetStringSlice("repo")
+	skipForks, _ := flag.GetBool("skip-forks")
+
+	if verifyFlags && len(orgs) == 0 && len(users) == 0 && len(repos) == 0 {
+		return nil, errors.New("no organization, user or repository set")
+	}
+
+	token, err := getToken(flag)
+	if err != nil {
+		return nil, err
+	}
+
+	cloneProtocol, err := getCloneProtocol(flag)
+	if err != nil {
+		return nil, err
+	}
+
+	repoRefs := make([]gitee.RepositoryReference, len(repos))
+	for i := range repos {
+		repoRefs[i], err = gitee.ParseRepositoryReference(repos[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	mergeTypes, err := getMergeTypes(flag)
+	if err != nil {
+		return nil, err
+	}
+
+	vc, err := gitee.New(token, giteeBaseURL, gitee.RepositoryListing{
+		Organizations: orgs,
+		Users:         users,
+		Repositories:  repoRefs,
+		SkipForks:     skipForks,
+	}, mergeTypes, cloneProtocol)
+	if err != nil {
+		return nil, err
+	}
+
+	return vc, nil
+}
+
+func createSourceHutClient(flag *flag.FlagSet, verifyFlags bool) (multigitter.VersionController, error) {
+	gitURL, _ := flag.GetString("base-url")
+	users, _ := flag.GetStringSlice("user")
+	repos, _ := flag.GetStringSlice("repo")
+	mailingList, _ := flag.GetString("mailing-list")
+
+	if verifyFlags && len(users) == 0 && len(repos) == 0 {
+		return nil, errors.New("no user or repository set")
+	}
+
+	token, err := getToken(flag)
+	if err != nil {
+		return nil, err
+	}
+
+	cloneProtocol, err := getCloneProtocol(flag)
+	if err != nil {
+		return nil, err
+	}
+
+	repoRefs := make([]sourcehut.RepositoryReference, len(repos))
+	for i := range repos {
+		repoRefs[i], err = sourcehut.ParseRepositoryReference(repos[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sourcehut.New(token, gitURL, sourcehut.RepositoryListing{
+		Users:        users,
+		Repositories: repoRefs,
+	}, mailingList, cloneProtocol)
+}
+
+func createStaticClient(flag *flag.FlagSet, verifyFlags bool) (multigitter.VersionController, error) {
+	repoURLs, _ := flag.GetStringSlice("repo-url")
+
+	if verifyFlags && len(repoURLs) == 0 {
+		return nil, errors.New("no repo-url set")
+	}
+
+	// The default branches are found with the same git implementation and settings as the clones, so that the same
+	// credential helpers, ssh command and proxy are used
+	var finder static.DefaultBranchFinder = &gogit.Git{}
+	if flag.Lookup("git-type") != nil {
+		gitCreator, err := getBaseGitCreator(flag)
+		if err != nil {
+			return nil, err
+		}
+		if f, ok := gitCreator("").(static.DefaultBranchFinder); ok {
+			finder = f
+		}
+	}
+
+	return static.New(repoURLs, finder)
+}
+
 func createBitbucketServerClient(flag *flag.FlagSet, verifyFlags bool) (multigitter.VersionController, error) {
 	bitbucketServerBaseURL, _ := flag.GetString("base-url")
 	projects, _ := flag.GetStringSlice("org")
@@ -290,7 +452,6 @@ func createBitbucketServerClient(flag *flag.FlagSet, verifyFlags bool) (multigit
 	repos, _ := flag.GetStringSlice("repo")
 	username, _ := flag.GetString("username")
 	insecure, _ := flag.GetBool("insecure")
-	sshAuth, _ := flag.GetBool("ssh-auth")
 
 	if verifyFlags && len(projects) == 0 && len(users) == 0 && len(repos) == 0 {
 		return nil, errors.New("no organization, user or repository set")
@@ -309,6 +470,11 @@ func createBitbucketServerClient(flag *flag.FlagSet, verifyFlags bool) (multigit
 		return nil, err
 	}
 
+	cloneProtocol, err := getCloneProtocol(flag)
+	if err != nil {
+		return nil, err
+	}
+
 	repoRefs := make([]bitbucketserver.RepositoryReference, len(repos))
 	for i := range repos {
 		repoRefs[i], err = bitbucketserver.ParseRepositoryReference(repos[i])
@@ -317,7 +483,7 @@ func createBitbucketServerClient(flag *flag.FlagSet, verifyFlags bool) (multigit
 		}
 	}
 
-	vc, err := bitbucketserver.New(username, token, bitbucketServerBaseURL, insecure, sshAuth, http.NewLoggingRoundTripper, bitbucketserver.RepositoryListing{
+	vc, err := bitbucketserver.New(username, token, bitbucketServerBaseURL, insecure, cloneProtocol, http.NewLoggingRoundTripper, bitbucketserver.RepositoryListing{
 		Projects:     projects,
 		Users:        users,
 		Repositories: repoRefs,
@@ -0,0 +1,66 @@
+// Package email sends digests, like the results of a run, by email
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Sender sends emails through an SMTP server
+type Sender struct {
+	Server   string // The address of the SMTP server, in the format "host:port"
+	User     string // If set, the user and password are used to authenticate with the server
+	Password string
+	From     string
+	To       []string
+}
+
+// terminalLink matches links formatted for the terminal, which are replaced with the text followed by the url
+var terminalLink = regexp.MustCompile("\x1B]8;;([^\a]*)\a([^\x1B]*)\x1B]8;;\a")
+
+// terminalFormatting matches any other formatting for the terminal, like bold text
+var terminalFormatting = regexp.MustCompile("\x1B\\[[0-9;]*m")
+
+// Send sends a plain text email. Terminal formatting in the body is removed, and links are written out
+func (s Sender) Send(subject, body string) error {
+	host, _, err := net.SplitHostPort(s.Server)
+	if err != nil {
+		return errors.WithMessage(err, "the SMTP server has to be in the format host:port")
+	}
+
+	var auth smtp.Auth
+	if s.User != "" {
+		auth = smtp.PlainAuth("", s.User, s.Password, host)
+	}
+
+	if err := smtp.SendMail(s.Server, auth, s.From, s.To, s.message(subject, body)); err != nil {
+		return errors.WithMessage(err, "could not send email")
+	}
+	return nil
+}
+
+func (s Sender) message(subject, body string) []byte {
+	body = terminalLink.ReplaceAllString(body, "$2 ($1)")
+	body = terminalFormatting.ReplaceAllString(body, "")
+	// Lines in emails are separated by CRLF
+	body = strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n")
+
+	msg := &bytes.Buffer{}
+	fmt.Fprintf(msg, "From: %s\r\n", s.From)
+	fmt.Fprintf(msg, "To: %s\r\n", strings.Join(s.To, ", "))
+	fmt.Fprintf(msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(body)
+	return msg.Bytes()
+}
@@ -0,0 +1,26 @@
+package email
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessage(t *testing.T) {
+	sender := Sender{
+		From: "multi-gitter@example.com",
+		To:   []string{"a@example.com", "b@example.com"},
+	}
+
+	body := "Repositories with a successful run:\n  \x1B]8;;https://example.com/owner/repo/pull/1\aowner/repo #1\x1B]8;;\a\n\033[1mDone\033[0m\n"
+	msg := string(sender.message("multi-gitter run of my-branch", body))
+
+	headers, text, found := strings.Cut(msg, "\r\n\r\n")
+	assert.True(t, found)
+	assert.Contains(t, headers, "From: multi-gitter@example.com\r\n")
+	assert.Contains(t, headers, "To: a@example.com, b@example.com\r\n")
+	assert.Contains(t, headers, "Subject: multi-gitter run of my-branch\r\n")
+	assert.Contains(t, headers, "Content-Type: text/plain; charset=utf-8")
+	assert.Equal(t, "Repositories with a successful run:\r\n  owner/repo #1 (https://example.com/owner/repo/pull/1)\r\nDone\r\n", text)
+}
@@ -0,0 +1,135 @@
+// Package encryption encrypts the files written by multi-gitter, like reports and plans, with a passphrase
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/scrypt"
+)
+
+// header is written first in every encrypted file, so that they can be recognized
+const header = "multi-gitter encrypted v1\n"
+
+const saltSize = 16
+
+// ReadKeyFile reads the passphrase, or key, in a file. A trailing newline is not part of the key
+func ReadKeyFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read the encryption key file")
+	}
+	key := strings.TrimRight(string(data), "\r\n")
+	if key == "" {
+		return nil, errors.New("the encryption key file is empty")
+	}
+	return []byte(key), nil
+}
+
+// IsEncrypted returns if the data has been encrypted with Encrypt
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(header))
+}
+
+// Encrypt encrypts the data with AES-256-GCM, with a key derived from the passphrase with scrypt
+func Encrypt(plaintext []byte, passphrase []byte) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, len(header)+len(salt)+len(nonce)+len(plaintext)+aead.Overhead())
+	out = append(out, header...)
+	out = append(out, salt...)
+	out = append(out, nonce...)
+	return aead.Seal(out, nonce, plaintext, []byte(header)), nil
+}
+
+// Decrypt decrypts data encrypted with Encrypt
+func Decrypt(data []byte, passphrase []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return nil, errors.New("the data is not encrypted by multi-gitter")
+	}
+	data = data[len(header):]
+
+	if len(data) < saltSize {
+		return nil, errors.New("the encrypted data is truncated")
+	}
+	salt, data := data[:saltSize], data[saltSize:]
+
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("the encrypted data is truncated")
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(header))
+	if err != nil {
+		return nil, errors.New("could not decrypt the data, the key is wrong or the data has been modified")
+	}
+	return plaintext, nil
+}
+
+func newAEAD(passphrase []byte, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// NewWriter creates a writer that encrypts everything written to it, and writes it to the underlying writer when closed.
+// Since the whole content is authenticated at once, nothing is written before it's closed
+func NewWriter(w io.WriteCloser, passphrase []byte) io.WriteCloser {
+	return &writer{
+		underlying: w,
+		passphrase: passphrase,
+	}
+}
+
+type writer struct {
+	underlying io.WriteCloser
+	passphrase []byte
+	buf        bytes.Buffer
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+func (w *writer) Close() error {
+	encrypted, err := Encrypt(w.buf.Bytes(), w.passphrase)
+	if err != nil {
+		_ = w.underlying.Close()
+		return err
+	}
+	if _, err := w.underlying.Write(encrypted); err != nil {
+		_ = w.underlying.Close()
+		return err
+	}
+	return w.underlying.Close()
+}
@@ -0,0 +1,36 @@
+package encryption
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryption(t *testing.T) {
+	plaintext := []byte("owner/repo: https://example.com/owner/repo/pull/1")
+
+	encrypted, err := Encrypt(plaintext, []byte("passphrase"))
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(encrypted))
+	assert.NotContains(t, string(encrypted), "owner/repo")
+
+	decrypted, err := Decrypt(encrypted, []byte("passphrase"))
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, decrypted)
+
+	_, err = Decrypt(encrypted, []byte("wrong passphrase"))
+	assert.Error(t, err)
+
+	modified := append([]byte{}, encrypted...)
+	modified[len(modified)-1] ^= 1
+	_, err = Decrypt(modified, []byte("passphrase"))
+	assert.Error(t, err)
+
+	_, err = Decrypt(encrypted[:len(header)+4], []byte("passphrase"))
+	assert.Error(t, err)
+
+	assert.False(t, IsEncrypted(plaintext))
+	_, err = Decrypt(plaintext, []byte("passphrase"))
+	assert.Error(t, err)
+}
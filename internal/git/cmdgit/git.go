@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"os"
 	"os/exec"
 	"regexp"
 	"strings"
@@ -17,6 +18,10 @@ import (
 type Git struct {
 	Directory  string // The (temporary) directory that should be worked within
 	FetchDepth int    // Limit fetching to the specified number of commits
+	SSHCommand string // If set, used instead of ssh when connecting to ssh remotes, like core.sshCommand
+	Proxy      string // If set, the proxy used to connect to http(s) remotes, like http.proxy
+
+	Paths []string // If set, only these directories are checked out, in addition to the files in the root, and only changes within them are committed
 }
 
 var errRe = regexp.MustCompile(`(^|\n)(error|fatal): (.+)`)
@@ -29,6 +34,17 @@ func (g *Git) run(cmd *exec.Cmd) (string, error) {
 	cmd.Stderr = stderr
 	cmd.Stdout = stdout
 
+	// Configured for every command, instead of in the config of the repository, so that it's used by the clone as well
+	if g.Proxy != "" {
+		cmd.Args = append([]string{cmd.Args[0], "-c", "http.proxy=" + g.Proxy}, cmd.Args[1:]...)
+	}
+	if g.SSHCommand != "" {
+		if cmd.Env == nil {
+			cmd.Env = os.Environ()
+		}
+		cmd.Env = append(cmd.Env, "GIT_SSH_COMMAND="+g.SSHCommand)
+	}
+
 	err := cmd.Run()
 	if err != nil {
 		matches := errRe.FindStringSubmatch(stderr.String())
@@ -52,9 +68,33 @@ func (g *Git) Clone(ctx context.Context, url string, baseName string) error {
 	if g.FetchDepth > 0 {
 		args = append(args, "--depth", fmt.Sprint(g.FetchDepth))
 	}
+	if len(g.Paths) > 0 {
+		args = append(args, "--sparse")
+	}
 	args = append(args, g.Directory)
 
 	cmd := exec.CommandContext(ctx, "git", args...)
+	if _, err := g.run(cmd); err != nil || len(g.Paths) == 0 {
+		return err
+	}
+
+	cmd = exec.CommandContext(ctx, "git", append([]string{"sparse-checkout", "set", "--"}, g.Paths...)...)
+	_, err := g.run(cmd)
+	return err
+}
+
+// CheckoutRef fetches a ref, like a tag, branch or commit, from the remote and checks it out
+func (g *Git) CheckoutRef(ctx context.Context, remoteName, ref string) error {
+	args := []string{"fetch", remoteName, ref}
+	if g.FetchDepth > 0 {
+		args = append(args, "--depth", fmt.Sprint(g.FetchDepth))
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	if _, err := g.run(cmd); err != nil {
+		return err
+	}
+
+	cmd = exec.Command("git", "checkout", "--detach", "FETCH_HEAD")
 	_, err := g.run(cmd)
 	return err
 }
@@ -68,15 +108,19 @@ func (g *Git) ChangeBranch(branchName string) error {
 
 // Changes detect if any changes has been made in the directory
 func (g *Git) Changes() (bool, error) {
-	cmd := exec.Command("git", "status", "-s")
+	cmd := exec.Command("git", append([]string{"status", "-s", "--"}, g.Paths...)...)
 	stdOut, err := g.run(cmd)
 	return len(stdOut) > 0, err
 }
 
 // Commit and push all changes
 func (g *Git) Commit(commitAuthor *git.CommitAuthor, commitMessage string) error {
-	cmd := exec.Command("git", "add", ".")
-	_, err := g.run(cmd)
+	files, err := g.changedPaths()
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command("git", append([]string{"add", "--all", "--"}, files...)...)
+	_, err = g.run(cmd)
 	if err != nil {
 		return err
 	}
@@ -104,6 +148,37 @@ func (g *Git) Commit(commitAuthor *git.CommitAuthor, commitMessage string) error
 	return err
 }
 
+// changedPaths returns the paths that should be added to the commit. Paths that does not match any file can not be
+// added, so when the changes are limited to some paths, the changed files within them are listed
+func (g *Git) changedPaths() ([]string, error) {
+	if len(g.Paths) == 0 {
+		return []string{"."}, nil
+	}
+
+	cmd := exec.Command("git", append([]string{"status", "--porcelain", "-z", "--untracked-files=all", "--"}, g.Paths...)...)
+	stdOut, err := g.run(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	entries := strings.Split(stdOut, "\x00")
+	for i := 0; i < len(entries); i++ {
+		// Every entry is the two letter status, a space and the path
+		entry := entries[i]
+		if len(entry) <= 3 {
+			continue
+		}
+		files = append(files, entry[3:])
+
+		// Renames and copies are followed by an entry with only the original path
+		if entry[0] == 'R' || entry[0] == 'C' || entry[1] == 'R' || entry[1] == 'C' {
+			i++
+		}
+	}
+	return files, nil
+}
+
 func (g *Git) logDiff() error {
 	if !log.IsLevelEnabled(log.DebugLevel) {
 		return nil
@@ -120,6 +195,36 @@ func (g *Git) logDiff() error {
 	return nil
 }
 
+// Diff returns the diff of the last commit
+func (g *Git) Diff() (string, error) {
+	cmd := exec.Command("git", "diff", "--no-renames", "HEAD~1", "HEAD")
+	return g.run(cmd)
+}
+
+// FormatPatch returns the last commit as an email formatted patch
+func (g *Git) FormatPatch(subjectPrefix string) (string, error) {
+	cmd := exec.Command("git", "format-patch", "-1", "HEAD", "--stdout", "--no-renames", "--subject-prefix="+subjectPrefix)
+	return g.run(cmd)
+}
+
+// ChangedFiles returns the paths of all files changed in the last commit
+func (g *Git) ChangedFiles() ([]string, error) {
+	cmd := exec.Command("git", "diff", "--name-only", "-z", "HEAD~1", "HEAD")
+	stdOut, err := g.run(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	// The paths are separated by NUL, since they may contain spaces
+	files := []string{}
+	for _, file := range strings.Split(stdOut, "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
 // BranchExist checks if the new branch exists
 func (g *Git) BranchExist(remoteName, branchName string) (bool, error) {
 	cmd := exec.Command("git", "ls-remote", "-q", "-h", remoteName)
@@ -130,6 +235,44 @@ func (g *Git) BranchExist(remoteName, branchName string) (bool, error) {
 	return strings.Contains(stdOut, fmt.Sprintf("\trefs/heads/%s\n", branchName)), nil
 }
 
+// RemoteDefaultBranch finds the branch that HEAD points to in a remote repository, without cloning it
+func (g *Git) RemoteDefaultBranch(ctx context.Context, cloneURL string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "ls-remote", "--symref", cloneURL)
+	stdOut, err := g.run(cmd)
+	if err != nil {
+		return "", err
+	}
+
+	var headHash string
+	branches := map[string]string{} // Commit hash to the first branch pointing to it
+	for _, line := range strings.Split(stdOut, "\n") {
+		ref, name, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		if target, isSymref := strings.CutPrefix(ref, "ref: "); isSymref {
+			if name == "HEAD" {
+				return strings.TrimPrefix(target, "refs/heads/"), nil
+			}
+			continue
+		}
+		if name == "HEAD" {
+			headHash = ref
+		} else if branch, isBranch := strings.CutPrefix(name, "refs/heads/"); isBranch && branches[ref] == "" {
+			branches[ref] = branch
+		}
+	}
+	if headHash == "" {
+		return "", errors.New("the remote has no HEAD")
+	}
+
+	// If the remote does not tell which branch HEAD points to, use the first branch with the same commit
+	if branch := branches[headHash]; branch != "" {
+		return branch, nil
+	}
+	return "", errors.New("could not find the branch HEAD points to")
+}
+
 // Push the committed changes to the remote
 func (g *Git) Push(ctx context.Context, remoteName string, force bool) error {
 	args := []string{"push", "--no-verify", remoteName}
@@ -143,6 +286,16 @@ func (g *Git) Push(ctx context.Context, remoteName string, force bool) error {
 	return err
 }
 
+// HeadCommit returns the hash of the commit that is checked out
+func (g *Git) HeadCommit() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	stdOut, err := g.run(cmd)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(stdOut), nil
+}
+
 // AddRemote adds a new remote
 func (g *Git) AddRemote(name, url string) error {
 	cmd := exec.Command("git", "remote", "add", name, url)
@@ -3,11 +3,15 @@ package gogit
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/storage/memory"
 	internalgit "github.com/lindell/multi-gitter/internal/git"
 	"github.com/pkg/errors"
 
@@ -20,6 +24,11 @@ import (
 type Git struct {
 	Directory  string // The (temporary) directory that should be worked within
 	FetchDepth int    // Limit fetching to the specified number of commits
+	Proxy      string // If set, the proxy used to connect to remotes. Ssh remotes can be reached through socks5 proxies
+
+	// If set, only changes within these paths are committed. Unlike with git itself, the whole repository is still
+	// checked out, since go-git removes everything else from the index of sparse checkouts
+	Paths []string
 
 	repo *git.Repository // The repository after the clone has been made
 }
@@ -32,6 +41,7 @@ func (g *Git) Clone(ctx context.Context, url string, baseName string) error {
 		Depth:         g.FetchDepth,
 		ReferenceName: plumbing.NewBranchReferenceName(baseName),
 		SingleBranch:  true,
+		ProxyOptions:  g.proxyOptions(),
 	})
 	if err != nil {
 		return errors.Wrap(err, "could not clone from the remote")
@@ -42,6 +52,39 @@ func (g *Git) Clone(ctx context.Context, url string, baseName string) error {
 	return nil
 }
 
+// CheckoutRef fetches a ref, like a tag, branch or commit, from the remote and checks it out
+func (g *Git) CheckoutRef(ctx context.Context, remoteName, ref string) error {
+	err := g.repo.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: remoteName,
+		RefSpecs: []config.RefSpec{
+			config.RefSpec(fmt.Sprintf("+refs/heads/*:refs/remotes/%s/*", remoteName)),
+			"+refs/tags/*:refs/tags/*",
+		},
+		Depth:        g.FetchDepth,
+		ProxyOptions: g.proxyOptions(),
+	})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return errors.Wrap(err, "could not fetch from the remote")
+	}
+
+	hash, err := g.repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		// Branches are only fetched as remote branches
+		hash, err = g.repo.ResolveRevision(plumbing.Revision(remoteName + "/" + ref))
+		if err != nil {
+			return errors.Errorf("could not find %s", ref)
+		}
+	}
+
+	w, err := g.repo.Worktree()
+	if err != nil {
+		return err
+	}
+	return w.Checkout(&git.CheckoutOptions{
+		Hash: *hash,
+	})
+}
+
 // ChangeBranch changes the branch
 func (g *Git) ChangeBranch(branchName string) error {
 	w, err := g.repo.Worktree()
@@ -72,7 +115,12 @@ func (g *Git) Changes() (bool, error) {
 		return false, err
 	}
 
-	return !status.IsClean(), nil
+	for file, s := range status {
+		if (s.Worktree != git.Unmodified || s.Staging != git.Unmodified) && internalgit.InPaths(file, g.Paths) {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 // Commit and push all changes
@@ -89,29 +137,15 @@ func (g *Git) Commit(commitAuthor *internalgit.CommitAuthor, commitMessage strin
 	}
 	w.Excludes = patterns
 
-	err = w.AddWithOptions(&git.AddOptions{
-		All: true,
-	})
-	if err != nil {
-		return err
+	if len(g.Paths) > 0 {
+		err = g.addPaths(w)
+	} else {
+		err = g.addAll(w)
 	}
-
-	status, err := w.Status()
 	if err != nil {
 		return err
 	}
 
-	// This is a workaround for a bug in go-git where "add all" does not add deleted files
-	// If https://github.com/go-git/go-git/issues/223 is fixed, this can be removed
-	for file, s := range status {
-		if s.Worktree == git.Deleted {
-			_, err = w.Add(file)
-			if err != nil {
-				return err
-			}
-		}
-	}
-
 	// Get the current hash to be able to diff it with the committed changes later
 	oldHead, err := g.repo.Head()
 	if err != nil {
@@ -145,42 +179,188 @@ func (g *Git) Commit(commitAuthor *internalgit.CommitAuthor, commitMessage strin
 	return nil
 }
 
+func (g *Git) addAll(w *git.Worktree) error {
+	err := w.AddWithOptions(&git.AddOptions{
+		All: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	status, err := w.Status()
+	if err != nil {
+		return err
+	}
+
+	// This is a workaround for a bug in go-git where "add all" does not add deleted files
+	// If https://github.com/go-git/go-git/issues/223 is fixed, this can be removed
+	for file, s := range status {
+		if s.Worktree == git.Deleted {
+			_, err = w.Add(file)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addPaths adds the changed files that are within the paths
+func (g *Git) addPaths(w *git.Worktree) error {
+	status, err := w.Status()
+	if err != nil {
+		return err
+	}
+
+	for file, s := range status {
+		if s.Worktree != git.Unmodified && internalgit.InPaths(file, g.Paths) {
+			if _, err := w.Add(file); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (g *Git) logDiff(aHash, bHash plumbing.Hash) error {
 	if !log.IsLevelEnabled(log.DebugLevel) {
 		return nil
 	}
 
-	aCommit, err := g.repo.CommitObject(aHash)
+	diff, err := g.diff(aHash, bHash)
 	if err != nil {
 		return err
 	}
+	log.Debug(diff)
+
+	return nil
+}
+
+// Diff returns the diff of the last commit
+func (g *Git) Diff() (string, error) {
+	head, err := g.repo.Head()
+	if err != nil {
+		return "", err
+	}
+
+	commit, err := g.repo.CommitObject(head.Hash())
+	if err != nil {
+		return "", err
+	}
+
+	parent, err := commit.Parent(0)
+	if err != nil {
+		return "", err
+	}
+
+	return g.diff(parent.Hash, commit.Hash)
+}
+
+// FormatPatch returns the last commit as an email formatted patch, in the same format as git format-patch
+func (g *Git) FormatPatch(subjectPrefix string) (string, error) {
+	head, err := g.repo.Head()
+	if err != nil {
+		return "", err
+	}
+
+	commit, err := g.repo.CommitObject(head.Hash())
+	if err != nil {
+		return "", err
+	}
+
+	diff, err := g.Diff()
+	if err != nil {
+		return "", err
+	}
+
+	subject, body, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n")
+	body = strings.TrimSpace(body)
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "From %s Mon Sep 17 00:00:00 2001\n", commit.Hash)
+	fmt.Fprintf(buf, "From: %s <%s>\n", commit.Author.Name, commit.Author.Email)
+	fmt.Fprintf(buf, "Date: %s\n", commit.Author.When.Format("Mon, 2 Jan 2006 15:04:05 -0700"))
+	fmt.Fprintf(buf, "Subject: [%s] %s\n\n", subjectPrefix, subject)
+	if body != "" {
+		fmt.Fprintf(buf, "%s\n", body)
+	}
+	fmt.Fprintf(buf, "---\n%s", diff)
+
+	return buf.String(), nil
+}
+
+func (g *Git) diff(aHash, bHash plumbing.Hash) (string, error) {
+	aCommit, err := g.repo.CommitObject(aHash)
+	if err != nil {
+		return "", err
+	}
 	aTree, err := aCommit.Tree()
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	bCommit, err := g.repo.CommitObject(bHash)
 	if err != nil {
-		return err
+		return "", err
 	}
 	bTree, err := bCommit.Tree()
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	patch, err := aTree.Patch(bTree)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	buf := &bytes.Buffer{}
 	err = patch.Encode(buf)
 	if err != nil {
-		return err
+		return "", err
 	}
-	log.Debug(buf.String())
+	return buf.String(), nil
+}
 
-	return nil
+// ChangedFiles returns the paths of all files changed in the last commit
+func (g *Git) ChangedFiles() ([]string, error) {
+	head, err := g.repo.Head()
+	if err != nil {
+		return nil, err
+	}
+
+	commit, err := g.repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	parent, err := commit.Parent(0)
+	if err != nil {
+		return nil, err
+	}
+	parentTree, err := parent.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := object.DiffTree(parentTree, tree)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(changes))
+	for _, change := range changes {
+		if change.To.Name != "" {
+			files = append(files, change.To.Name)
+		} else {
+			files = append(files, change.From.Name)
+		}
+	}
+
+	return files, nil
 }
 
 // BranchExist checks if the new branch exists
@@ -190,7 +370,9 @@ func (g *Git) BranchExist(remoteName, branchName string) (bool, error) {
 		return false, err
 	}
 
-	refs, err := remote.List(&git.ListOptions{})
+	refs, err := remote.List(&git.ListOptions{
+		ProxyOptions: g.proxyOptions(),
+	})
 	if err != nil {
 		return false, err
 	}
@@ -202,14 +384,65 @@ func (g *Git) BranchExist(remoteName, branchName string) (bool, error) {
 	return false, nil
 }
 
+// RemoteDefaultBranch finds the branch that HEAD points to in a remote repository, without cloning it
+func (g *Git) RemoteDefaultBranch(ctx context.Context, cloneURL string) (string, error) {
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{cloneURL},
+	})
+	refs, err := remote.ListContext(ctx, &git.ListOptions{
+		ProxyOptions: g.proxyOptions(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var head *plumbing.Reference
+	for _, ref := range refs {
+		if ref.Name() == plumbing.HEAD {
+			head = ref
+		}
+	}
+	if head == nil {
+		return "", errors.New("the remote has no HEAD")
+	}
+	if head.Type() == plumbing.SymbolicReference {
+		return head.Target().Short(), nil
+	}
+
+	// If the remote does not tell which branch HEAD points to, use the first branch with the same commit
+	for _, ref := range refs {
+		if ref.Name().IsBranch() && ref.Hash() == head.Hash() {
+			return ref.Name().Short(), nil
+		}
+	}
+	return "", errors.New("could not find the branch HEAD points to")
+}
+
 // Push the committed changes to the remote
 func (g *Git) Push(ctx context.Context, remoteName string, force bool) error {
 	return g.repo.PushContext(ctx, &git.PushOptions{
-		RemoteName: remoteName,
-		Force:      force,
+		RemoteName:   remoteName,
+		Force:        force,
+		ProxyOptions: g.proxyOptions(),
 	})
 }
 
+func (g *Git) proxyOptions() transport.ProxyOptions {
+	return transport.ProxyOptions{
+		URL: g.Proxy,
+	}
+}
+
+// HeadCommit returns the hash of the commit that is checked out
+func (g *Git) HeadCommit() (string, error) {
+	head, err := g.repo.Head()
+	if err != nil {
+		return "", err
+	}
+	return head.Hash().String(), nil
+}
+
 // AddRemote adds a new remote
 func (g *Git) AddRemote(name, url string) error {
 	_, err := g.repo.CreateRemote(&config.RemoteConfig{
@@ -0,0 +1,17 @@
+package git
+
+import "strings"
+
+// InPaths returns if a file, relative to the root of the repository, is one of the paths or within any of them.
+// All files are within an empty list of paths
+func InPaths(file string, paths []string) bool {
+	if len(paths) == 0 {
+		return true
+	}
+	for _, p := range paths {
+		if file == p || strings.HasPrefix(file, p+"/") {
+			return true
+		}
+	}
+	return false
+}
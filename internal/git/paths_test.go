@@ -0,0 +1,26 @@
+package git
+
+import "testing"
+
+func TestInPaths(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string
+		paths []string
+		want  bool
+	}{
+		{name: "no paths", file: "a/b.txt", paths: nil, want: true},
+		{name: "within directory", file: "deploy/app.yaml", paths: []string{"deploy"}, want: true},
+		{name: "nested directory", file: "deploy/prod/app.yaml", paths: []string{"src", "deploy/prod"}, want: true},
+		{name: "the path itself", file: "deploy/app.yaml", paths: []string{"deploy/app.yaml"}, want: true},
+		{name: "same prefix", file: "deployment/app.yaml", paths: []string{"deploy"}, want: false},
+		{name: "outside", file: "README.md", paths: []string{"deploy"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InPaths(tt.file, tt.paths); got != tt.want {
+				t.Errorf("InPaths() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+)
+
+// Interaction is a recorded request and the response to it
+type Interaction struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"` // The path and query of the request, without the host or any credentials
+	RequestBody string      `json:"requestBody,omitempty"`
+	StatusCode  int         `json:"statusCode"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        string      `json:"body"`
+}
+
+// credentialParameters are query parameters that are never recorded, since some platforms use them to authenticate
+var credentialParameters = []string{"access_token", "private_token", "token"}
+
+// cassetteTransport is used instead of the default transport by all clients using the LoggingRoundTripper, if set
+var cassetteTransport func(next http.RoundTripper) http.RoundTripper
+
+// Record records all requests made through the LoggingRoundTripper to the cassette file at path.
+// The file is updated after each request, so that it's complete even if the process exits unexpectedly
+func Record(path string) {
+	rec := &recorder{path: path}
+	cassetteTransport = func(next http.RoundTripper) http.RoundTripper {
+		return recordingRoundTripper{next: next, recorder: rec}
+	}
+}
+
+// Replay responds to all requests made through the LoggingRoundTripper with the responses recorded in the cassette file at path.
+// Requests are matched by method, path and query, in the order they were recorded
+func Replay(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var interactions []Interaction
+	if err := json.Unmarshal(data, &interactions); err != nil {
+		return fmt.Errorf("could not parse cassette %s: %w", path, err)
+	}
+
+	rep := &replayer{interactions: interactions, used: make([]bool, len(interactions))}
+	cassetteTransport = func(http.RoundTripper) http.RoundTripper {
+		return rep
+	}
+	return nil
+}
+
+// StopCassette stops any recording or replay
+func StopCassette() {
+	cassetteTransport = nil
+}
+
+func cassetteURL(u *url.URL) string {
+	query := u.Query()
+	for _, param := range credentialParameters {
+		query.Del(param)
+	}
+
+	ret := u.EscapedPath()
+	if encoded := query.Encode(); encoded != "" {
+		ret += "?" + encoded
+	}
+	return ret
+}
+
+type recorder struct {
+	path         string
+	lock         sync.Mutex
+	interactions []Interaction
+}
+
+func (r *recorder) add(interaction Interaction) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.interactions = append(r.interactions, interaction)
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, data, 0o600)
+}
+
+type recordingRoundTripper struct {
+	next     http.RoundTripper
+	recorder *recorder
+}
+
+func (rt recordingRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if r.Body != nil && r.GetBody != nil {
+		body, err := r.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		reqBody, err = io.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := rt.next.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	header := resp.Header.Clone()
+	header.Del("Set-Cookie")
+
+	err = rt.recorder.add(Interaction{
+		Method:      r.Method,
+		URL:         cassetteURL(r.URL),
+		RequestBody: string(reqBody),
+		StatusCode:  resp.StatusCode,
+		Header:      header,
+		Body:        string(respBody),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not record request: %w", err)
+	}
+
+	return resp, nil
+}
+
+type replayer struct {
+	lock         sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+func (rep *replayer) RoundTrip(r *http.Request) (*http.Response, error) {
+	rep.lock.Lock()
+	defer rep.lock.Unlock()
+
+	u := cassetteURL(r.URL)
+	for i, interaction := range rep.interactions {
+		if rep.used[i] || interaction.Method != r.Method || interaction.URL != u {
+			continue
+		}
+		rep.used[i] = true
+
+		header := interaction.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.StatusCode, http.StatusText(interaction.StatusCode)),
+			StatusCode:    interaction.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader([]byte(interaction.Body))),
+			ContentLength: int64(len(interaction.Body)),
+			Request:       r,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no recorded response for %s %s", r.Method, u)
+}
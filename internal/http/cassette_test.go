@@ -0,0 +1,59 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCassette(t *testing.T) {
+	defer StopCassette()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(r.URL.Path + ":" + string(body)))
+	}))
+
+	cassette := filepath.Join(t.TempDir(), "cassette.json")
+	client := &http.Client{Transport: LoggingRoundTripper{}}
+	do := func(path string) (int, string) {
+		resp, err := client.Post(server.URL+path, "text/plain", strings.NewReader("body"))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	Record(cassette)
+	status, body := do("/first?access_token=secret")
+	assert.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, "/first:body", body)
+	_, _ = do("/second")
+	server.Close()
+
+	data, err := os.ReadFile(cassette)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secret")
+
+	require.NoError(t, Replay(cassette))
+	status, body = do("/first?access_token=other-secret")
+	assert.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, "/first:body", body)
+
+	_, err = client.Get(server.URL + "/unknown")
+	assert.ErrorContains(t, err, "no recorded response for GET /unknown")
+
+	// Every interaction can only be replayed once
+	_, err = client.Post(server.URL+"/first", "text/plain", strings.NewReader("body"))
+	assert.ErrorContains(t, err, "no recorded response for POST /first")
+}
@@ -0,0 +1,63 @@
+package http
+
+import (
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// maxInjectedDelay is the longest time a request is delayed when slow responses are injected
+const maxInjectedDelay = 5 * time.Second
+
+// faultRate is the probability of each kind of fault being injected into a request
+var faultRate float64
+
+// InjectFaults makes requests through the LoggingRoundTripper randomly fail, or respond slowly. rate is the
+// probability, between 0 and 1, of a request failing, and the probability of a request being delayed
+func InjectFaults(rate float64) {
+	faultRate = rate
+}
+
+// injectFault returns a failed response to the request, or delays it, if a fault should be injected
+func injectFault(r *http.Request) *http.Response {
+	if faultRate <= 0 {
+		return nil
+	}
+
+	if rand.Float64() < faultRate {
+		delay := time.Duration(rand.Int63n(int64(maxInjectedDelay)))
+		log.WithField("url", r.URL.Path).Warnf("Injecting a delay of %s", delay.Round(time.Millisecond))
+		// A cancelled request is not delayed any further, and is left to the transport to fail with the error of the context
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-r.Context().Done():
+			timer.Stop()
+		}
+		if r.Context().Err() != nil {
+			return nil
+		}
+	}
+
+	if rand.Float64() < faultRate {
+		log.WithField("url", r.URL.Path).Warn("Injecting a failed response")
+		body := "fault injected by multi-gitter"
+		return &http.Response{
+			Status:        "503 Service Unavailable",
+			StatusCode:    http.StatusServiceUnavailable,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": []string{"text/plain"}},
+			Body:          io.NopCloser(strings.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       r,
+		}
+	}
+
+	return nil
+}
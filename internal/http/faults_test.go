@@ -0,0 +1,26 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInjectFaultCancelled(t *testing.T) {
+	InjectFaults(1)
+	defer InjectFaults(0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://example.com", nil)
+	require.NoError(t, err)
+
+	start := time.Now()
+	resp := injectFault(req)
+	assert.Nil(t, resp, "a failed response was injected into a cancelled request")
+	assert.Less(t, time.Since(start), maxInjectedDelay/2)
+}
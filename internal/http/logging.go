@@ -18,11 +18,21 @@ func NewLoggingRoundTripper(rt http.RoundTripper) http.RoundTripper {
 // LoggingRoundTripper logs a request-response
 type LoggingRoundTripper struct {
 	Next http.RoundTripper
+
+	// If set, the request and response are neither logged nor recorded, since they contain secrets that are not censored
+	Sensitive bool
 }
 
 // RoundTrip logs a request-response
 func (l LoggingRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
-	req, _ := httputil.DumpRequestOut(r, true)
+	if err := checkReadOnly(r); err != nil {
+		return nil, err
+	}
+
+	var req []byte
+	if !l.Sensitive {
+		req, _ = httputil.DumpRequestOut(r, true)
+	}
 
 	var roundTripper http.RoundTripper
 	if l.Next != nil {
@@ -30,13 +40,23 @@ func (l LoggingRoundTripper) RoundTrip(r *http.Request) (*http.Response, error)
 	} else {
 		roundTripper = http.DefaultTransport
 	}
+	if cassetteTransport != nil && !l.Sensitive {
+		roundTripper = cassetteTransport(roundTripper)
+	}
 
 	start := time.Now()
-	resp, err := roundTripper.RoundTrip(r)
+	var resp *http.Response
+	var err error
+	if injected := injectFault(r); injected != nil {
+		resp = injected
+	} else {
+		resp, err = roundTripper.RoundTrip(r)
+		recordUsage(r, resp)
+	}
 	took := time.Since(start)
 
 	var res []byte
-	if resp != nil {
+	if resp != nil && !l.Sensitive {
 		res, _ = httputil.DumpResponse(resp, true)
 	}
 
@@ -0,0 +1,77 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrReadOnly is returned for requests that could change something, when read-only mode is enabled
+var ErrReadOnly = errors.New("the request was blocked since multi-gitter is in read-only mode")
+
+// mutationOperation matches mutation operations anywhere in a GraphQL document. Documents with the word in other
+// places, like in a string, are blocked as well, to be on the safe side
+var mutationOperation = regexp.MustCompile(`\bmutation\b`)
+
+// readOnly blocks all requests that could change something, if set
+var readOnly bool
+
+// SetReadOnly makes all requests through the LoggingRoundTripper that could change something fail, instead of being sent
+func SetReadOnly(enabled bool) {
+	readOnly = enabled
+}
+
+// checkReadOnly returns an error if read-only mode is enabled, and the request could change something. Only GET, HEAD
+// and OPTIONS requests are allowed, together with GraphQL queries, since some platforms are read with GraphQL
+func checkReadOnly(r *http.Request) error {
+	if !readOnly {
+		return nil
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return nil
+	case http.MethodPost:
+		if isGraphQLQuery(r) {
+			return nil
+		}
+	}
+	return errors.WithMessagef(ErrReadOnly, "%s %s", r.Method, r.URL.Path)
+}
+
+// isGraphQLQuery returns if the request is a GraphQL request that only contains a query, and no mutation
+func isGraphQLQuery(r *http.Request) bool {
+	if r.GetBody == nil {
+		return false
+	}
+	body, err := r.GetBody()
+	if err != nil {
+		return false
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return false
+	}
+
+	var graphQLRequest struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal(data, &graphQLRequest); err != nil {
+		return false
+	}
+	query := strings.TrimSpace(graphQLRequest.Query)
+	if query == "" {
+		return false
+	}
+	if mutationOperation.MatchString(query) {
+		return false
+	}
+	// Anonymous operations, starting with "{", are always queries
+	return strings.HasPrefix(query, "{") || strings.HasPrefix(query, "query")
+}
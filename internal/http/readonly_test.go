@@ -0,0 +1,59 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadOnly(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	SetReadOnly(true)
+	defer SetReadOnly(false)
+
+	client := &http.Client{Transport: LoggingRoundTripper{}}
+
+	tests := []struct {
+		name    string
+		method  string
+		body    string
+		allowed bool
+	}{
+		{name: "get", method: http.MethodGet, allowed: true},
+		{name: "head", method: http.MethodHead, allowed: true},
+		{name: "post", method: http.MethodPost, body: `{"title":"a title"}`},
+		{name: "put", method: http.MethodPut, body: `{"title":"a title"}`},
+		{name: "patch", method: http.MethodPatch, body: `{"title":"a title"}`},
+		{name: "delete", method: http.MethodDelete},
+		{name: "graphql query", method: http.MethodPost, body: `{"query":"query($owner: String!) { repository(owner: $owner) { id } }"}`, allowed: true},
+		{name: "anonymous graphql query", method: http.MethodPost, body: `{"query":"{ viewer { login } }"}`, allowed: true},
+		{name: "graphql mutation", method: http.MethodPost, body: `{"query":"mutation { mergePullRequest(input: {}) { clientMutationId } }"}`},
+		{name: "graphql query and mutation", method: http.MethodPost, body: `{"query":"query A { viewer { login } } mutation B { addStar(input: {}) { clientMutationId } }"}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			requestsBefore := requests
+
+			req, err := http.NewRequest(test.method, server.URL+"/api", strings.NewReader(test.body))
+			require.NoError(t, err)
+			resp, err := client.Do(req)
+			if test.allowed {
+				require.NoError(t, err)
+				resp.Body.Close()
+				assert.Equal(t, requestsBefore+1, requests)
+			} else {
+				assert.ErrorIs(t, err, ErrReadOnly)
+				assert.Equal(t, requestsBefore, requests)
+			}
+		})
+	}
+}
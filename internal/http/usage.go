@@ -0,0 +1,100 @@
+package http
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// APIUsage is the usage of the API of a host during the lifetime of the process
+type APIUsage struct {
+	Host     string
+	Resource string // The rate limited resource, if the host has several (like GitHub's core, graphql and search)
+	Requests int
+
+	// The rate limit reported by the latest response, only set if HasRateLimit is true
+	HasRateLimit       bool
+	RateLimit          int
+	RateLimitRemaining int
+	RateLimitReset     time.Time
+}
+
+// FitsInWindow returns if the same amount of requests can be made again before the rate limit is exceeded
+func (u APIUsage) FitsInWindow() bool {
+	return !u.HasRateLimit || u.RateLimitRemaining >= u.Requests
+}
+
+type usageKey struct {
+	host     string
+	resource string
+}
+
+var (
+	usageLock sync.Mutex
+	usage     = map[usageKey]*APIUsage{}
+)
+
+// rate limit headers, as used by GitHub (X-RateLimit-*) and GitLab (RateLimit-*)
+var rateLimitHeaderPrefixes = []string{"X-RateLimit-", "RateLimit-"}
+
+func recordUsage(r *http.Request, resp *http.Response) {
+	key := usageKey{host: r.URL.Host}
+	if resp != nil {
+		key.resource = resp.Header.Get("X-RateLimit-Resource")
+	}
+
+	usageLock.Lock()
+	defer usageLock.Unlock()
+
+	u, ok := usage[key]
+	if !ok {
+		u = &APIUsage{
+			Host:     key.host,
+			Resource: key.resource,
+		}
+		usage[key] = u
+	}
+	u.Requests++
+
+	if resp == nil {
+		return
+	}
+	for _, prefix := range rateLimitHeaderPrefixes {
+		limit, err := strconv.Atoi(resp.Header.Get(prefix + "Limit"))
+		if err != nil {
+			continue
+		}
+		remaining, err := strconv.Atoi(resp.Header.Get(prefix + "Remaining"))
+		if err != nil {
+			continue
+		}
+
+		u.HasRateLimit = true
+		u.RateLimit = limit
+		u.RateLimitRemaining = remaining
+		if reset, err := strconv.ParseInt(resp.Header.Get(prefix+"Reset"), 10, 64); err == nil {
+			u.RateLimitReset = time.Unix(reset, 0)
+		}
+		return
+	}
+}
+
+// Usage returns the usage of every API that requests has been made to
+func Usage() []APIUsage {
+	usageLock.Lock()
+	defer usageLock.Unlock()
+
+	ret := make([]APIUsage, 0, len(usage))
+	for _, u := range usage {
+		ret = append(ret, *u)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Host != ret[j].Host {
+			return ret[i].Host < ret[j].Host
+		}
+		return ret[i].Resource < ret[j].Resource
+	})
+	return ret
+}
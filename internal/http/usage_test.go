@@ -0,0 +1,54 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "10")
+		w.Header().Set("X-RateLimit-Reset", "1700000000")
+		w.Header().Set("X-RateLimit-Resource", "core")
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: LoggingRoundTripper{}}
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	var found *APIUsage
+	for _, usage := range Usage() {
+		if usage.Host == u.Host {
+			usage := usage
+			found = &usage
+		}
+	}
+	require.NotNil(t, found)
+	assert.Equal(t, APIUsage{
+		Host:               u.Host,
+		Resource:           "core",
+		Requests:           3,
+		HasRateLimit:       true,
+		RateLimit:          100,
+		RateLimitRemaining: 10,
+		RateLimitReset:     time.Unix(1700000000, 0),
+	}, *found)
+	assert.True(t, found.FitsInWindow())
+
+	found.RateLimitRemaining = 2
+	assert.False(t, found.FitsInWindow())
+}
@@ -0,0 +1,171 @@
+// Package jira contains a client for the parts of the Jira REST API that are used to track campaigns
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	internalhttp "github.com/lindell/multi-gitter/internal/http"
+	"github.com/pkg/errors"
+)
+
+// Jira creates, finds and transitions issues in a Jira project
+type Jira struct {
+	BaseURL   string // The url of the Jira instance, like https://example.atlassian.net
+	Project   string // The key of the project issues are created in
+	IssueType string // The type of created issues, like Task
+
+	// If User is set, basic authentication with the user and token (the API token on Jira Cloud) is used,
+	// otherwise the token is used as a personal access token
+	User  string
+	Token string
+
+	client *http.Client
+}
+
+// New creates a new Jira client
+func New(baseURL, project, issueType, user, token string) *Jira {
+	return &Jira{
+		BaseURL:   strings.TrimSuffix(baseURL, "/"),
+		Project:   project,
+		IssueType: issueType,
+		User:      user,
+		Token:     token,
+		client: &http.Client{
+			Transport: internalhttp.NewLoggingRoundTripper(nil),
+		},
+	}
+}
+
+// FindIssue finds the issue with the label, an empty string is returned if there is none
+func (j *Jira) FindIssue(ctx context.Context, label string) (string, error) {
+	jql := fmt.Sprintf(`labels = %q`, label)
+	if j.Project != "" {
+		jql = fmt.Sprintf(`project = %q AND %s`, j.Project, jql)
+	}
+
+	var result struct {
+		Issues []struct {
+			Key string `json:"key"`
+		} `json:"issues"`
+	}
+	query := url.Values{
+		"jql":        []string{jql},
+		"fields":     []string{"key"},
+		"maxResults": []string{"1"},
+	}
+	if err := j.do(ctx, http.MethodGet, "/rest/api/2/search?"+query.Encode(), nil, &result); err != nil {
+		return "", errors.WithMessage(err, "could not search for Jira issues")
+	}
+
+	if len(result.Issues) == 0 {
+		return "", nil
+	}
+	return result.Issues[0].Key, nil
+}
+
+// CreateIssue creates an issue with the label, and returns its key
+func (j *Jira) CreateIssue(ctx context.Context, summary, description, label string) (string, error) {
+	if j.Project == "" {
+		return "", errors.New("a Jira project has to be set to create issues")
+	}
+
+	body := map[string]interface{}{
+		"fields": map[string]interface{}{
+			"project":     map[string]string{"key": j.Project},
+			"issuetype":   map[string]string{"name": j.IssueType},
+			"summary":     summary,
+			"description": description,
+			"labels":      []string{label},
+		},
+	}
+
+	var result struct {
+		Key string `json:"key"`
+	}
+	if err := j.do(ctx, http.MethodPost, "/rest/api/2/issue", body, &result); err != nil {
+		return "", errors.WithMessage(err, "could not create Jira issue")
+	}
+	return result.Key, nil
+}
+
+// TransitionIssue moves an issue with the transition that has the name, like "Done"
+func (j *Jira) TransitionIssue(ctx context.Context, key string, transition string) error {
+	path := fmt.Sprintf("/rest/api/2/issue/%s/transitions", url.PathEscape(key))
+
+	var transitions struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"transitions"`
+	}
+	if err := j.do(ctx, http.MethodGet, path, nil, &transitions); err != nil {
+		return errors.WithMessagef(err, "could not get the transitions of %s", key)
+	}
+
+	for _, t := range transitions.Transitions {
+		if strings.EqualFold(t.Name, transition) {
+			body := map[string]interface{}{
+				"transition": map[string]string{"id": t.ID},
+			}
+			if err := j.do(ctx, http.MethodPost, path, body, nil); err != nil {
+				return errors.WithMessagef(err, "could not transition %s", key)
+			}
+			return nil
+		}
+	}
+
+	return errors.Errorf("%s has no transition named %q", key, transition)
+}
+
+// IssueURL returns the url of an issue
+func (j *Jira) IssueURL(key string) string {
+	return fmt.Sprintf("%s/browse/%s", j.BaseURL, key)
+}
+
+func (j *Jira) do(ctx context.Context, method, path string, body interface{}, result interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, j.BaseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if j.User != "" {
+		req.SetBasicAuth(j.User, j.Token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+j.Token)
+	}
+
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("unexpected status code %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
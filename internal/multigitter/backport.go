@@ -0,0 +1,129 @@
+package multigitter
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// backportFeatureBranch returns the feature branch of the changes made against a backport branch, which has to differ
+// from the feature branch of the regular run, since the changes are based on another branch
+func backportFeatureBranch(featureBranch, backportBranch string) string {
+	return featureBranch + "-" + backportBranch
+}
+
+// branchLister is implemented by platforms that can list the branches of a repository
+type branchLister interface {
+	GetBranches(ctx context.Context, repo scm.Repository) ([]string, error)
+}
+
+// isBranchPattern returns if a backport branch is a pattern, like "release/*", instead of the name of a branch
+func isBranchPattern(branch string) bool {
+	return strings.ContainsAny(branch, "*?[")
+}
+
+// backportTarget is a branch that the changes are backported to, and the repositories it exists in
+type backportTarget struct {
+	branch string
+	repos  []scm.Repository
+}
+
+// runBackports makes the changes once more against each of the backport branches, in the repositories where they exist,
+// with one pull request per backport branch and repository. It returns on how many repositories, of how many, it failed
+func (r *Runner) runBackports(ctx context.Context, repos []scm.Repository) (failed int, total int, err error) {
+	for _, pattern := range r.BackportBranches {
+		targets, err := r.backportTargets(ctx, pattern, repos)
+		if err != nil {
+			return 0, 0, err
+		}
+		if len(targets) == 0 {
+			log.Infof("Skipping the backport to %s, since none of the repositories has the branch", pattern)
+			continue
+		}
+
+		for _, target := range targets {
+			backportFailed, err := r.runBackport(ctx, target)
+			if err != nil {
+				return 0, 0, err
+			}
+			failed += backportFailed
+			total += len(target.repos)
+		}
+	}
+	return failed, total, nil
+}
+
+// runBackport makes the changes against a single backport branch, and returns on how many repositories it failed
+func (r *Runner) runBackport(ctx context.Context, target backportTarget) (int, error) {
+	backport := *r
+	backport.BackportBranches = nil
+	backport.BaseBranch = target.branch
+	backport.BaseBranchRules = nil
+	backport.ruleBaseBranches = nil
+	backport.BaseRef = nil
+	backport.FeatureBranch = backportFeatureBranch(r.FeatureBranch, target.branch)
+	backport.CheckpointPath = "" // The checkpoint is about the regular run, and is not overwritten
+
+	log.Infof("Backporting the changes to %s, with the branch %s", target.branch, backport.FeatureBranch)
+	failed, err := backport.runRepositories(ctx, target.repos)
+	if err != nil {
+		return 0, errors.WithMessagef(err, "could not backport the changes to %s", target.branch)
+	}
+	return failed, nil
+}
+
+// backportTargets finds the branches a backport branch refers to, and the repositories they exist in. A pattern, like
+// "release/*", can refer to several branches, which are listed in order. A wildcard does not match across a "/"
+func (r *Runner) backportTargets(ctx context.Context, branch string, repos []scm.Repository) ([]backportTarget, error) {
+	if !isBranchPattern(branch) {
+		filtered, err := r.filterBackportRepositories(ctx, branch, repos)
+		if err != nil || len(filtered) == 0 {
+			return nil, err
+		}
+		return []backportTarget{{branch: branch, repos: filtered}}, nil
+	}
+
+	lister := r.VersionController.(branchLister)
+	branchRepos := map[string][]scm.Repository{}
+	for _, repo := range repos {
+		branches, err := lister.GetBranches(ctx, repo)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "could not get the branches of %s", repo.FullName())
+		}
+		for _, name := range branches {
+			if ok, _ := path.Match(branch, name); ok && name != r.FeatureBranch {
+				branchRepos[name] = append(branchRepos[name], repo)
+			}
+		}
+	}
+
+	targets := make([]backportTarget, 0, len(branchRepos))
+	for name, matched := range branchRepos {
+		targets = append(targets, backportTarget{branch: name, repos: matched})
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].branch < targets[j].branch })
+	return targets, nil
+}
+
+// filterBackportRepositories removes the repositories where the backport branch does not exist
+func (r *Runner) filterBackportRepositories(ctx context.Context, branch string, repos []scm.Repository) ([]scm.Repository, error) {
+	checker := r.VersionController.(branchExistChecker)
+	filtered := make([]scm.Repository, 0, len(repos))
+	for _, repo := range repos {
+		exists, err := checker.BranchExists(ctx, repo, branch)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "could not check if %s exists in %s", branch, repo.FullName())
+		}
+		if !exists {
+			log.WithField("repo", repo.FullName()).Debugf("Skipping since the branch %s does not exist", branch)
+			continue
+		}
+		filtered = append(filtered, repo)
+	}
+	return filtered, nil
+}
@@ -0,0 +1,78 @@
+package multigitter
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// branchExistChecker is implemented by platforms that can check if a branch exists in a repository
+type branchExistChecker interface {
+	BranchExists(ctx context.Context, repo scm.Repository, branchName string) (bool, error)
+}
+
+// BaseBranchRule makes a branch the base branch of the repositories it applies to, if the branch exists in them
+type BaseBranchRule struct {
+	Repository *regexp.Regexp // The repositories the rule applies to, if nil it applies to all repositories
+	Branch     string
+}
+
+// ParseBaseBranchRule parses a base branch rule in the format "[repository regex:]branch", like "develop"
+// or "^my-org/legacy-.*:develop". Since branch names can't contain colons, the last colon separates the two parts
+func ParseBaseBranchRule(str string) (BaseBranchRule, error) {
+	i := strings.LastIndex(str, ":")
+	if i == -1 {
+		if str == "" {
+			return BaseBranchRule{}, errors.New("the branch of a base branch rule can't be empty")
+		}
+		return BaseBranchRule{Branch: str}, nil
+	}
+
+	repoRegex, branch := str[:i], str[i+1:]
+	if branch == "" {
+		return BaseBranchRule{}, fmt.Errorf("the branch of the base branch rule \"%s\" can't be empty", str)
+	}
+	repository, err := regexp.Compile(repoRegex)
+	if err != nil {
+		return BaseBranchRule{}, errors.WithMessagef(err, "could not parse the repository regex of the base branch rule \"%s\"", str)
+	}
+	return BaseBranchRule{Repository: repository, Branch: branch}, nil
+}
+
+func (rule BaseBranchRule) appliesTo(repoName string) bool {
+	return rule.Repository == nil || rule.Repository.MatchString(repoName)
+}
+
+// resolveBaseBranches evaluates the base branch rules for every repository. The branch of the first rule that applies to
+// a repository, and exists in it, becomes its base branch. Repositories without such a rule keep the regular base branch
+func (r *Runner) resolveBaseBranches(ctx context.Context, repos []scm.Repository) error {
+	if len(r.BaseBranchRules) == 0 {
+		return nil
+	}
+
+	checker := r.VersionController.(branchExistChecker)
+	r.ruleBaseBranches = map[string]string{}
+	for _, repo := range repos {
+		for _, rule := range r.BaseBranchRules {
+			if !rule.appliesTo(repo.FullName()) {
+				continue
+			}
+
+			exists, err := checker.BranchExists(ctx, repo, rule.Branch)
+			if err != nil {
+				return errors.WithMessagef(err, "could not check if the branch %s exists in %s", rule.Branch, repo.FullName())
+			}
+			if exists {
+				log.WithField("repo", repo.FullName()).Debugf("Using %s as base branch", rule.Branch)
+				r.ruleBaseBranches[repo.FullName()] = rule.Branch
+				break
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package multigitter
+
+import (
+	"context"
+	"strings"
+	"text/template"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// baseRefTemplateData is the data available in the base ref template
+type baseRefTemplateData struct {
+	Repository string // The full name of the repository
+	Owner      string // The owner of the repository, everything before the last slash of the full name
+	Name       string // The name of the repository, without the owner
+	BaseBranch string // The branch pull requests are made to
+}
+
+// ParseBaseRef parses the template of the ref, like a tag or commit, that changes are made on top of
+func ParseBaseRef(text string) (*template.Template, error) {
+	tmpl, err := template.New("base-ref").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not parse the base ref template")
+	}
+	return tmpl, nil
+}
+
+// baseRef executes the base ref template for a repository. An empty string means that the tip of the base branch is used
+func (r *Runner) baseRef(repo scm.Repository, baseBranch string) (string, error) {
+	if r.BaseRef == nil {
+		return "", nil
+	}
+
+	fullName := repo.FullName()
+	data := baseRefTemplateData{
+		Repository: fullName,
+		Name:       fullName,
+		BaseBranch: baseBranch,
+	}
+	if i := strings.LastIndex(fullName, "/"); i != -1 {
+		data.Owner = fullName[:i]
+		data.Name = fullName[i+1:]
+	}
+
+	sb := &strings.Builder{}
+	if err := r.BaseRef.Execute(sb, data); err != nil {
+		return "", errors.WithMessage(err, "could not execute the base ref template")
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
+
+// checkoutBaseRef checks out the base ref of a repository, if any, in a clone of its base branch
+func (r *Runner) checkoutBaseRef(ctx context.Context, log log.FieldLogger, repo scm.Repository, baseBranch string, sourceController Git) error {
+	ref, err := r.baseRef(repo, baseBranch)
+	if err != nil || ref == "" {
+		return err
+	}
+
+	log.Infof("Making the changes on top of %s", ref)
+	if err := sourceController.CheckoutRef(ctx, "origin", ref); err != nil {
+		return errors.WithMessagef(err, "could not check out %s", ref)
+	}
+	return nil
+}
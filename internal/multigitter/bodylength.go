@@ -0,0 +1,100 @@
+package multigitter
+
+import (
+	"context"
+	"slices"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// bodyLengthLimiter is implemented by platforms that limit the length of pull request bodies
+type bodyLengthLimiter interface {
+	// MaxPullRequestBodyLength returns the maximum number of characters in a pull request body
+	MaxPullRequestBodyLength() int
+}
+
+// pullRequestCommenter is implemented by platforms that can comment on pull requests
+type pullRequestCommenter interface {
+	CommentOnPullRequest(ctx context.Context, pr scm.PullRequest, comment string) error
+	// PullRequestComments returns the bodies of all comments on the pull request
+	PullRequestComments(ctx context.Context, pr scm.PullRequest) ([]string, error)
+}
+
+// maxBodyLength returns the maximum length of pull request bodies on the platform, or zero if there is no limit
+func (r *Runner) maxBodyLength() int {
+	limiter, ok := r.VersionController.(bodyLengthLimiter)
+	if !ok {
+		return 0
+	}
+	return limiter.MaxPullRequestBodyLength()
+}
+
+// truncateBody shortens the body to fit within the limit, ending it with the marker. Returns if the body was truncated
+func truncateBody(body string, limit int, marker string) (string, bool) {
+	runes := []rune(body)
+	if limit <= 0 || len(runes) <= limit {
+		return body, false
+	}
+
+	markerRunes := []rune(marker)
+	if len(markerRunes) >= limit {
+		return string(markerRunes[:limit]), true
+	}
+
+	return string(runes[:limit-len(markerRunes)]) + marker, true
+}
+
+// splitBody splits the body into parts that are all within the limit
+func splitBody(body string, limit int) []string {
+	runes := []rune(body)
+	parts := []string{}
+	for len(runes) > limit {
+		parts = append(parts, string(runes[:limit]))
+		runes = runes[limit:]
+	}
+	if len(runes) > 0 {
+		parts = append(parts, string(runes))
+	}
+	return parts
+}
+
+// commentFullBody adds the full, untruncated, body as comments on the pull request, unless an earlier run already did
+func (r *Runner) commentFullBody(ctx context.Context, log log.FieldLogger, pr scm.PullRequest, body string) error {
+	commenter := r.VersionController.(pullRequestCommenter)
+
+	parts := splitBody(body, r.maxBodyLength())
+	commented, err := isCommented(ctx, commenter, pr, parts...)
+	if err != nil {
+		return errors.Wrap(err, "could not get the comments of the pull request")
+	}
+	if commented {
+		log.Info("The full pull request body is already commented")
+		return nil
+	}
+
+	log.Info("Adding the full pull request body as comments")
+	for _, part := range parts {
+		if err := commenter.CommentOnPullRequest(ctx, pr, part); err != nil {
+			return errors.Wrap(err, "could not add the full pull request body as a comment")
+		}
+	}
+
+	return nil
+}
+
+// isCommented returns if all of the comments already exist on the pull request, so that updating a pull request with
+// the same changes does not post the same comments again
+func isCommented(ctx context.Context, commenter pullRequestCommenter, pr scm.PullRequest, comments ...string) (bool, error) {
+	existing, err := commenter.PullRequestComments(ctx, pr)
+	if err != nil {
+		return false, err
+	}
+	for _, comment := range comments {
+		if !slices.Contains(existing, comment) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,80 @@
+package multigitter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// campaignPullRequestFinder is implemented by platforms that can find open pull requests by their title and body,
+// regardless of which branch they are made from
+type campaignPullRequestFinder interface {
+	// FindCampaignPullRequest finds an open pull request to the base branch with the title, and a body containing the marker.
+	// The branch of the pull request is returned together with it
+	FindCampaignPullRequest(ctx context.Context, repo scm.Repository, baseBranch string, title string, marker string) (scm.PullRequest, string, error)
+}
+
+// campaignMarker returns the hidden text added to the body of every pull request of a campaign
+func campaignMarker(campaign string) string {
+	return fmt.Sprintf("<!-- multi-gitter campaign: %s -->", campaign)
+}
+
+// addCampaignMarker adds the campaign marker first in the body, so that it's kept if the body is truncated
+func addCampaignMarker(body string, campaign string) string {
+	marker := campaignMarker(campaign)
+	if strings.Contains(body, marker) {
+		return body
+	}
+	return marker + "\n" + body
+}
+
+// updateRenamedPullRequest finds an open pull request of the same campaign, made from another branch than the
+// feature branch, like when the branch name of the campaign has been changed. If one is found, it's handled like a pull
+// request from an existing feature branch, instead of a duplicate pull request being created
+func (r *Runner) updateRenamedPullRequest(
+	ctx context.Context, log log.FieldLogger, repo scm.Repository, prRepo scm.Repository, sourceController Git, remoteName string, baseBranch string,
+) (scm.PullRequest, error) {
+	existing, err := r.VersionController.GetOpenPullRequest(ctx, repo, r.FeatureBranch)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, nil
+	}
+
+	newPR, err := r.newPullRequest(repo, sourceController, baseBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, branch, err := r.VersionController.(campaignPullRequestFinder).FindCampaignPullRequest(ctx, repo, baseBranch, newPR.Title, campaignMarker(r.Campaign))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not search for pull requests of the campaign")
+	}
+	if pr == nil || branch == r.FeatureBranch {
+		return nil, nil
+	}
+
+	if r.ConflictStrategy == ConflictStrategySkip {
+		log.Infof("Found %s of the same campaign on the branch %s, skipping since the branch already exists", pr.String(), branch)
+		return pr, errBranchExist
+	}
+
+	log.Infof("Found %s of the same campaign on the branch %s, updating it instead of creating a new pull request", pr.String(), branch)
+
+	if err := sourceController.ChangeBranch(branch); err != nil {
+		return nil, err
+	}
+	if err := sourceController.Push(ctx, remoteName, true); err != nil {
+		return nil, errors.Wrap(err, "could not push changes")
+	}
+
+	// The pull request is updated like any other, just from the branch it was made from
+	renamed := *r
+	renamed.FeatureBranch = branch
+	return renamed.ensurePullRequestExists(ctx, log, repo, prRepo, sourceController, baseBranch, true)
+}
@@ -0,0 +1,161 @@
+package multigitter
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+
+	internalhttp "github.com/lindell/multi-gitter/internal/http"
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const changeRequestTimeout = 30 * time.Second
+
+// ChangeRequestGate requires a valid change request, like one in ServiceNow, before any change is made in repositories
+// of production organizations
+type ChangeRequestGate struct {
+	ProductionOrganizations []string // The organizations, and their subgroups, that require a change request, wildcards like "prod-*" can be used
+	ChangeRequest           string   // The ID of the change request
+
+	// The url that validates the change request, where {{.ChangeRequest}} is replaced with its (url escaped) ID. The change
+	// request is valid if the response has a 2xx status code
+	validationURL *template.Template
+	headers       http.Header
+}
+
+// NewChangeRequestGate creates a change request gate. The headers, like "Authorization: Basic ...", are sent with the validation request
+func NewChangeRequestGate(productionOrganizations []string, changeRequest, validationURL string, headers []string) (*ChangeRequestGate, error) {
+	for _, org := range productionOrganizations {
+		if _, err := path.Match(org, ""); err != nil {
+			return nil, errors.Errorf("invalid production organization pattern %q", org)
+		}
+	}
+
+	if validationURL == "" {
+		return nil, errors.New("a change request validation url has to be set")
+	}
+	tmpl, err := template.New("change-request-url").Option("missingkey=error").Parse(validationURL)
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not parse the change request validation url")
+	}
+
+	header := http.Header{}
+	for _, h := range headers {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok {
+			return nil, errors.Errorf(`the change request header %q is not in the format "Name: value"`, h)
+		}
+		header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	return &ChangeRequestGate{
+		ProductionOrganizations: productionOrganizations,
+		ChangeRequest:           changeRequest,
+		validationURL:           tmpl,
+		headers:                 header,
+	}, nil
+}
+
+// productionRepositories returns the repositories that belong to a production organization, or any of its subgroups
+func (g *ChangeRequestGate) productionRepositories(repoNames []string) []string {
+	var ret []string
+	for _, repoName := range repoNames {
+		if g.isProduction(repoName) {
+			ret = append(ret, repoName)
+		}
+	}
+	return ret
+}
+
+// isProduction checks if the organization of a repository, or any organization it's a subgroup of, like "prod" and
+// "prod/sub" of "prod/sub/repo", matches a production organization pattern. Wildcards don't match across a "/"
+func (g *ChangeRequestGate) isProduction(repoName string) bool {
+	segments := strings.Split(repoName, "/")
+	for i := 1; i < len(segments); i++ {
+		org := strings.Join(segments[:i], "/")
+		for _, pattern := range g.ProductionOrganizations {
+			if ok, _ := path.Match(pattern, org); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// verify makes sure that the change request is valid, if any of the repositories belongs to a production organization.
+// The change request is logged together with the action and the production repositories it's used for
+func (g *ChangeRequestGate) verify(ctx context.Context, action string, repoNames []string) error {
+	if g == nil {
+		return nil
+	}
+
+	prodRepos := g.productionRepositories(repoNames)
+	if len(prodRepos) == 0 {
+		return nil
+	}
+
+	if g.ChangeRequest == "" {
+		return errors.Errorf("a change request is required to %s in production organizations, like %s", action, prodRepos[0])
+	}
+
+	if err := g.validate(ctx); err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"changeRequest": g.ChangeRequest,
+		"action":        action,
+		"repositories":  strings.Join(prodRepos, ","),
+	}).Infof("Change request %s approves to %s in %d production repositories", g.ChangeRequest, action, len(prodRepos))
+	return nil
+}
+
+func (g *ChangeRequestGate) validate(ctx context.Context) error {
+	buf := &bytes.Buffer{}
+	err := g.validationURL.Execute(buf, struct{ ChangeRequest string }{
+		ChangeRequest: url.PathEscape(g.ChangeRequest),
+	})
+	if err != nil {
+		return errors.WithMessage(err, "could not create the change request validation url")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, changeRequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, buf.String(), nil)
+	if err != nil {
+		return errors.WithMessage(err, "could not create the change request validation request")
+	}
+	for name, values := range g.headers {
+		req.Header[name] = values
+	}
+
+	client := &http.Client{
+		Transport: internalhttp.NewLoggingRoundTripper(nil),
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.WithMessage(err, "could not validate the change request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("the change request %s is not valid, the validation responded with status code %d", g.ChangeRequest, resp.StatusCode)
+	}
+	return nil
+}
+
+func repositoryNames(repos []scm.Repository) []string {
+	names := make([]string, len(repos))
+	for i, repo := range repos {
+		names[i] = repo.FullName()
+	}
+	return names
+}
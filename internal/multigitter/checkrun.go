@@ -0,0 +1,72 @@
+package multigitter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// checkRunCreator is implemented by platforms that can add check runs to the last commit of a pull request
+type checkRunCreator interface {
+	CreateCheckRun(ctx context.Context, pr scm.PullRequest, checkRun scm.CheckRun) error
+}
+
+// hashScript returns the sha256 hash of the script file followed by its arguments, which identifies the exact changes
+// that were made, as long as the script does not depend on anything else
+func hashScript(scriptPath string, arguments []string) (string, error) {
+	file, err := os.Open(scriptPath)
+	if err != nil {
+		return "", errors.Wrap(err, "could not read the script to hash it")
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", errors.Wrap(err, "could not read the script to hash it")
+	}
+	for _, argument := range arguments {
+		fmt.Fprintf(hash, "\x00%s", argument)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// checkRun returns the check run that marks the pull requests of the run
+func (r *Runner) checkRun() scm.CheckRun {
+	campaign := r.Campaign
+	if campaign == "" {
+		campaign = r.FeatureBranch
+	}
+	command := strings.Join(append([]string{r.ScriptPath}, r.Arguments...), " ")
+
+	return scm.CheckRun{
+		Name:  r.CheckRunName,
+		Title: "Changed by multi-gitter",
+		Summary: fmt.Sprintf("**Campaign:** %s\n**Branch:** %s\n**Script:** `%s`\n**Script hash:** sha256:%s\n",
+			campaign, r.FeatureBranch, command, r.scriptHash),
+	}
+}
+
+// createCheckRun marks the pushed commit of the pull request with a check run, which other automation can rely on.
+// The commit is taken from the clone, since the platform might not yet know about the push
+func (r *Runner) createCheckRun(ctx context.Context, log log.FieldLogger, pr scm.PullRequest, sourceController Git) error {
+	checkRun := r.checkRun()
+	sha, err := sourceController.HeadCommit()
+	if err != nil {
+		return errors.Wrap(err, "could not get the pushed commit")
+	}
+	checkRun.SHA = sha
+
+	if err := r.VersionController.(checkRunCreator).CreateCheckRun(ctx, pr, checkRun); err != nil {
+		return errors.Wrap(err, "could not create the check run")
+	}
+	log.Infof("Created the check run %q", r.CheckRunName)
+	return nil
+}
@@ -4,22 +4,55 @@ import (
 	"context"
 
 	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
+// forkDeleter is implemented by platforms that can delete the forks pull requests was made from
+type forkDeleter interface {
+	// DeleteFork deletes the fork of the pull request, if it was made from one that is no longer used. Returns if the fork was deleted
+	DeleteFork(ctx context.Context, pr scm.PullRequest) (bool, error)
+}
+
+// branchKeepingCloser is implemented by platforms that can close pull requests without deleting their branches
+type branchKeepingCloser interface {
+	ClosePullRequestKeepBranch(ctx context.Context, pr scm.PullRequest) error
+}
+
 // Closer closes pull requests
 type Closer struct {
 	VersionController VersionController
 
 	FeatureBranch string
+
+	PlanRepositories []PlannedRepository // If set, only the pull requests of these repositories, usually from a saved plan, are closed
+
+	DeleteForks bool // If set, the forks of all closed and merged pull requests are deleted
+	KeepBranch  bool // If set, the branches of the closed pull requests are not deleted
 }
 
 // Close closes pull requests
 func (s Closer) Close(ctx context.Context) error {
+	if s.DeleteForks {
+		if _, ok := s.VersionController.(forkDeleter); !ok {
+			return errors.New("the platform does not support deleting forks")
+		}
+	}
+
+	if s.KeepBranch {
+		if _, ok := s.VersionController.(branchKeepingCloser); !ok {
+			return errors.New("the platform does not support closing pull requests without deleting their branches")
+		}
+	}
+
 	prs, err := s.VersionController.GetPullRequests(ctx, s.FeatureBranch)
 	if err != nil {
 		return err
 	}
+	prs, err = filterPlannedPullRequests(ctx, s.VersionController, prs, s.PlanRepositories)
+	if err != nil {
+		return err
+	}
 
 	openPRs := make([]scm.PullRequest, 0, len(prs))
 	for _, pr := range prs {
@@ -32,11 +65,31 @@ func (s Closer) Close(ctx context.Context) error {
 
 	for _, pr := range openPRs {
 		log.WithField("pr", pr.String()).Infof("Closing")
-		err := s.VersionController.ClosePullRequest(ctx, pr)
+		err := s.closePullRequest(ctx, pr)
 		if err != nil {
 			return err
 		}
 	}
 
+	if s.DeleteForks {
+		// All pull requests are now closed or merged
+		for _, pr := range prs {
+			deleted, err := s.VersionController.(forkDeleter).DeleteFork(ctx, pr)
+			if err != nil {
+				return errors.WithMessagef(err, "could not delete the fork of %s", pr.String())
+			}
+			if deleted {
+				log.WithField("pr", pr.String()).Infof("Deleted fork")
+			}
+		}
+	}
+
 	return nil
 }
+
+func (s Closer) closePullRequest(ctx context.Context, pr scm.PullRequest) error {
+	if s.KeepBranch {
+		return s.VersionController.(branchKeepingCloser).ClosePullRequestKeepBranch(ctx, pr)
+	}
+	return s.VersionController.ClosePullRequest(ctx, pr)
+}
@@ -3,23 +3,114 @@ package multigitter
 import (
 	"context"
 	"fmt"
-	"github.com/lindell/multi-gitter/internal/scm"
 	"os"
 	"os/exec"
+	"path"
+	"runtime"
+	"strings"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
 )
 
+// defaultScriptEnvironment is the environment variables that are passed to scripts by default.
+// Other environment variables has to be explicitly forwarded, to not leak credentials into scripts
+var defaultScriptEnvironment = []string{
+	"PATH",
+	"HOME",
+	"USER",
+	"LOGNAME",
+	"SHELL",
+	"TERM",
+	"LANG",
+	"LC_*",
+	"TZ",
+	"TMPDIR",
+	"TMP",
+	"TEMP",
+
+	// Needed by scripts that use git over ssh, or the network through a proxy
+	"SSH_AUTH_SOCK",
+	"HTTP_PROXY",
+	"HTTPS_PROXY",
+	"NO_PROXY",
+	"http_proxy",
+	"https_proxy",
+	"no_proxy",
+
+	// Windows
+	"SYSTEMROOT",
+	"SYSTEMDRIVE",
+	"WINDIR",
+	"COMSPEC",
+	"PATHEXT",
+	"USERPROFILE",
+	"APPDATA",
+	"LOCALAPPDATA",
+	"PROGRAMDATA",
+	"PROGRAMFILES",
+}
+
+// ScriptEnvironment creates the environment that scripts are run with, based on the environment of this process.
+// Only the default allow-listed variables are included, together with the forwarded values, which can either be
+// in the format NAME=VALUE, or a name (or pattern like "GO*") of variables that should be forwarded
+func ScriptEnvironment(environ []string, forwarded []string) ([]string, error) {
+	patterns := append([]string{}, defaultScriptEnvironment...)
+	var setValues []string
+	for _, f := range forwarded {
+		if strings.Contains(f, "=") {
+			setValues = append(setValues, f)
+			continue
+		}
+		if _, err := path.Match(f, ""); err != nil {
+			return nil, errors.Errorf(`could not parse environment variable pattern "%s"`, f)
+		}
+		patterns = append(patterns, f)
+	}
+
+	env := []string{}
+	for _, e := range environ {
+		name, _, _ := strings.Cut(e, "=")
+		for _, pattern := range patterns {
+			if envNameMatch(pattern, name) {
+				env = append(env, e)
+				break
+			}
+		}
+	}
+
+	return append(env, setValues...), nil
+}
+
+// envNameMatch checks if the name of an environment variable matches a pattern. Names are case-insensitive on Windows,
+// where variables like PATH are commonly named "Path"
+func envNameMatch(pattern, name string) bool {
+	if runtime.GOOS == "windows" {
+		pattern = strings.ToUpper(pattern)
+		name = strings.ToUpper(name)
+	}
+	match, _ := path.Match(pattern, name)
+	return match
+}
+
 func prepareScriptCommand(
 	ctx context.Context,
 	repo scm.Repository,
 	workDir string,
 	scriptPath string,
 	arguments []string,
+	env []string,
 ) (cmd *exec.Cmd) {
+	// If no environment is explicitly defined, the full environment of this process is used
+	if env == nil {
+		env = os.Environ()
+	}
+
 	// Run the command that might or might not change the content of the repo
 	// If the command return a non-zero exit code, abort.
 	cmd = exec.CommandContext(ctx, scriptPath, arguments...)
 	cmd.Dir = workDir
-	cmd.Env = append(os.Environ(),
+	cmd.Env = append(append([]string{}, env...),
 		fmt.Sprintf("REPOSITORY=%s", repo.FullName()),
 	)
 	return cmd
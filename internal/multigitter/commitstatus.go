@@ -0,0 +1,43 @@
+package multigitter
+
+import (
+	"context"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// commitStatusSetter is implemented by platforms that can set statuses, like the ones of CI systems, on commits
+type commitStatusSetter interface {
+	SetCommitStatus(ctx context.Context, pr scm.PullRequest, status scm.CommitStatus) error
+}
+
+// The description of commit statuses, if none is configured
+const defaultStatusDescription = "Changed by multi-gitter"
+
+// setCommitStatus marks the pushed commit of the pull request with a successful status, which status policies of the
+// platform can rely on. The commit is taken from the clone, since the platform might not yet know about the push
+func (r *Runner) setCommitStatus(ctx context.Context, log log.FieldLogger, pr scm.PullRequest, sourceController Git) error {
+	description := r.StatusDescription
+	if description == "" {
+		description = defaultStatusDescription
+	}
+
+	sha, err := sourceController.HeadCommit()
+	if err != nil {
+		return errors.Wrap(err, "could not get the pushed commit")
+	}
+
+	err = r.VersionController.(commitStatusSetter).SetCommitStatus(ctx, pr, scm.CommitStatus{
+		Context:     r.StatusContext,
+		Description: description,
+		TargetURL:   r.StatusURL,
+		SHA:         sha,
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not set the status")
+	}
+	log.Infof("Set the status %q", r.StatusContext)
+	return nil
+}
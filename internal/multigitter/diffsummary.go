@@ -0,0 +1,117 @@
+package multigitter
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxDiffSummaryLines is the maximum number of diff lines included in a diff summary
+const maxDiffSummaryLines = 100
+
+// fileDiff contains the changes made to a single file
+type fileDiff struct {
+	path      string
+	additions int
+	deletions int
+}
+
+// parseDiff parses a unified diff (as outputted by git diff) into per file changes
+func parseDiff(diff string) []fileDiff {
+	var files []fileDiff
+	var current *fileDiff
+	inHunk := false
+
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			files = append(files, fileDiff{path: diffGitPath(line)})
+			current = &files[len(files)-1]
+			inHunk = false
+		case current == nil:
+			continue
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+		case !inHunk:
+			continue
+		case strings.HasPrefix(line, "+"):
+			current.additions++
+		case strings.HasPrefix(line, "-"):
+			current.deletions++
+		}
+	}
+
+	return files
+}
+
+// diffGitPath extracts the path of the changed file from a "diff --git a/path b/path" line. Git quotes paths
+// with special characters, like "b/\303\251.txt", in which case the path is unquoted
+func diffGitPath(line string) string {
+	line = strings.TrimPrefix(line, "diff --git ")
+	if strings.HasSuffix(line, `"`) {
+		if i := strings.LastIndex(line, ` "b/`); i != -1 {
+			if unquoted, err := strconv.Unquote(line[i+1:]); err == nil {
+				return strings.TrimPrefix(unquoted, "b/")
+			}
+		}
+	}
+	if i := strings.LastIndex(line, " b/"); i != -1 {
+		return line[i+len(" b/"):]
+	}
+	return line
+}
+
+// diffSummary creates a markdown summary of a diff, with the changed files grouped by directory
+// and the beginning of the diff itself
+func diffSummary(diff string) string {
+	files := parseDiff(diff)
+	if len(files) == 0 {
+		return ""
+	}
+
+	totalAdditions, totalDeletions := 0, 0
+	dirs := map[string][]fileDiff{}
+	for _, f := range files {
+		totalAdditions += f.additions
+		totalDeletions += f.deletions
+
+		dir := path.Dir(f.path)
+		dirs[dir] = append(dirs[dir], f)
+	}
+
+	dirNames := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		dirNames = append(dirNames, dir)
+	}
+	sort.Strings(dirNames)
+
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "<details>\n<summary>%d files changed, %d insertions(+), %d deletions(-)</summary>\n\n",
+		len(files), totalAdditions, totalDeletions)
+	for _, dir := range dirNames {
+		fmt.Fprintf(sb, "**%s/**\n", dir)
+		for _, f := range dirs[dir] {
+			fmt.Fprintf(sb, "- `%s` (+%d -%d)\n", f.path, f.additions, f.deletions)
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("</details>\n\n")
+
+	diffLines := strings.Split(strings.TrimRight(diff, "\n"), "\n")
+	truncated := len(diffLines) > maxDiffSummaryLines
+	if truncated {
+		diffLines = diffLines[:maxDiffSummaryLines]
+	}
+
+	sb.WriteString("<details>\n<summary>Diff</summary>\n\n```diff\n")
+	sb.WriteString(strings.Join(diffLines, "\n"))
+	sb.WriteString("\n```\n")
+	if truncated {
+		sb.WriteString("\nThe diff was truncated.\n")
+	}
+	sb.WriteString("\n</details>")
+
+	return sb.String()
+}
@@ -0,0 +1,196 @@
+package multigitter
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const megabyte = 1024 * 1024
+
+// diskSpace keeps track of the disk space used by clones, places new clones in the directory with the most free space,
+// and holds back new clones while there is not enough space
+type diskSpace struct {
+	dirs    []string // The directories clones can be placed in
+	budget  int64    // The maximum number of bytes all clones may use together, zero means no limit
+	minFree int64    // The number of bytes that should be free before a new clone is started, zero means no limit
+
+	lock    sync.Mutex
+	changed chan struct{}     // Closed, and replaced, every time a clone is measured or removed
+	active  int               // The number of clones currently on disk
+	used    map[string]int64  // The size of each measured clone
+	largest int64             // The size of the largest clone measured so far, used as an estimate of clones not yet measured
+	roots   map[string]string // The directory of the run in each directory clones are placed in
+}
+
+func newDiskSpace(dirs []string, budget, minFree int64) *diskSpace {
+	if len(dirs) == 0 {
+		dirs = []string{os.TempDir()}
+	}
+	return &diskSpace{
+		dirs:    dirs,
+		budget:  budget,
+		minFree: minFree,
+		changed: make(chan struct{}),
+		used:    map[string]int64{},
+		roots:   map[string]string{},
+	}
+}
+
+// acquire waits until there is room for another clone, and returns the directory of the run it should be placed in.
+// The returned function has to be called once the clone is removed. Waiting stops if the context is cancelled
+func (d *diskSpace) acquire(ctx context.Context) (string, func(dir string), error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	waiting := false
+	for {
+		dir, ok, err := d.place()
+		if err != nil {
+			return "", nil, err
+		}
+		if ok {
+			root, err := d.root(dir)
+			if err != nil {
+				return "", nil, err
+			}
+			d.active++
+			return root, d.release, nil
+		}
+		if !waiting {
+			log.Info("Waiting for other clones to be removed, since disk space is low")
+			waiting = true
+		}
+
+		changed := d.changed
+		d.lock.Unlock()
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			d.lock.Lock()
+			return "", nil, ctx.Err()
+		}
+		d.lock.Lock()
+	}
+}
+
+// place returns the directory with the most free space, and if a new clone can be started in it.
+// Must be called with the lock held
+func (d *diskSpace) place() (string, bool, error) {
+	dir := d.dirs[0]
+	free, freeKnown := freeDiskSpace(dir)
+	for _, otherDir := range d.dirs[1:] {
+		if otherFree, ok := freeDiskSpace(otherDir); ok && (!freeKnown || otherFree > free) {
+			dir, free, freeKnown = otherDir, otherFree, true
+		}
+	}
+
+	if d.budget > 0 && d.active > 0 {
+		// Clones that are not yet measured are expected to be as large as the largest clone so far. Until a clone
+		// has been measured, there is nothing to go by, and only one clone is made at a time
+		unmeasured := int64(d.active - len(d.used))
+		if unmeasured > 0 && d.largest == 0 {
+			return dir, false, nil
+		}
+		if d.usedTotal()+unmeasured*d.largest >= d.budget {
+			return dir, false, nil
+		}
+	}
+
+	if d.minFree > 0 && freeKnown && free < d.minFree {
+		if d.active == 0 {
+			return "", false, errors.Errorf("only %d MB of disk space is free in %s, at least %d MB is required", free/megabyte, dir, d.minFree/megabyte)
+		}
+		return dir, false, nil
+	}
+
+	return dir, true, nil
+}
+
+// root returns the directory of the run in a directory, and creates it the first time. It gets a unique name, so that
+// no one else can create it beforehand, and runs at the same time never use the clones of each other.
+// Must be called with the lock held
+func (d *diskSpace) root(dir string) (string, error) {
+	if root, ok := d.roots[dir]; ok {
+		return root, nil
+	}
+
+	absDir, err := makeAbsolutePath(dir)
+	if err != nil {
+		return "", err
+	}
+	if err := createDirectoryIfDoesntExist(absDir); err != nil {
+		return "", err
+	}
+	root, err := os.MkdirTemp(absDir, "multi-gitter-")
+	if err != nil {
+		return "", err
+	}
+	d.roots[dir] = root
+	return root, nil
+}
+
+// removeRoots removes the directories of the run, unless clones were kept in them
+func (d *diskSpace) removeRoots() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for dir, root := range d.roots {
+		if os.Remove(root) == nil {
+			delete(d.roots, dir)
+		}
+	}
+}
+
+func (d *diskSpace) usedTotal() int64 {
+	var total int64
+	for _, size := range d.used {
+		total += size
+	}
+	return total
+}
+
+// track measures the disk usage of a clone
+func (d *diskSpace) track(dir string) {
+	size := dirSize(dir)
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.used[dir] = size
+	d.largest = max(d.largest, size)
+	log.WithField("dir", dir).Debugf("The clone uses %d MB, all clones use %d MB", size/megabyte, d.usedTotal()/megabyte)
+	d.notify()
+}
+
+func (d *diskSpace) release(dir string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	delete(d.used, dir)
+	d.active--
+	d.notify()
+}
+
+// notify wakes up everyone waiting for room for a clone. Must be called with the lock held
+func (d *diskSpace) notify() {
+	close(d.changed)
+	d.changed = make(chan struct{})
+}
+
+// dirSize returns the number of bytes used by the files in a directory
+func dirSize(dir string) int64 {
+	var size int64
+	_ = filepath.WalkDir(dir, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return nil
+		}
+		if info, err := entry.Info(); err == nil {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package multigitter
+
+import "syscall"
+
+// freeDiskSpace returns the number of bytes available in the file system of a directory
+func freeDiskSpace(dir string) (int64, bool) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return 0, false
+	}
+	return int64(stat.Bavail) * int64(stat.Bsize), true //nolint:unconvert
+}
@@ -0,0 +1,17 @@
+package multigitter
+
+import "golang.org/x/sys/windows"
+
+// freeDiskSpace returns the number of bytes available in the file system of a directory
+func freeDiskSpace(dir string) (int64, bool) {
+	dirPtr, err := windows.UTF16PtrFromString(dir)
+	if err != nil {
+		return 0, false
+	}
+
+	var free uint64
+	if err := windows.GetDiskFreeSpaceEx(dirPtr, &free, nil, nil); err != nil {
+		return 0, false
+	}
+	return int64(free), true
+}
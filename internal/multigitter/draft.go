@@ -0,0 +1,78 @@
+package multigitter
+
+import (
+	"context"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// draftSetter is implemented by version controllers that can change the draft state of existing pull requests
+type draftSetter interface {
+	SetPullRequestDraft(ctx context.Context, pr scm.PullRequest, draft bool) error
+}
+
+// autoMergeEnabler is implemented by version controllers that can merge pull requests once all their requirements are met
+type autoMergeEnabler interface {
+	EnableAutoMerge(ctx context.Context, pr scm.PullRequest) error
+}
+
+// Drafter changes the draft state of pull requests
+type Drafter struct {
+	VersionController VersionController
+
+	FeatureBranch string
+	Draft         bool // If set, pull requests are converted to drafts, otherwise they are marked as ready for review
+	AutoMerge     bool // If set, pull requests that are marked as ready for review are merged once all their requirements are met
+}
+
+// SetDraft changes the draft state of all open pull requests
+func (s Drafter) SetDraft(ctx context.Context) error {
+	setter, ok := s.VersionController.(draftSetter)
+	if !ok {
+		return errors.New("the platform does not support changing the draft state of pull requests")
+	}
+	autoMerger, ok := s.VersionController.(autoMergeEnabler)
+	if s.AutoMerge && !ok {
+		return errors.New("the platform does not support merging pull requests automatically")
+	}
+
+	prs, err := s.VersionController.GetPullRequests(ctx, s.FeatureBranch)
+	if err != nil {
+		return err
+	}
+
+	openPRs := make([]scm.PullRequest, 0, len(prs))
+	for _, pr := range prs {
+		if pr.Status() != scm.PullRequestStatusClosed && pr.Status() != scm.PullRequestStatusMerged {
+			openPRs = append(openPRs, pr)
+		}
+	}
+
+	if s.Draft {
+		log.Infof("Converting %d pull requests to draft", len(openPRs))
+	} else {
+		log.Infof("Marking %d pull requests as ready for review", len(openPRs))
+	}
+
+	for _, pr := range openPRs {
+		log := log.WithField("pr", pr.String())
+
+		log.Infof("Updating draft state")
+		err := setter.SetPullRequestDraft(ctx, pr, s.Draft)
+		if err != nil {
+			log.Errorf("Error occurred while updating draft state: %s", err.Error())
+			continue
+		}
+
+		if s.AutoMerge {
+			log.Infof("Enabling auto-merge")
+			if err := autoMerger.EnableAutoMerge(ctx, pr); err != nil {
+				log.Errorf("Error occurred while enabling auto-merge: %s", err.Error())
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package multigitter
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	internalhttp "github.com/lindell/multi-gitter/internal/http"
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+)
+
+const (
+	eventTypeRepositorySucceeded = "com.github.lindell.multi-gitter.repository.succeeded"
+	eventTypeRepositoryFailed    = "com.github.lindell.multi-gitter.repository.failed"
+	eventTypeRepositorySkipped   = "com.github.lindell.multi-gitter.repository.skipped"
+
+	eventSource      = "multi-gitter/run"
+	eventTimeout     = 10 * time.Second
+	eventContentType = "application/cloudevents+json"
+)
+
+// cloudEvent is a CloudEvents 1.0 event in the structured json format
+type cloudEvent struct {
+	SpecVersion     string            `json:"specversion"`
+	ID              string            `json:"id"`
+	Source          string            `json:"source"`
+	Type            string            `json:"type"`
+	Subject         string            `json:"subject"`
+	Time            time.Time         `json:"time"`
+	DataContentType string            `json:"datacontenttype"`
+	Data            repositoryOutcome `json:"data"`
+}
+
+// repositoryOutcome is the data of an event describing the outcome of a run on a single repository
+type repositoryOutcome struct {
+	Repository    string                `json:"repository"`
+	FeatureBranch string                `json:"featureBranch,omitempty"`
+	Error         string                `json:"error,omitempty"`
+	Reason        string                `json:"reason,omitempty"` // Why the repository was skipped, like that the script made no changes
+	PullRequest   *pullRequestReference `json:"pullRequest,omitempty"`
+	ChangeRequest string                `json:"changeRequest,omitempty"`
+}
+
+type pullRequestReference struct {
+	Name   string `json:"name"`
+	URL    string `json:"url,omitempty"`
+	Status string `json:"status"`
+}
+
+// sendRepositoryEvent sends a CloudEvent describing the outcome of the run on a repository to the event webhook
+func (r *Runner) sendRepositoryEvent(ctx context.Context, repo scm.Repository, pr scm.PullRequest, runErr error) error {
+	id, err := eventID()
+	if err != nil {
+		return err
+	}
+
+	event := cloudEvent{
+		SpecVersion:     "1.0",
+		ID:              id,
+		Source:          eventSource,
+		Type:            eventTypeRepositorySucceeded,
+		Subject:         repo.FullName(),
+		Time:            time.Now().UTC(),
+		DataContentType: "application/json",
+		Data: repositoryOutcome{
+			Repository:    repo.FullName(),
+			FeatureBranch: r.FeatureBranch,
+		},
+	}
+	if r.ChangeRequestGate != nil && len(r.ChangeRequestGate.productionRepositories([]string{repo.FullName()})) > 0 {
+		event.Data.ChangeRequest = r.ChangeRequestGate.ChangeRequest
+	}
+	if isFailure(runErr) {
+		event.Type = eventTypeRepositoryFailed
+		event.Data.Error = runErr.Error()
+	} else if runErr != nil {
+		event.Type = eventTypeRepositorySkipped
+		event.Data.Reason = runErr.Error()
+	}
+	if pr != nil {
+		ref := &pullRequestReference{
+			Name:   pr.String(),
+			Status: pr.Status().String(),
+		}
+		if urler, ok := pr.(urler); ok {
+			ref.URL = urler.URL()
+		}
+		event.Data.PullRequest = ref
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	// The event should be sent even if the run has been canceled
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), eventTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.EventWebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", eventContentType)
+
+	client := &http.Client{
+		Transport: internalhttp.NewLoggingRoundTripper(nil),
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("event webhook responded with status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func eventID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,229 @@
+package multigitter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/lindell/multi-gitter/internal/multigitter/terminal"
+	"github.com/pkg/errors"
+)
+
+// StatusFormatter writes the statuses of pull requests in a specific format
+type StatusFormatter interface {
+	Format(w io.Writer, statuses []PullRequestStatus) error
+}
+
+// ParseStatusFormatter parses the name of a built-in format, like "json", or "gotemplate=FILE" for a Go template in a file
+func ParseStatusFormatter(format string) (StatusFormatter, error) {
+	if templatePath, ok := strings.CutPrefix(format, "gotemplate="); ok {
+		return NewTemplateFormatter(templatePath)
+	}
+
+	switch format {
+	case "text":
+		return TextFormatter{}, nil
+	case "json":
+		return JSONFormatter{}, nil
+	case "csv":
+		return CSVFormatter{}, nil
+	case "markdown":
+		return MarkdownFormatter{}, nil
+	}
+	return nil, errors.Errorf(`unknown output format %q, available formats are text, json, csv, markdown and gotemplate=FILE`, format)
+}
+
+// TextFormatter writes one line with the status of each pull request, and the state of its checks if known. If any pull
+// request belongs to a team, the pull requests are grouped by team, with pull requests without a team last
+type TextFormatter struct{}
+
+// Format writes the statuses as text
+func (TextFormatter) Format(w io.Writer, statuses []PullRequestStatus) error {
+	grouped := false
+	for _, status := range statuses {
+		grouped = grouped || status.Team != ""
+	}
+	if !grouped {
+		for _, status := range statuses {
+			writeTextStatus(w, status, "")
+		}
+		return nil
+	}
+
+	byTeam := map[string][]PullRequestStatus{}
+	for _, status := range statuses {
+		byTeam[status.Team] = append(byTeam[status.Team], status)
+	}
+
+	for _, team := range sortedTeams(byTeam) {
+		if team == "" {
+			fmt.Fprintln(w, "No team:")
+		} else {
+			fmt.Fprintf(w, "%s:\n", team)
+		}
+		for _, status := range byTeam[team] {
+			writeTextStatus(w, status, "  ")
+		}
+	}
+	return nil
+}
+
+func writeTextStatus(w io.Writer, status PullRequestStatus, indent string) {
+	name := status.Name
+	if status.URL != "" {
+		name = terminal.Link(status.Name, status.URL)
+	}
+	if status.Checks != "" {
+		fmt.Fprintf(w, "%s%s: %s (checks: %s)\n", indent, name, status.status, status.checks())
+		return
+	}
+	fmt.Fprintf(w, "%s%s: %s\n", indent, name, status.status)
+}
+
+// sortedTeams returns the teams in alphabetical order, with the empty team, if any, last
+func sortedTeams(byTeam map[string][]PullRequestStatus) []string {
+	teams := make([]string, 0, len(byTeam))
+	for team := range byTeam {
+		if team != "" {
+			teams = append(teams, team)
+		}
+	}
+	sort.Strings(teams)
+	if _, ok := byTeam[""]; ok {
+		teams = append(teams, "")
+	}
+	return teams
+}
+
+// JSONFormatter writes the statuses as a json list
+type JSONFormatter struct{}
+
+// Format writes the statuses as json
+func (JSONFormatter) Format(w io.Writer, statuses []PullRequestStatus) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(statuses)
+}
+
+// CSVFormatter writes the statuses as csv, with a header row. Reviews are written as "reviewer:state" separated by spaces
+type CSVFormatter struct{}
+
+// Format writes the statuses as csv
+func (CSVFormatter) Format(w io.Writer, statuses []PullRequestStatus) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write([]string{
+		"platform", "repository", "name", "team", "id", "number", "url", "state", "checks", "reviews", "createdAt", "updatedAt",
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		number := ""
+		if status.Number != 0 {
+			number = strconv.Itoa(status.Number)
+		}
+		err := writer.Write([]string{
+			status.Platform,
+			status.Repository,
+			status.Name,
+			status.Team,
+			status.ID,
+			number,
+			status.URL,
+			status.State,
+			status.checks(),
+			status.reviews(),
+			formatOptionalTime(status.CreatedAt),
+			formatOptionalTime(status.UpdatedAt),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// MarkdownFormatter writes the statuses as a markdown table
+type MarkdownFormatter struct{}
+
+// Format writes the statuses as markdown
+func (MarkdownFormatter) Format(w io.Writer, statuses []PullRequestStatus) error {
+	fmt.Fprintln(w, "| Pull request | Team | State | Checks | Reviews |")
+	fmt.Fprintln(w, "| --- | --- | --- | --- | --- |")
+	for _, status := range statuses {
+		name := escapeMarkdownCell(status.Name)
+		if status.URL != "" {
+			name = fmt.Sprintf("[%s](%s)", name, status.URL)
+		}
+		fmt.Fprintf(w, "| %s | %s | %s | %s | %s |\n",
+			name,
+			escapeMarkdownCell(status.Team),
+			status.State,
+			escapeMarkdownCell(status.checks()),
+			escapeMarkdownCell(status.reviews()),
+		)
+	}
+	return nil
+}
+
+func escapeMarkdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// TemplateFormatter writes the statuses with a Go template, where the list of statuses is the data
+type TemplateFormatter struct {
+	Template *template.Template
+}
+
+// NewTemplateFormatter creates a formatter with the Go template in a file
+func NewTemplateFormatter(templatePath string) (TemplateFormatter, error) {
+	data, err := os.ReadFile(templatePath)
+	if err != nil {
+		return TemplateFormatter{}, errors.Wrap(err, "could not read the output template")
+	}
+	tmpl, err := template.New(templatePath).Parse(string(data))
+	if err != nil {
+		return TemplateFormatter{}, errors.WithMessage(err, "could not parse the output template")
+	}
+	return TemplateFormatter{Template: tmpl}, nil
+}
+
+// Format writes the statuses with the template
+func (f TemplateFormatter) Format(w io.Writer, statuses []PullRequestStatus) error {
+	return f.Template.Execute(w, statuses)
+}
+
+// checks returns the combined state of the checks, followed by the checks that have not passed, if any, to tell why a
+// pull request is pending or failing
+func (status PullRequestStatus) checks() string {
+	if len(status.UnsuccessfulChecks) == 0 {
+		return status.Checks
+	}
+	return fmt.Sprintf("%s (%s)", status.Checks, strings.Join(status.UnsuccessfulChecks, ", "))
+}
+
+// reviews returns the reviews in the format "reviewer:state", separated by spaces
+func (status PullRequestStatus) reviews() string {
+	reviews := make([]string, len(status.Reviews))
+	for i, review := range status.Reviews {
+		reviews[i] = review.Reviewer + ":" + review.State
+	}
+	return strings.Join(reviews, " ")
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,64 @@
+package multigitter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// maxIssuePatchLength is the maximum length of the patch included in a fallback issue,
+// to stay within the size limits of issue bodies
+const maxIssuePatchLength = 50000
+
+// createFallbackIssue creates an issue describing the changes on a repository where no pull request could be created,
+// with the patch included so that the changes can be applied manually
+func (r *Runner) createFallbackIssue(ctx context.Context, log log.FieldLogger, repo scm.Repository, sourceController Git, baseBranch string, prErr error) error {
+	log.Infof("Could not create pull request, creating an issue instead: %s", prErr)
+
+	newPR, err := r.newPullRequest(repo, sourceController, baseBranch)
+	if err != nil {
+		return err
+	}
+
+	patch, err := sourceController.Diff()
+	if err != nil {
+		return errors.Wrap(err, "could not get patch for issue")
+	}
+
+	url, err := r.VersionController.(issueUpserter).UpsertIssue(ctx, repo.FullName(), newPR.Title, fallbackIssueBody(newPR.Body, patch, prErr))
+	if err != nil {
+		return errors.Wrapf(err, "could not create pull request (%s) or fallback issue", prErr)
+	}
+
+	log.Infof("Created issue %s", url)
+	return errIssueCreated
+}
+
+func fallbackIssueBody(prBody string, patch string, prErr error) string {
+	sb := &strings.Builder{}
+	if prBody != "" {
+		sb.WriteString(prBody)
+		sb.WriteString("\n\n")
+	}
+
+	fmt.Fprintf(sb, "A pull request with these changes could not be created (%s). The changes can be applied manually with `git apply`.\n\n", prErr)
+
+	truncated := len(patch) > maxIssuePatchLength
+	if truncated {
+		patch = patch[:maxIssuePatchLength]
+	}
+
+	sb.WriteString("<details>\n<summary>Patch</summary>\n\n```diff\n")
+	sb.WriteString(strings.TrimRight(patch, "\n"))
+	sb.WriteString("\n```\n")
+	if truncated {
+		sb.WriteString("\nThe patch was truncated.\n")
+	}
+	sb.WriteString("\n</details>")
+
+	return sb.String()
+}
@@ -0,0 +1,189 @@
+package multigitter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// Jira is the parts of Jira that are used to track the changes of a run as issues
+type Jira interface {
+	// FindIssue finds the issue with the label, an empty string is returned if there is none
+	FindIssue(ctx context.Context, label string) (string, error)
+	// CreateIssue creates an issue with the label, and returns its key
+	CreateIssue(ctx context.Context, summary, description, label string) (string, error)
+	// TransitionIssue moves an issue with the transition that has the name, like "Done"
+	TransitionIssue(ctx context.Context, key string, transition string) error
+	// IssueURL returns the url of an issue
+	IssueURL(key string) string
+}
+
+// jiraLabel is the label of the issue that tracks all changes made with the feature branch
+func jiraLabel(featureBranch string) string {
+	return fmt.Sprintf("multi-gitter:%s", featureBranch)
+}
+
+// jiraRepositoryLabel is the label of the issue that tracks the changes made with the feature branch in one repository
+func jiraRepositoryLabel(featureBranch string, repoName string) string {
+	return fmt.Sprintf("multi-gitter:%s:%s", featureBranch, repoName)
+}
+
+// jiraIssues keeps track of the issues used in a run, by repository name
+type jiraIssues struct {
+	lock sync.RWMutex
+	keys map[string]string
+}
+
+func (ji *jiraIssues) set(repoName, key string) {
+	ji.lock.Lock()
+	defer ji.lock.Unlock()
+	if ji.keys == nil {
+		ji.keys = map[string]string{}
+	}
+	ji.keys[repoName] = key
+}
+
+func (ji *jiraIssues) get(repoName string) string {
+	if ji == nil {
+		return ""
+	}
+	ji.lock.RLock()
+	defer ji.lock.RUnlock()
+	return ji.keys[repoName]
+}
+
+// findOrCreateJiraIssue finds the issue with the label, or creates it if it does not exist. No issue is created on dry runs
+func (r *Runner) findOrCreateJiraIssue(ctx context.Context, log log.FieldLogger, summary, label string) (string, error) {
+	key, err := r.Jira.FindIssue(ctx, label)
+	if err != nil {
+		return "", err
+	}
+	if key != "" || r.DryRun {
+		return key, nil
+	}
+
+	key, err = r.Jira.CreateIssue(ctx, summary, r.PullRequestBody, label)
+	if err != nil {
+		return "", err
+	}
+	log.Infof("Created the Jira issue %s", key)
+	return key, nil
+}
+
+// resolveJiraIssue finds, or creates, the issue that tracks the whole run
+func (r *Runner) resolveJiraIssue(ctx context.Context) error {
+	if r.Jira == nil || r.JiraIssuePerRepository {
+		return nil
+	}
+
+	if r.JiraIssue == "" {
+		key, err := r.findOrCreateJiraIssue(ctx, log.StandardLogger(), r.PullRequestTitle, jiraLabel(r.FeatureBranch))
+		if err != nil {
+			return errors.WithMessage(err, "could not get the Jira issue of the run")
+		}
+		r.JiraIssue = key
+	}
+	return nil
+}
+
+// resolveRepositoryJiraIssue finds, or creates, the issue that tracks the changes in a repository
+func (r *Runner) resolveRepositoryJiraIssue(ctx context.Context, log log.FieldLogger, repo scm.Repository) error {
+	if r.Jira == nil || !r.JiraIssuePerRepository {
+		return nil
+	}
+
+	summary := fmt.Sprintf("%s in %s", r.PullRequestTitle, repo.FullName())
+	key, err := r.findOrCreateJiraIssue(ctx, log, summary, jiraRepositoryLabel(r.FeatureBranch, repo.FullName()))
+	if err != nil {
+		return errors.WithMessage(err, "could not get the Jira issue of the repository")
+	}
+	r.jiraIssues.set(repo.FullName(), key)
+	return nil
+}
+
+// jiraIssue returns the key of the issue that tracks the changes in the repository, if any
+func (r *Runner) jiraIssue(repo scm.Repository) string {
+	if r.JiraIssuePerRepository {
+		return r.jiraIssues.get(repo.FullName())
+	}
+	return r.JiraIssue
+}
+
+// commitMessage returns the commit message of a repository, with the Jira issue referenced first so that it's linked
+func (r *Runner) commitMessage(repo scm.Repository) string {
+	if key := r.jiraIssue(repo); key != "" {
+		return key + " " + r.CommitMessage
+	}
+	return r.CommitMessage
+}
+
+// transitionJiraIssue transitions the issue of the whole run, once all pull requests are merged. It's only done if
+// any pull request was merged since mergedBefore was counted, so that the issue is transitioned once
+func (s Merger) transitionJiraIssue(ctx context.Context, mergedBefore int) error {
+	if s.Jira == nil || s.JiraTransition == "" || s.JiraIssuePerRepository {
+		return nil
+	}
+
+	prs, err := s.VersionController.GetPullRequests(ctx, s.FeatureBranch)
+	if err != nil {
+		return err
+	}
+	if countMerged(prs) == mergedBefore {
+		return nil
+	}
+	for _, pr := range prs {
+		if pr.Status() != scm.PullRequestStatusMerged && pr.Status() != scm.PullRequestStatusClosed {
+			log.Info("Not transitioning the Jira issue since not all pull requests are merged")
+			return nil
+		}
+	}
+
+	key := s.JiraIssue
+	if key == "" {
+		key, err = s.Jira.FindIssue(ctx, jiraLabel(s.FeatureBranch))
+		if err != nil {
+			return err
+		}
+		if key == "" {
+			log.Warn("Could not find the Jira issue of the run")
+			return nil
+		}
+	}
+
+	log.Infof("Transitioning the Jira issue %s", key)
+	return s.Jira.TransitionIssue(ctx, key, s.JiraTransition)
+}
+
+// transitionRepositoryJiraIssue transitions the issue of the repository of a merged pull request
+func (s Merger) transitionRepositoryJiraIssue(ctx context.Context, log log.FieldLogger, pr scm.PullRequest) error {
+	if s.Jira == nil || s.JiraTransition == "" || !s.JiraIssuePerRepository {
+		return nil
+	}
+
+	repoName := pullRequestRepositoryName(pr)
+	key, err := s.Jira.FindIssue(ctx, jiraRepositoryLabel(s.FeatureBranch, repoName))
+	if err != nil {
+		return err
+	}
+	if key == "" {
+		log.Warn("Could not find the Jira issue of the repository")
+		return nil
+	}
+
+	log.Infof("Transitioning the Jira issue %s", key)
+	return s.Jira.TransitionIssue(ctx, key, s.JiraTransition)
+}
+
+func countMerged(prs []scm.PullRequest) int {
+	merged := 0
+	for _, pr := range prs {
+		if pr.Status() == scm.PullRequestStatusMerged {
+			merged++
+		}
+	}
+	return merged
+}
@@ -0,0 +1,50 @@
+package multigitter
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// keptClonesReport keeps track of the clones that were kept since the run failed
+type keptClonesReport struct {
+	dirs map[string]string
+	lock sync.Mutex
+}
+
+func (r *keptClonesReport) add(repoName string, dir string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.dirs == nil {
+		r.dirs = map[string]string{}
+	}
+	r.dirs[repoName] = dir
+}
+
+// info returns a formatted string with the location of all kept clones
+func (r *keptClonesReport) info() string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if len(r.dirs) == 0 {
+		return ""
+	}
+
+	repoNames := make([]string, 0, len(r.dirs))
+	for repoName := range r.dirs {
+		repoNames = append(repoNames, repoName)
+	}
+	sort.Strings(repoNames)
+
+	info := "Kept clones of the failed repositories:\n"
+	for _, repoName := range repoNames {
+		info += fmt.Sprintf("  %s: %s (script output: %s)\n", repoName, r.dirs[repoName], scriptLogPath(r.dirs[repoName]))
+	}
+	return info
+}
+
+// scriptLogPath returns the path of the file the script output is written to, next to the clone since the clone itself is committed
+func scriptLogPath(dir string) string {
+	return dir + ".log"
+}
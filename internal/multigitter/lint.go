@@ -0,0 +1,91 @@
+package multigitter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+)
+
+// pullRequestConstrainer is implemented by platforms that put limits on the values of pull requests
+type pullRequestConstrainer interface {
+	PullRequestConstraints() scm.PullRequestConstraints
+}
+
+// lint verifies, before any repository is changed, that the values of the run would be accepted by the platform
+func (r *Runner) lint(repos []scm.Repository) error {
+	problems := r.branchProblems()
+	for _, repo := range repos {
+		for _, problem := range r.pullRequestProblems(repo) {
+			problems = append(problems, fmt.Sprintf("%s: %s", repo.FullName(), problem))
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.Errorf("the platform would reject the changes:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// branchProblems returns every reason the feature branch would be rejected
+func (r *Runner) branchProblems() []string {
+	if r.SkipPullRequest {
+		return nil
+	}
+
+	problems := []string{}
+	for _, problem := range lintBranchName(r.FeatureBranch) {
+		problems = append(problems, fmt.Sprintf("branch %q: %s", r.FeatureBranch, problem))
+	}
+	return problems
+}
+
+// pullRequestProblems returns every reason the platform would reject the pull request of a repository
+func (r *Runner) pullRequestProblems(repo scm.Repository) []string {
+	constrainer, ok := r.VersionController.(pullRequestConstrainer)
+	if !ok || r.SkipPullRequest || r.PushOnly {
+		return nil
+	}
+	return constrainer.PullRequestConstraints().Lint(r.pullRequestValues(repo, r.baseBranch(repo)))
+}
+
+// lintBranchName returns every reason git would not accept the name as a branch name.
+// See https://git-scm.com/docs/git-check-ref-format
+func lintBranchName(name string) []string {
+	if name == "" {
+		return []string{"the name is empty"}
+	}
+
+	problems := []string{}
+	if name == "HEAD" || name == "@" {
+		problems = append(problems, "the name is reserved")
+	}
+	if strings.HasPrefix(name, "-") {
+		problems = append(problems, `the name starts with "-"`)
+	}
+	if strings.HasPrefix(name, "/") || strings.HasSuffix(name, "/") || strings.Contains(name, "//") {
+		problems = append(problems, "the name contains an empty path component")
+	}
+	if strings.HasSuffix(name, ".") {
+		problems = append(problems, `the name ends with "."`)
+	}
+	for _, sequence := range []string{"..", "@{"} {
+		if strings.Contains(name, sequence) {
+			problems = append(problems, fmt.Sprintf("the name contains %q", sequence))
+		}
+	}
+	if strings.ContainsAny(name, " ~^:?*[\\") {
+		problems = append(problems, `the name contains any of the characters " ~^:?*[\"`)
+	}
+	if strings.ContainsFunc(name, func(r rune) bool { return r < 0x20 || r == 0x7f }) {
+		problems = append(problems, "the name contains control characters")
+	}
+	for _, component := range strings.Split(name, "/") {
+		if strings.HasPrefix(component, ".") || strings.HasSuffix(component, ".lock") {
+			problems = append(problems, fmt.Sprintf(`the path component %q starts with "." or ends with ".lock"`, component))
+		}
+	}
+
+	return problems
+}
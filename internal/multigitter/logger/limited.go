@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// NewLimitedLogger creates a logger, like NewLogger, that only logs the first and last half of limit bytes of the output.
+// Everything in between is left out, so that the output does not have to be kept in memory. The tail is logged when the
+// writer is closed
+func NewLimitedLogger(logger logger, limit int) io.WriteCloser {
+	return &limitedLogger{
+		logger:   logger,
+		headLeft: limit - limit/2,
+		tailSize: limit / 2,
+	}
+}
+
+type limitedLogger struct {
+	logger logger
+	lock   sync.Mutex
+	closed bool
+
+	headLeft int          // The number of bytes that can still be logged directly
+	line     bytes.Buffer // The unfinished line of the head
+
+	tailSize int
+	tail     []byte // The last tailSize bytes written after the head
+	left     int    // The number of bytes written after the head that are not part of the tail
+	lineEnd  bool   // If the last byte that was left out ended a line
+}
+
+func (l *limitedLogger) Write(p []byte) (int, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	written := len(p)
+
+	if l.headLeft > 0 {
+		head := p
+		if len(head) > l.headLeft {
+			head = head[:l.headLeft]
+		}
+		l.headLeft -= len(head)
+		p = p[len(head):]
+
+		l.line.Write(head)
+		for {
+			i := bytes.IndexByte(l.line.Bytes(), '\n')
+			if i < 0 {
+				break
+			}
+			l.logger.Infof("Script output: %s", l.line.Next(i+1))
+		}
+	}
+
+	l.tail = append(l.tail, p...)
+	if overflow := len(l.tail) - l.tailSize; overflow > 0 {
+		l.left += overflow
+		l.lineEnd = l.tail[overflow-1] == '\n'
+		l.tail = append(l.tail[:0], l.tail[overflow:]...)
+	}
+
+	return written, nil
+}
+
+// Close logs what is left of the head and the tail
+func (l *limitedLogger) Close() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+
+	if l.line.Len() > 0 {
+		l.logger.Infof("Script output: %s", l.line.String())
+	}
+
+	tail := l.tail
+	if l.left > 0 {
+		// Start the tail at the beginning of a line, if there is one
+		left := l.left
+		if i := bytes.IndexByte(tail, '\n'); !l.lineEnd && i >= 0 && i < len(tail)-1 {
+			tail = tail[i+1:]
+			left += i + 1
+		}
+		l.logger.Infof("Script output: [%d bytes left out]", left)
+	}
+	for len(tail) > 0 {
+		line := tail
+		if i := bytes.IndexByte(tail, '\n'); i >= 0 {
+			line = tail[:i+1]
+		}
+		tail = tail[len(line):]
+		l.logger.Infof("Script output: %s", line)
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingLogger struct {
+	lines []string
+}
+
+func (l *recordingLogger) Infof(format string, args ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func TestLimitedLogger(t *testing.T) {
+	tests := []struct {
+		name   string
+		limit  int
+		writes []string
+		want   []string
+	}{
+		{
+			name:   "within limit",
+			limit:  100,
+			writes: []string{"first\n", "sec", "ond\n"},
+			want:   []string{"Script output: first\n", "Script output: second\n"},
+		},
+		{
+			name:   "head and tail",
+			limit:  12,
+			writes: []string{"line1\n", "line2\n", "line3\n", "line4\n", "line5\n"},
+			want: []string{
+				"Script output: line1\n",
+				"Script output: [18 bytes left out]",
+				"Script output: line5\n",
+			},
+		},
+		{
+			name:   "tail starting mid line",
+			limit:  10,
+			writes: []string{"line1\n", "line2\n", "ab\n"},
+			want: []string{
+				"Script output: line1",
+				"Script output: [7 bytes left out]",
+				"Script output: ab\n",
+			},
+		},
+		{
+			name:   "unfinished line",
+			limit:  100,
+			writes: []string{"no newline"},
+			want:   []string{"Script output: no newline"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := &recordingLogger{}
+			writer := NewLimitedLogger(logger, tt.limit)
+			for _, w := range tt.writes {
+				n, err := writer.Write([]byte(w))
+				assert.NoError(t, err)
+				assert.Equal(t, len(w), n)
+			}
+			assert.NoError(t, writer.Close())
+			assert.NoError(t, writer.Close())
+			assert.Equal(t, tt.want, logger.lines)
+		})
+	}
+}
@@ -2,8 +2,15 @@ package multigitter
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
 
 	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -12,33 +19,265 @@ type Merger struct {
 	VersionController VersionController
 
 	FeatureBranch string
+
+	PlanRepositories []PlannedRepository // If set, only the pull requests of these repositories, usually from a saved plan, are merged
+
+	// Patterns matching the full names of repositories. Pull requests are merged group by group, and a group is only
+	// merged once all pull requests of the previous groups are merged. Pull requests not in any group are merged last
+	Groups [][]string
+	// If set, pull requests are merged one by one in discovery order, and merging stops at the first one that is not merged
+	Serial bool
+	// If set, pull requests that are pending, like on checks or required policies, are waited for this long before merging
+	Wait time.Duration
+	// If set together with Wait, pull requests that can not be merged, like when an earlier merged pull request
+	// conflicts with them, get their branch updated with the base branch, and are merged again once they are ready
+	RetryConflicts bool
+
+	// If set, this command is run after each merged pull request, and merging is aborted if it fails
+	VerifyPath      string
+	VerifyArguments []string
+
+	Message *MergeMessageTemplate // If set, the message of merge commits is made from these templates
+	Release *ReleaseTemplate      // If set, a release is created on each merged pull request
+
+	Jira                   Jira   // If set together with JiraTransition, the Jira issues of the run are transitioned
+	JiraIssue              string // The Jira issue of the whole run, if not set it's found by its label
+	JiraIssuePerRepository bool   // If set, every repository has its own Jira issue
+	JiraTransition         string // The transition made when the pull requests are merged, like "Done"
+
+	ChangeRequestGate *ChangeRequestGate // If set, a valid change request is required to merge in production organizations
 }
 
 // Merge merges pull requests in an organization
 func (s Merger) Merge(ctx context.Context) error {
-	prs, err := s.VersionController.GetPullRequests(ctx, s.FeatureBranch)
+	if s.Release != nil {
+		if _, ok := s.VersionController.(releaseCreator); !ok {
+			return errors.New("the platform does not support creating releases")
+		}
+	}
+	if s.Message != nil {
+		if _, ok := s.VersionController.(messageMerger); !ok {
+			return errors.New("the platform does not support custom merge messages")
+		}
+	}
+
+	if s.RetryConflicts {
+		if _, ok := s.VersionController.(pullRequestBranchUpdater); !ok {
+			return errors.New("the platform does not support updating the branches of pull requests")
+		}
+	}
+
+	prs, err := s.getPullRequests(ctx)
 	if err != nil {
 		return err
 	}
 
-	successPrs := make([]scm.PullRequest, 0, len(prs))
+	if s.Wait > 0 {
+		if prs, err = s.waitForPending(ctx, prs, s.getPullRequests); err != nil {
+			return err
+		}
+	}
+
+	mergedBefore := countMerged(prs)
+
+	successCount := 0
+	var successRepos []string
 	for _, pr := range prs {
 		if pr.Status() == scm.PullRequestStatusSuccess {
-			successPrs = append(successPrs, pr)
+			successCount++
+			successRepos = append(successRepos, pullRequestRepositoryName(pr))
 		}
 	}
 
-	log.Infof("Merging %d pull requests", len(successPrs))
+	if err := s.ChangeRequestGate.verify(ctx, "merge pull requests", successRepos); err != nil {
+		return err
+	}
+
+	log.Infof("Merging %d pull requests", successCount)
+
+	groups := s.mergeGroups(prs)
+	for i, group := range groups {
+		allMerged, err := s.mergeGroup(ctx, group)
+		if err != nil {
+			return err
+		}
+		if !allMerged && i < len(groups)-1 {
+			return errors.Errorf("not all pull requests of merge group %d could be merged, the remaining groups were not merged", i+1)
+		}
+	}
+
+	if err := s.transitionJiraIssue(ctx, mergedBefore); err != nil {
+		return errors.WithMessage(err, "could not transition the Jira issue")
+	}
+
+	return nil
+}
+
+// getPullRequests gets the pull requests of the feature branch, on the repositories of the plan if one is used
+func (s Merger) getPullRequests(ctx context.Context) ([]scm.PullRequest, error) {
+	prs, err := s.VersionController.GetPullRequests(ctx, s.FeatureBranch)
+	if err != nil {
+		return nil, err
+	}
+	return filterPlannedPullRequests(ctx, s.VersionController, prs, s.PlanRepositories)
+}
+
+// waitForPending waits for pending pull requests to become ready to be merged, or to fail, and returns the pull requests,
+// as refresh gets them, once none is pending, or when the wait is over. Pull requests that are still pending are not merged
+func (s Merger) waitForPending(
+	ctx context.Context,
+	prs []scm.PullRequest,
+	refresh func(ctx context.Context) ([]scm.PullRequest, error),
+) ([]scm.PullRequest, error) {
+	// The statuses are checked often at first, to not wait long for checks that finish quickly
+	deadline := time.Now().Add(s.Wait)
+	interval := time.Second
+	for {
+		pending := 0
+		for _, pr := range prs {
+			if pr.Status() == scm.PullRequestStatusPending {
+				pending++
+			}
+		}
+		if pending == 0 {
+			return prs, nil
+		}
+		if time.Now().Add(interval).After(deadline) {
+			log.Warnf("%d pull requests were still pending after waiting %s", pending, s.Wait)
+			return prs, nil
+		}
+
+		log.Infof("Waiting for %d pending pull requests", pending)
+		select {
+		case <-ctx.Done():
+			return nil, errors.New("stopped waiting for the pending pull requests")
+		case <-time.After(interval):
+		}
+		interval = min(interval*2, maxPollInterval)
+
+		var err error
+		if prs, err = refresh(ctx); err != nil {
+			return nil, err
+		}
+	}
+}
 
-	for _, pr := range successPrs {
+// mergeGroup merges all pull requests in the group that are ready to be merged, returns true if all pull requests are merged
+func (s Merger) mergeGroup(ctx context.Context, prs []scm.PullRequest) (bool, error) {
+	allMerged := true
+	for _, pr := range prs {
 		log := log.WithField("pr", pr.String())
 
+		switch pr.Status() {
+		case scm.PullRequestStatusMerged, scm.PullRequestStatusClosed:
+			continue
+		case scm.PullRequestStatusSuccess:
+		default:
+			log.Infof("Not ready to be merged, status is %s", pr.Status())
+			allMerged = false
+			continue
+		}
+
 		log.Infof("Merging")
-		err := s.VersionController.MergePullRequest(ctx, pr)
+		err := s.mergePullRequest(ctx, pr)
+		if err != nil && s.RetryConflicts {
+			pr, err = s.retryMerge(ctx, pr, err)
+		}
 		if err != nil {
 			log.Errorf("Error occurred while merging: %s", err.Error())
+			allMerged = false
+			continue
+		}
+
+		if s.VerifyPath != "" {
+			log.Infof("Verifying merge")
+			if err := s.verify(ctx, pr); err != nil {
+				return false, err
+			}
+		}
+
+		if s.Release != nil {
+			log.Infof("Creating release")
+			if err := s.createRelease(ctx, pr); err != nil {
+				log.Errorf("Error occurred while creating release: %s", err.Error())
+				allMerged = false
+			}
+		}
+
+		if err := s.transitionRepositoryJiraIssue(ctx, log, pr); err != nil {
+			log.Errorf("Error occurred while transitioning the Jira issue: %s", err.Error())
 		}
 	}
+	return allMerged, nil
+}
+
+// verify runs the verification command for a merged pull request
+func (s Merger) verify(ctx context.Context, pr scm.PullRequest) error {
+	cmd := exec.CommandContext(ctx, s.VerifyPath, s.VerifyArguments...)
+	cmd.Env = append(os.Environ(),
+		fmt.Sprintf("REPOSITORY=%s", pullRequestRepositoryName(pr)),
+		fmt.Sprintf("PULL_REQUEST=%s", pr.String()),
+	)
 
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return errors.Errorf("verification of %s failed, the remaining pull requests were not merged: %s\n%s", pr.String(), err, out)
+	}
 	return nil
 }
+
+// mergeGroups divides the pull requests into the groups they should be merged in
+func (s Merger) mergeGroups(prs []scm.PullRequest) [][]scm.PullRequest {
+	groups := make([][]scm.PullRequest, len(s.Groups)+1)
+	for _, pr := range prs {
+		groups[s.groupIndex(pr)] = append(groups[s.groupIndex(pr)], pr)
+	}
+
+	ret := [][]scm.PullRequest{}
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+
+		if !s.Serial {
+			ret = append(ret, group)
+			continue
+		}
+		for _, pr := range group {
+			ret = append(ret, []scm.PullRequest{pr})
+		}
+	}
+	return ret
+}
+
+// groupIndex returns the index of the first group matching the repository of the pull request, or the index after the last group
+func (s Merger) groupIndex(pr scm.PullRequest) int {
+	repoName := pullRequestRepositoryName(pr)
+	for i, patterns := range s.Groups {
+		for _, pattern := range patterns {
+			if matched, _ := path.Match(pattern, repoName); matched {
+				return i
+			}
+		}
+	}
+	return len(s.Groups)
+}
+
+// repositoryNamer is implemented by pull requests that know the full name of their repository
+type repositoryNamer interface {
+	RepositoryFullName() string
+}
+
+// pullRequestRepositoryName returns the full name of the repository of a pull request. Pull requests that do not know
+// their repository are named "<repository> #<number>" on all platforms
+func pullRequestRepositoryName(pr scm.PullRequest) string {
+	if namer, ok := pr.(repositoryNamer); ok {
+		return namer.RepositoryFullName()
+	}
+
+	name := pr.String()
+	if i := strings.LastIndex(name, " #"); i >= 0 {
+		return name[:i]
+	}
+	return name
+}
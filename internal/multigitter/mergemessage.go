@@ -0,0 +1,70 @@
+package multigitter
+
+import (
+	"context"
+	"text/template"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+)
+
+// messageMerger is implemented by platforms that can merge pull requests with a custom message of the merge commit
+type messageMerger interface {
+	MergePullRequestWithMessage(ctx context.Context, pr scm.PullRequest, message scm.MergeMessage) error
+}
+
+// MergeMessageTemplate contains the templates of the message of the commit made when a pull request is merged
+type MergeMessageTemplate struct {
+	Title *template.Template // If nil, the platform default is used
+	Body  *template.Template // If nil, the platform default is used
+}
+
+// ParseMergeMessageTemplate parses the templates of the title and body of merge commits
+func ParseMergeMessageTemplate(title, body string) (*MergeMessageTemplate, error) {
+	parse := func(field, text string) (*template.Template, error) {
+		if text == "" {
+			return nil, nil
+		}
+		tmpl, err := template.New(field).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "could not parse the merge %s template", field)
+		}
+		return tmpl, nil
+	}
+
+	message := &MergeMessageTemplate{}
+	var err error
+	if message.Title, err = parse("title", title); err != nil {
+		return nil, err
+	}
+	if message.Body, err = parse("message", body); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// newMergeMessage executes the templates for a pull request that is about to be merged
+func (t *MergeMessageTemplate) newMergeMessage(pr scm.PullRequest, branch string) (scm.MergeMessage, error) {
+	var message scm.MergeMessage
+	var err error
+	if message.Title, err = executeMergeTemplate(t.Title, pr, branch); err != nil {
+		return scm.MergeMessage{}, err
+	}
+	if message.Body, err = executeMergeTemplate(t.Body, pr, branch); err != nil {
+		return scm.MergeMessage{}, err
+	}
+	return message, nil
+}
+
+// mergePullRequest merges a pull request, with the configured message of the merge commit if any
+func (s Merger) mergePullRequest(ctx context.Context, pr scm.PullRequest) error {
+	if s.Message == nil {
+		return s.VersionController.MergePullRequest(ctx, pr)
+	}
+
+	message, err := s.Message.newMergeMessage(pr, s.FeatureBranch)
+	if err != nil {
+		return errors.WithMessage(err, "could not create the merge message from the templates")
+	}
+	return s.VersionController.(messageMerger).MergePullRequestWithMessage(ctx, pr, message)
+}
@@ -0,0 +1,78 @@
+package multigitter
+
+import (
+	"context"
+	"time"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// The number of times the branch of a pull request is updated, to retry merging it
+const maxMergeRetries = 3
+
+// The time the platform has to start the checks of an updated branch, before the pull request is waited for
+const branchUpdateSettleTime = time.Second
+
+// pullRequestBranchUpdater is implemented by platforms that can update the branch of a pull request with its base branch
+type pullRequestBranchUpdater interface {
+	UpdatePullRequestBranch(ctx context.Context, pr scm.PullRequest) error
+}
+
+// retryMerge updates the branch of a pull request that could not be merged, like when an earlier merged pull request
+// conflicts with it, with its base branch, waits for the pull request to be ready again, and merges it again.
+// Returns the pull request as it was last seen, and the error of the last merge if it could still not be merged
+func (s Merger) retryMerge(ctx context.Context, pr scm.PullRequest, mergeErr error) (scm.PullRequest, error) {
+	log := log.WithField("pr", pr.String())
+	updater := s.VersionController.(pullRequestBranchUpdater)
+
+	for i := 0; i < maxMergeRetries; i++ {
+		log.Warnf("Could not merge, updating the branch with the base branch to retry: %s", mergeErr)
+		if err := updater.UpdatePullRequestBranch(ctx, pr); err != nil {
+			return pr, errors.WithMessagef(mergeErr, "the branch could not be updated to retry the merge (%s)", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return pr, ctx.Err()
+		case <-time.After(branchUpdateSettleTime):
+		}
+
+		refresh := s.pullRequestRefresher(pr)
+		prs, err := refresh(ctx)
+		if err != nil {
+			return pr, err
+		}
+		if prs, err = s.waitForPending(ctx, prs, refresh); err != nil {
+			return pr, err
+		}
+		pr = prs[0]
+
+		if pr.Status() != scm.PullRequestStatusSuccess {
+			return pr, errors.Errorf("the status is %s after the branch was updated", pr.Status())
+		}
+
+		log.Infof("Retrying merge")
+		if mergeErr = s.mergePullRequest(ctx, pr); mergeErr == nil {
+			return pr, nil
+		}
+	}
+	return pr, mergeErr
+}
+
+// pullRequestRefresher returns a function that gets the current state of a single pull request
+func (s Merger) pullRequestRefresher(pr scm.PullRequest) func(ctx context.Context) ([]scm.PullRequest, error) {
+	return func(ctx context.Context) ([]scm.PullRequest, error) {
+		prs, err := s.getPullRequests(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range prs {
+			if p.String() == pr.String() {
+				return []scm.PullRequest{p}, nil
+			}
+		}
+		return nil, errors.Errorf("could not find the pull request %s", pr.String())
+	}
+}
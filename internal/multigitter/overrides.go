@@ -0,0 +1,85 @@
+package multigitter
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// PullRequestOverride contains values that replace the default pull request values for a specific repository
+type PullRequestOverride struct {
+	Title  string   `yaml:"title"`
+	Body   string   `yaml:"-"`
+	Labels []string `yaml:"labels"`
+}
+
+const frontMatterDelimiter = "---"
+
+// ReadPullRequestOverrides reads all pull request overrides from a directory.
+// Each markdown file in the directory is keyed by the full name of the repository,
+// so the override for "owner/repo" is read from "<dir>/owner/repo.md".
+// The content of the file is used as the body of the pull request, and an optional
+// yaml front matter can be used to override the title and labels.
+func ReadPullRequestOverrides(dir string) (map[string]PullRequestOverride, error) {
+	overrides := map[string]PullRequestOverride{}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".md" {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		repoName := filepath.ToSlash(strings.TrimSuffix(relPath, ".md"))
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		override, err := parsePullRequestOverride(data)
+		if err != nil {
+			return errors.WithMessagef(err, "could not parse pull request override for %s", repoName)
+		}
+		overrides[repoName] = override
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not read pull request overrides")
+	}
+
+	return overrides, nil
+}
+
+func parsePullRequestOverride(data []byte) (PullRequestOverride, error) {
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+
+	var override PullRequestOverride
+	if !bytes.HasPrefix(data, []byte(frontMatterDelimiter+"\n")) {
+		override.Body = strings.TrimSpace(string(data))
+		return override, nil
+	}
+
+	split := strings.SplitN(string(data[len(frontMatterDelimiter)+1:]), "\n"+frontMatterDelimiter, 2)
+	if len(split) != 2 {
+		return PullRequestOverride{}, errors.New("front matter was never closed")
+	}
+
+	if err := yaml.Unmarshal([]byte(split[0]), &override); err != nil {
+		return PullRequestOverride{}, err
+	}
+	override.Body = strings.TrimSpace(split[1])
+
+	return override, nil
+}
@@ -0,0 +1,92 @@
+package multigitter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/lindell/multi-gitter/internal/scm"
+	log "github.com/sirupsen/logrus"
+)
+
+// The number of files without an owner among the reviewers that are listed in the warning
+const maxListedUnownedFiles = 5
+
+// unownedFile is a changed file that none of the reviewers own
+type unownedFile struct {
+	path   string
+	owners string
+}
+
+// unownedFiles returns the files that have owners in the CODEOWNERS rules, but where none of the owners are reviewers.
+// Reviewers are usernames, and team reviewers are in the format "org/team", like in CODEOWNERS but without the "@".
+// Team reviewers without an organization, which is how platforms like GitHub name them, belong to the repository owner
+func unownedFiles(rules []teamRule, files []string, reviewers []string, teamReviewers []string, repoOwner string) []unownedFile {
+	reviewerOwners := map[string]struct{}{}
+	for _, reviewer := range reviewers {
+		reviewerOwners["@"+strings.TrimPrefix(strings.ToLower(reviewer), "@")] = struct{}{}
+	}
+	for _, team := range teamReviewers {
+		team = strings.TrimPrefix(strings.ToLower(team), "@")
+		if !strings.Contains(team, "/") {
+			team = strings.ToLower(repoOwner) + "/" + team
+		}
+		reviewerOwners["@"+team] = struct{}{}
+	}
+
+	unowned := []unownedFile{}
+	for _, file := range files {
+		owners := fileOwners(rules, file)
+		if len(owners) == 0 {
+			continue
+		}
+
+		owned := false
+		for _, owner := range owners {
+			_, ok := reviewerOwners[strings.ToLower(owner)]
+			owned = owned || ok
+		}
+		if !owned {
+			unowned = append(unowned, unownedFile{path: file, owners: strings.Join(owners, " ")})
+		}
+	}
+	return unowned
+}
+
+// fileOwners returns the owners of a file, from the last matching CODEOWNERS rule
+func fileOwners(rules []teamRule, file string) []string {
+	pathParts := strings.Split(file, "/")
+	for i := len(rules) - 1; i >= 0; i-- {
+		pattern := gitignore.ParsePattern(rules[i].pattern, nil)
+		if pattern.Match(pathParts, false) == gitignore.Exclude {
+			return strings.Fields(rules[i].team)
+		}
+	}
+	return nil
+}
+
+// warnUnownedFiles warns if none of the reviewers own some of the changed files, since the pull request would then
+// need approval from someone who was never asked to review it
+func (r *Runner) warnUnownedFiles(log log.FieldLogger, repo scm.Repository, rules []teamRule, sourceController Git) {
+	files, err := sourceController.ChangedFiles()
+	if err != nil {
+		log.Warnf("Could not get the changed files to check their owners: %s", err)
+		return
+	}
+
+	repoOwner, _, _ := strings.Cut(repo.FullName(), "/")
+	unowned := unownedFiles(rules, files, r.Reviewers, r.TeamReviewers, repoOwner)
+	if len(unowned) == 0 {
+		return
+	}
+
+	listed := make([]string, 0, maxListedUnownedFiles)
+	for i, file := range unowned {
+		if i == maxListedUnownedFiles {
+			listed = append(listed, fmt.Sprintf("and %d more", len(unowned)-maxListedUnownedFiles))
+			break
+		}
+		listed = append(listed, fmt.Sprintf("%s (owned by %s)", file.path, file.owners))
+	}
+	log.Warnf("None of the reviewers own %d of the changed files: %s", len(unowned), strings.Join(listed, ", "))
+}
@@ -0,0 +1,35 @@
+package multigitter
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// patchSubmitter is implemented by platforms where changes are submitted as emailed patches, instead of pull requests
+type patchSubmitter interface {
+	// PatchSubjectPrefix returns the prefix used in the subject of the patch, used to later find the submitted patch
+	PatchSubjectPrefix(repo scm.Repository, branchName string) string
+}
+
+// writePatch writes the committed changes as a patch to the patch directory, ready to be sent with git send-email
+func (r *Runner) writePatch(log log.FieldLogger, repo scm.Repository, sourceController Git, submitter patchSubmitter) error {
+	patch, err := sourceController.FormatPatch(submitter.PatchSubjectPrefix(repo, r.FeatureBranch))
+	if err != nil {
+		return errors.Wrap(err, "could not create patch")
+	}
+
+	path := filepath.Join(r.PatchDir, filepath.FromSlash(repo.FullName())+".patch")
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return errors.Wrap(err, "could not create patch directory")
+	}
+	if err := os.WriteFile(path, []byte(patch), 0o600); err != nil {
+		return errors.Wrap(err, "could not write patch")
+	}
+
+	log.Infof("Wrote patch to %s", path)
+	return nil
+}
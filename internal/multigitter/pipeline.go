@@ -0,0 +1,61 @@
+package multigitter
+
+import (
+	"context"
+	"time"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// pipelineTriggerer is implemented by platforms that can start the pipeline of a pull request
+type pipelineTriggerer interface {
+	TriggerPipeline(ctx context.Context, pr scm.PullRequest) (scm.Pipeline, error)
+	GetPipeline(ctx context.Context, pipeline scm.Pipeline) (scm.Pipeline, error)
+}
+
+// The longest time between checks of the status of a pipeline, or of pull requests, that is waited for
+const maxPollInterval = 30 * time.Second
+
+// triggerPipeline starts the pipeline of the pull request and, if configured to, waits for it to finish
+func (r *Runner) triggerPipeline(ctx context.Context, log log.FieldLogger, pr scm.PullRequest) error {
+	triggerer := r.VersionController.(pipelineTriggerer)
+
+	pipeline, err := triggerer.TriggerPipeline(ctx, pr)
+	if err != nil {
+		return errors.Wrap(err, "could not trigger the pipeline")
+	}
+	log.Infof("Triggered the pipeline %s", pipeline.URL)
+
+	if r.PipelineTimeout == 0 {
+		return nil
+	}
+
+	// The status is checked often at first, to not wait long for pipelines that finish quickly
+	deadline := time.Now().Add(r.PipelineTimeout)
+	interval := time.Second
+	for !pipeline.Done {
+		if time.Now().Add(interval).After(deadline) {
+			return errors.Errorf("the pipeline did not finish within %s", r.PipelineTimeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.New("stopped waiting for the pipeline")
+		case <-time.After(interval):
+		}
+		interval = min(interval*2, maxPollInterval)
+
+		pipeline, err = triggerer.GetPipeline(ctx, pipeline)
+		if err != nil {
+			return errors.Wrap(err, "could not get the status of the pipeline")
+		}
+	}
+
+	if !pipeline.Passed {
+		return errors.Errorf("the pipeline ended with the status %s", pipeline.Status)
+	}
+	log.Info("The pipeline passed")
+	return nil
+}
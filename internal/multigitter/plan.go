@@ -0,0 +1,322 @@
+package multigitter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lindell/multi-gitter/internal/encryption"
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// Plan describes what a run would do, it's created without changing anything
+type Plan struct {
+	FeatureBranch string              `json:"featureBranch"`
+	Blockers      []string            `json:"blockers,omitempty"` // Problems that would stop the run on all repositories
+	Repositories  []PlannedRepository `json:"repositories"`
+}
+
+// PlannedRepository describes what a run would do on a single repository
+type PlannedRepository struct {
+	Name       string `json:"name"`
+	ID         string `json:"id,omitempty"` // Used to find the repository if it's renamed or moved, on platforms with stable ids
+	BaseBranch string `json:"baseBranch"`
+
+	Title         string   `json:"title,omitempty"`
+	Labels        []string `json:"labels,omitempty"`
+	Reviewers     []string `json:"reviewers,omitempty"`
+	TeamReviewers []string `json:"teamReviewers,omitempty"`
+	Assignees     []string `json:"assignees,omitempty"`
+	Draft         bool     `json:"draft,omitempty"`
+
+	ExistingPullRequest string   `json:"existingPullRequest,omitempty"`
+	RequiredChecks      []string `json:"requiredChecks,omitempty"`
+
+	Blockers []string `json:"blockers,omitempty"` // Problems that would stop the run on this repository
+}
+
+// Plan discovers the repositories of the run and detects anything that would stop it, without cloning or changing any repository
+func (r *Runner) Plan(ctx context.Context) (Plan, error) {
+	repos, err := r.VersionController.GetRepositories(ctx)
+	if err != nil {
+		return Plan{}, errors.Wrap(err, "could not fetch repositories")
+	}
+
+	if err := r.verifyPlatformSupport(); err != nil {
+		return Plan{}, err
+	}
+
+	repos = filterRepositories(repos, r.SkipRepository, r.RegExIncludeRepository, r.RegExExcludeRepository)
+	repos = filterShard(repos, r.Shard)
+
+	if err := r.resolveBaseBranches(ctx, repos); err != nil {
+		return Plan{}, err
+	}
+
+	plan := Plan{
+		FeatureBranch: r.FeatureBranch,
+		Blockers:      r.branchProblems(),
+		Repositories:  make([]PlannedRepository, 0, len(repos)),
+	}
+
+	resolved, err := r.resolveReviewers(ctx)
+	if err != nil {
+		return Plan{}, err
+	}
+	if len(resolved.missing) > 0 && r.LenientReviewers {
+		r.skipMissingReviewers(resolved)
+	} else {
+		for _, missing := range resolved.missing {
+			plan.Blockers = append(plan.Blockers, fmt.Sprintf("could not find %s", missing))
+		}
+	}
+	for _, repo := range repos {
+		planned, err := r.planRepository(ctx, repo)
+		if err != nil {
+			return Plan{}, errors.WithMessagef(err, "could not plan %s", repo.FullName())
+		}
+		plan.Repositories = append(plan.Repositories, planned)
+	}
+
+	return plan, nil
+}
+
+func (r *Runner) planRepository(ctx context.Context, repo scm.Repository) (PlannedRepository, error) {
+	baseBranch := r.baseBranch(repo)
+	planned := PlannedRepository{
+		Name:       repo.FullName(),
+		ID:         repositoryID(repo),
+		BaseBranch: baseBranch,
+		Blockers:   r.pullRequestProblems(repo),
+	}
+
+	if baseBranch == r.FeatureBranch {
+		planned.Blockers = append(planned.Blockers, "the feature branch and the base branch are the same")
+	}
+
+	if !r.SkipPullRequest && !r.PushOnly {
+		newPR := r.pullRequestValues(repo, baseBranch)
+		planned.Title = newPR.Title
+		planned.Labels = newPR.Labels
+		planned.Reviewers = newPR.Reviewers
+		planned.TeamReviewers = newPR.TeamReviewers
+		planned.Assignees = newPR.Assignees
+		planned.Draft = newPR.Draft
+
+		pr, err := r.VersionController.GetOpenPullRequest(ctx, repo, r.FeatureBranch)
+		if err != nil {
+			return PlannedRepository{}, err
+		}
+		if pr != nil {
+			planned.ExistingPullRequest = pr.String()
+			if r.ConflictStrategy != ConflictStrategyReplace {
+				planned.Blockers = append(planned.Blockers, "a pull request already exists, and it's only updated with the replace conflict strategy")
+			}
+		}
+	}
+
+	if checksGetter, ok := r.VersionController.(requiredStatusChecksGetter); ok {
+		checks, err := checksGetter.GetRequiredStatusChecks(ctx, repo, baseBranch)
+		if err != nil {
+			log.WithField("repo", repo.FullName()).Warnf("Could not fetch required status checks: %s", err)
+		}
+		planned.RequiredChecks = checks
+	}
+
+	return planned, nil
+}
+
+// RunnableRepositories returns all repositories the plan can be run on
+func (p Plan) RunnableRepositories() []PlannedRepository {
+	if len(p.Blockers) > 0 {
+		return []PlannedRepository{}
+	}
+
+	runnable := []PlannedRepository{}
+	for _, repo := range p.Repositories {
+		if len(repo.Blockers) == 0 {
+			runnable = append(runnable, repo)
+		}
+	}
+	return runnable
+}
+
+// String returns a human readable description of the plan
+func (p Plan) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Branch %q will be used on %d of %d repositories\n", p.FeatureBranch, len(p.RunnableRepositories()), len(p.Repositories))
+	for _, blocker := range p.Blockers {
+		fmt.Fprintf(&b, "  Blocked: %s\n", blocker)
+	}
+
+	for _, repo := range p.Repositories {
+		fmt.Fprintf(&b, "\n%s\n", repo.Name)
+		fmt.Fprintf(&b, "  Base branch: %s\n", repo.BaseBranch)
+		writePlanValue(&b, "Title", repo.Title)
+		writePlanValue(&b, "Labels", strings.Join(repo.Labels, ", "))
+		writePlanValue(&b, "Reviewers", strings.Join(repo.Reviewers, ", "))
+		writePlanValue(&b, "Team reviewers", strings.Join(repo.TeamReviewers, ", "))
+		writePlanValue(&b, "Assignees", strings.Join(repo.Assignees, ", "))
+		if repo.Draft {
+			writePlanValue(&b, "Draft", "yes")
+		}
+		writePlanValue(&b, "Existing pull request", repo.ExistingPullRequest)
+		writePlanValue(&b, "Required checks", strings.Join(repo.RequiredChecks, ", "))
+		for _, blocker := range repo.Blockers {
+			fmt.Fprintf(&b, "  Blocked: %s\n", blocker)
+		}
+	}
+
+	return b.String()
+}
+
+func writePlanValue(b *strings.Builder, name, value string) {
+	if value != "" {
+		fmt.Fprintf(b, "  %s: %s\n", name, value)
+	}
+}
+
+// WritePlan saves a plan as json, encrypted if a key is set
+func WritePlan(path string, plan Plan, key []byte) error {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return err
+	}
+	if key != nil {
+		data, err = encryption.Encrypt(data, key)
+		if err != nil {
+			return errors.Wrap(err, "could not encrypt plan")
+		}
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// ReadPlan reads a plan saved with WritePlan
+func ReadPlan(path string, key []byte) (Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Plan{}, errors.Wrap(err, "could not read plan")
+	}
+
+	if encryption.IsEncrypted(data) {
+		if key == nil {
+			return Plan{}, errors.New("the plan is encrypted, and no encryption key is set")
+		}
+		data, err = encryption.Decrypt(data, key)
+		if err != nil {
+			return Plan{}, errors.Wrap(err, "could not decrypt plan")
+		}
+	}
+
+	var plan Plan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return Plan{}, errors.Wrap(err, "could not parse plan")
+	}
+	return plan, nil
+}
+
+// identifiableRepository is implemented by repositories with an id that is kept when they are renamed or moved
+type identifiableRepository interface {
+	ID() string
+}
+
+// repositoryID returns the stable id of the repository, or an empty string if the platform has none
+func repositoryID(repo scm.Repository) string {
+	if identifiable, ok := repo.(identifiableRepository); ok {
+		return stableID(identifiable.ID())
+	}
+	return ""
+}
+
+// stableID returns the id, or an empty string if it is unset. Platforms that use numerical ids return "0" if the id
+// is unset, which would otherwise make unrelated repositories match each other
+func stableID(id string) string {
+	if id == "0" {
+		return ""
+	}
+	return id
+}
+
+// filterPlannedRepositories keeps the repositories that are part of the plan. Repositories that have been renamed
+// or moved since the plan was made are found by their id
+func filterPlannedRepositories(repos []scm.Repository, planned []PlannedRepository) []scm.Repository {
+	plannedMap := map[string]struct{}{}
+	idNames := map[string]string{}
+	for _, p := range planned {
+		plannedMap[p.Name] = struct{}{}
+		if id := stableID(p.ID); id != "" {
+			idNames[id] = p.Name
+		}
+	}
+
+	filteredRepos := make([]scm.Repository, 0, len(repos))
+	for _, repo := range repos {
+		if _, ok := plannedMap[repo.FullName()]; ok {
+			filteredRepos = append(filteredRepos, repo)
+			delete(plannedMap, repo.FullName())
+			continue
+		}
+
+		id := repositoryID(repo)
+		if oldName, ok := idNames[id]; ok && id != "" {
+			if _, ok := plannedMap[oldName]; ok {
+				log.Infof("%s has been renamed or moved to %s since the plan was made, using the new name", oldName, repo.FullName())
+				filteredRepos = append(filteredRepos, repo)
+				delete(plannedMap, oldName)
+				continue
+			}
+		}
+
+		log.Infof("Skipping %s since it is not part of the plan", repo.FullName())
+	}
+	for name := range plannedMap {
+		log.Warnf("Skipping %s since it could no longer be found", name)
+	}
+	return filteredRepos
+}
+
+// filterPlannedPullRequests keeps the pull requests of the repositories that are part of the plan. Repositories that
+// have been renamed or moved since the plan was made are found by their id
+func filterPlannedPullRequests(ctx context.Context, vc VersionController, prs []scm.PullRequest, planned []PlannedRepository) ([]scm.PullRequest, error) {
+	if planned == nil {
+		return prs, nil
+	}
+
+	repos, err := vc.GetRepositories(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch the repositories of the plan")
+	}
+	plannedNames := map[string]struct{}{}
+	for _, repo := range filterPlannedRepositories(repos, planned) {
+		plannedNames[repo.FullName()] = struct{}{}
+	}
+
+	filtered := make([]scm.PullRequest, 0, len(prs))
+	for _, pr := range prs {
+		if _, ok := plannedNames[pullRequestRepositoryName(pr)]; ok {
+			filtered = append(filtered, pr)
+		}
+	}
+	return filtered, nil
+}
+
+// writeCheckpoint saves a plan with the repositories that were never run, so that the run can be continued from it
+func (r *Runner) writeCheckpoint(repos []scm.Repository) error {
+	plan := Plan{
+		FeatureBranch: r.FeatureBranch,
+		Repositories:  make([]PlannedRepository, 0, len(repos)),
+	}
+	for _, repo := range repos {
+		plan.Repositories = append(plan.Repositories, PlannedRepository{
+			Name:       repo.FullName(),
+			ID:         repositoryID(repo),
+			BaseBranch: r.baseBranch(repo),
+		})
+	}
+	return WritePlan(r.CheckpointPath, plan, r.CheckpointKey)
+}
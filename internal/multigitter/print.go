@@ -17,6 +17,7 @@ type Printer struct {
 
 	ScriptPath string // Must be absolute path
 	Arguments  []string
+	ScriptEnv  []string // The environment the script is run with. If nil, the full environment of the process is used
 
 	Stdout io.Writer
 	Stderr io.Writer
@@ -74,14 +75,12 @@ func (r Printer) runSingleRepo(ctx context.Context, repo scm.Repository) error {
 		return err
 	}
 
-	sourceController := r.CreateGit(tmpDir)
-
-	err = sourceController.Clone(ctx, repo.CloneURL(), repo.DefaultBranch())
+	_, err = cloneRepository(ctx, log, r.CreateGit, tmpDir, repo, repo.DefaultBranch())
 	if err != nil {
 		return err
 	}
 
-	cmd := prepareScriptCommand(ctx, repo, tmpDir, r.ScriptPath, r.Arguments)
+	cmd := prepareScriptCommand(ctx, repo, tmpDir, r.ScriptPath, r.Arguments, r.ScriptEnv)
 
 	cmd.Stdout = r.Stdout
 	cmd.Stderr = r.Stderr
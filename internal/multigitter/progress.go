@@ -0,0 +1,191 @@
+package multigitter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// phase is a step in the run of a single repository
+type phase int
+
+const (
+	phaseCloning phase = iota
+	phaseScript
+	phasePublishing
+	numPhases
+)
+
+var phaseNames = [numPhases]string{"cloning", "running script", "publishing"}
+
+// The number of finished phases the average duration of a phase is calculated from
+const progressWindow = 20
+
+// progress keeps track of, and displays, how far a run has come, and estimates how long it has left.
+// All methods can be called on a nil progress, which does nothing
+type progress struct {
+	out        io.Writer
+	total      int
+	concurrent int
+	now        func() time.Time
+
+	lock      sync.Mutex
+	completed int
+	current   map[string]phase     // The phase of every repository in progress
+	since     map[string]time.Time // When every repository in progress started its current phase
+	durations [numPhases][]time.Duration
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newProgress(out io.Writer, total int, concurrent int) *progress {
+	return &progress{
+		out:        out,
+		total:      total,
+		concurrent: concurrent,
+		now:        time.Now,
+		current:    map[string]phase{},
+		since:      map[string]time.Time{},
+	}
+}
+
+// start redraws the progress every interval, until finish is called
+func (p *progress) start(interval time.Duration) {
+	if p == nil {
+		return
+	}
+
+	p.stop = make(chan struct{})
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				fmt.Fprintf(p.out, "\r\033[K%s", p.String())
+			}
+		}
+	}()
+}
+
+// finish stops the redrawing and prints the final progress
+func (p *progress) finish() {
+	if p == nil {
+		return
+	}
+
+	if p.stop != nil {
+		close(p.stop)
+		p.wg.Wait()
+	}
+	fmt.Fprintf(p.out, "\r\033[K%s\n", p.String())
+}
+
+// setPhase moves a repository into a phase
+func (p *progress) setPhase(repoName string, ph phase) {
+	if p == nil {
+		return
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.endPhase(repoName)
+	p.current[repoName] = ph
+	p.since[repoName] = p.now()
+}
+
+// done marks a repository as done
+func (p *progress) done(repoName string) {
+	if p == nil {
+		return
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.endPhase(repoName)
+	delete(p.current, repoName)
+	delete(p.since, repoName)
+	p.completed++
+}
+
+// endPhase records the duration of the current phase of a repository, must be called with the lock held
+func (p *progress) endPhase(repoName string) {
+	ph, ok := p.current[repoName]
+	if !ok {
+		return
+	}
+	durations := append(p.durations[ph], p.now().Sub(p.since[repoName]))
+	if len(durations) > progressWindow {
+		durations = durations[1:]
+	}
+	p.durations[ph] = durations
+}
+
+// String returns the number of done repositories, how many repositories are in each phase, and the estimated time left
+func (p *progress) String() string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	counts := [numPhases]int{}
+	for _, ph := range p.current {
+		counts[ph]++
+	}
+
+	parts := []string{fmt.Sprintf("%d/%d repositories done", p.completed, p.total)}
+	for ph, count := range counts {
+		if count > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %d", phaseNames[ph], count))
+		}
+	}
+	if eta, ok := p.eta(); ok && p.completed < p.total {
+		parts = append(parts, fmt.Sprintf("ETA: %s", eta.Round(time.Second)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// eta estimates the time left, from the average duration of each phase. Must be called with the lock held
+func (p *progress) eta() (time.Duration, bool) {
+	if p.completed == 0 {
+		return 0, false
+	}
+
+	// Phases that no repository has gone through yet, are estimated to take no time
+	var averages [numPhases]time.Duration
+	for ph, durations := range p.durations {
+		if len(durations) == 0 {
+			continue
+		}
+		var sum time.Duration
+		for _, d := range durations {
+			sum += d
+		}
+		averages[ph] = sum / time.Duration(len(durations))
+	}
+
+	// The work left of the repositories in progress
+	var left time.Duration
+	for repoName, ph := range p.current {
+		if phaseLeft := averages[ph] - p.now().Sub(p.since[repoName]); phaseLeft > 0 {
+			left += phaseLeft
+		}
+		for later := ph + 1; later < numPhases; later++ {
+			left += averages[later]
+		}
+	}
+
+	// The work of the repositories not yet started
+	var perRepository time.Duration
+	for _, average := range averages {
+		perRepository += average
+	}
+	left += perRepository * time.Duration(p.total-p.completed-len(p.current))
+
+	return left / time.Duration(p.concurrent), true
+}
@@ -0,0 +1,113 @@
+package multigitter
+
+import (
+	"context"
+	"strings"
+	"text/template"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+)
+
+// releaseCreator is implemented by platforms that can create releases, or tags, on merged pull requests
+type releaseCreator interface {
+	CreateRelease(ctx context.Context, pr scm.PullRequest, release scm.NewRelease) error
+}
+
+// ReleaseTemplate contains the templates used to create a release after a pull request has been merged
+type ReleaseTemplate struct {
+	TagName *template.Template
+	Name    *template.Template // If nil, the tag name is used
+	Notes   *template.Template // Can be nil
+	TagOnly bool               // If set, only a tag is created, without any release
+}
+
+// mergeTemplateData is the data available in the templates of the merge command, like the ones of releases
+type mergeTemplateData struct {
+	Repository  string // The full name of the repository
+	PullRequest string // The name of the merged pull request
+	Branch      string // The feature branch of the pull request
+}
+
+// ParseReleaseTemplate parses the templates of the tag name, and the name and notes of releases
+func ParseReleaseTemplate(tagName, name, notes string, tagOnly bool) (*ReleaseTemplate, error) {
+	parse := func(field, text string) (*template.Template, error) {
+		if text == "" {
+			return nil, nil
+		}
+		tmpl, err := template.New(field).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "could not parse the release %s template", field)
+		}
+		return tmpl, nil
+	}
+
+	release := &ReleaseTemplate{TagOnly: tagOnly}
+	var err error
+	if release.TagName, err = parse("tag", tagName); err != nil {
+		return nil, err
+	}
+	if release.TagName == nil {
+		return nil, errors.New("a release tag has to be set")
+	}
+	if release.Name, err = parse("name", name); err != nil {
+		return nil, err
+	}
+	if release.Notes, err = parse("notes", notes); err != nil {
+		return nil, err
+	}
+	return release, nil
+}
+
+// newRelease executes the templates for a merged pull request
+func (t *ReleaseTemplate) newRelease(pr scm.PullRequest, branch string) (scm.NewRelease, error) {
+	execute := func(tmpl *template.Template) (string, error) {
+		return executeMergeTemplate(tmpl, pr, branch)
+	}
+
+	release := scm.NewRelease{TagOnly: t.TagOnly}
+	var err error
+	if release.TagName, err = execute(t.TagName); err != nil {
+		return scm.NewRelease{}, err
+	}
+	if release.Name, err = execute(t.Name); err != nil {
+		return scm.NewRelease{}, err
+	}
+	if release.Name == "" {
+		release.Name = release.TagName
+	}
+	if release.Notes, err = execute(t.Notes); err != nil {
+		return scm.NewRelease{}, err
+	}
+	return release, nil
+}
+
+// executeMergeTemplate executes a template of the merge command for a pull request, a nil template results in an empty string
+func executeMergeTemplate(tmpl *template.Template, pr scm.PullRequest, branch string) (string, error) {
+	if tmpl == nil {
+		return "", nil
+	}
+	data := mergeTemplateData{
+		Repository:  pullRequestRepositoryName(pr),
+		PullRequest: pr.String(),
+		Branch:      branch,
+	}
+	sb := &strings.Builder{}
+	if err := tmpl.Execute(sb, data); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// createRelease creates a release on the merge commit of a pull request
+func (s Merger) createRelease(ctx context.Context, pr scm.PullRequest) error {
+	release, err := s.Release.newRelease(pr, s.FeatureBranch)
+	if err != nil {
+		return errors.WithMessage(err, "could not create the release from the templates")
+	}
+
+	if err := s.VersionController.(releaseCreator).CreateRelease(ctx, pr, release); err != nil {
+		return errors.WithMessagef(err, "could not create release %s", release.TagName)
+	}
+	return nil
+}
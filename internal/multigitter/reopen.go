@@ -0,0 +1,40 @@
+package multigitter
+
+import (
+	"context"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// pullRequestReopener is implemented by platforms that can reopen closed pull requests
+type pullRequestReopener interface {
+	// GetClosedPullRequest gets the latest closed, but not merged, pull request from a branch, or nil if there is none
+	GetClosedPullRequest(ctx context.Context, repo scm.Repository, branchName string) (scm.PullRequest, error)
+	ReopenPullRequest(ctx context.Context, pr scm.PullRequest) (scm.PullRequest, error)
+}
+
+// reopenClosedPullRequest reopens the pull request of the feature branch, if it was closed without being merged, so that
+// it can be updated instead of a new pull request being created next to it. Nil is returned if there is no such pull request,
+// or if it could not be reopened
+func (r *Runner) reopenClosedPullRequest(ctx context.Context, log log.FieldLogger, repo scm.Repository) (scm.PullRequest, error) {
+	reopener := r.VersionController.(pullRequestReopener)
+
+	closed, err := reopener.GetClosedPullRequest(ctx, repo, r.FeatureBranch)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch closed pull request")
+	}
+	if closed == nil {
+		return nil, nil
+	}
+
+	pr, err := reopener.ReopenPullRequest(ctx, closed)
+	if err != nil {
+		// A closed pull request can not always be reopened, for example on GitHub if its branch has been re-created
+		log.Warnf("Could not reopen %s, creating a new pull request instead: %s", closed.String(), err)
+		return nil, nil
+	}
+	log.Infof("Reopened the closed pull request %s", pr.String())
+	return pr, nil
+}
@@ -2,6 +2,7 @@ package repocounter
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 
@@ -13,6 +14,7 @@ import (
 type Counter struct {
 	successRepositories []repoInfo
 	errors              map[string][]repoInfo
+	teams               map[string]string // The teams that own the repositories, by repository name
 	lock                sync.RWMutex
 }
 
@@ -61,6 +63,17 @@ func (r *Counter) AddSuccessPullRequest(repo scm.Repository, pr scm.PullRequest)
 	})
 }
 
+// SetTeam sets the team that owns a repository. If any team is set, the info lists all repositories grouped by team as well
+func (r *Counter) SetTeam(repo scm.Repository, team string) {
+	defer r.lock.Unlock()
+	r.lock.Lock()
+
+	if r.teams == nil {
+		r.teams = map[string]string{}
+	}
+	r.teams[repo.FullName()] = team
+}
+
 // Info returns a formatted string about all repositories
 func (r *Counter) Info() string {
 	defer r.lock.RUnlock()
@@ -98,9 +111,67 @@ func (r *Counter) Info() string {
 		}
 	}
 
+	exitInfo += r.teamInfo()
+
 	return exitInfo
 }
 
+// teamInfo returns a formatted string about all repositories, grouped by the teams that own them
+func (r *Counter) teamInfo() string {
+	if len(r.teams) == 0 {
+		return ""
+	}
+
+	byTeam := map[string][]string{}
+	for errMsg, infos := range r.errors {
+		for _, info := range infos {
+			team := r.teams[info.repository.FullName()]
+			byTeam[team] = append(byTeam[team], fmt.Sprintf("%s: %s", info.name(), errMsg))
+		}
+	}
+	for _, info := range r.successRepositories {
+		team := r.teams[info.repository.FullName()]
+		byTeam[team] = append(byTeam[team], info.name())
+	}
+
+	teams := make([]string, 0, len(byTeam))
+	for team := range byTeam {
+		if team != "" {
+			teams = append(teams, team)
+		}
+	}
+	sort.Strings(teams)
+	if _, ok := byTeam[""]; ok {
+		teams = append(teams, "")
+	}
+
+	info := "Repositories by team:\n"
+	for _, team := range teams {
+		if team == "" {
+			info += "  No team:\n"
+		} else {
+			info += fmt.Sprintf("  %s:\n", team)
+		}
+		lines := byTeam[team]
+		sort.Strings(lines)
+		for _, line := range lines {
+			info += fmt.Sprintf("    %s\n", line)
+		}
+	}
+	return info
+}
+
+// name returns the pull request, as a link if possible, or the repository name if there is no pull request
+func (ri repoInfo) name() string {
+	if ri.pullRequest == nil {
+		return ri.repository.FullName()
+	}
+	if urler, hasURL := ri.pullRequest.(urler); hasURL && urler.URL() != "" {
+		return terminal.Link(ri.pullRequest.String(), urler.URL())
+	}
+	return ri.pullRequest.String()
+}
+
 type urler interface {
 	URL() string
 }
@@ -0,0 +1,58 @@
+package multigitter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+)
+
+// requiredStatusChecksGetter is implemented by platforms that can list the status checks required by a branch
+type requiredStatusChecksGetter interface {
+	GetRequiredStatusChecks(ctx context.Context, repo scm.Repository, branchName string) ([]string, error)
+}
+
+// requiredChecksReport keeps track of the required status checks on the base branch of each repository
+type requiredChecksReport struct {
+	checks map[string][]string
+	lock   sync.Mutex
+}
+
+func (r *requiredChecksReport) add(repoName string, checks []string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.checks == nil {
+		r.checks = map[string][]string{}
+	}
+	r.checks[repoName] = checks
+}
+
+// info returns a formatted string with the required checks of all repositories
+func (r *requiredChecksReport) info() string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if len(r.checks) == 0 {
+		return ""
+	}
+
+	repoNames := make([]string, 0, len(r.checks))
+	for repoName := range r.checks {
+		repoNames = append(repoNames, repoName)
+	}
+	sort.Strings(repoNames)
+
+	info := "Required status checks on the base branch:\n"
+	for _, repoName := range repoNames {
+		checks := "none"
+		if len(r.checks[repoName]) > 0 {
+			checks = strings.Join(r.checks[repoName], ", ")
+		}
+		info += fmt.Sprintf("  %s: %s\n", repoName, checks)
+	}
+	return info
+}
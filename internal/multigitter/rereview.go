@@ -0,0 +1,48 @@
+package multigitter
+
+import (
+	"context"
+	"strings"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// reviewRerequester is implemented by platforms that can ask the previous reviewers of a pull request to review it again
+type reviewRerequester interface {
+	// RerequestReviews asks everyone who has reviewed the pull request to review it again, and returns who was asked
+	RerequestReviews(ctx context.Context, pr scm.PullRequest) ([]string, error)
+}
+
+// rerequestReviews makes sure that approvals of a previous version of an updated pull request are not mistaken for approvals of the new changes
+func (r *Runner) rerequestReviews(ctx context.Context, log log.FieldLogger, pr scm.PullRequest) error {
+	reviewers, err := r.VersionController.(reviewRerequester).RerequestReviews(ctx, pr)
+	if err != nil {
+		return errors.Wrap(err, "could not re-request reviews")
+	}
+
+	if len(reviewers) > 0 {
+		log.Infof("Re-requested review from %s", strings.Join(reviewers, ", "))
+	}
+	return nil
+}
+
+// approvalResetter is implemented by platforms that can withdraw the approvals of a pull request
+type approvalResetter interface {
+	// ResetApprovals withdraws every approval of the pull request, and returns whose approvals were withdrawn
+	ResetApprovals(ctx context.Context, pr scm.PullRequest) ([]string, error)
+}
+
+// resetApprovals makes sure that an updated pull request can not be merged with the approvals of a previous version of it
+func (r *Runner) resetApprovals(ctx context.Context, log log.FieldLogger, pr scm.PullRequest) error {
+	approvers, err := r.VersionController.(approvalResetter).ResetApprovals(ctx, pr)
+	if err != nil {
+		return errors.Wrap(err, "could not reset the approvals")
+	}
+
+	if len(approvers) > 0 {
+		log.Infof("Reset the approvals of %s", strings.Join(approvers, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package multigitter
+
+import (
+	"context"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// pullRequestRetargeter is implemented by platforms that can change the branch a pull request is made against
+type pullRequestRetargeter interface {
+	GetPullRequestBase(ctx context.Context, pr scm.PullRequest) (string, error)
+	RetargetPullRequest(ctx context.Context, pr scm.PullRequest, base string) error
+}
+
+// retargetPullRequest makes an existing pull request target the base branch, if the branch it targets has been removed,
+// like when the default branch is renamed from master to main. Pull requests that target another existing branch are
+// left as they are, since that might be intentional
+func (r *Runner) retargetPullRequest(ctx context.Context, log log.FieldLogger, repo scm.Repository, pr scm.PullRequest, baseBranch string) error {
+	if r.SkipRetarget {
+		return nil
+	}
+	retargeter, ok := r.VersionController.(pullRequestRetargeter)
+	if !ok {
+		return nil
+	}
+	checker, ok := r.VersionController.(branchExistChecker)
+	if !ok {
+		return nil
+	}
+
+	currentBase, err := retargeter.GetPullRequestBase(ctx, pr)
+	if err != nil {
+		return errors.Wrap(err, "could not get the base branch of the pull request")
+	}
+	if currentBase == "" || currentBase == baseBranch {
+		return nil
+	}
+
+	exists, err := checker.BranchExists(ctx, repo, currentBase)
+	if err != nil {
+		return errors.Wrap(err, "could not check if the base branch of the pull request exists")
+	}
+	if exists {
+		return nil
+	}
+
+	if err := retargeter.RetargetPullRequest(ctx, pr, baseBranch); err != nil {
+		return errors.Wrapf(err, "could not retarget the pull request to %s", baseBranch)
+	}
+	log.Infof("Retargeted the pull request from the removed branch %s to %s", currentBase, baseBranch)
+	return nil
+}
+
+// retargetUnchangedPullRequest retargets the open pull request of a repository where the script made no changes, which
+// is the usual state of a repository while its pull request is waiting to be merged. errNoChange is returned if nothing
+// else went wrong
+func (r *Runner) retargetUnchangedPullRequest(ctx context.Context, log log.FieldLogger, repo scm.Repository, baseBranch string) error {
+	if r.SkipRetarget || r.DryRun {
+		return errNoChange
+	}
+	if _, ok := r.VersionController.(pullRequestRetargeter); !ok {
+		return errNoChange
+	}
+
+	pr, err := r.VersionController.GetOpenPullRequest(ctx, repo, r.FeatureBranch)
+	if err != nil {
+		return errors.Wrap(err, "could not fetch the open pull request")
+	}
+	if pr == nil {
+		return errNoChange
+	}
+
+	if err := r.retargetPullRequest(ctx, log, repo, pr, baseBranch); err != nil {
+		return err
+	}
+	return errNoChange
+}
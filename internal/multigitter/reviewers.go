@@ -0,0 +1,179 @@
+package multigitter
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// userResolver is implemented by platforms that can look up if a user exists
+type userResolver interface {
+	UserExists(ctx context.Context, username string) (bool, error)
+}
+
+// teamResolver is implemented by platforms that can look up if a team exists
+type teamResolver interface {
+	TeamExists(ctx context.Context, team string) (bool, error)
+}
+
+// resolvedReviewers are the reviewers and assignees of a run that could be found on the platform
+type resolvedReviewers struct {
+	reviewers     []string
+	teamReviewers []string
+	assignees     []string
+
+	missing []string // A description of everyone that could not be found
+}
+
+// resolveReviewers looks up every reviewer, team reviewer and assignee of the run on the platform.
+// Those that can't be looked up on the platform are assumed to exist
+func (r *Runner) resolveReviewers(ctx context.Context) (resolvedReviewers, error) {
+	resolved := resolvedReviewers{
+		reviewers:     r.Reviewers,
+		teamReviewers: r.TeamReviewers,
+		assignees:     r.Assignees,
+	}
+	if r.SkipPullRequest || r.PushOnly {
+		return resolved, nil
+	}
+
+	var err error
+	if resolver, ok := r.VersionController.(userResolver); ok {
+		if resolved.reviewers, err = resolved.resolve(ctx, "reviewer", r.Reviewers, resolver.UserExists); err != nil {
+			return resolvedReviewers{}, err
+		}
+		if resolved.assignees, err = resolved.resolve(ctx, "assignee", r.Assignees, resolver.UserExists); err != nil {
+			return resolvedReviewers{}, err
+		}
+	}
+	if resolver, ok := r.VersionController.(teamResolver); ok {
+		if resolved.teamReviewers, err = resolved.resolve(ctx, "team reviewer", r.TeamReviewers, resolver.TeamExists); err != nil {
+			return resolvedReviewers{}, err
+		}
+	}
+	return resolved, nil
+}
+
+// resolve returns the names that exist, and adds the ones that don't to the missing list
+func (resolved *resolvedReviewers) resolve(
+	ctx context.Context, kind string, names []string, exists func(ctx context.Context, name string) (bool, error),
+) ([]string, error) {
+	if names == nil {
+		return nil, nil
+	}
+
+	found := []string{}
+	for _, name := range names {
+		ok, err := exists(ctx, name)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "could not look up %s %q", kind, name)
+		}
+		if ok {
+			found = append(found, name)
+		} else {
+			resolved.missing = append(resolved.missing, fmt.Sprintf("%s %q", kind, name))
+		}
+	}
+	return found, nil
+}
+
+// verifyReviewers verifies, before any repository is changed, that all reviewers and assignees exist.
+// If LenientReviewers is set, the ones that don't exist are instead left out of the run
+func (r *Runner) verifyReviewers(ctx context.Context) error {
+	resolved, err := r.resolveReviewers(ctx)
+	if err != nil {
+		return err
+	}
+	if len(resolved.missing) == 0 {
+		return nil
+	}
+
+	if !r.LenientReviewers {
+		return errors.Errorf("could not find:\n  %s", strings.Join(resolved.missing, "\n  "))
+	}
+
+	r.skipMissingReviewers(resolved)
+	return nil
+}
+
+// skipMissingReviewers leaves everyone that could not be found out of the run
+func (r *Runner) skipMissingReviewers(resolved resolvedReviewers) {
+	log.Warnf("Could not find %s, they will not be added to any pull request", strings.Join(resolved.missing, ", "))
+	r.Reviewers = resolved.reviewers
+	r.TeamReviewers = resolved.teamReviewers
+	r.Assignees = resolved.assignees
+}
+
+// reviewerLoad balances the reviewers of a run, so that the reviewers with the fewest pull requests are picked first,
+// and no reviewer gets more than a maximum number of pull requests
+type reviewerLoad struct {
+	maxPerReviewer int
+
+	lock   sync.Mutex
+	counts map[string]int
+}
+
+func newReviewerLoad(maxPerReviewer int) *reviewerLoad {
+	return &reviewerLoad{
+		maxPerReviewer: maxPerReviewer,
+		counts:         map[string]int{},
+	}
+}
+
+// pick picks up to count reviewers, or all available if count is zero, from the pool. The picked reviewers are counted
+// right away, so that pull requests made at the same time can't go over the maximum together, and have to be uncounted if
+// no pull request is made for them
+func (l *reviewerLoad) pick(log log.FieldLogger, pool []string, count int) []string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	// Shuffle before sorting, so that reviewers with the same number of pull requests are picked randomly
+	candidates := make([]string, 0, len(pool))
+	for _, reviewer := range pool {
+		if l.counts[reviewer] < l.maxPerReviewer {
+			candidates = append(candidates, reviewer)
+		}
+	}
+	rand.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return l.counts[candidates[i]] < l.counts[candidates[j]]
+	})
+
+	if count == 0 {
+		count = len(pool)
+	}
+	if len(candidates) < count {
+		log.Warnf("Only %d of %d reviewers could be added, the other reviewers already have the maximum number of pull requests", len(candidates), count)
+		count = len(candidates)
+	}
+
+	picked := candidates[:count]
+	for _, reviewer := range picked {
+		l.counts[reviewer]++
+	}
+	return picked
+}
+
+// count counts reviewers that were added to a pull request in another way than being picked, like by the script
+func (l *reviewerLoad) count(reviewers []string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	for _, reviewer := range reviewers {
+		l.counts[reviewer]++
+	}
+}
+
+// uncount takes back the count of the reviewers of a pull request that could not be made
+func (l *reviewerLoad) uncount(reviewers []string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	for _, reviewer := range reviewers {
+		l.counts[reviewer]--
+	}
+}
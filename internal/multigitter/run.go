@@ -7,9 +7,14 @@ import (
 	"math/rand"
 	"os"
 	"os/exec"
+	"path"
 	"regexp"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
+	"text/template"
+	"time"
 	"unicode"
 
 	"github.com/eiannone/keyboard"
@@ -24,16 +29,7 @@ import (
 )
 
 // VersionController fetches repositories
-type VersionController interface {
-	GetRepositories(ctx context.Context) ([]scm.Repository, error)
-	CreatePullRequest(ctx context.Context, repo scm.Repository, prRepo scm.Repository, newPR scm.NewPullRequest) (scm.PullRequest, error)
-	UpdatePullRequest(ctx context.Context, repo scm.Repository, pullReq scm.PullRequest, updatedPR scm.NewPullRequest) (scm.PullRequest, error)
-	GetPullRequests(ctx context.Context, branchName string) ([]scm.PullRequest, error)
-	GetOpenPullRequest(ctx context.Context, repo scm.Repository, branchName string) (scm.PullRequest, error)
-	MergePullRequest(ctx context.Context, pr scm.PullRequest) error
-	ClosePullRequest(ctx context.Context, pr scm.PullRequest) error
-	ForkRepository(ctx context.Context, repo scm.Repository, newOwner string) (scm.Repository, error)
-}
+type VersionController = scm.VersionController
 
 // Runner contains fields to be able to do the run
 type Runner struct {
@@ -41,10 +37,14 @@ type Runner struct {
 
 	ScriptPath    string // Must be absolute path
 	Arguments     []string
+	ScriptEnv     []string // The environment the script is run with. If nil, the full environment of the process is used
 	FeatureBranch string
 
 	Output io.Writer
 
+	PullRequestTitlePrefix string // If set, added before the title of every pull request, including overridden titles
+	PullRequestTitleSuffix string // If set, added after the title of every pull request, including overridden titles
+
 	CommitMessage    string
 	PullRequestTitle string
 	PullRequestBody  string
@@ -56,6 +56,17 @@ type Runner struct {
 	CommitAuthor     *git.CommitAuthor
 	BaseBranch       string // The base branch of the PR, use default branch if not set
 	Assignees        []string
+	LenientReviewers bool // If set, reviewers and assignees that can't be found are skipped instead of stopping the run
+
+	BaseBranchRules []BaseBranchRule // Rules that, per repository, pick the first existing branch as base branch instead of BaseBranch
+
+	BackportBranches []string // If set, the changes are also made against each of these branches, or branches matching these patterns like "release/*", in the repositories where they exist, with pull requests of their own
+
+	BaseRef *template.Template // If set, the ref, like a tag or commit, rendered per repository, that changes are made on top of instead of the tip of the base branch
+
+	Paths []string // If set, the changes are limited to these paths, which are mentioned in the title and body of pull requests. The git implementation has to be limited to them as well
+
+	MaxPullRequestsPerReviewer int // If set, reviewers are balanced so that none of them gets more than this number of pull requests
 
 	Concurrent             int
 	SkipPullRequest        bool     // If set, the script will run directly on the base-branch without creating any PR
@@ -63,6 +74,9 @@ type Runner struct {
 	SkipRepository         []string // A list of repositories that run will skip
 	RegExIncludeRepository *regexp.Regexp
 	RegExExcludeRepository *regexp.Regexp
+	Shard                  Shard // If set, only the repositories of this shard are used
+
+	PlanRepositories []PlannedRepository // If set, only these repositories, usually from a saved plan, are used
 
 	Fork      bool   // If set, create a fork and make the pull request from it
 	ForkOwner string // The owner of the new fork. If empty, the fork should happen on the logged in user
@@ -71,21 +85,132 @@ type Runner struct {
 
 	Draft bool // If set, creates Pull Requests as draft
 
-	Labels   []string // Labels to be added to the pull request
-	CloneDir string   // Directory to clone repositories to
+	CloseObsolete bool // If set, any open pull request on a repository where the script made no changes will be closed
+
+	SkipConflictingPullRequests bool // If set, repositories with other open pull requests changing the same files will be skipped
+
+	Labels []string // Labels to be added to the pull request
+
+	Campaign string // If set, pull requests are marked with the campaign, and open pull requests of the campaign made from other branches are updated instead of duplicated
+
+	PullRequestDiffSummary bool // If set, a summary of the changes made is appended to the pull request body
+
+	SizeLabelThresholds []int // If set, a size label is added to pull requests based on the number of changed lines, see DefaultSizeLabelThresholds
+
+	SplitDiffLines int // If set, changes of more lines than this are split by top-level directory into one pull request per directory
+
+	PullRequestOverrides map[string]PullRequestOverride // Pull request values that should be replaced for specific repositories, keyed by the full name of the repository
+
+	CloneDirs        []string // Directories to clone repositories to, each clone is placed in the one with the most free space
+	KeepFailedClones bool     // If set, the clones of repositories where the run failed are not removed
+	DiskBudget       int64    // The maximum number of bytes all clones may use at the same time, zero means no limit
+	MinFreeDiskSpace int64    // New clones are held back while less than this number of bytes is free in the clone directory
+
+	ScriptOutputLimit int    // The number of bytes of the output of each script run that is logged, the middle is left out. Zero means no limit
+	ScriptOutputDir   string // If set, the full output of each script run is written to <dir>/<owner>/<repo>.log
+
+	SkipExitCode int  // If not zero, a script that exits with this code marks the repository as skipped instead of failed
+	FailOnError  bool // If set, the run returns an error if it failed on any repository. Skipped repositories are not failures
+
+	StopAfterFailures int           // If set, no more repositories are started once the run has failed on this many repositories
+	StopAfterDuration time.Duration // If set, no more repositories are started once the run has taken this long
+	CheckpointPath    string        // If set, a plan with the repositories that were never run, if any, is written here
+	CheckpointKey     []byte        // If set, the checkpoint is encrypted with this key
+
+	EventWebhookURL string // If set, a CloudEvent with the outcome of each repository is sent to this url
+
+	TrackingIssueRepository string // If set, an issue with the status of all pull requests is created, or updated, in this repository
+
+	IssueFallback bool // If set, an issue with the changes is created on repositories where no pull request could be created
+
+	Jira                   Jira   // If set, the changes are tracked in Jira issues, which are referenced in commits and pull requests
+	JiraIssue              string // The Jira issue that tracks the whole run, if not set one is found by its label or created
+	JiraIssuePerRepository bool   // If set, every repository gets its own Jira issue instead of one for the whole run
+
+	ChangeRequestGate *ChangeRequestGate // If set, a valid change request is required to make changes in production organizations
+
+	TeamMapping         TeamMapping // If set, the report lists the repositories grouped by the teams that own them
+	TeamsFromCodeOwners bool        // If set, the report lists the repositories grouped by the default owners in their CODEOWNERS files
+
+	CheckReviewerOwnership bool // If set, a warning is logged for repositories where none of the reviewers own some of the changed files, according to CODEOWNERS
+
+	ReportRequiredChecks bool // If set, the status checks required on the base branch of every pull request are reported
+
+	ReportSlowest int // If set, the report lists this many repositories that took the longest, with the time spent cloning, running the script and publishing
+
+	BodyTruncationMarker string // Appended to pull request bodies that are truncated to fit the length limit of the platform
+	FullBodyComment      bool   // If set, the full body of truncated pull requests is added as comments
+
+	RerequestReviews bool // If set, everyone who reviewed an existing pull request is asked to review it again when it's updated
+	ResetApprovals   bool // If set, the approvals of an existing pull request are withdrawn when it's updated
+	KeepReviewers    bool // If set, reviewers that were added to an existing pull request by someone else are kept when it's updated
+
+	ReopenClosed bool // If set, a closed pull request from the feature branch is reopened and updated instead of a new one being created
+	SkipAdoption bool // If set, open pull requests from the feature branch are not searched for if the branch did not exist before the run
+	SkipRetarget bool // If set, open pull requests whose base branch has been removed are not retargeted
+
+	TriggerPipeline bool          // If set, the pipeline of every created or updated pull request is started
+	PipelineTimeout time.Duration // If set, the run waits this long for triggered pipelines to finish, and fails on repositories where they do not pass
+
+	SummaryComment bool   // If set, a comment with the script, the size of the changes and the version of multi-gitter is added to every created or updated pull request
+	Version        string // The version of multi-gitter
+
+	CheckRunName string // If set, a check run with this name, the campaign and the hash of the script is added to the last commit of every pull request
+
+	StatusContext     string // If set, a successful status with this name is set on the last commit of every pull request
+	StatusDescription string // The description of the status, a default description is used if not set
+	StatusURL         string // The link of the status, if any
+
+	PatchDir string // The directory patches are written to, on platforms where changes are submitted as patches instead of pull requests
 
 	Interactive bool // If set, interactive mode is activated and the user will be asked to verify every change
 
+	Progress io.Writer // If set, the progress of the run, and the estimated time left, is continuously displayed here
+
 	CreateGit func(dir string) Git
+
+	diskSpace    *diskSpace
+	keptClones   *keptClonesReport
+	progress     *progress
+	reviewerLoad *reviewerLoad
+
+	ruleBaseBranches map[string]string // The base branches picked by BaseBranchRules, by repository name
+	jiraIssues       *jiraIssues
+	codeOwnerTeams   *repositoryTeams
+
+	scriptPullRequests *scriptPullRequests
+	stop               *stopConditions
+	timings            *phaseTimings
+
+	scriptHash string // The hash of the script, set if check runs are created
 }
 
 var (
-	errAborted     = errors.New("run was never started because of aborted execution")
-	errRejected    = errors.New("changes were not included since they were manually rejected")
-	errNoChange    = errors.New("no data was changed")
-	errBranchExist = errors.New("the new branch already exists")
+	errAborted        = errors.New("run was never started because of aborted execution")
+	errRejected       = errors.New("changes were not included since they were manually rejected")
+	errNoChange       = errors.New("no data was changed")
+	errBranchExist    = errors.New("the new branch already exists")
+	errObsoleteClosed = errors.New("no data was changed and the obsolete pull request was closed")
+	errObsoleteOpen   = errors.New("no data was changed and the obsolete pull request would be closed, but was not because of dry run")
+	errConflictingPR  = errors.New("skipped since another open pull request changes the same files")
+	errIssueCreated   = errors.New("could not create a pull request, an issue with the changes was created instead")
+	errScriptSkipped  = errors.New("skipped by the script")
+	errUnavailable    = errors.New("the repository became unavailable during the run, like by being archived")
 )
 
+// isFailure returns if an error, returned from a run on a single repository, means that something went wrong
+func isFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	for _, outcome := range []error{errAborted, errRejected, errNoChange, errBranchExist, errObsoleteClosed, errObsoleteOpen, errConflictingPR, errIssueCreated, errScriptSkipped, errUnavailable} {
+		if errors.Is(err, outcome) {
+			return false
+		}
+	}
+	return true
+}
+
 type dryRunPullRequest struct {
 	status     scm.PullRequestStatus
 	Repository scm.Repository
@@ -99,6 +224,11 @@ func (pr dryRunPullRequest) String() string {
 	return fmt.Sprintf("%s #0", pr.Repository.FullName())
 }
 
+// RepositoryFullName returns the full name of the repository the pull request is made against
+func (pr dryRunPullRequest) RepositoryFullName() string {
+	return pr.Repository.FullName()
+}
+
 // Run runs a script for multiple repositories and creates PRs with the changes made
 func (r *Runner) Run(ctx context.Context) error {
 	// Fetch all repositories that are are going to be used in the run
@@ -107,39 +237,157 @@ func (r *Runner) Run(ctx context.Context) error {
 		return errors.Wrap(err, "could not fetch repositories")
 	}
 
+	if err := r.verifyPlatformSupport(); err != nil {
+		return err
+	}
+
 	repos = filterRepositories(repos, r.SkipRepository, r.RegExIncludeRepository, r.RegExExcludeRepository)
+	repos = filterShard(repos, r.Shard)
+	if r.PlanRepositories != nil {
+		repos = filterPlannedRepositories(repos, r.PlanRepositories)
+	}
 
 	if len(repos) == 0 {
 		log.Infof("No repositories found. Please make sure the user of the token has the correct access to the repos you want to change.")
 		return nil
 	}
 
+	if err := r.resolveBaseBranches(ctx, repos); err != nil {
+		return err
+	}
+
+	if err := r.lint(repos); err != nil {
+		return err
+	}
+
+	if err := r.verifyReviewers(ctx); err != nil {
+		return err
+	}
+
+	if !r.DryRun {
+		if err := r.ChangeRequestGate.verify(ctx, "make changes", repositoryNames(repos)); err != nil {
+			return err
+		}
+	}
+
+	if err := r.resolveJiraIssue(ctx); err != nil {
+		return err
+	}
+	// Everything set up here is shared with the runs against backport branches, so that limits apply to all of them together
+	r.jiraIssues = &jiraIssues{}
+	r.scriptPullRequests = &scriptPullRequests{}
+	r.stop = newStopConditions(r.StopAfterFailures, r.StopAfterDuration)
+	if r.TeamsFromCodeOwners {
+		r.codeOwnerTeams = &repositoryTeams{}
+	}
+	if r.CheckRunName != "" {
+		if r.scriptHash, err = hashScript(r.ScriptPath, r.Arguments); err != nil {
+			return err
+		}
+	}
+
+	r.diskSpace = newDiskSpace(r.CloneDirs, r.DiskBudget, r.MinFreeDiskSpace)
+	defer r.diskSpace.removeRoots()
+	if r.MaxPullRequestsPerReviewer > 0 {
+		r.reviewerLoad = newReviewerLoad(r.MaxPullRequestsPerReviewer)
+	}
+
+	failed, err := r.runRepositories(ctx, repos)
+	if err != nil {
+		return err
+	}
+	total := len(repos)
+
+	backportFailed, backportTotal, err := r.runBackports(ctx, repos)
+	if err != nil {
+		return err
+	}
+	failed += backportFailed
+	total += backportTotal
+
+	if err := r.stop.err(); err != nil {
+		return err
+	}
+
+	if r.FailOnError && failed > 0 {
+		return errors.Errorf("the run failed on %d of %d repositories", failed, total)
+	}
+
+	return nil
+}
+
+// runRepositories runs the script on the repositories, and returns on how many of them it failed
+func (r *Runner) runRepositories(ctx context.Context, repos []scm.Repository) (int, error) {
+	if r.ReportSlowest > 0 {
+		r.timings = newPhaseTimings()
+	}
+	r.keptClones = &keptClonesReport{}
+
 	// Setting up a "counter" that keeps track of successful and failed runs
 	rc := repocounter.NewCounter()
+	var failed atomic.Int64
+	neverRun := make([]bool, len(repos))
+	checksReport := &requiredChecksReport{}
 	defer func() {
 		if info := rc.Info(); info != "" {
 			fmt.Fprint(r.Output, info)
 		}
+		if info := checksReport.info(); info != "" {
+			fmt.Fprint(r.Output, info)
+		}
+		if info := r.keptClones.info(); info != "" {
+			fmt.Fprint(r.Output, info)
+		}
+		if info := r.timings.info(r.ReportSlowest); info != "" {
+			fmt.Fprint(r.Output, info)
+		}
 	}()
 
 	log.Infof("Running on %d repositories", len(repos))
 
+	if r.Progress != nil {
+		r.progress = newProgress(r.Progress, len(repos), r.Concurrent)
+		r.progress.start(time.Second)
+	}
+
 	runInParallel(func(i int) {
 		logger := log.WithField("repo", repos[i].FullName())
+		defer r.progress.done(repos[i].FullName())
+		defer r.timings.done(repos[i].FullName())
 
 		defer func() {
-			if r := recover(); r != nil {
-				log.Error(r)
+			if recovered := recover(); recovered != nil {
+				log.Error(recovered)
 				rc.AddError(errors.New("run panicked"), repos[i], nil)
+				failed.Add(1)
+				r.stop.failed()
 			}
 		}()
 
 		pr, err := r.runSingleRepo(ctx, repos[i])
+		if isFailure(err) {
+			err = r.checkUnavailable(ctx, logger, repos[i], err)
+		}
+		if r.TeamMapping != nil || r.TeamsFromCodeOwners {
+			rc.SetTeam(repos[i], r.team(repos[i].FullName()))
+		}
+
+		if r.EventWebhookURL != "" {
+			if eventErr := r.sendRepositoryEvent(ctx, repos[i], pr, err); eventErr != nil {
+				logger.Warnf("Could not send event: %s", eventErr)
+			}
+		}
+
 		if err != nil {
 			if err != errAborted {
 				logger.Info(err)
 			}
 			rc.AddError(err, repos[i], pr)
+			if isFailure(err) {
+				failed.Add(1)
+				r.stop.failed()
+			}
+			neverRun[i] = err == errAborted
 
 			if log.IsLevelEnabled(log.TraceLevel) {
 				if stackTrace := getStackTrace(err); stackTrace != "" {
@@ -150,12 +398,146 @@ func (r *Runner) Run(ctx context.Context) error {
 			return
 		}
 
+		if pr != nil && r.ReportRequiredChecks {
+			checks, err := r.requiredStatusChecks(ctx, repos[i])
+			if err != nil {
+				logger.Warnf("Could not fetch required status checks: %s", err)
+			} else {
+				checksReport.add(repos[i].FullName(), checks)
+			}
+		}
+
 		if pr != nil {
 			rc.AddSuccessPullRequest(repos[i], pr)
 		} else {
 			rc.AddSuccessRepositories(repos[i])
 		}
 	}, len(repos), r.Concurrent)
+	r.progress.finish()
+
+	if r.TrackingIssueRepository != "" && !r.DryRun {
+		if err := updateTrackingIssue(ctx, r.VersionController, r.TrackingIssueRepository, r.FeatureBranch); err != nil {
+			return 0, err
+		}
+	}
+
+	if r.CheckpointPath != "" {
+		remaining := []scm.Repository{}
+		for i := range repos {
+			if neverRun[i] {
+				remaining = append(remaining, repos[i])
+			}
+		}
+		if len(remaining) > 0 {
+			if err := r.writeCheckpoint(remaining); err != nil {
+				return 0, errors.WithMessage(err, "could not write the checkpoint")
+			}
+			log.Infof("The %d repositories that were never run were saved to %s, the run can be continued with --from-plan", len(remaining), r.CheckpointPath)
+		}
+	}
+
+	return int(failed.Load()), nil
+}
+
+// verifyPlatformSupport verifies that the platform supports everything the run is configured to do
+func (r *Runner) verifyPlatformSupport() error {
+	if r.SkipConflictingPullRequests {
+		if _, ok := r.VersionController.(conflictingPullRequestsGetter); !ok {
+			return errors.New("the platform does not support finding conflicting pull requests")
+		}
+	}
+
+	if r.TrackingIssueRepository != "" || r.IssueFallback {
+		if err := verifyTrackingIssueSupport(r.VersionController); err != nil {
+			return err
+		}
+	}
+
+	if r.ReportRequiredChecks {
+		if _, ok := r.VersionController.(requiredStatusChecksGetter); !ok {
+			return errors.New("the platform does not support fetching required status checks")
+		}
+	}
+
+	if _, ok := r.VersionController.(patchSubmitter); ok && r.PatchDir == "" && !r.SkipPullRequest && !r.PushOnly {
+		return errors.New("the platform submits changes as patches, and requires a patch directory to be set")
+	}
+
+	if r.FullBodyComment || r.SummaryComment {
+		if _, ok := r.VersionController.(pullRequestCommenter); !ok {
+			return errors.New("the platform does not support commenting on pull requests")
+		}
+	}
+
+	if r.ReopenClosed {
+		if _, ok := r.VersionController.(pullRequestReopener); !ok {
+			return errors.New("the platform does not support reopening closed pull requests")
+		}
+	}
+
+	if r.RerequestReviews {
+		if _, ok := r.VersionController.(reviewRerequester); !ok {
+			return errors.New("the platform does not support re-requesting reviews")
+		}
+	}
+
+	if r.ResetApprovals {
+		if _, ok := r.VersionController.(approvalResetter); !ok {
+			return errors.New("the platform does not support resetting approvals")
+		}
+	}
+
+	if r.TriggerPipeline {
+		if _, ok := r.VersionController.(pipelineTriggerer); !ok {
+			return errors.New("the platform does not support triggering pipelines")
+		}
+	}
+
+	if r.CheckRunName != "" {
+		if _, ok := r.VersionController.(checkRunCreator); !ok {
+			return errors.New("the platform does not support check runs")
+		}
+	}
+
+	if r.StatusContext != "" {
+		if _, ok := r.VersionController.(commitStatusSetter); !ok {
+			return errors.New("the platform does not support commit statuses")
+		}
+	}
+
+	if len(r.BaseBranchRules) > 0 {
+		if _, ok := r.VersionController.(branchExistChecker); !ok {
+			return errors.New("the platform does not support base branch rules")
+		}
+	}
+
+	if len(r.BackportBranches) > 0 {
+		if _, ok := r.VersionController.(branchExistChecker); !ok {
+			return errors.New("the platform does not support backport branches")
+		}
+	}
+	for _, branch := range r.BackportBranches {
+		if !isBranchPattern(branch) {
+			continue
+		}
+		if _, err := path.Match(branch, ""); err != nil {
+			return errors.Errorf("invalid backport branch pattern %q", branch)
+		}
+		if _, ok := r.VersionController.(branchLister); !ok {
+			return errors.New("the platform does not support backport branch patterns")
+		}
+	}
+
+	if r.Campaign != "" {
+		if _, ok := r.VersionController.(campaignPullRequestFinder); !ok {
+			return errors.New("the platform does not support finding the pull requests of a campaign")
+		}
+	}
+
+	// Platforms that can represent drafts are able to change the draft state of existing pull requests
+	if _, ok := r.VersionController.(draftSetter); r.Draft && !ok {
+		log.Warn("The platform does not support draft pull requests, pull requests will be created as ready for review")
+	}
 
 	return nil
 }
@@ -214,6 +596,17 @@ func runInParallel(fun func(i int), total int, maxConcurrent int) {
 	wg.Wait()
 }
 
+// baseBranch returns the branch the changes of a repository are based on
+func (r *Runner) baseBranch(repo scm.Repository) string {
+	if branch, ok := r.ruleBaseBranches[repo.FullName()]; ok {
+		return branch
+	}
+	if r.BaseBranch != "" {
+		return r.BaseBranch
+	}
+	return repo.DefaultBranch()
+}
+
 func getReviewers(reviewers []string, maxReviewers int) []string {
 	if maxReviewers == 0 || len(reviewers) <= maxReviewers {
 		return reviewers
@@ -224,35 +617,70 @@ func getReviewers(reviewers []string, maxReviewers int) []string {
 	return reviewers[0:maxReviewers]
 }
 
-func (r *Runner) runSingleRepo(ctx context.Context, repo scm.Repository) (scm.PullRequest, error) {
-	if ctx.Err() != nil {
+func (r *Runner) runSingleRepo(ctx context.Context, repo scm.Repository) (_ scm.PullRequest, err error) {
+	if ctx.Err() != nil || r.stop.stopped() {
 		return nil, errAborted
 	}
 
 	log := log.WithField("repo", repo.FullName())
 	log.Info("Cloning and running script")
-	tmpDir, err := createTempDir(r.CloneDir)
-
-	defer os.RemoveAll(tmpDir)
+	r.progress.setPhase(repo.FullName(), phaseCloning)
+	r.timings.setPhase(repo.FullName(), phaseCloning)
+	cloneDir, release, err := r.diskSpace.acquire(ctx)
 	if err != nil {
+		if ctx.Err() != nil {
+			return nil, errAborted
+		}
 		return nil, err
 	}
 
-	sourceController := r.CreateGit(tmpDir)
-
-	baseBranch := r.BaseBranch
-	if baseBranch == "" {
-		baseBranch = repo.DefaultBranch()
+	tmpDir, err := createRepositoryDir(cloneDir, r.FeatureBranch, repo)
+	if err != nil {
+		release(tmpDir)
+		return nil, err
 	}
+	defer func() {
+		if r.KeepFailedClones && isFailure(err) {
+			log.Infof("The clone was kept at %s", tmpDir)
+			r.keptClones.add(repo.FullName(), tmpDir)
+		} else {
+			removeRepositoryDir(cloneDir, tmpDir)
+		}
+		release(tmpDir)
+	}()
 
+	baseBranch := r.baseBranch(repo)
 	if baseBranch == r.FeatureBranch {
 		return nil, errors.Errorf("both the feature branch and base branch was named %s, if you intended to push directly into the base branch, please use the `skip-pr` option", baseBranch)
 	}
 
-	err = sourceController.Clone(ctx, repo.CloneURL(), baseBranch)
+	sourceController, err := cloneRepository(ctx, log, r.CreateGit, tmpDir, repo, baseBranch)
 	if err != nil {
 		return nil, err
 	}
+	r.diskSpace.track(tmpDir)
+
+	// The owners are read before the script is run, since the script could change them
+	if r.TeamsFromCodeOwners {
+		team, err := codeOwnersTeam(tmpDir)
+		if err != nil {
+			log.Warnf("Could not read the CODEOWNERS file: %s", err)
+		}
+		r.codeOwnerTeams.set(repo.FullName(), team)
+	}
+
+	var ownerRules []teamRule
+	if r.CheckReviewerOwnership {
+		rules, err := codeOwnerRules(tmpDir)
+		if err != nil {
+			log.Warnf("Could not read the CODEOWNERS file: %s", err)
+		}
+		ownerRules = rules
+	}
+
+	if err := r.checkoutBaseRef(ctx, log, repo, baseBranch, sourceController); err != nil {
+		return nil, err
+	}
 
 	// Change the branch to the feature branch
 	if !r.SkipPullRequest {
@@ -262,33 +690,94 @@ func (r *Runner) runSingleRepo(ctx context.Context, repo scm.Repository) (scm.Pu
 		}
 	}
 
-	cmd := prepareScriptCommand(ctx, repo, tmpDir, r.ScriptPath, r.Arguments)
+	cmd := prepareScriptCommand(ctx, repo, tmpDir, r.ScriptPath, r.Arguments, r.ScriptEnv)
 	if r.DryRun {
 		cmd.Env = append(cmd.Env, "DRY_RUN=true")
 	}
+	pullRequestFile := scriptPullRequestPath(tmpDir)
+	_ = os.Remove(pullRequestFile)
+	defer os.Remove(pullRequestFile)
+	cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", pullRequestFileEnv, pullRequestFile))
 
 	// Setup logger that transfers stdout and stderr from the run to logs
 	writer := logger.NewLogger(log)
+	if r.ScriptOutputLimit > 0 {
+		writer = logger.NewLimitedLogger(log, r.ScriptOutputLimit)
+	}
 	defer writer.Close()
-	cmd.Stdout = writer
-	cmd.Stderr = writer
+	outputs := []io.Writer{writer}
+
+	// Keep a copy of the output next to the clone, in case the clone is kept
+	if r.KeepFailedClones {
+		scriptLog, err := os.Create(scriptLogPath(tmpDir))
+		if err != nil {
+			return nil, err
+		}
+		defer scriptLog.Close()
+		outputs = append(outputs, scriptLog)
+	}
 
+	if r.ScriptOutputDir != "" {
+		scriptOutput, err := createScriptOutputFile(r.ScriptOutputDir, repo)
+		if err != nil {
+			return nil, err
+		}
+		defer scriptOutput.Close()
+		outputs = append(outputs, scriptOutput)
+	}
+
+	cmd.Stdout = io.MultiWriter(outputs...)
+	cmd.Stderr = cmd.Stdout
+
+	r.progress.setPhase(repo.FullName(), phaseScript)
+	r.timings.setPhase(repo.FullName(), phaseScript)
 	err = cmd.Run()
+	_ = writer.Close()
+	var exitErr *exec.ExitError
+	if r.SkipExitCode != 0 && errors.As(err, &exitErr) && exitErr.ExitCode() == r.SkipExitCode {
+		return nil, errScriptSkipped
+	}
 	if err != nil {
 		return nil, transformExecError(err)
 	}
+	r.progress.setPhase(repo.FullName(), phasePublishing)
+	r.timings.setPhase(repo.FullName(), phasePublishing)
+
+	spr, err := readScriptPullRequest(pullRequestFile)
+	if err != nil {
+		return nil, err
+	}
+	r.scriptPullRequests.set(repo.FullName(), spr)
 
 	if changed, err := sourceController.Changes(); err != nil {
 		return nil, err
 	} else if !changed {
-		return nil, errNoChange
+		return r.handleNoChange(ctx, log, repo, baseBranch)
+	}
+
+	if err := r.resolveRepositoryJiraIssue(ctx, log, repo); err != nil {
+		return nil, err
 	}
 
-	err = sourceController.Commit(r.CommitAuthor, r.CommitMessage)
+	err = sourceController.Commit(r.CommitAuthor, r.commitMessage(repo))
 	if err != nil {
 		return nil, err
 	}
 
+	if ownerRules != nil {
+		r.warnUnownedFiles(log, repo, ownerRules, sourceController)
+	}
+
+	if r.SkipConflictingPullRequests {
+		conflictingPR, err := r.findConflictingPullRequest(ctx, repo, sourceController)
+		if err != nil {
+			return nil, err
+		}
+		if conflictingPR != nil {
+			return conflictingPR, errConflictingPR
+		}
+	}
+
 	if r.Interactive {
 		err = r.interactive(tmpDir, repo)
 		if err != nil {
@@ -296,6 +785,20 @@ func (r *Runner) runSingleRepo(ctx context.Context, repo scm.Repository) (scm.Pu
 		}
 	}
 
+	_, isPatchSubmitter := r.VersionController.(patchSubmitter)
+	if r.SplitDiffLines > 0 && !r.SkipPullRequest && !r.PushOnly && !isPatchSubmitter {
+		parts, err := splitChanges(sourceController, r.SplitDiffLines)
+		if err != nil {
+			return nil, err
+		}
+		if parts != nil {
+			log.Infof("The changes exceed %d lines, and are split into %d pull requests by top-level directory", r.SplitDiffLines, len(parts))
+			if !r.DryRun {
+				return r.publishSplitChanges(ctx, log, repo, cloneDir, tmpDir, baseBranch, parts)
+			}
+		}
+	}
+
 	if r.DryRun {
 		log.Info("Skipping pushing changes because of dry run")
 		return dryRunPullRequest{
@@ -303,6 +806,23 @@ func (r *Runner) runSingleRepo(ctx context.Context, repo scm.Repository) (scm.Pu
 		}, nil
 	}
 
+	if submitter, ok := r.VersionController.(patchSubmitter); ok && !r.SkipPullRequest && !r.PushOnly {
+		return nil, r.writePatch(log, repo, sourceController, submitter)
+	}
+
+	// Only errors that will not go away by themselves fall back to an issue, so that a temporary problem, like the
+	// platform being unavailable, does not leave issues behind next to the pull requests of later runs
+	pr, err := r.publishChanges(ctx, log, repo, sourceController, baseBranch)
+	var notPermitted *scm.PullRequestNotPermittedError
+	if errors.As(err, &notPermitted) && pr == nil && r.IssueFallback && !r.SkipPullRequest && !r.PushOnly {
+		return nil, r.createFallbackIssue(ctx, log, repo, sourceController, baseBranch, err)
+	}
+	return pr, err
+}
+
+// publishChanges pushes the committed changes and creates a pull request with them
+func (r *Runner) publishChanges(ctx context.Context, log log.FieldLogger, repo scm.Repository, sourceController Git, baseBranch string) (scm.PullRequest, error) {
+	var err error
 	remoteName := "origin"
 	prRepo := repo
 	if r.Fork {
@@ -320,6 +840,13 @@ func (r *Runner) runSingleRepo(ctx context.Context, repo scm.Repository) (scm.Pu
 		remoteName = "fork"
 	}
 
+	if r.Campaign != "" && !r.SkipPullRequest && !r.PushOnly {
+		pr, err := r.updateRenamedPullRequest(ctx, log, repo, prRepo, sourceController, remoteName, baseBranch)
+		if pr != nil || err != nil {
+			return pr, err
+		}
+	}
+
 	// Determine if a branch already exists and (depending on the conflict strategy) skip making changes
 	featureBranchExist := false
 	if !r.SkipPullRequest && !r.PushOnly {
@@ -327,7 +854,7 @@ func (r *Runner) runSingleRepo(ctx context.Context, repo scm.Repository) (scm.Pu
 		if err != nil {
 			return nil, errors.Wrap(err, "could not verify if branch already exists")
 		} else if featureBranchExist && r.ConflictStrategy == ConflictStrategySkip {
-			pr, err := r.ensurePullRequestExists(ctx, log, repo, prRepo, baseBranch, featureBranchExist)
+			pr, err := r.ensurePullRequestExists(ctx, log, repo, prRepo, sourceController, baseBranch, featureBranchExist)
 			if err != nil {
 				return nil, err
 			}
@@ -349,17 +876,81 @@ func (r *Runner) runSingleRepo(ctx context.Context, repo scm.Repository) (scm.Pu
 		}, nil
 	}
 
-	return r.ensurePullRequestExists(ctx, log, repo, prRepo, baseBranch, featureBranchExist)
+	return r.ensurePullRequestExists(ctx, log, repo, prRepo, sourceController, baseBranch, featureBranchExist)
+}
+
+// requiredStatusChecks fetches the status checks that has to pass before a pull request can be merged into the base branch
+func (r *Runner) requiredStatusChecks(ctx context.Context, repo scm.Repository) ([]string, error) {
+	return r.VersionController.(requiredStatusChecksGetter).GetRequiredStatusChecks(ctx, repo, r.baseBranch(repo))
+}
+
+// findConflictingPullRequest finds any open pull request, not made by this run, that changes the same files as the run did
+func (r *Runner) findConflictingPullRequest(ctx context.Context, repo scm.Repository, sourceController Git) (scm.PullRequest, error) {
+	files, err := sourceController.ChangedFiles()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get changed files")
+	}
+
+	prs, err := r.VersionController.(conflictingPullRequestsGetter).GetConflictingPullRequests(ctx, repo, r.FeatureBranch, files)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get conflicting pull requests")
+	}
+	if len(prs) == 0 {
+		return nil, nil
+	}
+
+	return prs[0], nil
+}
+
+// handleNoChange is called when the script did not make any changes to a repository. If configured to, any existing
+// pull request is closed, since the problem it was addressing does no longer exist. Otherwise it is kept, but retargeted
+// if its base branch has been removed
+func (r *Runner) handleNoChange(ctx context.Context, log log.FieldLogger, repo scm.Repository, baseBranch string) (scm.PullRequest, error) {
+	if r.SkipPullRequest || r.PushOnly {
+		return nil, errNoChange
+	}
+	if !r.CloseObsolete {
+		return nil, r.retargetUnchangedPullRequest(ctx, log, repo, baseBranch)
+	}
+
+	pr, err := r.VersionController.GetOpenPullRequest(ctx, repo, r.FeatureBranch)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch obsolete pull request")
+	}
+	if pr == nil {
+		return nil, errNoChange
+	}
+
+	if r.DryRun {
+		log.Info("Skipping closing obsolete pull request because of dry run")
+		return pr, errObsoleteOpen
+	}
+
+	log.Info("Closing obsolete pull request")
+	if err := r.VersionController.ClosePullRequest(ctx, pr); err != nil {
+		return pr, errors.Wrap(err, "could not close obsolete pull request")
+	}
+
+	return pr, errObsoleteClosed
 }
 
-func (r *Runner) ensurePullRequestExists(ctx context.Context, log log.FieldLogger, repo scm.Repository, prRepo scm.Repository, baseBranch string, featureBranchExist bool) (scm.PullRequest, error) {
+func (r *Runner) ensurePullRequestExists(
+	ctx context.Context,
+	log log.FieldLogger,
+	repo scm.Repository,
+	prRepo scm.Repository,
+	sourceController Git,
+	baseBranch string,
+	featureBranchExist bool,
+) (scm.PullRequest, error) {
 	if r.SkipPullRequest {
 		return nil, nil
 	}
 
-	// Fetching any potentially existing pull request
+	// Fetching any potentially existing pull request. A pull request can be open even if the branch did not exist, like
+	// when it was opened by other tooling and the branch was deleted afterwards
 	var existingPullRequest scm.PullRequest
-	if featureBranchExist {
+	if featureBranchExist || !r.SkipAdoption {
 		pr, err := r.VersionController.GetOpenPullRequest(ctx, repo, r.FeatureBranch)
 		if err != nil {
 			return nil, err
@@ -367,27 +958,148 @@ func (r *Runner) ensurePullRequestExists(ctx context.Context, log log.FieldLogge
 		existingPullRequest = pr
 	}
 
+	// Adopted and reopened pull requests are always updated, since they do not contain the pushed changes in their description
+	forceUpdate := false
+	if existingPullRequest != nil && !featureBranchExist {
+		log.Infof("Adopting the open pull request %s, that was made outside of multi-gitter", existingPullRequest.String())
+		forceUpdate = true
+	}
+	if existingPullRequest == nil && r.ReopenClosed {
+		pr, err := r.reopenClosedPullRequest(ctx, log, repo)
+		if err != nil {
+			return nil, err
+		}
+		existingPullRequest = pr
+		forceUpdate = pr != nil
+	}
+	if existingPullRequest != nil {
+		if err := r.retargetPullRequest(ctx, log, repo, existingPullRequest, baseBranch); err != nil {
+			return existingPullRequest, err
+		}
+		if r.ConflictStrategy != ConflictStrategyReplace && !forceUpdate {
+			log.Info("Skip creating pull requests since one is already open")
+			return existingPullRequest, nil
+		}
+	}
+
+	// Reviewers are picked only once it is known that the pull request is created or updated, so that skipped
+	// repositories do not count towards the limit of pull requests per reviewer
+	newPR, err := r.newPullRequest(repo, sourceController, baseBranch)
+	if err != nil {
+		return nil, err
+	}
+	if r.reviewerLoad != nil {
+		picked := r.reviewerLoad.pick(log, r.Reviewers, r.MaxReviewers)
+		newPR.Reviewers = picked
+		newPR = r.scriptPullRequests.get(repo.FullName()).apply(newPR)
+		// The reviewers added by the script, which come after the picked ones, count towards the limit as well
+		r.reviewerLoad.count(newPR.Reviewers[len(picked):])
+	} else {
+		newPR = r.scriptPullRequests.get(repo.FullName()).apply(newPR)
+	}
+
+	fullBody := newPR.Body
+	body, truncated := truncateBody(newPR.Body, r.maxBodyLength(), r.BodyTruncationMarker)
+	if truncated {
+		newPR.Body = body
+		log.Warn("The pull request body exceeds the length limit of the platform and was truncated")
+	}
+
+	var pr scm.PullRequest
 	if existingPullRequest != nil {
-		if r.ConflictStrategy == ConflictStrategyReplace {
-			log.Info("Updating pull request since one is already open")
-			return r.VersionController.UpdatePullRequest(ctx, repo, existingPullRequest, scm.NewPullRequest{
-				Title:         r.PullRequestTitle,
-				Body:          r.PullRequestBody,
-				Head:          r.FeatureBranch,
-				Base:          baseBranch,
-				Reviewers:     getReviewers(r.Reviewers, r.MaxReviewers),
-				TeamReviewers: getReviewers(r.TeamReviewers, r.MaxTeamReviewers),
-				Assignees:     r.Assignees,
-				Draft:         r.Draft,
-				Labels:        r.Labels,
-			})
-		}
-		log.Info("Skip creating pull requests since one is already open")
-		return existingPullRequest, nil
-	}
-
-	log.Info("Creating pull request")
-	return r.VersionController.CreatePullRequest(ctx, repo, prRepo, scm.NewPullRequest{
+		log.Info("Updating pull request since one is already open")
+		pr, err = r.VersionController.UpdatePullRequest(ctx, repo, existingPullRequest, newPR)
+		if err == nil && r.RerequestReviews {
+			err = r.rerequestReviews(ctx, log, pr)
+		}
+		if err == nil && r.ResetApprovals {
+			err = r.resetApprovals(ctx, log, pr)
+		}
+	} else {
+		log.Info("Creating pull request")
+		pr, err = r.VersionController.CreatePullRequest(ctx, repo, prRepo, newPR)
+	}
+	if err != nil {
+		// The reviewers are only counted for pull requests that were made, and not if only re-requesting reviews failed
+		if pr == nil && r.reviewerLoad != nil {
+			r.reviewerLoad.uncount(newPR.Reviewers)
+		}
+		return nil, err
+	}
+
+	if truncated && r.FullBodyComment {
+		if err := r.commentFullBody(ctx, log, pr, fullBody); err != nil {
+			return pr, err
+		}
+	}
+
+	// Nothing was pushed if the branch already existed and was kept, so the commit made in the clone does not exist on the
+	// platform, and there are no changes of the run to describe
+	pushed := !featureBranchExist || r.ConflictStrategy != ConflictStrategySkip
+
+	if r.SummaryComment && pushed {
+		if err := r.commentSummary(ctx, log, pr, sourceController); err != nil {
+			return pr, err
+		}
+	}
+
+	if r.CheckRunName != "" && pushed {
+		if err := r.createCheckRun(ctx, log, pr, sourceController); err != nil {
+			return pr, err
+		}
+	}
+
+	if r.StatusContext != "" && pushed {
+		if err := r.setCommitStatus(ctx, log, pr, sourceController); err != nil {
+			return pr, err
+		}
+	}
+
+	if r.TriggerPipeline {
+		if err := r.triggerPipeline(ctx, log, pr); err != nil {
+			return pr, err
+		}
+	}
+
+	return pr, nil
+}
+
+// newPullRequest creates the pull request data for a repository, with any repository specific overrides applied
+func (r *Runner) newPullRequest(repo scm.Repository, sourceController Git, baseBranch string) (scm.NewPullRequest, error) {
+	newPR := r.pullRequestValues(repo, baseBranch)
+
+	if r.Campaign != "" {
+		newPR.Body = addCampaignMarker(newPR.Body, r.Campaign)
+	}
+
+	if !r.PullRequestDiffSummary && r.SizeLabelThresholds == nil {
+		return newPR, nil
+	}
+
+	diff, err := sourceController.Diff()
+	if err != nil {
+		return scm.NewPullRequest{}, errors.Wrap(err, "could not get the diff of the changes")
+	}
+
+	if r.PullRequestDiffSummary {
+		if summary := diffSummary(diff); summary != "" {
+			if newPR.Body != "" {
+				newPR.Body += "\n\n"
+			}
+			newPR.Body += summary
+		}
+	}
+
+	if r.SizeLabelThresholds != nil {
+		newPR.Labels = append(append([]string{}, newPR.Labels...), sizeLabel(diff, r.SizeLabelThresholds))
+	}
+
+	return newPR, nil
+}
+
+// pullRequestValues returns the values of the pull request of a repository that does not depend on the changes made
+func (r *Runner) pullRequestValues(repo scm.Repository, baseBranch string) scm.NewPullRequest {
+	newPR := scm.NewPullRequest{
 		Title:         r.PullRequestTitle,
 		Body:          r.PullRequestBody,
 		Head:          r.FeatureBranch,
@@ -397,7 +1109,42 @@ func (r *Runner) ensurePullRequestExists(ctx context.Context, log log.FieldLogge
 		Assignees:     r.Assignees,
 		Draft:         r.Draft,
 		Labels:        r.Labels,
-	})
+		KeepReviewers: r.KeepReviewers,
+	}
+
+	if override, ok := r.PullRequestOverrides[repo.FullName()]; ok {
+		if override.Title != "" {
+			newPR.Title = override.Title
+		}
+		if override.Body != "" {
+			newPR.Body = override.Body
+		}
+		if override.Labels != nil {
+			newPR.Labels = override.Labels
+		}
+	}
+
+	// Telling which part of a monorepo is changed helps reviewers that are responsible for only some of it
+	if len(r.Paths) > 0 {
+		newPR.Title = strings.Join(r.Paths, ", ") + ": " + newPR.Title
+		newPR.Body = strings.TrimLeft(newPR.Body+"\n\nThe changes are limited to "+formatPaths(r.Paths)+".", "\n")
+	}
+
+	// Referencing the Jira issue in the title and body links the pull request to the issue
+	if key := r.jiraIssue(repo); key != "" {
+		newPR.Title = key + " " + newPR.Title
+		newPR.Body = strings.TrimLeft(newPR.Body+"\n\nJira issue: ["+key+"]("+r.Jira.IssueURL(key)+")", "\n")
+	}
+
+	// Decorations are added to overridden titles as well, since they are usually required by the organization
+	if r.PullRequestTitlePrefix != "" {
+		newPR.Title = r.PullRequestTitlePrefix + " " + newPR.Title
+	}
+	if r.PullRequestTitleSuffix != "" {
+		newPR.Title = newPR.Title + " " + r.PullRequestTitleSuffix
+	}
+
+	return newPR
 }
 
 var interactiveInfo = `(V)iew changes. (A)ccept or (R)eject`
@@ -444,3 +1191,15 @@ func (r *Runner) interactive(dir string, repo scm.Repository) error {
 		}
 	}
 }
+
+// formatPaths formats the paths as markdown code in a sentence, like "`a`, `b` and `c`"
+func formatPaths(paths []string) string {
+	quoted := make([]string, len(paths))
+	for i, p := range paths {
+		quoted[i] = "`" + p + "`"
+	}
+	if len(quoted) == 1 {
+		return quoted[0]
+	}
+	return strings.Join(quoted[:len(quoted)-1], ", ") + " and " + quoted[len(quoted)-1]
+}
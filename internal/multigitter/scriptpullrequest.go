@@ -0,0 +1,115 @@
+package multigitter
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+)
+
+// pullRequestFileEnv is the environment variable with the path of the file where scripts can write pull request metadata
+const pullRequestFileEnv = "PULL_REQUEST_FILE"
+
+// scriptPullRequest is the pull request metadata that a script can write, as json, to the file in $PULL_REQUEST_FILE.
+// It's added to the pull request of that specific repository, which makes it possible to, for example, label pull requests
+// based on what the script found in the repository
+type scriptPullRequest struct {
+	Labels        []string `json:"labels"`
+	Reviewers     []string `json:"reviewers"`
+	TeamReviewers []string `json:"teamReviewers"`
+	Assignees     []string `json:"assignees"`
+	TitleSuffix   string   `json:"titleSuffix"` // Added after the title, separated by a space
+	Body          string   `json:"body"`        // Added after the body, separated by an empty line
+}
+
+func scriptPullRequestPath(dir string) string {
+	return dir + ".pull-request.json"
+}
+
+// readScriptPullRequest reads the pull request metadata written by a script, nil is returned if the script did not write any
+func readScriptPullRequest(path string) (*scriptPullRequest, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var spr scriptPullRequest
+	if err := decoder.Decode(&spr); err != nil {
+		return nil, errors.Wrapf(err, "could not parse the pull request file written by the script")
+	}
+	return &spr, nil
+}
+
+// apply adds the metadata to the pull request
+func (spr *scriptPullRequest) apply(newPR scm.NewPullRequest) scm.NewPullRequest {
+	if spr == nil {
+		return newPR
+	}
+
+	newPR.Labels = appendMissing(newPR.Labels, spr.Labels)
+	newPR.Reviewers = appendMissing(newPR.Reviewers, spr.Reviewers)
+	newPR.TeamReviewers = appendMissing(newPR.TeamReviewers, spr.TeamReviewers)
+	newPR.Assignees = appendMissing(newPR.Assignees, spr.Assignees)
+	if spr.TitleSuffix != "" {
+		newPR.Title += " " + spr.TitleSuffix
+	}
+	if spr.Body != "" {
+		if newPR.Body != "" {
+			newPR.Body += "\n\n"
+		}
+		newPR.Body += spr.Body
+	}
+	return newPR
+}
+
+// appendMissing returns a new slice with the values added to the existing ones, unless they already are in it
+func appendMissing(existing, values []string) []string {
+	result := append([]string{}, existing...)
+	for _, v := range values {
+		found := false
+		for _, e := range result {
+			found = found || e == v
+		}
+		if !found {
+			result = append(result, v)
+		}
+	}
+	if len(result) == 0 {
+		return existing
+	}
+	return result
+}
+
+// scriptPullRequests keeps track of the pull request metadata written by scripts, by repository name
+type scriptPullRequests struct {
+	lock sync.RWMutex
+	prs  map[string]*scriptPullRequest
+}
+
+func (sprs *scriptPullRequests) set(repoName string, spr *scriptPullRequest) {
+	sprs.lock.Lock()
+	defer sprs.lock.Unlock()
+	if sprs.prs == nil {
+		sprs.prs = map[string]*scriptPullRequest{}
+	}
+	sprs.prs[repoName] = spr
+}
+
+func (sprs *scriptPullRequests) get(repoName string) *scriptPullRequest {
+	if sprs == nil {
+		return nil
+	}
+	sprs.lock.RLock()
+	defer sprs.lock.RUnlock()
+	return sprs.prs[repoName]
+}
@@ -0,0 +1,51 @@
+package multigitter
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+)
+
+// Shard is one of several parts the repositories of a run are divided into, so that the parts can be run on different machines
+type Shard struct {
+	Index int // Which part, starting at 1
+	Count int // The total number of parts
+}
+
+// ParseShard parses a shard in the format "index/count", like "2/5"
+func ParseShard(str string) (Shard, error) {
+	indexStr, countStr, found := strings.Cut(str, "/")
+	index, indexErr := strconv.Atoi(indexStr)
+	count, countErr := strconv.Atoi(countStr)
+	if !found || indexErr != nil || countErr != nil {
+		return Shard{}, fmt.Errorf("could not parse \"%s\" as shard, it should be in the format index/count", str)
+	}
+	if count < 1 || index < 1 || index > count {
+		return Shard{}, fmt.Errorf("the shard index has to be between 1 and %d", count)
+	}
+	return Shard{Index: index, Count: count}, nil
+}
+
+// contains returns if a repository belongs to the shard. The same repository always belongs to the same shard,
+// regardless of which other repositories exist
+func (s Shard) contains(repoName string) bool {
+	if s.Count <= 1 {
+		return true
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(repoName))
+	return int(hash.Sum32()%uint32(s.Count)) == s.Index-1
+}
+
+func filterShard(repos []scm.Repository, shard Shard) []scm.Repository {
+	filteredRepos := make([]scm.Repository, 0, len(repos))
+	for _, repo := range repos {
+		if shard.contains(repo.FullName()) {
+			filteredRepos = append(filteredRepos, repo)
+		}
+	}
+	return filteredRepos
+}
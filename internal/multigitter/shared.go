@@ -3,12 +3,16 @@ package multigitter
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"syscall"
 
 	"github.com/lindell/multi-gitter/internal/git"
+	"github.com/lindell/multi-gitter/internal/scm"
 	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
 )
 
 type urler interface {
@@ -28,12 +32,73 @@ func transformExecError(err error) error {
 // Git is a git implementation
 type Git interface {
 	Clone(ctx context.Context, url string, baseName string) error
+	CheckoutRef(ctx context.Context, remoteName, ref string) error
 	ChangeBranch(branchName string) error
 	Changes() (bool, error)
 	Commit(commitAuthor *git.CommitAuthor, commitMessage string) error
+	ChangedFiles() ([]string, error)
+	Diff() (string, error)
+	FormatPatch(subjectPrefix string) (string, error)
 	BranchExist(remoteName, branchName string) (bool, error)
 	Push(ctx context.Context, remoteName string, force bool) error
 	AddRemote(name, url string) error
+	HeadCommit() (string, error)
+}
+
+// fallbackCloneURLer is implemented by repositories that have a second clone url that can be used if cloning fails
+type fallbackCloneURLer interface {
+	FallbackCloneURL() string
+}
+
+// cloneRepository clones a repository into the directory. If the clone fails, and the repository has a fallback
+// clone url (for example when the clone protocol is set to auto), a new clone attempt is made with that url
+func cloneRepository(ctx context.Context, log log.FieldLogger, createGit func(dir string) Git, dir string, repo scm.Repository, baseBranch string) (Git, error) {
+	fallbacker, hasFallback := repo.(fallbackCloneURLer)
+	hasFallback = hasFallback && fallbacker.FallbackCloneURL() != ""
+
+	sourceController := createGit(dir)
+	err := sourceController.Clone(ctx, repo.CloneURL(), baseBranch)
+	if err == nil {
+		if hasFallback {
+			log.Infof("Cloned using %s", cloneURLProtocol(repo.CloneURL()))
+		}
+		return sourceController, nil
+	} else if !hasFallback {
+		return nil, err
+	}
+
+	log.Infof("Could not clone using %s, falling back to %s: %s",
+		cloneURLProtocol(repo.CloneURL()), cloneURLProtocol(fallbacker.FallbackCloneURL()), err)
+
+	// Make sure nothing from the failed clone is left in the directory
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	sourceController = createGit(dir)
+	if err := sourceController.Clone(ctx, fallbacker.FallbackCloneURL(), baseBranch); err != nil {
+		return nil, err
+	}
+	log.Infof("Cloned using %s", cloneURLProtocol(fallbacker.FallbackCloneURL()))
+
+	return sourceController, nil
+}
+
+// cloneURLProtocol returns a description of the protocol used by a clone url, without exposing any credentials
+func cloneURLProtocol(cloneURL string) string {
+	if i := strings.Index(cloneURL, "://"); i != -1 {
+		return cloneURL[:i]
+	}
+	return "ssh"
+}
+
+// conflictingPullRequestsGetter is implemented by version controllers that can find open pull requests
+// that change the same files as a run
+type conflictingPullRequestsGetter interface {
+	GetConflictingPullRequests(ctx context.Context, repo scm.Repository, branchName string, files []string) ([]scm.PullRequest, error)
 }
 
 type stackTracer interface {
@@ -98,6 +163,38 @@ func createTempDir(cloneDir string) (string, error) {
 	return tmpDir, nil
 }
 
+// createRepositoryDir creates a directory for a repository in the directory of the run, named after the feature branch
+// and the repository, so that kept clones are easy to find
+func createRepositoryDir(root string, branch string, repo scm.Repository) (string, error) {
+	// Escaping the branch keeps branches like "a/b" and "a-b" apart
+	dir := filepath.Join(root, url.PathEscape(branch), filepath.FromSlash(repo.FullName()))
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// removeRepositoryDir removes a directory created by createRepositoryDir, and any of its parents in the directory of
+// the run that are left empty
+func removeRepositoryDir(root string, dir string) {
+	_ = os.RemoveAll(dir)
+	_ = os.Remove(scriptLogPath(dir))
+	for parent := filepath.Dir(dir); strings.HasPrefix(parent, root+string(filepath.Separator)); parent = filepath.Dir(parent) {
+		if os.Remove(parent) != nil {
+			return
+		}
+	}
+}
+
+// createScriptOutputFile creates the file the full output of the script run on a repository is written to
+func createScriptOutputFile(outputDir string, repo scm.Repository) (*os.File, error) {
+	path := filepath.Join(outputDir, filepath.FromSlash(repo.FullName())+".log")
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	return os.Create(path)
+}
+
 func createDirectoryIfDoesntExist(directoryPath string) error {
 	// Check if the directory exists
 	if _, err := os.Stat(directoryPath); !os.IsNotExist(err) {
@@ -0,0 +1,38 @@
+package multigitter
+
+import (
+	"github.com/pkg/errors"
+)
+
+// sizeLabelNames are the labels added to pull requests to tell the size of their changes, from smallest to largest
+var sizeLabelNames = []string{"size/XS", "size/S", "size/M", "size/L", "size/XL", "size/XXL"}
+
+// DefaultSizeLabelThresholds are the number of changed lines where each size label, after the smallest, starts
+var DefaultSizeLabelThresholds = []int{10, 30, 100, 500, 1000}
+
+// ValidateSizeLabelThresholds verifies that there is one threshold for every size label after the smallest, in ascending order
+func ValidateSizeLabelThresholds(thresholds []int) error {
+	if len(thresholds) != len(sizeLabelNames)-1 {
+		return errors.Errorf("there has to be exactly %d size label thresholds, one for each of %v", len(sizeLabelNames)-1, sizeLabelNames[1:])
+	}
+	for i := 1; i < len(thresholds); i++ {
+		if thresholds[i] <= thresholds[i-1] {
+			return errors.New("the size label thresholds have to be in ascending order")
+		}
+	}
+	return nil
+}
+
+// sizeLabel returns the size label of a diff
+func sizeLabel(diff string, thresholds []int) string {
+	changedLines := 0
+	for _, f := range parseDiff(diff) {
+		changedLines += f.additions + f.deletions
+	}
+
+	i := 0
+	for i < len(thresholds) && changedLines >= thresholds[i] {
+		i++
+	}
+	return sizeLabelNames[i]
+}
@@ -0,0 +1,204 @@
+package multigitter
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// changePart is the part of the changes to a repository that is made in a single top-level directory
+type changePart struct {
+	dir   string // The top-level directory, empty for files in the root of the repository
+	files []string
+}
+
+// splitChanges splits the committed changes by top-level directory, if more than maxLines lines were changed.
+// Nil is returned if the changes should not be split
+func splitChanges(sourceController Git, maxLines int) ([]changePart, error) {
+	diff, err := sourceController.Diff()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get the diff of the changes")
+	}
+
+	files := parseDiff(diff)
+	changedLines := 0
+	for _, f := range files {
+		changedLines += f.additions + f.deletions
+	}
+	if changedLines <= maxLines {
+		return nil, nil
+	}
+
+	byDir := map[string][]string{}
+	for _, f := range files {
+		dir := ""
+		if i := strings.Index(f.path, "/"); i != -1 {
+			dir = f.path[:i]
+		}
+		byDir[dir] = append(byDir[dir], f.path)
+	}
+	if len(byDir) < 2 {
+		return nil, nil
+	}
+
+	parts := make([]changePart, 0, len(byDir))
+	for dir, files := range byDir {
+		parts = append(parts, changePart{dir: dir, files: files})
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].dir < parts[j].dir })
+	return parts, nil
+}
+
+// splitBranchName returns the name of the branch that a part of the changes is published on
+func splitBranchName(featureBranch string, part changePart) string {
+	name := part.dir
+	if name == "" {
+		name = "root"
+	}
+	name = strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+			return r
+		}
+		return '-'
+	}, name)
+	return featureBranch + "-" + name
+}
+
+// publishSplitChanges publishes every part of the changes on its own branch, with its own pull request.
+// The pull requests refer to each other with the names of their branches. The first pull request is returned
+func (r *Runner) publishSplitChanges(
+	ctx context.Context,
+	log log.FieldLogger,
+	repo scm.Repository,
+	cloneDir string,
+	dir string,
+	baseBranch string,
+	parts []changePart,
+) (scm.PullRequest, error) {
+	branches := make([]string, len(parts))
+	for i, part := range parts {
+		branches[i] = splitBranchName(r.FeatureBranch, part)
+	}
+
+	var firstPR scm.PullRequest
+	var branchExistErr error
+	for i, part := range parts {
+		partLog := log.WithField("branch", branches[i])
+		pr, err := r.publishChangePart(ctx, partLog, repo, cloneDir, dir, baseBranch, part, i, branches)
+		if errors.Is(err, errBranchExist) {
+			branchExistErr = err
+		} else if err != nil {
+			return firstPR, errors.WithMessagef(err, "could not publish the changes on %s", branches[i])
+		}
+		if firstPR == nil {
+			firstPR = pr
+		}
+	}
+
+	if firstPR == nil {
+		return nil, branchExistErr
+	}
+	return firstPR, nil
+}
+
+// publishChangePart publishes a part of the changes, from a new clone of the base branch
+func (r *Runner) publishChangePart(
+	ctx context.Context,
+	log log.FieldLogger,
+	repo scm.Repository,
+	cloneDir string,
+	dir string,
+	baseBranch string,
+	part changePart,
+	index int,
+	branches []string,
+) (scm.PullRequest, error) {
+	partDir, err := createRepositoryDir(cloneDir, branches[index], repo)
+	if err != nil {
+		return nil, err
+	}
+	defer removeRepositoryDir(cloneDir, partDir)
+
+	sourceController, err := cloneRepository(ctx, log, r.CreateGit, partDir, repo, baseBranch)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.checkoutBaseRef(ctx, log, repo, baseBranch, sourceController); err != nil {
+		return nil, err
+	}
+	if err := sourceController.ChangeBranch(branches[index]); err != nil {
+		return nil, err
+	}
+	if err := copyChangedFiles(dir, partDir, part.files); err != nil {
+		return nil, err
+	}
+	if err := sourceController.Commit(r.CommitAuthor, r.commitMessage(repo)); err != nil {
+		return nil, err
+	}
+
+	// The part is numbered, and refers to the other parts, by adding to the pull request values written by the script
+	spr := scriptPullRequest{}
+	if existing := r.scriptPullRequests.get(repo.FullName()); existing != nil {
+		spr = *existing
+	}
+	spr.TitleSuffix = strings.TrimSpace(fmt.Sprintf("%s (%d/%d)", spr.TitleSuffix, index+1, len(branches)))
+	spr.Body = strings.TrimLeft(spr.Body+"\n\n"+splitDescription(index, branches), "\n")
+
+	partRunner := *r
+	partRunner.FeatureBranch = branches[index]
+	partRunner.scriptPullRequests = &scriptPullRequests{}
+	partRunner.scriptPullRequests.set(repo.FullName(), &spr)
+
+	return partRunner.publishChanges(ctx, log, repo, sourceController, baseBranch)
+}
+
+// splitDescription describes how the changes were split, and where the other parts are
+func splitDescription(index int, branches []string) string {
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "The changes were split by top-level directory into %d pull requests, on these branches:\n", len(branches))
+	for i, branch := range branches {
+		if i == index {
+			fmt.Fprintf(sb, "- %s (this pull request)\n", branch)
+		} else {
+			fmt.Fprintf(sb, "- %s\n", branch)
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// copyChangedFiles copies the changed files from one clone to another. Files that don't exist, since they were removed, are removed
+func copyChangedFiles(fromDir, toDir string, files []string) error {
+	for _, file := range files {
+		from := filepath.Join(fromDir, filepath.FromSlash(file))
+		to := filepath.Join(toDir, filepath.FromSlash(file))
+
+		info, err := os.Stat(from)
+		if os.IsNotExist(err) {
+			if err := os.Remove(to); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(from)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(to), 0o700); err != nil {
+			return err
+		}
+		if err := os.WriteFile(to, data, info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
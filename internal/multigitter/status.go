@@ -2,19 +2,54 @@ package multigitter
 
 import (
 	"context"
-	"fmt"
 	"io"
+	"strings"
+	"time"
 
-	"github.com/lindell/multi-gitter/internal/multigitter/terminal"
+	"github.com/lindell/multi-gitter/internal/scm"
 )
 
 // Statuser checks the statuses of pull requests
 type Statuser struct {
 	VersionController VersionController
 
-	Output io.Writer
+	Output    io.Writer
+	Formatter StatusFormatter // The format the statuses are written in, text if not set
 
 	FeatureBranch string
+
+	PlanRepositories []PlannedRepository // If set, only the pull requests of these repositories, usually from a saved plan, are used
+
+	TrackingIssueRepository string // If set, an issue with the status of all pull requests is created, or updated, in this repository
+
+	TeamMapping TeamMapping // If set, the pull requests are grouped by the teams that own their repositories
+
+	Platform string // The name of the platform, included in the statuses
+}
+
+// PullRequestStatus is the status of a pull request, in the same format on all platforms
+type PullRequestStatus struct {
+	Platform   string                  `json:"platform,omitempty"`
+	Repository string                  `json:"repository"`
+	Name       string                  `json:"name"`
+	Team       string                  `json:"team,omitempty"`
+	ID         string                  `json:"id,omitempty"`
+	Number     int                     `json:"number,omitempty"`
+	URL        string                  `json:"url,omitempty"`
+	State      string                  `json:"state"`
+	Checks     string                  `json:"checks,omitempty"`
+	Reviews    []scm.PullRequestReview `json:"reviews,omitempty"`
+	CreatedAt  *time.Time              `json:"createdAt,omitempty"`
+	UpdatedAt  *time.Time              `json:"updatedAt,omitempty"`
+
+	UnsuccessfulChecks []string `json:"unsuccessfulChecks,omitempty"` // The checks that have not passed, in the format "name: state"
+
+	status scm.PullRequestStatus
+}
+
+// detailer is implemented by pull requests on platforms that provide details about them
+type detailer interface {
+	Details() scm.PullRequestDetails
 }
 
 // Statuses checks the statuses of pull requests
@@ -23,14 +58,57 @@ func (s Statuser) Statuses(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	prs, err = filterPlannedPullRequests(ctx, s.VersionController, prs, s.PlanRepositories)
+	if err != nil {
+		return err
+	}
 
+	statuses := make([]PullRequestStatus, 0, len(prs))
 	for _, pr := range prs {
-		if urler, hasURL := pr.(urler); hasURL && urler.URL() != "" {
-			fmt.Fprintf(s.Output, "%s: %s\n", terminal.Link(pr.String(), urler.URL()), pr.Status())
-		} else {
-			fmt.Fprintf(s.Output, "%s: %s\n", pr.String(), pr.Status())
-		}
+		statuses = append(statuses, s.pullRequestStatus(pr))
+	}
+
+	formatter := s.Formatter
+	if formatter == nil {
+		formatter = TextFormatter{}
+	}
+	if err := formatter.Format(s.Output, statuses); err != nil {
+		return err
+	}
+
+	if s.TrackingIssueRepository != "" {
+		return updateTrackingIssue(ctx, s.VersionController, s.TrackingIssueRepository, s.FeatureBranch)
 	}
 
 	return nil
 }
+
+func (s Statuser) pullRequestStatus(pr scm.PullRequest) PullRequestStatus {
+	repoName := pullRequestRepositoryName(pr)
+	status := PullRequestStatus{
+		Platform:   s.Platform,
+		Repository: repoName,
+		Name:       pr.String(),
+		Team:       s.TeamMapping.Team(repoName),
+		State:      strings.ToLower(pr.Status().String()),
+		status:     pr.Status(),
+	}
+	if urler, ok := pr.(urler); ok {
+		status.URL = urler.URL()
+	}
+	if detailer, ok := pr.(detailer); ok {
+		details := detailer.Details()
+		status.ID = details.ID
+		status.Number = details.Number
+		status.Checks = details.Checks
+		status.UnsuccessfulChecks = details.UnsuccessfulChecks
+		status.Reviews = details.Reviews
+		if !details.CreatedAt.IsZero() {
+			status.CreatedAt = &details.CreatedAt
+		}
+		if !details.UpdatedAt.IsZero() {
+			status.UpdatedAt = &details.UpdatedAt
+		}
+	}
+	return status
+}
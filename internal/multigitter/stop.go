@@ -0,0 +1,82 @@
+package multigitter
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// stopConditions stops a run early, by not starting any more repositories, when it has failed on too many
+// repositories or taken too long. Repositories that have already been started are finished
+type stopConditions struct {
+	maxFailures int
+	deadline    time.Time
+	maxDuration time.Duration
+
+	lock      sync.Mutex
+	failures  int
+	reason    string
+	unstarted bool // If any repository was not started because the run was stopped
+}
+
+func newStopConditions(maxFailures int, maxDuration time.Duration) *stopConditions {
+	if maxFailures <= 0 && maxDuration <= 0 {
+		return nil
+	}
+	sc := &stopConditions{
+		maxFailures: maxFailures,
+		maxDuration: maxDuration,
+	}
+	if maxDuration > 0 {
+		sc.deadline = time.Now().Add(maxDuration)
+	}
+	return sc
+}
+
+// failed registers a repository the run failed on
+func (sc *stopConditions) failed() {
+	if sc == nil {
+		return
+	}
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+
+	sc.failures++
+	if sc.maxFailures > 0 && sc.failures >= sc.maxFailures && sc.reason == "" {
+		sc.reason = fmt.Sprintf("it failed on %d repositories", sc.failures)
+		log.Warnf("Stopping the run since %s, no more repositories will be started", sc.reason)
+	}
+}
+
+// stopped returns if no more repositories should be started. It must only be called right before a repository is
+// started, since the repository is then regarded as not started
+func (sc *stopConditions) stopped() bool {
+	if sc == nil {
+		return false
+	}
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+
+	if sc.reason == "" && !sc.deadline.IsZero() && time.Now().After(sc.deadline) {
+		sc.reason = fmt.Sprintf("it has run for more than %s", sc.maxDuration)
+		log.Warnf("Stopping the run since %s, no more repositories will be started", sc.reason)
+	}
+	sc.unstarted = sc.unstarted || sc.reason != ""
+	return sc.reason != ""
+}
+
+// err returns an error describing why the run was stopped, or nil if it was not stopped before all repositories were started
+func (sc *stopConditions) err() error {
+	if sc == nil {
+		return nil
+	}
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+
+	if !sc.unstarted {
+		return nil
+	}
+	return fmt.Errorf("the run was stopped early since %s", sc.reason)
+}
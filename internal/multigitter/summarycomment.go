@@ -0,0 +1,60 @@
+package multigitter
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// summaryComment describes how the changes of a pull request were made, to make them easier to review
+func (r *Runner) summaryComment(diff string) string {
+	additions, deletions := 0, 0
+	files := parseDiff(diff)
+	for _, f := range files {
+		additions += f.additions
+		deletions += f.deletions
+	}
+
+	version := r.Version
+	if version == "" {
+		version = "unknown"
+	}
+	command := strings.Join(append([]string{filepath.Base(r.ScriptPath)}, r.Arguments...), " ")
+
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "The changes were made by multi-gitter (version %s).\n\n", version)
+	fmt.Fprintf(sb, "- Script: `%s`\n", command)
+	fmt.Fprintf(sb, "- %d files changed, %d insertions(+), %d deletions(-)\n", len(files), additions, deletions)
+	return sb.String()
+}
+
+// commentSummary adds a comment with a summary of how the changes were made to the pull request, unless the same
+// summary is already commented
+func (r *Runner) commentSummary(ctx context.Context, log log.FieldLogger, pr scm.PullRequest, sourceController Git) error {
+	diff, err := sourceController.Diff()
+	if err != nil {
+		return errors.Wrap(err, "could not get the diff of the changes")
+	}
+
+	commenter := r.VersionController.(pullRequestCommenter)
+	summary := r.summaryComment(diff)
+	commented, err := isCommented(ctx, commenter, pr, summary)
+	if err != nil {
+		return errors.Wrap(err, "could not get the comments of the pull request")
+	}
+	if commented {
+		log.Info("The summary of the changes is already commented")
+		return nil
+	}
+
+	if err := commenter.CommentOnPullRequest(ctx, pr, summary); err != nil {
+		return errors.Wrap(err, "could not comment the summary of the changes")
+	}
+	log.Info("Commented a summary of the changes")
+	return nil
+}
@@ -0,0 +1,153 @@
+package multigitter
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// codeOwnersPaths are the places where CODEOWNERS files are looked for, in order
+var codeOwnersPaths = []string{
+	".github/CODEOWNERS",
+	"CODEOWNERS",
+	"docs/CODEOWNERS",
+	".gitlab/CODEOWNERS",
+}
+
+// TeamMapping maps repositories to the teams that own them
+type TeamMapping []teamRule
+
+type teamRule struct {
+	pattern string // A repository name pattern, like "my-org/web-*"
+	team    string
+}
+
+// ReadTeamMapping reads a team mapping file. The file is in the CODEOWNERS format, but with repository names instead
+// of file paths, like "my-org/web-* @my-org/frontend". Like in CODEOWNERS, the last matching line takes precedence
+func ReadTeamMapping(mappingPath string) (TeamMapping, error) {
+	file, err := os.Open(mappingPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open the team mapping file")
+	}
+	defer file.Close()
+
+	rules, err := parseOwnerRules(file)
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not read the team mapping file")
+	}
+
+	for _, rule := range rules {
+		if _, err := path.Match(rule.pattern, ""); err != nil {
+			return nil, errors.Errorf("invalid repository pattern %q in the team mapping file", rule.pattern)
+		}
+	}
+	return rules, nil
+}
+
+// Team returns the team that owns a repository, or an empty string if no team does
+func (m TeamMapping) Team(repoName string) string {
+	for i := len(m) - 1; i >= 0; i-- {
+		if ok, _ := path.Match(m[i].pattern, repoName); ok {
+			return m[i].team
+		}
+	}
+	return ""
+}
+
+// parseOwnerRules parses lines in the CODEOWNERS format, where the owners of each line together make up the team
+func parseOwnerRules(r io.Reader) ([]teamRule, error) {
+	var rules []teamRule
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		rules = append(rules, teamRule{
+			pattern: fields[0],
+			team:    strings.Join(fields[1:], " "),
+		})
+	}
+	return rules, scanner.Err()
+}
+
+// codeOwnersTeam returns the default owners, the owners of all files, in the CODEOWNERS file of a cloned repository
+func codeOwnersTeam(dir string) (string, error) {
+	rules, err := codeOwnerRules(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for i := len(rules) - 1; i >= 0; i-- {
+		switch rules[i].pattern {
+		case "*", "/", "/*", "/**", "**":
+			return rules[i].team, nil
+		}
+	}
+	return "", nil
+}
+
+// codeOwnerRules reads the rules of the CODEOWNERS file of a cloned repository, nil is returned if there is no such file
+func codeOwnerRules(dir string) ([]teamRule, error) {
+	for _, p := range codeOwnersPaths {
+		file, err := os.Open(filepath.Join(dir, filepath.FromSlash(p)))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		rules, err := parseOwnerRules(file)
+		file.Close()
+		if err != nil {
+			return nil, errors.WithMessagef(err, "could not read %s", p)
+		}
+		return rules, nil
+	}
+	return nil, nil
+}
+
+// repositoryTeams keeps track of the teams that own repositories, by repository name
+type repositoryTeams struct {
+	lock  sync.RWMutex
+	teams map[string]string
+}
+
+func (rt *repositoryTeams) set(repoName, team string) {
+	if rt == nil {
+		return
+	}
+	rt.lock.Lock()
+	defer rt.lock.Unlock()
+	if rt.teams == nil {
+		rt.teams = map[string]string{}
+	}
+	rt.teams[repoName] = team
+}
+
+func (rt *repositoryTeams) get(repoName string) string {
+	if rt == nil {
+		return ""
+	}
+	rt.lock.RLock()
+	defer rt.lock.RUnlock()
+	return rt.teams[repoName]
+}
+
+// team returns the team that owns a repository, with the team mapping taking precedence over CODEOWNERS files
+func (r *Runner) team(repoName string) string {
+	if team := r.TeamMapping.Team(repoName); team != "" {
+		return team
+	}
+	return r.codeOwnerTeams.get(repoName)
+}
@@ -0,0 +1,113 @@
+package multigitter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// phaseTimings keeps track of how long every phase took on every repository, to report the slowest repositories.
+// All methods can be called on a nil phaseTimings, which does nothing
+type phaseTimings struct {
+	now func() time.Time
+
+	lock      sync.Mutex
+	current   map[string]phase     // The phase of every repository in progress
+	since     map[string]time.Time // When every repository in progress started its current phase
+	durations map[string]*[numPhases]time.Duration
+}
+
+func newPhaseTimings() *phaseTimings {
+	return &phaseTimings{
+		now:       time.Now,
+		current:   map[string]phase{},
+		since:     map[string]time.Time{},
+		durations: map[string]*[numPhases]time.Duration{},
+	}
+}
+
+// setPhase moves a repository into a phase
+func (pt *phaseTimings) setPhase(repoName string, ph phase) {
+	if pt == nil {
+		return
+	}
+
+	pt.lock.Lock()
+	defer pt.lock.Unlock()
+	pt.endPhase(repoName)
+	pt.current[repoName] = ph
+	pt.since[repoName] = pt.now()
+}
+
+// done marks a repository as done
+func (pt *phaseTimings) done(repoName string) {
+	if pt == nil {
+		return
+	}
+
+	pt.lock.Lock()
+	defer pt.lock.Unlock()
+	pt.endPhase(repoName)
+	delete(pt.current, repoName)
+	delete(pt.since, repoName)
+}
+
+// endPhase records the duration of the current phase of a repository, must be called with the lock held
+func (pt *phaseTimings) endPhase(repoName string) {
+	ph, ok := pt.current[repoName]
+	if !ok {
+		return
+	}
+	if pt.durations[repoName] == nil {
+		pt.durations[repoName] = &[numPhases]time.Duration{}
+	}
+	pt.durations[repoName][ph] += pt.now().Sub(pt.since[repoName])
+}
+
+// info returns a formatted string with the repositories that took the longest, and the time of each of their phases
+func (pt *phaseTimings) info(count int) string {
+	if pt == nil {
+		return ""
+	}
+
+	pt.lock.Lock()
+	defer pt.lock.Unlock()
+
+	if len(pt.durations) == 0 {
+		return ""
+	}
+
+	type repoTime struct {
+		name  string
+		total time.Duration
+	}
+	times := make([]repoTime, 0, len(pt.durations))
+	for repoName, durations := range pt.durations {
+		var total time.Duration
+		for _, d := range durations {
+			total += d
+		}
+		times = append(times, repoTime{name: repoName, total: total})
+	}
+	sort.Slice(times, func(i, j int) bool {
+		if times[i].total != times[j].total {
+			return times[i].total > times[j].total
+		}
+		return times[i].name < times[j].name
+	})
+	if len(times) > count {
+		times = times[:count]
+	}
+
+	info := "Slowest repositories:\n"
+	for _, t := range times {
+		phases := []string{}
+		for ph, d := range pt.durations[t.name] {
+			phases = append(phases, fmt.Sprintf("%s %s", phaseNames[ph], d.Round(time.Millisecond)))
+		}
+		info += fmt.Sprintf("  %s: %s (%s)\n", t.name, t.total.Round(time.Millisecond), strings.Join(phases, ", "))
+	}
+	return info
+}
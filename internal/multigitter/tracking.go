@@ -0,0 +1,92 @@
+package multigitter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// issueUpserter is implemented by platforms that can create, or update, issues
+type issueUpserter interface {
+	UpsertIssue(ctx context.Context, repoName string, title string, body string) (string, error)
+}
+
+func verifyTrackingIssueSupport(vc VersionController) error {
+	if _, ok := vc.(issueUpserter); !ok {
+		return errors.New("the platform does not support creating issues")
+	}
+	return nil
+}
+
+// updateTrackingIssue creates, or updates, an issue in the repository with the status of all pull requests of the feature branch
+func updateTrackingIssue(ctx context.Context, vc VersionController, repoName string, featureBranch string) error {
+	if err := verifyTrackingIssueSupport(vc); err != nil {
+		return err
+	}
+	upserter := vc.(issueUpserter)
+
+	prs, err := vc.GetPullRequests(ctx, featureBranch)
+	if err != nil {
+		return errors.WithMessage(err, "could not get pull requests for the tracking issue")
+	}
+
+	title := fmt.Sprintf("multi-gitter: %s", featureBranch)
+	url, err := upserter.UpsertIssue(ctx, repoName, title, trackingIssueBody(featureBranch, prs))
+	if err != nil {
+		return errors.WithMessage(err, "could not update tracking issue")
+	}
+
+	log.Infof("Updated tracking issue %s", url)
+	return nil
+}
+
+func trackingIssueBody(featureBranch string, prs []scm.PullRequest) string {
+	prs = append([]scm.PullRequest{}, prs...)
+	sort.Slice(prs, func(i, j int) bool {
+		return prs[i].String() < prs[j].String()
+	})
+
+	statusCount := map[scm.PullRequestStatus]int{}
+	for _, pr := range prs {
+		statusCount[pr.Status()]++
+	}
+
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "This issue tracks the pull requests created by multi-gitter with the branch `%s`.\n\n", featureBranch)
+
+	counts := []string{}
+	for _, status := range []scm.PullRequestStatus{
+		scm.PullRequestStatusMerged,
+		scm.PullRequestStatusSuccess,
+		scm.PullRequestStatusPending,
+		scm.PullRequestStatusError,
+		scm.PullRequestStatusClosed,
+		scm.PullRequestStatusUnknown,
+	} {
+		if statusCount[status] > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", statusCount[status], status))
+		}
+	}
+	fmt.Fprintf(sb, "**%d pull requests**", len(prs))
+	if len(counts) > 0 {
+		fmt.Fprintf(sb, ": %s", strings.Join(counts, ", "))
+	}
+	sb.WriteString("\n\n")
+
+	sb.WriteString("| Pull request | Status |\n")
+	sb.WriteString("| --- | --- |\n")
+	for _, pr := range prs {
+		name := pr.String()
+		if urler, hasURL := pr.(urler); hasURL && urler.URL() != "" {
+			name = fmt.Sprintf("[%s](%s)", name, urler.URL())
+		}
+		fmt.Fprintf(sb, "| %s | %s |\n", name, pr.Status())
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,35 @@
+package multigitter
+
+import (
+	"context"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	log "github.com/sirupsen/logrus"
+)
+
+// availabilityChecker is implemented by platforms that can check if a repository can still be changed
+type availabilityChecker interface {
+	// RepositoryAvailable returns if the repository still exists, and is not archived or disabled
+	RepositoryAvailable(ctx context.Context, repo scm.Repository) (bool, error)
+}
+
+// checkUnavailable replaces the error of a failed run with errUnavailable, if the repository has been archived, disabled
+// or deleted since it was listed. Such repositories are no longer listed, and are therefore left out of later commands
+func (r *Runner) checkUnavailable(ctx context.Context, log log.FieldLogger, repo scm.Repository, err error) error {
+	checker, ok := r.VersionController.(availabilityChecker)
+	if !ok || ctx.Err() != nil {
+		return err
+	}
+
+	available, checkErr := checker.RepositoryAvailable(ctx, repo)
+	if checkErr != nil {
+		log.Debugf("Could not check if the repository is still available: %s", checkErr)
+		return err
+	}
+	if available {
+		return err
+	}
+
+	log.Debugf("The run failed since the repository became unavailable: %s", err)
+	return errUnavailable
+}
@@ -27,7 +27,13 @@ const (
 )
 
 // New create a new BitbucketServer client
-func New(username, token, baseURL string, insecure bool, sshAuth bool, transportMiddleware func(http.RoundTripper) http.RoundTripper, repoListing RepositoryListing) (*BitbucketServer, error) {
+func New(
+	username, token, baseURL string,
+	insecure bool,
+	cloneProtocol scm.CloneProtocol,
+	transportMiddleware func(http.RoundTripper) http.RoundTripper,
+	repoListing RepositoryListing,
+) (*BitbucketServer, error) {
 	if strings.TrimSpace(token) == "" {
 		return nil, errors.New("token is empty")
 	}
@@ -50,7 +56,7 @@ func New(username, token, baseURL string, insecure bool, sshAuth bool, transport
 	bitbucketServer.baseURL = bitbucketBaseURL
 	bitbucketServer.username = username
 	bitbucketServer.token = token
-	bitbucketServer.sshAuth = sshAuth
+	bitbucketServer.cloneProtocol = cloneProtocol
 	bitbucketServer.httpClient = &http.Client{
 		Transport: transportMiddleware(&http.Transport{
 			TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure}, // nolint: gosec
@@ -80,7 +86,7 @@ type BitbucketServer struct {
 	RepositoryListing
 	baseURL         *url.URL
 	username, token string
-	sshAuth         bool
+	cloneProtocol   scm.CloneProtocol
 	config          *bitbucketv1.Configuration
 	httpClient      *http.Client
 }
@@ -100,9 +106,9 @@ type RepositoryReference struct {
 
 // ParseRepositoryReference parses a GiteaRepository reference from the format "projectKey/repoName"
 func ParseRepositoryReference(val string) (RepositoryReference, error) {
-	split := strings.Split(val, "/")
-	if len(split) != 2 {
-		return RepositoryReference{}, fmt.Errorf("could not parse repository reference: %s", val)
+	split, err := scm.SplitRepositoryReference(val, 2, 2)
+	if err != nil {
+		return RepositoryReference{}, fmt.Errorf(`%w, expected the format "projectKey/repoName"`, err)
 	}
 	return RepositoryReference{
 		ProjectKey: split[0],
@@ -489,12 +495,21 @@ func (b *BitbucketServer) MergePullRequest(ctx context.Context, pr scm.PullReque
 
 // ClosePullRequest Close a pull request, the pr parameter will always originate from the same package
 func (b *BitbucketServer) ClosePullRequest(ctx context.Context, pr scm.PullRequest) error {
+	return b.closePullRequest(ctx, pr, true)
+}
+
+// ClosePullRequestKeepBranch closes a pull request without deleting its branch
+func (b *BitbucketServer) ClosePullRequestKeepBranch(ctx context.Context, pr scm.PullRequest) error {
+	return b.closePullRequest(ctx, pr, false)
+}
+
+func (b *BitbucketServer) closePullRequest(ctx context.Context, pr scm.PullRequest, deleteBranch bool) error {
 	bitbucketPR := pr.(pullRequest)
 
 	client := newClient(ctx, b.config)
 
 	_, err := client.DefaultApi.DeleteWithVersion(bitbucketPR.project, bitbucketPR.repoName, bitbucketPR.number, int(bitbucketPR.version))
-	if err != nil {
+	if err != nil || !deleteBranch {
 		return err
 	}
 
@@ -546,6 +561,26 @@ func (b *BitbucketServer) ForkRepository(_ context.Context, _ scm.Repository, _
 	return nil, errors.New("forking not implemented for bitbucket server")
 }
 
+// UserExists checks if a user exists
+func (b *BitbucketServer) UserExists(ctx context.Context, username string) (bool, error) {
+	client := newClient(ctx, b.config)
+
+	response, err := client.DefaultApi.GetUser(username)
+	if response != nil && response.Response != nil && response.StatusCode == http.StatusNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// PullRequestConstraints returns the limits Bitbucket Server puts on pull requests
+func (b *BitbucketServer) PullRequestConstraints() scm.PullRequestConstraints {
+	return scm.PullRequestConstraints{
+		MaxTitleLength: 255,
+	}
+}
+
 type bitbucketRepositoryPager struct {
 	Size          int                      `json:"size"`
 	Limit         int                      `json:"limit"`
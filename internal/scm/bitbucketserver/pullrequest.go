@@ -36,6 +36,11 @@ func (pr pullRequest) String() string {
 	return fmt.Sprintf("%s/%s #%d", pr.project, pr.repoName, pr.number)
 }
 
+// RepositoryFullName returns the full name of the repository the pull request is made against
+func (pr pullRequest) RepositoryFullName() string {
+	return fmt.Sprintf("%s/%s", pr.project, pr.repoName)
+}
+
 func (pr pullRequest) Status() scm.PullRequestStatus {
 	return pr.status
 }
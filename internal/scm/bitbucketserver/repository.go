@@ -9,32 +9,34 @@ import (
 )
 
 func (b *BitbucketServer) convertRepository(bitbucketRepository *bitbucketv1.Repository, defaultBranch bitbucketv1.Branch) (*repository, error) {
-	var cloneURL string
+	sshURL := findLinkType(bitbucketRepository.Links.Clone, cloneSSHType)
 
-	if b.sshAuth {
-		cloneURL = findLinkType(bitbucketRepository.Links.Clone, cloneSSHType)
-		if cloneURL == "" {
-			return nil, errors.Errorf("unable to find clone url for repository %s using clone type %s", bitbucketRepository.Name, cloneSSHType)
-		}
-	} else {
-		httpURL := findLinkType(bitbucketRepository.Links.Clone, cloneHTTPType)
-		if httpURL == "" {
-			return nil, errors.Errorf("unable to find clone url for repository %s using clone type %s", bitbucketRepository.Name, cloneHTTPType)
-		}
-		parsedURL, err := url.Parse(httpURL)
+	var httpURL string
+	if href := findLinkType(bitbucketRepository.Links.Clone, cloneHTTPType); href != "" {
+		parsedURL, err := url.Parse(href)
 		if err != nil {
 			return nil, err
 		}
 
 		parsedURL.User = url.UserPassword(b.username, b.token)
-		cloneURL = parsedURL.String()
+		httpURL = parsedURL.String()
+	}
+
+	cloneURL, fallbackURL := b.cloneProtocol.CloneURLs(httpURL, sshURL)
+	if cloneURL == "" {
+		cloneType := cloneHTTPType
+		if b.cloneProtocol.UsesSSH() {
+			cloneType = cloneSSHType
+		}
+		return nil, errors.Errorf("unable to find clone url for repository %s using clone type %s", bitbucketRepository.Name, cloneType)
 	}
 
 	repo := repository{
-		name:          bitbucketRepository.Slug,
-		project:       bitbucketRepository.Project.Key,
-		defaultBranch: defaultBranch.DisplayID,
-		cloneURL:      cloneURL,
+		name:             bitbucketRepository.Slug,
+		project:          bitbucketRepository.Project.Key,
+		defaultBranch:    defaultBranch.DisplayID,
+		cloneURL:         cloneURL,
+		fallbackCloneURL: fallbackURL,
 	}
 
 	return &repo, nil
@@ -52,16 +54,21 @@ func findLinkType(links []bitbucketv1.CloneLink, cloneType string) string {
 
 // repository contains information about a bitbucket repository
 type repository struct {
-	name          string
-	project       string
-	defaultBranch string
-	cloneURL      string
+	name             string
+	project          string
+	defaultBranch    string
+	cloneURL         string
+	fallbackCloneURL string
 }
 
 func (r repository) CloneURL() string {
 	return r.cloneURL
 }
 
+func (r repository) FallbackCloneURL() string {
+	return r.fallbackCloneURL
+}
+
 func (r repository) DefaultBranch() string {
 	return r.defaultBranch
 }
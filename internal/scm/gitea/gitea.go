@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
 	"code.gitea.io/sdk/gitea"
@@ -17,15 +18,15 @@ import (
 )
 
 // New create a new Gitea client
-func New(token, baseURL string, repoListing RepositoryListing, mergeTypes []scm.MergeType, sshAuth bool) (*Gitea, error) {
+func New(token, baseURL string, repoListing RepositoryListing, mergeTypes []scm.MergeType, cloneProtocol scm.CloneProtocol) (*Gitea, error) {
 	gitea := &Gitea{
 		RepositoryListing: repoListing,
 
 		baseURL: baseURL,
 		token:   token,
 
-		MergeTypes: mergeTypes,
-		SSHAuth:    sshAuth,
+		MergeTypes:    mergeTypes,
+		CloneProtocol: cloneProtocol,
 	}
 
 	// Initialize the gitea client to ensure no error will occur when running a function
@@ -60,8 +61,8 @@ type Gitea struct {
 
 	currentUser *gitea.User
 
-	MergeTypes []scm.MergeType
-	SSHAuth    bool
+	MergeTypes    []scm.MergeType
+	CloneProtocol scm.CloneProtocol
 }
 
 // RepositoryListing contains information about which repositories that should be fetched
@@ -81,9 +82,9 @@ type RepositoryReference struct {
 
 // ParseRepositoryReference parses a repository reference from the format "ownerName/repoName"
 func ParseRepositoryReference(val string) (RepositoryReference, error) {
-	split := strings.Split(val, "/")
-	if len(split) != 2 {
-		return RepositoryReference{}, fmt.Errorf("could not parse repository reference: %s", val)
+	split, err := scm.SplitRepositoryReference(val, 2, 2)
+	if err != nil {
+		return RepositoryReference{}, fmt.Errorf(`%w, expected the format "ownerName/repoName"`, err)
 	}
 	return RepositoryReference{
 		OwnerName: split[0],
@@ -168,6 +169,9 @@ func (g *Gitea) getRepositories(ctx context.Context) ([]*gitea.Repository, error
 	}
 	allRepos = make([]*gitea.Repository, 0, len(repoMap))
 	for _, repo := range repoMap {
+		if repo.Archived {
+			continue
+		}
 		allRepos = append(allRepos, repo)
 	}
 	sort.Slice(allRepos, func(i, j int) bool {
@@ -204,6 +208,10 @@ func (g *Gitea) getRepository(ctx context.Context, repoRef RepositoryReference)
 	if err != nil {
 		return nil, err
 	}
+	// Requests of renamed and moved repositories are redirected to their new name
+	if !strings.EqualFold(repo.FullName, fmt.Sprintf("%s/%s", repoRef.OwnerName, repoRef.Name)) {
+		log.Infof("%s/%s has been renamed or moved to %s, using the new name", repoRef.OwnerName, repoRef.Name, repo.FullName)
+	}
 	return repo, err
 }
 
@@ -276,9 +284,24 @@ func (g *Gitea) CreatePullRequest(ctx context.Context, repo scm.Repository, prRe
 		prRepoName:  pr.Head.Repository.Name,
 		index:       pr.Index,
 		webURL:      pr.HTMLURL,
+		details:     pullRequestDetails(pr),
 	}, nil
 }
 
+func pullRequestDetails(pr *gitea.PullRequest) scm.PullRequestDetails {
+	details := scm.PullRequestDetails{
+		ID:     strconv.FormatInt(pr.ID, 10),
+		Number: int(pr.Index),
+	}
+	if pr.Created != nil {
+		details.CreatedAt = *pr.Created
+	}
+	if pr.Updated != nil {
+		details.UpdatedAt = *pr.Updated
+	}
+	return details
+}
+
 func (g *Gitea) getLabelsFromStrings(ctx context.Context, repo repository, labelNames []string) ([]int64, error) {
 	if len(labelNames) == 0 {
 		return nil, nil
@@ -332,7 +355,7 @@ func (g *Gitea) setReviewers(ctx context.Context, repo repository, newPR scm.New
 		}
 	}
 
-	if len(removedReviewers) > 0 {
+	if len(removedReviewers) > 0 && !newPR.KeepReviewers {
 		_, err := g.giteaClient(ctx).DeleteReviewRequests(repo.ownerName, repo.name, createdPR.Index, gitea.PullReviewRequestOptions{
 			Reviewers: removedReviewers,
 		})
@@ -381,6 +404,7 @@ func (g *Gitea) UpdatePullRequest(ctx context.Context, repo scm.Repository, pull
 		prRepoName:  giteaPr.Head.Repository.Name,
 		index:       giteaPr.Index,
 		webURL:      giteaPr.HTMLURL,
+		details:     pullRequestDetails(giteaPr),
 	}, nil
 }
 
@@ -427,6 +451,7 @@ func (g *Gitea) convertPullRequest(ctx context.Context, pr *gitea.PullRequest) (
 		status:      status,
 		index:       pr.Index,
 		webURL:      pr.HTMLURL,
+		details:     pullRequestDetails(pr),
 	}, nil
 }
 
@@ -493,8 +518,52 @@ func (g *Gitea) GetOpenPullRequest(ctx context.Context, repo scm.Repository, bra
 	return g.convertPullRequest(ctx, pr)
 }
 
+// GetClosedPullRequest gets the latest closed, but not merged, pull request from a branch
+func (g *Gitea) GetClosedPullRequest(ctx context.Context, repo scm.Repository, branchName string) (scm.PullRequest, error) {
+	r := repo.(repository)
+
+	prs, _, err := g.giteaClient(ctx).ListRepoPullRequests(r.ownerName, r.name, gitea.ListPullRequestsOptions{
+		State: gitea.StateClosed,
+		Sort:  "recentupdate",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pr := range prs {
+		if pr.Head.Name == branchName && !pr.HasMerged {
+			return g.convertPullRequest(ctx, pr)
+		}
+	}
+	return nil, nil
+}
+
+// ReopenPullRequest reopens a closed pull request
+func (g *Gitea) ReopenPullRequest(ctx context.Context, pullReq scm.PullRequest) (scm.PullRequest, error) {
+	pr := pullReq.(pullRequest)
+
+	state := gitea.StateOpen
+	reopened, _, err := g.giteaClient(ctx).EditPullRequest(pr.ownerName, pr.repoName, pr.index, gitea.EditPullRequestOption{
+		State: &state,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not reopen %s/%s#%d", pr.ownerName, pr.repoName, pr.index)
+	}
+
+	return g.convertPullRequest(ctx, reopened)
+}
+
 // MergePullRequest merges a pull request
 func (g *Gitea) MergePullRequest(ctx context.Context, pullReq scm.PullRequest) error {
+	return g.mergePullRequest(ctx, pullReq, scm.MergeMessage{})
+}
+
+// MergePullRequestWithMessage merges a pull request with a custom message of the merge, or squashed, commit
+func (g *Gitea) MergePullRequestWithMessage(ctx context.Context, pullReq scm.PullRequest, message scm.MergeMessage) error {
+	return g.mergePullRequest(ctx, pullReq, message)
+}
+
+func (g *Gitea) mergePullRequest(ctx context.Context, pullReq scm.PullRequest, message scm.MergeMessage) error {
 	pr := pullReq.(pullRequest)
 
 	repo, _, err := g.giteaClient(ctx).GetRepo(pr.ownerName, pr.repoName)
@@ -502,14 +571,18 @@ func (g *Gitea) MergePullRequest(ctx context.Context, pullReq scm.PullRequest) e
 		return errors.Wrapf(err, "could not fetch %s/%s repository", pr.ownerName, pr.repoName)
 	}
 
-	// Filter out all merge types to only the allowed ones, but keep the order of the ones left
-	mergeTypes := scm.MergeTypeIntersection(g.MergeTypes, repoMergeTypes(repo))
-	if len(mergeTypes) == 0 {
-		return errors.New("none of the configured merge types was permitted")
+	mergeType, fallback, err := scm.ChooseMergeType(g.MergeTypes, repoMergeTypes(repo))
+	if err != nil {
+		return errors.Wrapf(err, "could not merge %s/%s#%d", pr.ownerName, pr.repoName, pr.index)
+	}
+	if fallback {
+		log.Warnf("None of the configured merge types is allowed in %s/%s, using %s instead", pr.ownerName, pr.repoName, mergeType)
 	}
 
 	merged, _, err := g.giteaClient(ctx).MergePullRequest(pr.ownerName, pr.repoName, pr.index, gitea.MergePullRequestOption{
-		Style: mergeTypeGiteaName[mergeTypes[0]],
+		Style:   mergeTypeGiteaName[mergeType],
+		Title:   message.Title,
+		Message: message.Body,
 	})
 	if err != nil {
 		return errors.Wrapf(err, "could not merge %s/%s#%d", pr.ownerName, pr.repoName, pr.index)
@@ -531,8 +604,53 @@ func (g *Gitea) MergePullRequest(ctx context.Context, pullReq scm.PullRequest) e
 	return nil
 }
 
+// CreateRelease creates a release, or only a tag, on the merge commit of a merged pull request
+func (g *Gitea) CreateRelease(ctx context.Context, pullReq scm.PullRequest, release scm.NewRelease) error {
+	pr := pullReq.(pullRequest)
+
+	giteaPr, _, err := g.giteaClient(ctx).GetPullRequest(pr.ownerName, pr.repoName, pr.index)
+	if err != nil {
+		return errors.Wrap(err, "could not get pull request")
+	}
+	if giteaPr.MergedCommitID == nil || *giteaPr.MergedCommitID == "" {
+		return errors.New("the pull request has no merge commit")
+	}
+	sha := *giteaPr.MergedCommitID
+
+	if release.TagOnly {
+		_, _, err = g.giteaClient(ctx).CreateTag(pr.ownerName, pr.repoName, gitea.CreateTagOption{
+			TagName: release.TagName,
+			Target:  sha,
+		})
+		if err != nil {
+			return errors.Wrap(err, "could not create tag")
+		}
+		return nil
+	}
+
+	_, _, err = g.giteaClient(ctx).CreateRelease(pr.ownerName, pr.repoName, gitea.CreateReleaseOption{
+		TagName: release.TagName,
+		Target:  sha,
+		Title:   release.Name,
+		Note:    release.Notes,
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not create release")
+	}
+	return nil
+}
+
 // ClosePullRequest closes a pull request
 func (g *Gitea) ClosePullRequest(ctx context.Context, pullReq scm.PullRequest) error {
+	return g.closePullRequest(ctx, pullReq, true)
+}
+
+// ClosePullRequestKeepBranch closes a pull request without deleting its branch
+func (g *Gitea) ClosePullRequestKeepBranch(ctx context.Context, pullReq scm.PullRequest) error {
+	return g.closePullRequest(ctx, pullReq, false)
+}
+
+func (g *Gitea) closePullRequest(ctx context.Context, pullReq scm.PullRequest, deleteBranch bool) error {
 	pr := pullReq.(pullRequest)
 
 	state := gitea.StateClosed
@@ -542,6 +660,9 @@ func (g *Gitea) ClosePullRequest(ctx context.Context, pullReq scm.PullRequest) e
 	if err != nil {
 		return errors.Wrapf(err, "could not close %s/%s#%d", pr.ownerName, pr.repoName, pr.index)
 	}
+	if !deleteBranch {
+		return nil
+	}
 
 	deleted, _, err := g.giteaClient(ctx).DeleteRepoBranch(pr.prOwnerName, pr.prRepoName, pr.branchName)
 	if err != nil {
@@ -555,6 +676,142 @@ func (g *Gitea) ClosePullRequest(ctx context.Context, pullReq scm.PullRequest) e
 	return nil
 }
 
+// RepositoryAvailable returns if the repository still exists, and is not archived
+func (g *Gitea) RepositoryAvailable(ctx context.Context, repo scm.Repository) (bool, error) {
+	r := repo.(repository)
+
+	giteaRepo, resp, err := g.giteaClient(ctx).GetRepoByID(r.id)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return !giteaRepo.Archived, nil
+}
+
+// UserExists checks if a user exists
+func (g *Gitea) UserExists(ctx context.Context, username string) (bool, error) {
+	_, resp, err := g.giteaClient(ctx).GetUserInfo(username)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetPullRequestBase gets the branch a pull request is made against
+func (g *Gitea) GetPullRequestBase(ctx context.Context, pullReq scm.PullRequest) (string, error) {
+	pr := pullReq.(pullRequest)
+
+	giteaPR, _, err := g.giteaClient(ctx).GetPullRequest(pr.ownerName, pr.repoName, pr.index)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not get %s/%s#%d", pr.ownerName, pr.repoName, pr.index)
+	}
+	return giteaPR.Base.Ref, nil
+}
+
+// RetargetPullRequest changes the branch a pull request is made against
+func (g *Gitea) RetargetPullRequest(ctx context.Context, pullReq scm.PullRequest, base string) error {
+	pr := pullReq.(pullRequest)
+
+	_, _, err := g.giteaClient(ctx).EditPullRequest(pr.ownerName, pr.repoName, pr.index, gitea.EditPullRequestOption{
+		Base: base,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "could not retarget %s/%s#%d", pr.ownerName, pr.repoName, pr.index)
+	}
+	return nil
+}
+
+// BranchExists checks if a branch exists in a repository
+func (g *Gitea) BranchExists(ctx context.Context, repo scm.Repository, branchName string) (bool, error) {
+	r := repo.(repository)
+
+	_, resp, err := g.giteaClient(ctx).GetRepoBranch(r.ownerName, r.name, branchName)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetBranches gets the names of all branches in a repository
+func (g *Gitea) GetBranches(ctx context.Context, repo scm.Repository) ([]string, error) {
+	r := repo.(repository)
+
+	var names []string
+	for i := 1; ; i++ {
+		branches, _, err := g.giteaClient(ctx).ListRepoBranches(r.ownerName, r.name, gitea.ListRepoBranchesOptions{
+			ListOptions: gitea.ListOptions{
+				Page:     i,
+				PageSize: 100,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, branch := range branches {
+			names = append(names, branch.Name)
+		}
+		if len(branches) < 100 {
+			break
+		}
+	}
+	return names, nil
+}
+
+// PullRequestConstraints returns the limits Gitea puts on pull requests
+func (g *Gitea) PullRequestConstraints() scm.PullRequestConstraints {
+	return scm.PullRequestConstraints{
+		MaxTitleLength: 255,
+	}
+}
+
+// SetCommitStatus sets a successful status on a commit of a pull request
+func (g *Gitea) SetCommitStatus(ctx context.Context, pullReq scm.PullRequest, status scm.CommitStatus) error {
+	pr := pullReq.(pullRequest)
+
+	_, _, err := g.giteaClient(ctx).CreateStatus(pr.ownerName, pr.repoName, status.SHA, gitea.CreateStatusOption{
+		State:       gitea.StatusSuccess,
+		TargetURL:   status.TargetURL,
+		Description: status.Description,
+		Context:     status.Context,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "could not set the status of %s/%s#%d", pr.ownerName, pr.repoName, pr.index)
+	}
+	return nil
+}
+
+// SetPullRequestDraft marks a pull request as work in progress, or removes the mark
+func (g *Gitea) SetPullRequestDraft(ctx context.Context, pullReq scm.PullRequest, draft bool) error {
+	pr := pullReq.(pullRequest)
+
+	giteaPr, _, err := g.giteaClient(ctx).GetPullRequest(pr.ownerName, pr.repoName, pr.index)
+	if err != nil {
+		return errors.Wrap(err, "could not get pull request")
+	}
+
+	title := strings.TrimPrefix(giteaPr.Title, "WIP: ")
+	if draft {
+		title = "WIP: " + title // See https://docs.gitea.io/en-us/pull-request/
+	}
+	if title == giteaPr.Title {
+		return nil
+	}
+
+	_, _, err = g.giteaClient(ctx).EditPullRequest(pr.ownerName, pr.repoName, pr.index, gitea.EditPullRequestOption{
+		Title: title,
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not update pull request")
+	}
+
+	return nil
+}
+
 // ForkRepository forks a GiteaRepository. If newOwner is empty, fork on the logged in user
 func (g *Gitea) ForkRepository(ctx context.Context, repo scm.Repository, newOwner string) (scm.Repository, error) {
 	r := repo.(repository)
@@ -586,6 +843,40 @@ func (g *Gitea) ForkRepository(ctx context.Context, repo scm.Repository, newOwne
 	return g.convertRepository(createdRepo)
 }
 
+// DeleteFork deletes the fork a pull request was made from. The fork is not deleted if it has any other branches
+// than the default branch and the branch of the pull request, since it might then be in use by something else
+func (g *Gitea) DeleteFork(ctx context.Context, pullReq scm.PullRequest) (bool, error) {
+	pr := pullReq.(pullRequest)
+	if pr.prOwnerName == "" || (pr.prOwnerName == pr.ownerName && pr.prRepoName == pr.repoName) {
+		return false, nil
+	}
+
+	fork, resp, err := g.giteaClient(ctx).GetRepo(pr.prOwnerName, pr.prRepoName)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Wrapf(err, "could not fetch %s/%s repository", pr.prOwnerName, pr.prRepoName)
+	}
+	if !fork.Fork {
+		return false, nil
+	}
+
+	branches, _, err := g.giteaClient(ctx).ListRepoBranches(pr.prOwnerName, pr.prRepoName, gitea.ListRepoBranchesOptions{})
+	if err != nil {
+		return false, errors.Wrap(err, "could not list branches of fork")
+	}
+	for _, branch := range branches {
+		if branch.Name != fork.DefaultBranch && branch.Name != pr.branchName {
+			return false, nil
+		}
+	}
+
+	if _, err := g.giteaClient(ctx).DeleteRepo(pr.prOwnerName, pr.prRepoName); err != nil {
+		return false, errors.Wrapf(err, "could not delete %s/%s", pr.prOwnerName, pr.prRepoName)
+	}
+	return true, nil
+}
+
 func (g *Gitea) getUser(ctx context.Context) (*gitea.User, error) {
 	if g.currentUser != nil {
 		return g.currentUser, nil
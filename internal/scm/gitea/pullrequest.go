@@ -15,12 +15,18 @@ type pullRequest struct {
 	index       int64 // The id of the PR
 	webURL      string
 	status      scm.PullRequestStatus
+	details     scm.PullRequestDetails
 }
 
 func (pr pullRequest) String() string {
 	return fmt.Sprintf("%s/%s #%d", pr.ownerName, pr.repoName, pr.index)
 }
 
+// RepositoryFullName returns the full name of the repository the pull request is made against
+func (pr pullRequest) RepositoryFullName() string {
+	return fmt.Sprintf("%s/%s", pr.ownerName, pr.repoName)
+}
+
 func (pr pullRequest) Status() scm.PullRequestStatus {
 	return pr.status
 }
@@ -28,3 +34,7 @@ func (pr pullRequest) Status() scm.PullRequestStatus {
 func (pr pullRequest) URL() string {
 	return pr.webURL
 }
+
+func (pr pullRequest) Details() scm.PullRequestDetails {
+	return pr.details
+}
@@ -18,7 +18,7 @@ func repoMergeTypes(repo *gitea.Repository) []scm.MergeType {
 	if repo.AllowMerge {
 		ret = append(ret, scm.MergeTypeMerge)
 	}
-	if repo.AllowMerge {
+	if repo.AllowRebase {
 		ret = append(ret, scm.MergeTypeRebase)
 	}
 	if repo.AllowSquash {
@@ -0,0 +1,143 @@
+package gitee
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// apiError is the error returned from the Gitee API
+type apiError struct {
+	StatusCode int
+	Message    string `json:"message"`
+}
+
+func (e *apiError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("gitee responded with status code %d", e.StatusCode)
+	}
+	return fmt.Sprintf("gitee responded with status code %d: %s", e.StatusCode, e.Message)
+}
+
+// request makes a request against the Gitee API, and decodes the json response into result (if not nil)
+func (g *Gitee) request(ctx context.Context, method, path string, query url.Values, body interface{}, result interface{}) error {
+	u, err := url.Parse(strings.TrimSuffix(g.baseURL, "/") + path)
+	if err != nil {
+		return err
+	}
+
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("access_token", g.token)
+	u.RawQuery = query.Encode()
+
+	var bodyReader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		bodyReader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bodyReader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := &apiError{StatusCode: resp.StatusCode}
+		_ = json.NewDecoder(resp.Body).Decode(apiErr)
+		return apiErr
+	}
+
+	if result == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return errors.Wrap(err, "could not decode the gitee response")
+	}
+	return nil
+}
+
+// getAllRepositories fetches all pages of repositories from a repository listing endpoint
+func (g *Gitee) getAllRepositories(ctx context.Context, path string) ([]giteeRepository, error) {
+	var allRepos []giteeRepository
+	for i := 1; ; i++ {
+		var repos []giteeRepository
+		err := g.request(ctx, http.MethodGet, path, url.Values{
+			"page":     []string{strconv.Itoa(i)},
+			"per_page": []string{"100"},
+		}, nil, &repos)
+		if err != nil {
+			return nil, err
+		}
+
+		allRepos = append(allRepos, repos...)
+
+		if len(repos) < 100 {
+			break
+		}
+	}
+	return allRepos, nil
+}
+
+func isNotFound(err error) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+type giteeNamespace struct {
+	Path string `json:"path"`
+}
+
+type giteeRepository struct {
+	ID            int64          `json:"id"`
+	FullName      string         `json:"full_name"`
+	Path          string         `json:"path"`
+	Namespace     giteeNamespace `json:"namespace"`
+	HTMLURL       string         `json:"html_url"`
+	SSHURL        string         `json:"ssh_url"`
+	DefaultBranch string         `json:"default_branch"`
+	Fork          bool           `json:"fork"`
+}
+
+type giteeBranch struct {
+	Ref  string          `json:"ref"`
+	Sha  string          `json:"sha"`
+	Repo giteeRepository `json:"repo"`
+}
+
+type giteePullRequest struct {
+	Number    int         `json:"number"`
+	State     string      `json:"state"`
+	Title     string      `json:"title"`
+	HTMLURL   string      `json:"html_url"`
+	Draft     bool        `json:"draft"`
+	Mergeable bool        `json:"mergeable"`
+	Head      giteeBranch `json:"head"`
+	Base      giteeBranch `json:"base"`
+}
+
+type giteeUser struct {
+	Login string `json:"login"`
+}
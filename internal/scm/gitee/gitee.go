@@ -0,0 +1,418 @@
+package gitee
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	internalHTTP "github.com/lindell/multi-gitter/internal/http"
+)
+
+// DefaultBaseURL is the url of the API of gitee.com
+const DefaultBaseURL = "https://gitee.com/api/v5"
+
+// New create a new Gitee client
+func New(token, baseURL string, repoListing RepositoryListing, mergeTypes []scm.MergeType, cloneProtocol scm.CloneProtocol) (*Gitee, error) {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	if _, err := url.Parse(baseURL); err != nil {
+		return nil, errors.Wrap(err, "could not parse base-url")
+	}
+
+	return &Gitee{
+		RepositoryListing: repoListing,
+
+		baseURL: baseURL,
+		token:   token,
+		httpClient: &http.Client{
+			Transport: internalHTTP.LoggingRoundTripper{},
+		},
+
+		MergeTypes:    mergeTypes,
+		CloneProtocol: cloneProtocol,
+	}, nil
+}
+
+// Gitee contain Gitee configuration
+type Gitee struct {
+	RepositoryListing
+
+	baseURL    string
+	token      string
+	httpClient *http.Client
+
+	// Caching of the logged in user
+	currentUser *giteeUser
+	userMutex   sync.Mutex
+
+	MergeTypes    []scm.MergeType
+	CloneProtocol scm.CloneProtocol
+}
+
+// RepositoryListing contains information about which repositories that should be fetched
+type RepositoryListing struct {
+	Organizations []string
+	Users         []string
+	Repositories  []RepositoryReference
+	SkipForks     bool
+}
+
+// RepositoryReference contains information to be able to reference a repository
+type RepositoryReference struct {
+	OwnerName string
+	Name      string
+}
+
+// ParseRepositoryReference parses a repository reference from the format "ownerName/repoName"
+func ParseRepositoryReference(val string) (RepositoryReference, error) {
+	split, err := scm.SplitRepositoryReference(val, 2, 2)
+	if err != nil {
+		return RepositoryReference{}, fmt.Errorf(`%w, expected the format "ownerName/repoName"`, err)
+	}
+	return RepositoryReference{
+		OwnerName: split[0],
+		Name:      split[1],
+	}, nil
+}
+
+// GetRepositories fetches repositories from all sources (orgs/user/specific repo)
+func (g *Gitee) GetRepositories(ctx context.Context) ([]scm.Repository, error) {
+	allRepos, err := g.getRepositories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	repos := make([]scm.Repository, 0, len(allRepos))
+	for _, repo := range allRepos {
+		if g.SkipForks && repo.Fork {
+			log.WithField("repo", repo.FullName).Debug("Skipping repository since it's a fork")
+			continue
+		}
+
+		convertedRepo, err := g.convertRepository(repo)
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, convertedRepo)
+	}
+
+	return repos, nil
+}
+
+func (g *Gitee) getRepositories(ctx context.Context) ([]giteeRepository, error) {
+	allRepos := []giteeRepository{}
+
+	for _, org := range g.Organizations {
+		repos, err := g.getAllRepositories(ctx, fmt.Sprintf("/orgs/%s/repos", url.PathEscape(org)))
+		if err != nil {
+			return nil, err
+		}
+		allRepos = append(allRepos, repos...)
+	}
+
+	for _, user := range g.Users {
+		repos, err := g.getAllRepositories(ctx, fmt.Sprintf("/users/%s/repos", url.PathEscape(user)))
+		if err != nil {
+			return nil, err
+		}
+		allRepos = append(allRepos, repos...)
+	}
+
+	for _, repoRef := range g.Repositories {
+		repo, err := g.getRepository(ctx, repoRef.OwnerName, repoRef.Name)
+		if err != nil {
+			return nil, err
+		}
+		allRepos = append(allRepos, repo)
+	}
+
+	// Remove duplicate repos
+	repoMap := map[int64]giteeRepository{}
+	for _, repo := range allRepos {
+		repoMap[repo.ID] = repo
+	}
+	allRepos = make([]giteeRepository, 0, len(repoMap))
+	for _, repo := range repoMap {
+		allRepos = append(allRepos, repo)
+	}
+	sort.Slice(allRepos, func(i, j int) bool {
+		return allRepos[i].ID < allRepos[j].ID
+	})
+
+	return allRepos, nil
+}
+
+func (g *Gitee) getRepository(ctx context.Context, owner, name string) (giteeRepository, error) {
+	var repo giteeRepository
+	err := g.request(ctx, http.MethodGet, repoPath(owner, name), nil, nil, &repo)
+	return repo, err
+}
+
+func repoPath(owner, name string) string {
+	return fmt.Sprintf("/repos/%s/%s", url.PathEscape(owner), url.PathEscape(name))
+}
+
+// CreatePullRequest creates a pull request
+func (g *Gitee) CreatePullRequest(ctx context.Context, repo scm.Repository, prRepo scm.Repository, newPR scm.NewPullRequest) (scm.PullRequest, error) {
+	r := repo.(repository)
+	prR := prRepo.(repository)
+
+	head := newPR.Head
+	if r.ownerName != prR.ownerName {
+		head = fmt.Sprintf("%s:%s", prR.ownerName, newPR.Head)
+	}
+
+	// In Gitee, the reviewers of a pull request are called assignees, and the assignees are called testers
+	body := map[string]interface{}{
+		"title":               newPR.Title,
+		"head":                head,
+		"base":                newPR.Base,
+		"body":                newPR.Body,
+		"draft":               newPR.Draft,
+		"prune_source_branch": true,
+	}
+	if len(newPR.Reviewers) > 0 {
+		body["assignees"] = strings.Join(newPR.Reviewers, ",")
+	}
+	if len(newPR.Assignees) > 0 {
+		body["testers"] = strings.Join(newPR.Assignees, ",")
+	}
+	if len(newPR.Labels) > 0 {
+		body["labels"] = strings.Join(newPR.Labels, ",")
+	}
+
+	var pr giteePullRequest
+	err := g.request(ctx, http.MethodPost, repoPath(r.ownerName, r.name)+"/pulls", nil, body, &pr)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create pull request")
+	}
+
+	return convertPullRequest(pr), nil
+}
+
+// UpdatePullRequest updates an existing pull request
+func (g *Gitee) UpdatePullRequest(ctx context.Context, repo scm.Repository, pullReq scm.PullRequest, updatedPR scm.NewPullRequest) (scm.PullRequest, error) {
+	r := repo.(repository)
+	pr := pullReq.(pullRequest)
+
+	body := map[string]interface{}{
+		"title":  updatedPR.Title,
+		"body":   updatedPR.Body,
+		"draft":  updatedPR.Draft,
+		"labels": strings.Join(updatedPR.Labels, ","),
+	}
+
+	var giteePR giteePullRequest
+	err := g.request(ctx, http.MethodPatch, fmt.Sprintf("%s/pulls/%d", repoPath(r.ownerName, r.name), pr.number), nil, body, &giteePR)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not update pull request")
+	}
+
+	if len(updatedPR.Reviewers) > 0 {
+		err := g.request(ctx, http.MethodPost, fmt.Sprintf("%s/pulls/%d/assignees", repoPath(r.ownerName, r.name), pr.number), nil, map[string]interface{}{
+			"assignees": strings.Join(updatedPR.Reviewers, ","),
+		}, nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not add reviewers to pull request")
+		}
+	}
+
+	return convertPullRequest(giteePR), nil
+}
+
+// GetPullRequests gets all pull requests of with a specific branch
+func (g *Gitee) GetPullRequests(ctx context.Context, branchName string) ([]scm.PullRequest, error) {
+	repos, err := g.getRepositories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	prs := []scm.PullRequest{}
+	for _, repo := range repos {
+		pr, err := g.getPullRequest(ctx, branchName, repo.Namespace.Path, repo.Path, "all")
+		if err != nil {
+			return nil, err
+		}
+		if pr == nil {
+			continue
+		}
+
+		prs = append(prs, convertPullRequest(*pr))
+	}
+
+	return prs, nil
+}
+
+func (g *Gitee) getPullRequest(ctx context.Context, branchName string, owner, repoName string, state string) (*giteePullRequest, error) {
+	var prs []giteePullRequest
+	err := g.request(ctx, http.MethodGet, repoPath(owner, repoName)+"/pulls", url.Values{
+		"state":     []string{state},
+		"head":      []string{branchName},
+		"sort":      []string{"updated"},
+		"direction": []string{"desc"},
+	}, nil, &prs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pr := range prs {
+		if pr.Head.Ref == branchName {
+			return &pr, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetOpenPullRequest gets a pull request for one specific repository
+func (g *Gitee) GetOpenPullRequest(ctx context.Context, repo scm.Repository, branchName string) (scm.PullRequest, error) {
+	r := repo.(repository)
+
+	pr, err := g.getPullRequest(ctx, branchName, r.ownerName, r.name, "open")
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		return nil, nil
+	}
+
+	return convertPullRequest(*pr), nil
+}
+
+// MergePullRequest merges a pull request, the source branch is removed as a part of the merge
+func (g *Gitee) MergePullRequest(ctx context.Context, pullReq scm.PullRequest) error {
+	pr := pullReq.(pullRequest)
+
+	if len(g.MergeTypes) == 0 {
+		return errors.New("no merge type was configured")
+	}
+
+	err := g.request(ctx, http.MethodPut, fmt.Sprintf("%s/pulls/%d/merge", repoPath(pr.ownerName, pr.repoName), pr.number), nil, map[string]interface{}{
+		"merge_method":        mergeTypeGiteeName[g.MergeTypes[0]],
+		"prune_source_branch": true,
+	}, nil)
+	if err != nil {
+		return errors.Wrapf(err, "could not merge %s/%s#%d", pr.ownerName, pr.repoName, pr.number)
+	}
+
+	return nil
+}
+
+// ClosePullRequest closes a pull request
+func (g *Gitee) ClosePullRequest(ctx context.Context, pullReq scm.PullRequest) error {
+	pr := pullReq.(pullRequest)
+
+	// The Gitee API does not support deleting branches, so the branch is left as is
+	err := g.request(ctx, http.MethodPatch, fmt.Sprintf("%s/pulls/%d", repoPath(pr.ownerName, pr.repoName), pr.number), nil, map[string]interface{}{
+		"state": "closed",
+	}, nil)
+	if err != nil {
+		return errors.Wrapf(err, "could not close %s/%s#%d", pr.ownerName, pr.repoName, pr.number)
+	}
+
+	return nil
+}
+
+// ClosePullRequestKeepBranch closes a pull request, which never deletes its branch on Gitee
+func (g *Gitee) ClosePullRequestKeepBranch(ctx context.Context, pullReq scm.PullRequest) error {
+	return g.ClosePullRequest(ctx, pullReq)
+}
+
+// UserExists checks if a user exists
+func (g *Gitee) UserExists(ctx context.Context, username string) (bool, error) {
+	err := g.request(ctx, http.MethodGet, fmt.Sprintf("/users/%s", url.PathEscape(username)), nil, nil, &giteeUser{})
+	if isNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// PullRequestConstraints returns the limits Gitee puts on pull requests
+func (g *Gitee) PullRequestConstraints() scm.PullRequestConstraints {
+	return scm.PullRequestConstraints{
+		ForbiddenLabelChars: ",", // Labels are sent as a comma separated list
+	}
+}
+
+// SetPullRequestDraft marks a pull request as draft, or as ready for review
+func (g *Gitee) SetPullRequestDraft(ctx context.Context, pullReq scm.PullRequest, draft bool) error {
+	pr := pullReq.(pullRequest)
+
+	err := g.request(ctx, http.MethodPatch, fmt.Sprintf("%s/pulls/%d", repoPath(pr.ownerName, pr.repoName), pr.number), nil, map[string]interface{}{
+		"draft": draft,
+	}, nil)
+	if err != nil {
+		return errors.Wrapf(err, "could not change the draft state of %s/%s#%d", pr.ownerName, pr.repoName, pr.number)
+	}
+
+	return nil
+}
+
+// ForkRepository forks a repository. If newOwner is empty, fork on the logged in user
+func (g *Gitee) ForkRepository(ctx context.Context, repo scm.Repository, newOwner string) (scm.Repository, error) {
+	r := repo.(repository)
+
+	forkTo := newOwner
+	if forkTo == "" {
+		user, err := g.getUser(ctx)
+		if err != nil {
+			return nil, err
+		}
+		forkTo = user.Login
+	}
+
+	existingRepo, err := g.getRepository(ctx, forkTo, r.name)
+	if err == nil {
+		return g.convertRepository(existingRepo)
+	} else if !isNotFound(err) {
+		return nil, err
+	}
+
+	body := map[string]interface{}{}
+	if newOwner != "" {
+		body["organization"] = newOwner
+	}
+
+	var createdRepo giteeRepository
+	err = g.request(ctx, http.MethodPost, repoPath(r.ownerName, r.name)+"/forks", nil, body, &createdRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.convertRepository(createdRepo)
+}
+
+func (g *Gitee) getUser(ctx context.Context) (*giteeUser, error) {
+	g.userMutex.Lock()
+	defer g.userMutex.Unlock()
+
+	if g.currentUser != nil {
+		return g.currentUser, nil
+	}
+
+	user := &giteeUser{}
+	if err := g.request(ctx, http.MethodGet, "/user", nil, nil, user); err != nil {
+		return nil, err
+	}
+
+	g.currentUser = user
+	return user, nil
+}
+
+// maps merge types to what they are called in the gitee api
+var mergeTypeGiteeName = map[scm.MergeType]string{
+	scm.MergeTypeMerge:  "merge",
+	scm.MergeTypeRebase: "rebase",
+	scm.MergeTypeSquash: "squash",
+}
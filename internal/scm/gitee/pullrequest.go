@@ -0,0 +1,62 @@
+package gitee
+
+import (
+	"fmt"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+)
+
+func convertPullRequest(pr giteePullRequest) pullRequest {
+	return pullRequest{
+		ownerName:   pr.Base.Repo.Namespace.Path,
+		repoName:    pr.Base.Repo.Path,
+		branchName:  pr.Head.Ref,
+		prOwnerName: pr.Head.Repo.Namespace.Path,
+		prRepoName:  pr.Head.Repo.Path,
+		number:      pr.Number,
+		webURL:      pr.HTMLURL,
+		status:      pullRequestStatus(pr),
+	}
+}
+
+func pullRequestStatus(pr giteePullRequest) scm.PullRequestStatus {
+	switch pr.State {
+	case "merged":
+		return scm.PullRequestStatusMerged
+	case "closed":
+		return scm.PullRequestStatusClosed
+	}
+
+	if pr.Mergeable {
+		return scm.PullRequestStatusSuccess
+	}
+	return scm.PullRequestStatusPending
+}
+
+type pullRequest struct {
+	ownerName   string
+	repoName    string
+	branchName  string
+	prOwnerName string
+	prRepoName  string
+	number      int
+	webURL      string
+	status      scm.PullRequestStatus
+}
+
+func (pr pullRequest) String() string {
+	return fmt.Sprintf("%s/%s #%d", pr.ownerName, pr.repoName, pr.number)
+}
+
+// RepositoryFullName returns the full name of the repository the pull request is made against
+func (pr pullRequest) RepositoryFullName() string {
+	return fmt.Sprintf("%s/%s", pr.ownerName, pr.repoName)
+}
+
+func (pr pullRequest) Status() scm.PullRequestStatus {
+	return pr.status
+}
+
+func (pr pullRequest) URL() string {
+	return pr.webURL
+}
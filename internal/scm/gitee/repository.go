@@ -0,0 +1,52 @@
+package gitee
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+func (g *Gitee) convertRepository(repo giteeRepository) (repository, error) {
+	u, err := url.Parse(repo.HTMLURL)
+	if err != nil {
+		return repository{}, err
+	}
+	if !strings.HasSuffix(u.Path, ".git") {
+		u.Path += ".git"
+	}
+	u.User = url.UserPassword("oauth2", g.token)
+
+	repoURL, fallbackURL := g.CloneProtocol.CloneURLs(u.String(), repo.SSHURL)
+
+	return repository{
+		url:           repoURL,
+		fallbackURL:   fallbackURL,
+		name:          repo.Path,
+		ownerName:     repo.Namespace.Path,
+		defaultBranch: repo.DefaultBranch,
+	}, nil
+}
+
+type repository struct {
+	url           string
+	fallbackURL   string
+	name          string
+	ownerName     string
+	defaultBranch string
+}
+
+func (r repository) CloneURL() string {
+	return r.url
+}
+
+func (r repository) FallbackCloneURL() string {
+	return r.fallbackURL
+}
+
+func (r repository) DefaultBranch() string {
+	return r.defaultBranch
+}
+
+func (r repository) FullName() string {
+	return fmt.Sprintf("%s/%s", r.ownerName, r.name)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -26,7 +27,7 @@ type Config struct {
 	MergeTypes          []scm.MergeType
 	ForkMode            bool
 	ForkOwner           string
-	SSHAuth             bool
+	CloneProtocol       scm.CloneProtocol
 	ReadOnly            bool
 	CheckPermissions    bool
 }
@@ -60,7 +61,7 @@ func New(
 		baseURL:           config.BaseURL,
 		Fork:              config.ForkMode,
 		ForkOwner:         config.ForkOwner,
-		SSHAuth:           config.SSHAuth,
+		CloneProtocol:     config.CloneProtocol,
 		ghClient:          client,
 		ReadOnly:          config.ReadOnly,
 		checkPermissions:  config.CheckPermissions,
@@ -87,8 +88,8 @@ type Github struct {
 	// If set, the fork will happen to the ForkOwner value, and not the logged in user
 	ForkOwner string
 
-	// If set, use the SSH clone url instead of http(s)
-	SSHAuth bool
+	// The protocol used when cloning repositories
+	CloneProtocol scm.CloneProtocol
 
 	ghClient   *github.Client
 	httpClient *http.Client
@@ -129,9 +130,9 @@ func (rr RepositoryReference) String() string {
 
 // ParseRepositoryReference parses a repository reference from the format "ownerName/repoName"
 func ParseRepositoryReference(val string) (RepositoryReference, error) {
-	split := strings.Split(val, "/")
-	if len(split) != 2 {
-		return RepositoryReference{}, fmt.Errorf("could not parse repository reference: %s", val)
+	split, err := scm.SplitRepositoryReference(val, 2, 2)
+	if err != nil {
+		return RepositoryReference{}, fmt.Errorf(`%w, expected the format "ownerName/repoName"`, err)
 	}
 	return RepositoryReference{
 		OwnerName: split[0],
@@ -416,6 +417,10 @@ func (g *Github) getRepository(ctx context.Context, repoRef RepositoryReference)
 	if err != nil {
 		return nil, err
 	}
+	// Requests of renamed and moved repositories are redirected to their new name
+	if !strings.EqualFold(repo.GetFullName(), repoRef.String()) {
+		log.Infof("%s has been renamed or moved to %s, using the new name", repoRef.String(), repo.GetFullName())
+	}
 	return repo, nil
 }
 
@@ -427,8 +432,10 @@ func (g *Github) CreatePullRequest(ctx context.Context, repo scm.Repository, prR
 	g.modLock()
 	defer g.modUnlock()
 
-	pr, err := g.createPullRequest(ctx, r, prR, newPR)
-	if err != nil {
+	pr, resp, err := g.createPullRequest(ctx, r, prR, newPR)
+	if resp != nil && resp.StatusCode == http.StatusForbidden && !isRateLimitError(err) {
+		return nil, &scm.PullRequestNotPermittedError{Err: err}
+	} else if err != nil {
 		return nil, err
 	}
 
@@ -447,10 +454,10 @@ func (g *Github) CreatePullRequest(ctx context.Context, repo scm.Repository, prR
 	return convertPullRequest(pr), nil
 }
 
-func (g *Github) createPullRequest(ctx context.Context, repo repository, prRepo repository, newPR scm.NewPullRequest) (*github.PullRequest, error) {
+func (g *Github) createPullRequest(ctx context.Context, repo repository, prRepo repository, newPR scm.NewPullRequest) (*github.PullRequest, *github.Response, error) {
 	head := fmt.Sprintf("%s:%s", prRepo.ownerName, newPR.Head)
 
-	pr, _, err := retry(ctx, func() (*github.PullRequest, *github.Response, error) {
+	return retry(ctx, func() (*github.PullRequest, *github.Response, error) {
 		return g.ghClient.PullRequests.Create(ctx, repo.ownerName, repo.name, &github.NewPullRequest{
 			Title: &newPR.Title,
 			Body:  &newPR.Body,
@@ -459,7 +466,6 @@ func (g *Github) createPullRequest(ctx context.Context, repo repository, prRepo
 			Draft: &newPR.Draft,
 		})
 	})
-	return pr, err
 }
 
 func (g *Github) setReviewers(ctx context.Context, repo repository, newPR scm.NewPullRequest, createdPR *github.PullRequest) error {
@@ -491,7 +497,7 @@ func (g *Github) setReviewers(ctx context.Context, repo repository, newPR scm.Ne
 		}
 	}
 
-	if len(removedReviewers) > 0 || len(removedTeamReviewers) > 0 {
+	if (len(removedReviewers) > 0 || len(removedTeamReviewers) > 0) && !newPR.KeepReviewers {
 		_, err := retryWithoutReturn(ctx, func() (*github.Response, error) {
 			return g.ghClient.PullRequests.RemoveReviewers(ctx, repo.ownerName, repo.name, createdPR.GetNumber(), github.ReviewersRequest{
 				Reviewers:     removedReviewers,
@@ -631,11 +637,34 @@ func (g *Github) getPullRequests(ctx context.Context, branchName string, repos [
 	const fragment = `fragment repoProperties on Repository {
 		pullRequests(headRefName: $branchName, last: 1) {
 			nodes {
+				id
 				number
 				headRefName
 				closed
 				url
 				merged
+				createdAt
+				updatedAt
+				latestReviews(first: 100) {
+					nodes {
+						state
+						author {
+							login
+						}
+					}
+				}
+				reviewRequests(first: 100) {
+					nodes {
+						requestedReviewer {
+							... on User {
+								login
+							}
+							... on Team {
+								slug
+							}
+						}
+					}
+				}
 				baseRepository {
 					name
 					owner {
@@ -653,6 +682,19 @@ func (g *Github) getPullRequests(ctx context.Context, branchName string, repos [
 						commit {
 							statusCheckRollup {
 								state
+								contexts(first: 100) {
+									nodes {
+										... on CheckRun {
+											name
+											status
+											conclusion
+										}
+										... on StatusContext {
+											context
+											state
+										}
+									}
+								}
 							}
 						}
 					}
@@ -787,8 +829,162 @@ func (g *Github) GetOpenPullRequest(ctx context.Context, repo scm.Repository, br
 	return convertPullRequest(prs[0]), nil
 }
 
+// GetClosedPullRequest gets the latest closed, but not merged, pull request from a branch
+func (g *Github) GetClosedPullRequest(ctx context.Context, repo scm.Repository, branchName string) (scm.PullRequest, error) {
+	r := repo.(repository)
+
+	headOwner, err := g.headOwner(ctx, r.ownerName)
+	if err != nil {
+		return nil, err
+	}
+
+	prs, _, err := retry(ctx, func() ([]*github.PullRequest, *github.Response, error) {
+		return g.ghClient.PullRequests.List(ctx, headOwner, r.name, &github.PullRequestListOptions{
+			Head:      fmt.Sprintf("%s:%s", headOwner, branchName),
+			State:     "closed",
+			Sort:      "updated",
+			Direction: "desc",
+			ListOptions: github.ListOptions{
+				PerPage: 100,
+			},
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get closed pull requests: %w", err)
+	}
+	for _, pr := range prs {
+		if pr.MergedAt == nil {
+			return convertPullRequest(pr), nil
+		}
+	}
+	return nil, nil
+}
+
+// ReopenPullRequest reopens a closed pull request
+func (g *Github) ReopenPullRequest(ctx context.Context, pullReq scm.PullRequest) (scm.PullRequest, error) {
+	pr := pullReq.(pullRequest)
+
+	g.modLock()
+	defer g.modUnlock()
+
+	reopened, _, err := retry(ctx, func() (*github.PullRequest, *github.Response, error) {
+		return g.ghClient.PullRequests.Edit(ctx, pr.ownerName, pr.repoName, pr.number, &github.PullRequest{
+			State: &[]string{"open"}[0],
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return convertPullRequest(reopened), nil
+}
+
+// GetConflictingPullRequests gets all open pull requests, except the one from branchName, that change any of the files
+func (g *Github) GetConflictingPullRequests(ctx context.Context, repo scm.Repository, branchName string, files []string) ([]scm.PullRequest, error) {
+	r := repo.(repository)
+
+	var openPRs []*github.PullRequest
+	for i := 1; ; i++ {
+		prs, _, err := retry(ctx, func() ([]*github.PullRequest, *github.Response, error) {
+			return g.ghClient.PullRequests.List(ctx, r.ownerName, r.name, &github.PullRequestListOptions{
+				State: "open",
+				ListOptions: github.ListOptions{
+					Page:    i,
+					PerPage: 100,
+				},
+			})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get open pull requests: %w", err)
+		}
+		openPRs = append(openPRs, prs...)
+		if len(prs) != 100 {
+			break
+		}
+	}
+
+	var conflictingPRs []scm.PullRequest
+	for _, pr := range openPRs {
+		if pr.GetHead().GetRef() == branchName {
+			continue
+		}
+
+		prFiles, err := g.getPullRequestFiles(ctx, r, pr.GetNumber())
+		if err != nil {
+			return nil, err
+		}
+
+		if scm.Overlaps(prFiles, files) {
+			conflictingPRs = append(conflictingPRs, convertPullRequest(pr))
+		}
+	}
+
+	return conflictingPRs, nil
+}
+
+// FindCampaignPullRequest gets the open pull request to the base branch with the title and a body that contains the campaign marker
+func (g *Github) FindCampaignPullRequest(ctx context.Context, repo scm.Repository, baseBranch, title, marker string) (scm.PullRequest, string, error) {
+	r := repo.(repository)
+
+	for i := 1; ; i++ {
+		prs, _, err := retry(ctx, func() ([]*github.PullRequest, *github.Response, error) {
+			return g.ghClient.PullRequests.List(ctx, r.ownerName, r.name, &github.PullRequestListOptions{
+				State: "open",
+				Base:  baseBranch,
+				ListOptions: github.ListOptions{
+					Page:    i,
+					PerPage: 100,
+				},
+			})
+		})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get open pull requests: %w", err)
+		}
+		for _, pr := range prs {
+			if pr.GetTitle() == title && strings.Contains(pr.GetBody(), marker) {
+				return convertPullRequest(pr), pr.GetHead().GetRef(), nil
+			}
+		}
+		if len(prs) != 100 {
+			break
+		}
+	}
+
+	return nil, "", nil
+}
+
+func (g *Github) getPullRequestFiles(ctx context.Context, repo repository, number int) ([]string, error) {
+	var files []string
+	for i := 1; ; i++ {
+		ff, _, err := retry(ctx, func() ([]*github.CommitFile, *github.Response, error) {
+			return g.ghClient.PullRequests.ListFiles(ctx, repo.ownerName, repo.name, number, &github.ListOptions{
+				Page:    i,
+				PerPage: 100,
+			})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pull request files: %w", err)
+		}
+		for _, f := range ff {
+			files = append(files, f.GetFilename())
+		}
+		if len(ff) != 100 {
+			break
+		}
+	}
+	return files, nil
+}
+
 // MergePullRequest merges a pull request
 func (g *Github) MergePullRequest(ctx context.Context, pullReq scm.PullRequest) error {
+	return g.mergePullRequest(ctx, pullReq, scm.MergeMessage{})
+}
+
+// MergePullRequestWithMessage merges a pull request with a custom message of the merge, or squashed, commit
+func (g *Github) MergePullRequestWithMessage(ctx context.Context, pullReq scm.PullRequest, message scm.MergeMessage) error {
+	return g.mergePullRequest(ctx, pullReq, message)
+}
+
+func (g *Github) mergePullRequest(ctx context.Context, pullReq scm.PullRequest, message scm.MergeMessage) error {
 	pr := pullReq.(pullRequest)
 
 	g.modLock()
@@ -802,15 +998,15 @@ func (g *Github) MergePullRequest(ctx context.Context, pullReq scm.PullRequest)
 		return err
 	}
 
-	// Filter out all merge types to only the allowed ones, but keep the order of the ones left
-	mergeTypes := scm.MergeTypeIntersection(g.MergeTypes, repoMergeTypes(repo))
-	if len(mergeTypes) == 0 {
-		return errors.New("none of the configured merge types was permitted")
+	mergeType, err := g.chooseMergeType(repo)
+	if err != nil {
+		return err
 	}
 
 	_, _, err = retry(ctx, func() (*github.PullRequestMergeResult, *github.Response, error) {
-		return g.ghClient.PullRequests.Merge(ctx, pr.ownerName, pr.repoName, pr.number, "", &github.PullRequestOptions{
-			MergeMethod: mergeTypeGhName[mergeTypes[0]],
+		return g.ghClient.PullRequests.Merge(ctx, pr.ownerName, pr.repoName, pr.number, message.Body, &github.PullRequestOptions{
+			CommitTitle: message.Title,
+			MergeMethod: mergeTypeGhName[mergeType],
 		})
 	})
 	if err != nil {
@@ -829,8 +1025,57 @@ func (g *Github) MergePullRequest(ctx context.Context, pullReq scm.PullRequest)
 	return nil
 }
 
+// CreateRelease creates a release, or only a tag, on the merge commit of a merged pull request
+func (g *Github) CreateRelease(ctx context.Context, pullReq scm.PullRequest, release scm.NewRelease) error {
+	pr := pullReq.(pullRequest)
+
+	ghPR, _, err := retry(ctx, func() (*github.PullRequest, *github.Response, error) {
+		return g.ghClient.PullRequests.Get(ctx, pr.ownerName, pr.repoName, pr.number)
+	})
+	if err != nil {
+		return err
+	}
+	sha := ghPR.GetMergeCommitSHA()
+	if sha == "" {
+		return errors.New("the pull request has no merge commit")
+	}
+
+	g.modLock()
+	defer g.modUnlock()
+
+	if release.TagOnly {
+		ref := "refs/tags/" + release.TagName
+		_, _, err = retry(ctx, func() (*github.Reference, *github.Response, error) {
+			return g.ghClient.Git.CreateRef(ctx, pr.ownerName, pr.repoName, &github.Reference{
+				Ref:    &ref,
+				Object: &github.GitObject{SHA: &sha},
+			})
+		})
+		return err
+	}
+
+	_, _, err = retry(ctx, func() (*github.RepositoryRelease, *github.Response, error) {
+		return g.ghClient.Repositories.CreateRelease(ctx, pr.ownerName, pr.repoName, &github.RepositoryRelease{
+			TagName:         &release.TagName,
+			TargetCommitish: &sha,
+			Name:            &release.Name,
+			Body:            &release.Notes,
+		})
+	})
+	return err
+}
+
 // ClosePullRequest closes a pull request
 func (g *Github) ClosePullRequest(ctx context.Context, pullReq scm.PullRequest) error {
+	return g.closePullRequest(ctx, pullReq, true)
+}
+
+// ClosePullRequestKeepBranch closes a pull request without deleting its branch
+func (g *Github) ClosePullRequestKeepBranch(ctx context.Context, pullReq scm.PullRequest) error {
+	return g.closePullRequest(ctx, pullReq, false)
+}
+
+func (g *Github) closePullRequest(ctx context.Context, pullReq scm.PullRequest, deleteBranch bool) error {
 	pr := pullReq.(pullRequest)
 
 	g.modLock()
@@ -841,7 +1086,7 @@ func (g *Github) ClosePullRequest(ctx context.Context, pullReq scm.PullRequest)
 			State: &[]string{"closed"}[0],
 		})
 	})
-	if err != nil {
+	if err != nil || !deleteBranch {
 		return err
 	}
 
@@ -851,6 +1096,511 @@ func (g *Github) ClosePullRequest(ctx context.Context, pullReq scm.PullRequest)
 	return err
 }
 
+// SetPullRequestDraft converts a pull request to a draft, or marks it as ready for review
+func (g *Github) SetPullRequestDraft(ctx context.Context, pullReq scm.PullRequest, draft bool) error {
+	pr := pullReq.(pullRequest)
+
+	g.modLock()
+	defer g.modUnlock()
+
+	mutation := "markPullRequestReadyForReview"
+	if draft {
+		mutation = "convertPullRequestToDraft"
+	}
+
+	query := fmt.Sprintf(`mutation ($id: ID!) {
+		%s(input: {pullRequestId: $id}) {
+			clientMutationId
+		}
+	}`, mutation)
+
+	result := map[string]interface{}{}
+	return g.makeGraphQLRequest(ctx, query, map[string]interface{}{
+		"id": pr.nodeID,
+	}, &result)
+}
+
+// EnableAutoMerge makes a pull request be merged once all its requirements are met, with the first of the configured
+// merge types that the repository allows
+func (g *Github) EnableAutoMerge(ctx context.Context, pullReq scm.PullRequest) error {
+	pr := pullReq.(pullRequest)
+
+	g.modLock()
+	defer g.modUnlock()
+
+	repo, _, err := retry(ctx, func() (*github.Repository, *github.Response, error) {
+		return g.ghClient.Repositories.Get(ctx, pr.ownerName, pr.repoName)
+	})
+	if err != nil {
+		return err
+	}
+
+	mergeType, err := g.chooseMergeType(repo)
+	if err != nil {
+		return err
+	}
+
+	query := `mutation ($id: ID!, $mergeMethod: PullRequestMergeMethod!) {
+		enablePullRequestAutoMerge(input: {pullRequestId: $id, mergeMethod: $mergeMethod}) {
+			clientMutationId
+		}
+	}`
+
+	result := map[string]interface{}{}
+	return g.makeGraphQLRequest(ctx, query, map[string]interface{}{
+		"id":          pr.nodeID,
+		"mergeMethod": strings.ToUpper(mergeTypeGhName[mergeType]),
+	}, &result)
+}
+
+// UpdatePullRequestBranch merges the base branch of a pull request into its branch
+func (g *Github) UpdatePullRequestBranch(ctx context.Context, pullReq scm.PullRequest) error {
+	pr := pullReq.(pullRequest)
+
+	g.modLock()
+	defer g.modUnlock()
+
+	_, _, err := retry(ctx, func() (*github.PullRequestBranchUpdateResponse, *github.Response, error) {
+		return g.ghClient.PullRequests.UpdateBranch(ctx, pr.ownerName, pr.repoName, pr.number, nil)
+	})
+	// The branch is updated in the background, once GitHub has accepted the request
+	if _, isAccepted := err.(*github.AcceptedError); isAccepted {
+		return nil
+	}
+	return err
+}
+
+// chooseMergeType returns the first configured merge type that the repository allows, or the best merge type it allows if
+// it allows none of them
+func (g *Github) chooseMergeType(repo *github.Repository) (scm.MergeType, error) {
+	mergeType, fallback, err := scm.ChooseMergeType(g.MergeTypes, repoMergeTypes(repo))
+	if err != nil {
+		return scm.MergeTypeUnknown, errors.Wrapf(err, "could not merge in %s", repo.GetFullName())
+	}
+	if fallback {
+		log.Warnf("None of the configured merge types is allowed in %s, using %s instead", repo.GetFullName(), mergeType)
+	}
+	return mergeType, nil
+}
+
+// UserExists checks if a user exists
+func (g *Github) UserExists(ctx context.Context, username string) (bool, error) {
+	_, resp, err := retry(ctx, func() (*github.User, *github.Response, error) {
+		return g.ghClient.Users.Get(ctx, username)
+	})
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetPullRequestBase gets the branch a pull request is made against
+func (g *Github) GetPullRequestBase(ctx context.Context, pullReq scm.PullRequest) (string, error) {
+	pr := pullReq.(pullRequest)
+
+	ghPR, _, err := retry(ctx, func() (*github.PullRequest, *github.Response, error) {
+		return g.ghClient.PullRequests.Get(ctx, pr.ownerName, pr.repoName, pr.number)
+	})
+	if err != nil {
+		return "", err
+	}
+	return ghPR.GetBase().GetRef(), nil
+}
+
+// RetargetPullRequest changes the branch a pull request is made against
+func (g *Github) RetargetPullRequest(ctx context.Context, pullReq scm.PullRequest, base string) error {
+	pr := pullReq.(pullRequest)
+
+	g.modLock()
+	defer g.modUnlock()
+
+	_, _, err := retry(ctx, func() (*github.PullRequest, *github.Response, error) {
+		return g.ghClient.PullRequests.Edit(ctx, pr.ownerName, pr.repoName, pr.number, &github.PullRequest{
+			Base: &github.PullRequestBranch{Ref: &base},
+		})
+	})
+	return err
+}
+
+// BranchExists checks if a branch exists in a repository. Renamed branches are not followed to their new name
+func (g *Github) BranchExists(ctx context.Context, repo scm.Repository, branchName string) (bool, error) {
+	r := repo.(repository)
+
+	_, resp, err := retry(ctx, func() (*github.Branch, *github.Response, error) {
+		return g.ghClient.Repositories.GetBranch(ctx, r.ownerName, r.name, branchName, 0)
+	})
+	if resp != nil && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMovedPermanently) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetBranches gets the names of all branches in a repository
+func (g *Github) GetBranches(ctx context.Context, repo scm.Repository) ([]string, error) {
+	r := repo.(repository)
+
+	var names []string
+	for i := 1; ; i++ {
+		branches, _, err := retry(ctx, func() ([]*github.Branch, *github.Response, error) {
+			return g.ghClient.Repositories.ListBranches(ctx, r.ownerName, r.name, &github.BranchListOptions{
+				ListOptions: github.ListOptions{
+					Page:    i,
+					PerPage: 100,
+				},
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, branch := range branches {
+			names = append(names, branch.GetName())
+		}
+		if len(branches) != 100 {
+			break
+		}
+	}
+	return names, nil
+}
+
+// TeamExists checks if a team, in the format "org/team", exists. A team without an organization, as it's given to
+// pull requests, exists if it's in any of the organizations that repositories are listed from. If no organization is
+// known, it's assumed to exist
+func (g *Github) TeamExists(ctx context.Context, team string) (bool, error) {
+	if org, slug, found := strings.Cut(team, "/"); found {
+		return g.teamExists(ctx, org, slug)
+	}
+
+	for _, org := range g.teamOrganizations() {
+		exists, err := g.teamExists(ctx, org, team)
+		if err != nil || exists {
+			return exists, err
+		}
+	}
+	return len(g.teamOrganizations()) == 0, nil
+}
+
+func (g *Github) teamExists(ctx context.Context, org, slug string) (bool, error) {
+	_, resp, err := retry(ctx, func() (*github.Team, *github.Response, error) {
+		return g.ghClient.Teams.GetTeamBySlug(ctx, org, slug)
+	})
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// teamOrganizations returns the organizations, and owners of repositories, that repositories are listed from
+func (g *Github) teamOrganizations() []string {
+	orgs := append([]string{}, g.Organizations...)
+	for _, repo := range g.Repositories {
+		if !slices.Contains(orgs, repo.OwnerName) {
+			orgs = append(orgs, repo.OwnerName)
+		}
+	}
+	return orgs
+}
+
+// PullRequestConstraints returns the limits GitHub puts on pull requests
+func (g *Github) PullRequestConstraints() scm.PullRequestConstraints {
+	return scm.PullRequestConstraints{
+		MaxTitleLength: 256,
+		MaxLabelLength: 50,
+	}
+}
+
+// MaxPullRequestBodyLength returns the maximum number of characters in the body of a pull request
+func (g *Github) MaxPullRequestBodyLength() int {
+	return 65536
+}
+
+// CommentOnPullRequest adds a comment to a pull request
+func (g *Github) CommentOnPullRequest(ctx context.Context, pullReq scm.PullRequest, comment string) error {
+	pr := pullReq.(pullRequest)
+
+	g.modLock()
+	defer g.modUnlock()
+
+	_, _, err := retry(ctx, func() (*github.IssueComment, *github.Response, error) {
+		return g.ghClient.Issues.CreateComment(ctx, pr.ownerName, pr.repoName, pr.number, &github.IssueComment{
+			Body: &comment,
+		})
+	})
+	return err
+}
+
+// PullRequestComments returns the bodies of all comments on a pull request
+func (g *Github) PullRequestComments(ctx context.Context, pullReq scm.PullRequest) ([]string, error) {
+	pr := pullReq.(pullRequest)
+
+	var comments []string
+	for i := 1; ; i++ {
+		cc, _, err := retry(ctx, func() ([]*github.IssueComment, *github.Response, error) {
+			return g.ghClient.Issues.ListComments(ctx, pr.ownerName, pr.repoName, pr.number, &github.IssueListCommentsOptions{
+				ListOptions: github.ListOptions{
+					Page:    i,
+					PerPage: 100,
+				},
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range cc {
+			comments = append(comments, c.GetBody())
+		}
+		if len(cc) != 100 {
+			break
+		}
+	}
+
+	return comments, nil
+}
+
+// CreateCheckRun adds a completed check run to a commit of a pull request. Check runs can only be created with the token of a GitHub App
+func (g *Github) CreateCheckRun(ctx context.Context, pullReq scm.PullRequest, checkRun scm.CheckRun) error {
+	pr := pullReq.(pullRequest)
+
+	g.modLock()
+	defer g.modUnlock()
+
+	status := "completed"
+	conclusion := "success"
+	_, _, err := retry(ctx, func() (*github.CheckRun, *github.Response, error) {
+		return g.ghClient.Checks.CreateCheckRun(ctx, pr.ownerName, pr.repoName, github.CreateCheckRunOptions{
+			Name:       checkRun.Name,
+			HeadSHA:    checkRun.SHA,
+			Status:     &status,
+			Conclusion: &conclusion,
+			Output: &github.CheckRunOutput{
+				Title:   &checkRun.Title,
+				Summary: &checkRun.Summary,
+			},
+		})
+	})
+	return err
+}
+
+// SetCommitStatus sets a successful status on a commit of a pull request
+func (g *Github) SetCommitStatus(ctx context.Context, pullReq scm.PullRequest, status scm.CommitStatus) error {
+	pr := pullReq.(pullRequest)
+
+	g.modLock()
+	defer g.modUnlock()
+
+	repoStatus := &github.RepoStatus{
+		State:       &[]string{"success"}[0],
+		Context:     &status.Context,
+		Description: &status.Description,
+	}
+	if status.TargetURL != "" {
+		repoStatus.TargetURL = &status.TargetURL
+	}
+	_, _, err := retry(ctx, func() (*github.RepoStatus, *github.Response, error) {
+		return g.ghClient.Repositories.CreateStatus(ctx, pr.ownerName, pr.repoName, status.SHA, repoStatus)
+	})
+	return err
+}
+
+// RerequestReviews requests a new review from everyone who has approved, or requested changes on, the pull request
+func (g *Github) RerequestReviews(ctx context.Context, pullReq scm.PullRequest) ([]string, error) {
+	pr := pullReq.(pullRequest)
+
+	reviews, err := g.getReviews(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewers := []string{}
+	for _, review := range reviews {
+		login := review.GetUser().GetLogin()
+		state := review.GetState()
+		if (state == "APPROVED" || state == "CHANGES_REQUESTED") && !slices.Contains(reviewers, login) {
+			reviewers = append(reviewers, login)
+		}
+	}
+	if len(reviewers) == 0 {
+		return nil, nil
+	}
+
+	g.modLock()
+	defer g.modUnlock()
+
+	_, _, err = retry(ctx, func() (*github.PullRequest, *github.Response, error) {
+		return g.ghClient.PullRequests.RequestReviewers(ctx, pr.ownerName, pr.repoName, pr.number, github.ReviewersRequest{
+			Reviewers: reviewers,
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to request reviews: %w", err)
+	}
+
+	return reviewers, nil
+}
+
+// ResetApprovals dismisses every approving review of the pull request
+func (g *Github) ResetApprovals(ctx context.Context, pullReq scm.PullRequest) ([]string, error) {
+	pr := pullReq.(pullRequest)
+
+	reviews, err := g.getReviews(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	g.modLock()
+	defer g.modUnlock()
+
+	message := "The pull request was updated by multi-gitter, the new changes have to be reviewed again"
+	approvers := []string{}
+	for _, review := range reviews {
+		if review.GetState() != "APPROVED" {
+			continue
+		}
+		_, _, err := retry(ctx, func() (*github.PullRequestReview, *github.Response, error) {
+			return g.ghClient.PullRequests.DismissReview(ctx, pr.ownerName, pr.repoName, pr.number, review.GetID(), &github.PullRequestReviewDismissalRequest{
+				Message: &message,
+			})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to dismiss the review of %s: %w", review.GetUser().GetLogin(), err)
+		}
+		if login := review.GetUser().GetLogin(); !slices.Contains(approvers, login) {
+			approvers = append(approvers, login)
+		}
+	}
+
+	return approvers, nil
+}
+
+func (g *Github) getReviews(ctx context.Context, pr pullRequest) ([]*github.PullRequestReview, error) {
+	var reviews []*github.PullRequestReview
+	for i := 1; ; i++ {
+		rr, _, err := retry(ctx, func() ([]*github.PullRequestReview, *github.Response, error) {
+			return g.ghClient.PullRequests.ListReviews(ctx, pr.ownerName, pr.repoName, pr.number, &github.ListOptions{
+				Page:    i,
+				PerPage: 100,
+			})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get reviews: %w", err)
+		}
+		reviews = append(reviews, rr...)
+		if len(rr) != 100 {
+			break
+		}
+	}
+	return reviews, nil
+}
+
+// RepositoryAvailable returns if the repository still exists, and is neither archived nor disabled
+func (g *Github) RepositoryAvailable(ctx context.Context, repo scm.Repository) (bool, error) {
+	r := repo.(repository)
+
+	ghRepo, resp, err := retry(ctx, func() (*github.Repository, *github.Response, error) {
+		return g.ghClient.Repositories.GetByID(ctx, r.id)
+	})
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return !ghRepo.GetArchived() && !ghRepo.GetDisabled(), nil
+}
+
+// GetRequiredStatusChecks gets the names of the status checks that are required to pass before merging into the branch
+func (g *Github) GetRequiredStatusChecks(ctx context.Context, repo scm.Repository, branchName string) ([]string, error) {
+	r := repo.(repository)
+
+	checks, resp, err := retry(ctx, func() (*github.RequiredStatusChecks, *github.Response, error) {
+		return g.ghClient.Repositories.GetRequiredStatusChecks(ctx, r.ownerName, r.name, branchName)
+	})
+	if errors.Is(err, github.ErrBranchNotProtected) || (resp != nil && resp.StatusCode == http.StatusNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get required status checks: %w", err)
+	}
+
+	names := make([]string, 0, len(checks.Checks)+len(checks.Contexts))
+	for _, check := range checks.Checks {
+		names = append(names, check.Context)
+	}
+	for _, c := range checks.Contexts {
+		if !slices.Contains(names, c) {
+			names = append(names, c)
+		}
+	}
+	return names, nil
+}
+
+// UpsertIssue creates an issue with the title in the repository, or updates the body of it if an open issue with the same title already exists
+func (g *Github) UpsertIssue(ctx context.Context, repoName string, title string, body string) (string, error) {
+	repoRef, err := ParseRepositoryReference(repoName)
+	if err != nil {
+		return "", err
+	}
+
+	existing, err := g.findOpenIssue(ctx, repoRef, title)
+	if err != nil {
+		return "", err
+	}
+
+	g.modLock()
+	defer g.modUnlock()
+
+	if existing != nil {
+		issue, _, err := retry(ctx, func() (*github.Issue, *github.Response, error) {
+			return g.ghClient.Issues.Edit(ctx, repoRef.OwnerName, repoRef.Name, existing.GetNumber(), &github.IssueRequest{
+				Body: &body,
+			})
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to update issue: %w", err)
+		}
+		return issue.GetHTMLURL(), nil
+	}
+
+	issue, _, err := retry(ctx, func() (*github.Issue, *github.Response, error) {
+		return g.ghClient.Issues.Create(ctx, repoRef.OwnerName, repoRef.Name, &github.IssueRequest{
+			Title: &title,
+			Body:  &body,
+		})
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create issue: %w", err)
+	}
+	return issue.GetHTMLURL(), nil
+}
+
+func (g *Github) findOpenIssue(ctx context.Context, repoRef RepositoryReference, title string) (*github.Issue, error) {
+	for i := 1; ; i++ {
+		issues, _, err := retry(ctx, func() ([]*github.Issue, *github.Response, error) {
+			return g.ghClient.Issues.ListByRepo(ctx, repoRef.OwnerName, repoRef.Name, &github.IssueListByRepoOptions{
+				State: "open",
+				ListOptions: github.ListOptions{
+					Page:    i,
+					PerPage: 100,
+				},
+			})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get issues: %w", err)
+		}
+		for _, issue := range issues {
+			if !issue.IsPullRequest() && issue.GetTitle() == title {
+				return issue, nil
+			}
+		}
+		if len(issues) != 100 {
+			return nil, nil
+		}
+	}
+}
+
 // ForkRepository forks a repository. If newOwner is empty, fork on the logged in user
 func (g *Github) ForkRepository(ctx context.Context, repo scm.Repository, newOwner string) (scm.Repository, error) {
 	r := repo.(repository)
@@ -889,6 +1639,60 @@ func (g *Github) ForkRepository(ctx context.Context, repo scm.Repository, newOwn
 	return g.convertRepo(createdRepo)
 }
 
+// DeleteFork deletes the fork a pull request was made from. The fork is not deleted if it has any other branches
+// than the default branch and the branch of the pull request, since it might then be in use by something else
+func (g *Github) DeleteFork(ctx context.Context, pullReq scm.PullRequest) (bool, error) {
+	pr := pullReq.(pullRequest)
+	if pr.prOwnerName == "" || (pr.prOwnerName == pr.ownerName && pr.prRepoName == pr.repoName) {
+		return false, nil
+	}
+
+	fork, resp, err := retry(ctx, func() (*github.Repository, *github.Response, error) {
+		return g.ghClient.Repositories.Get(ctx, pr.prOwnerName, pr.prRepoName)
+	})
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if !fork.GetFork() {
+		return false, nil
+	}
+
+	for i := 1; ; i++ {
+		branches, _, err := retry(ctx, func() ([]*github.Branch, *github.Response, error) {
+			return g.ghClient.Repositories.ListBranches(ctx, pr.prOwnerName, pr.prRepoName, &github.BranchListOptions{
+				ListOptions: github.ListOptions{
+					Page:    i,
+					PerPage: 100,
+				},
+			})
+		})
+		if err != nil {
+			return false, err
+		}
+		for _, branch := range branches {
+			if branch.GetName() != fork.GetDefaultBranch() && branch.GetName() != pr.branchName {
+				return false, nil
+			}
+		}
+		if len(branches) != 100 {
+			break
+		}
+	}
+
+	g.modLock()
+	defer g.modUnlock()
+
+	_, err = retryWithoutReturn(ctx, func() (*github.Response, error) {
+		return g.ghClient.Repositories.Delete(ctx, pr.prOwnerName, pr.prRepoName)
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // GetAutocompleteOrganizations gets organizations for autocompletion
 func (g *Github) GetAutocompleteOrganizations(ctx context.Context, _ string) ([]string, error) {
 	orgs, _, err := retry(ctx, func() ([]*github.Organization, *github.Response, error) {
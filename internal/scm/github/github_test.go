@@ -571,3 +571,41 @@ func Test_SearchTooManyResults(t *testing.T) {
 		assert.Len(t, repos, 0)
 	})
 }
+
+type teamTransport struct{}
+
+func (teamTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	status := http.StatusNotFound
+	if req.URL.Path == "/orgs/second-org/teams/frontend" {
+		status = http.StatusOK
+	}
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(strings.NewReader(`{}`)),
+		Request:    req,
+		Header:     make(http.Header),
+	}, nil
+}
+
+func Test_TeamExists(t *testing.T) {
+	gh, err := github.New(github.Config{
+		TransportMiddleware: func(http.RoundTripper) http.RoundTripper { return teamTransport{} },
+		RepoListing: github.RepositoryListing{
+			Organizations: []string{"first-org"},
+			Repositories:  []github.RepositoryReference{{OwnerName: "second-org", Name: "repo"}},
+		},
+	})
+	require.NoError(t, err)
+
+	tests := map[string]bool{
+		"second-org/frontend": true,
+		"first-org/frontend":  false,
+		"frontend":            true,
+		"backend":             false,
+	}
+	for team, want := range tests {
+		exists, err := gh.TeamExists(context.Background(), team)
+		require.NoError(t, err)
+		assert.Equal(t, want, exists, team)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -118,6 +119,7 @@ type graphqlRepo struct {
 }
 
 type graphqlPR struct {
+	ID             string `json:"id"`
 	Number         int    `json:"number"`
 	HeadRefName    string `json:"headRefName"`
 	Closed         bool   `json:"closed"`
@@ -139,9 +141,40 @@ type graphqlPR struct {
 		Nodes []struct {
 			Commit struct {
 				StatusCheckRollup struct {
-					State *graphqlPullRequestState `json:"state"`
+					State    *graphqlPullRequestState `json:"state"`
+					Contexts struct {
+						Nodes []graphqlCheckContext `json:"nodes"`
+					} `json:"contexts"`
 				} `json:"statusCheckRollup"`
 			} `json:"commit"`
 		} `json:"nodes"`
 	} `json:"commits"`
+
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+	LatestReviews struct {
+		Nodes []struct {
+			State  string `json:"state"`
+			Author struct {
+				Login string `json:"login"`
+			} `json:"author"`
+		} `json:"nodes"`
+	} `json:"latestReviews"`
+	ReviewRequests struct {
+		Nodes []struct {
+			RequestedReviewer struct {
+				Login string `json:"login"`
+				Slug  string `json:"slug"`
+			} `json:"requestedReviewer"`
+		} `json:"nodes"`
+	} `json:"reviewRequests"`
+}
+
+// graphqlCheckContext is a check run, or a commit status, of the last commit of a pull request
+type graphqlCheckContext struct {
+	Name       string `json:"name"`       // The name of a check run
+	Status     string `json:"status"`     // The status of a check run, like "IN_PROGRESS" or "COMPLETED"
+	Conclusion string `json:"conclusion"` // The conclusion of a completed check run, like "SUCCESS" or "FAILURE"
+	Context    string `json:"context"`    // The name of a commit status
+	State      string `json:"state"`      // The state of a commit status, like "PENDING" or "SUCCESS"
 }
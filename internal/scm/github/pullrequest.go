@@ -2,6 +2,7 @@ package github
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/google/go-github/v59/github"
 
@@ -16,15 +17,24 @@ func convertPullRequest(pr *github.PullRequest) pullRequest {
 		prOwnerName: pr.GetHead().GetUser().GetLogin(),
 		prRepoName:  pr.GetHead().GetRepo().GetName(),
 		number:      pr.GetNumber(),
+		nodeID:      pr.GetNodeID(),
 		guiURL:      pr.GetHTMLURL(),
+		details: scm.PullRequestDetails{
+			ID:        pr.GetNodeID(),
+			Number:    pr.GetNumber(),
+			CreatedAt: pr.GetCreatedAt().Time,
+			UpdatedAt: pr.GetUpdatedAt().Time,
+		},
 	}
 }
 
 func convertGraphQLPullRequest(pr graphqlPR) pullRequest {
 	var combinedStatus *graphqlPullRequestState
+	var unsuccessful []string
 	nodes := pr.Commits.Nodes
 	if len(nodes) > 0 {
 		combinedStatus = nodes[0].Commit.StatusCheckRollup.State
+		unsuccessful = unsuccessfulChecks(nodes[0].Commit.StatusCheckRollup.Contexts.Nodes)
 	}
 
 	status := scm.PullRequestStatusUnknown
@@ -46,6 +56,29 @@ func convertGraphQLPullRequest(pr graphqlPR) pullRequest {
 		}
 	}
 
+	var reviews []scm.PullRequestReview
+	for _, review := range pr.LatestReviews.Nodes {
+		reviews = append(reviews, scm.PullRequestReview{
+			Reviewer: review.Author.Login,
+			State:    strings.ToLower(review.State),
+		})
+	}
+	for _, request := range pr.ReviewRequests.Nodes {
+		reviewer := request.RequestedReviewer.Login
+		if reviewer == "" {
+			reviewer = request.RequestedReviewer.Slug
+		}
+		reviews = append(reviews, scm.PullRequestReview{
+			Reviewer: reviewer,
+			State:    "requested",
+		})
+	}
+
+	var checks string
+	if combinedStatus != nil {
+		checks = strings.ToLower(string(*combinedStatus))
+	}
+
 	return pullRequest{
 		ownerName:   pr.BaseRepository.Owner.Login,
 		repoName:    pr.BaseRepository.Name,
@@ -53,9 +86,43 @@ func convertGraphQLPullRequest(pr graphqlPR) pullRequest {
 		prOwnerName: pr.HeadRepository.Owner.Login,
 		prRepoName:  pr.HeadRepository.Name,
 		number:      pr.Number,
+		nodeID:      pr.ID,
 		guiURL:      pr.URL,
 		status:      status,
+		details: scm.PullRequestDetails{
+			ID:                 pr.ID,
+			Number:             pr.Number,
+			Checks:             checks,
+			UnsuccessfulChecks: unsuccessful,
+			Reviews:            reviews,
+			CreatedAt:          pr.CreatedAt,
+			UpdatedAt:          pr.UpdatedAt,
+		},
+	}
+}
+
+// unsuccessfulChecks returns the checks, and commit statuses, that have not passed, in the format "name: state"
+func unsuccessfulChecks(contexts []graphqlCheckContext) []string {
+	var unsuccessful []string
+	for _, context := range contexts {
+		var name, state string
+		switch {
+		case context.Context != "":
+			name, state = context.Context, context.State
+			if state == "SUCCESS" {
+				continue
+			}
+		case context.Status != "COMPLETED":
+			name, state = context.Name, context.Status
+		default:
+			name, state = context.Name, context.Conclusion
+			if state == "SUCCESS" || state == "NEUTRAL" || state == "SKIPPED" {
+				continue
+			}
+		}
+		unsuccessful = append(unsuccessful, fmt.Sprintf("%s: %s", name, strings.ToLower(state)))
 	}
+	return unsuccessful
 }
 
 type pullRequest struct {
@@ -65,14 +132,21 @@ type pullRequest struct {
 	prOwnerName string
 	prRepoName  string
 	number      int
+	nodeID      string // The GraphQL node id of the pull request
 	guiURL      string
 	status      scm.PullRequestStatus
+	details     scm.PullRequestDetails
 }
 
 func (pr pullRequest) String() string {
 	return fmt.Sprintf("%s/%s #%d", pr.ownerName, pr.repoName, pr.number)
 }
 
+// RepositoryFullName returns the full name of the repository the pull request is made against
+func (pr pullRequest) RepositoryFullName() string {
+	return fmt.Sprintf("%s/%s", pr.ownerName, pr.repoName)
+}
+
 func (pr pullRequest) Status() scm.PullRequestStatus {
 	return pr.status
 }
@@ -80,3 +154,7 @@ func (pr pullRequest) Status() scm.PullRequestStatus {
 func (pr pullRequest) URL() string {
 	return pr.guiURL
 }
+
+func (pr pullRequest) Details() scm.PullRequestDetails {
+	return pr.details
+}
@@ -52,15 +52,34 @@ func Test_convertGraphQLPullRequest(t *testing.T) {
 			prRepoName:  "pr_owner",
 			number:      1,
 			guiURL:      "http://dummy.url",
+			details: scm.PullRequestDetails{
+				Number: 1,
+			},
 		},
 	}}
 
 	for _, scenario := range scenarios {
 		t.Run(scenario.name, func(t *testing.T) {
 			got := convertGraphQLPullRequest(scenario.pr)
-			if got != scenario.expected {
-				assert.Equal(t, scenario.expected, got)
-			}
+			assert.Equal(t, scenario.expected, got)
 		})
 	}
 }
+
+func Test_unsuccessfulChecks(t *testing.T) {
+	contexts := []graphqlCheckContext{
+		{Name: "build", Status: "COMPLETED", Conclusion: "SUCCESS"},
+		{Name: "lint", Status: "COMPLETED", Conclusion: "FAILURE"},
+		{Name: "optional", Status: "COMPLETED", Conclusion: "SKIPPED"},
+		{Name: "test", Status: "IN_PROGRESS"},
+		{Context: "ci/jenkins", State: "SUCCESS"},
+		{Context: "multi-gitter/run", State: "PENDING"},
+	}
+	assert.Equal(t, []string{
+		"lint: failure",
+		"test: in_progress",
+		"multi-gitter/run: pending",
+	}, unsuccessfulChecks(contexts))
+
+	assert.Nil(t, unsuccessfulChecks(nil))
+}
@@ -3,27 +3,26 @@ package github
 import (
 	"fmt"
 	"net/url"
+	"strconv"
 
 	"github.com/google/go-github/v59/github"
 	"github.com/pkg/errors"
 )
 
 func (g *Github) convertRepo(r *github.Repository) (repository, error) {
-	var repoURL string
-	if g.SSHAuth {
-		repoURL = r.GetSSHURL()
-	} else {
-		u, err := url.Parse(r.GetCloneURL())
-		if err != nil {
-			return repository{}, errors.Wrap(err, "could not parse github clone error")
-		}
-		// Set the token as https://oauth2@TOKEN@url
-		u.User = url.UserPassword("oauth2", g.token)
-		repoURL = u.String()
+	u, err := url.Parse(r.GetCloneURL())
+	if err != nil {
+		return repository{}, errors.Wrap(err, "could not parse github clone error")
 	}
+	// Set the token as https://oauth2@TOKEN@url
+	u.User = url.UserPassword("oauth2", g.token)
+
+	repoURL, fallbackURL := g.CloneProtocol.CloneURLs(u.String(), r.GetSSHURL())
 
 	return repository{
 		url:           repoURL,
+		fallbackURL:   fallbackURL,
+		id:            r.GetID(),
 		name:          r.GetName(),
 		ownerName:     r.GetOwner().GetLogin(),
 		defaultBranch: r.GetDefaultBranch(),
@@ -32,15 +31,26 @@ func (g *Github) convertRepo(r *github.Repository) (repository, error) {
 
 type repository struct {
 	url           string
+	fallbackURL   string
+	id            int64
 	name          string
 	ownerName     string
 	defaultBranch string
 }
 
+// ID returns the id of the repository, which is kept when it's renamed or moved
+func (r repository) ID() string {
+	return strconv.FormatInt(r.id, 10)
+}
+
 func (r repository) CloneURL() string {
 	return r.url
 }
 
+func (r repository) FallbackCloneURL() string {
+	return r.fallbackURL
+}
+
 func (r repository) DefaultBranch() string {
 	return r.defaultBranch
 }
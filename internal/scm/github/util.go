@@ -1,6 +1,7 @@
 package github
 
 import (
+	"errors"
 	"strings"
 
 	"github.com/google/go-github/v59/github"
@@ -44,3 +45,15 @@ func chunkSlice[T any](stack []T, chunkSize int) [][]T {
 
 	return append(chunks, stack)
 }
+
+// isRateLimitError returns if an error is caused by a primary or secondary rate limit, which GitHub also responds
+// to with 403 Forbidden
+func isRateLimitError(err error) bool {
+	var rateLimitErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &rateLimitErr) || errors.As(err, &abuseErr) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "rate limit") || strings.Contains(msg, "rate-limit")
+}
@@ -3,6 +3,8 @@ package github
 import (
 	"testing"
 
+	"github.com/google/go-github/v59/github"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -15,3 +17,11 @@ func Test_chunkSlice(t *testing.T) {
 	assert.Equal(t, [][]int{{0, 1}, {2}}, chunkSlice([]int{0, 1, 2}, 2))
 	assert.Equal(t, [][]int{{0, 1, 2}}, chunkSlice([]int{0, 1, 2}, 4))
 }
+
+func Test_isRateLimitError(t *testing.T) {
+	assert.True(t, isRateLimitError(&github.RateLimitError{}))
+	assert.True(t, isRateLimitError(errors.WithMessage(&github.AbuseRateLimitError{}, "could not create pull request")))
+	assert.True(t, isRateLimitError(errors.New(secondaryErrorMsg)))
+	assert.True(t, isRateLimitError(errors.New("aborted while waiting for rate-limit")))
+	assert.False(t, isRateLimitError(errors.New("Resource not accessible by integration")))
+}
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -62,7 +64,7 @@ type RepositoryListing struct {
 // Config includes extra config parameters for the GitLab client
 type Config struct {
 	IncludeSubgroups bool
-	SSHAuth          bool
+	CloneProtocol    scm.CloneProtocol
 }
 
 // ProjectReference contains information to be able to reference a repository
@@ -71,15 +73,15 @@ type ProjectReference struct {
 	Name      string
 }
 
-// ParseProjectReference parses a repository reference from the format "ownerName/repoName"
+// ParseProjectReference parses a repository reference from the format "ownerName/repoName", where the owner may include any number of subgroups
 func ParseProjectReference(val string) (ProjectReference, error) {
-	lastSlashIndex := strings.LastIndex(val, "/")
-	if lastSlashIndex == -1 {
-		return ProjectReference{}, fmt.Errorf("could not parse repository reference: %s", val)
+	split, err := scm.SplitRepositoryReference(val, 2, 0)
+	if err != nil {
+		return ProjectReference{}, fmt.Errorf(`%w, expected the format "groupName/projectName" or "groupName/subgroupName/projectName"`, err)
 	}
 	return ProjectReference{
-		OwnerName: val[:lastSlashIndex],
-		Name:      val[lastSlashIndex+1:],
+		OwnerName: strings.Join(split[:len(split)-1], "/"),
+		Name:      split[len(split)-1],
 	}, nil
 }
 
@@ -185,14 +187,19 @@ func (g *Gitlab) getGroupProjects(ctx context.Context, groupName string) ([]*git
 }
 
 func (g *Gitlab) getProject(ctx context.Context, projRef ProjectReference) (*gitlab.Project, error) {
+	path := fmt.Sprintf("%s/%s", projRef.OwnerName, projRef.Name)
 	project, _, err := g.glClient.Projects.GetProject(
-		fmt.Sprintf("%s/%s", projRef.OwnerName, projRef.Name),
+		path,
 		nil,
 		gitlab.WithContext(ctx),
 	)
 	if err != nil {
 		return nil, err
 	}
+	// Requests of renamed and moved projects are redirected to their new path
+	if !strings.EqualFold(project.PathWithNamespace, path) {
+		log.Infof("%s has been renamed or moved to %s, using the new name", path, project.PathWithNamespace)
+	}
 	return project, err
 }
 
@@ -242,7 +249,7 @@ func (g *Gitlab) CreatePullRequest(ctx context.Context, repo scm.Repository, prR
 
 	labels := gitlab.LabelOptions(newPR.Labels)
 	removeSourceBranch := true
-	mr, _, err := g.glClient.MergeRequests.CreateMergeRequest(prR.pid, &gitlab.CreateMergeRequestOptions{
+	mr, resp, err := g.glClient.MergeRequests.CreateMergeRequest(prR.pid, &gitlab.CreateMergeRequestOptions{
 		Title:              &prTitle,
 		Description:        &newPR.Body,
 		SourceBranch:       &newPR.Head,
@@ -254,7 +261,9 @@ func (g *Gitlab) CreatePullRequest(ctx context.Context, repo scm.Repository, prR
 		AssigneeIDs:        &assigneesIDs,
 		Labels:             &labels,
 	})
-	if err != nil {
+	if resp != nil && resp.StatusCode == http.StatusForbidden {
+		return nil, &scm.PullRequestNotPermittedError{Err: err}
+	} else if err != nil {
 		return nil, err
 	}
 
@@ -301,6 +310,76 @@ func (g *Gitlab) getUserIDs(ctx context.Context, usernames []string) ([]int, err
 	return userIDs, nil
 }
 
+// UserExists checks if a user exists
+func (g *Gitlab) UserExists(ctx context.Context, username string) (bool, error) {
+	users, _, err := g.glClient.Users.ListUsers(&gitlab.ListUsersOptions{
+		Username: &username,
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return false, err
+	}
+	return len(users) == 1, nil
+}
+
+// GetPullRequestBase gets the branch a merge request is made against
+func (g *Gitlab) GetPullRequestBase(ctx context.Context, pullReq scm.PullRequest) (string, error) {
+	pr := pullReq.(pullRequest)
+
+	mr, _, err := g.glClient.MergeRequests.GetMergeRequest(pr.targetPID, pr.iid, nil, gitlab.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	return mr.TargetBranch, nil
+}
+
+// RetargetPullRequest changes the branch a merge request is made against
+func (g *Gitlab) RetargetPullRequest(ctx context.Context, pullReq scm.PullRequest, base string) error {
+	pr := pullReq.(pullRequest)
+
+	_, _, err := g.glClient.MergeRequests.UpdateMergeRequest(pr.targetPID, pr.iid, &gitlab.UpdateMergeRequestOptions{
+		TargetBranch: &base,
+	}, gitlab.WithContext(ctx))
+	return err
+}
+
+// BranchExists checks if a branch exists in a repository
+func (g *Gitlab) BranchExists(ctx context.Context, repo scm.Repository, branchName string) (bool, error) {
+	project := repo.(repository)
+
+	_, resp, err := g.glClient.Branches.GetBranch(project.pid, branchName, gitlab.WithContext(ctx))
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetBranches gets the names of all branches in a repository
+func (g *Gitlab) GetBranches(ctx context.Context, repo scm.Repository) ([]string, error) {
+	project := repo.(repository)
+
+	var names []string
+	for i := 1; ; i++ {
+		branches, _, err := g.glClient.Branches.ListBranches(project.pid, &gitlab.ListBranchesOptions{
+			ListOptions: gitlab.ListOptions{
+				Page:    i,
+				PerPage: 100,
+			},
+		}, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+		for _, branch := range branches {
+			names = append(names, branch.Name)
+		}
+		if len(branches) != 100 {
+			break
+		}
+	}
+	return names, nil
+}
+
 // UpdatePullRequest updates an existing pull request
 func (g *Gitlab) UpdatePullRequest(ctx context.Context, repo scm.Repository, pullReq scm.PullRequest, updatedPR scm.NewPullRequest) (scm.PullRequest, error) {
 	r := repo.(repository)
@@ -311,6 +390,19 @@ func (g *Gitlab) UpdatePullRequest(ctx context.Context, repo scm.Repository, pul
 		return nil, err
 	}
 
+	// The reviewers are replaced, so the ones that should be kept has to be part of the new reviewers
+	if updatedPR.KeepReviewers {
+		mr, _, err := g.glClient.MergeRequests.GetMergeRequest(pr.targetPID, pr.iid, nil, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+		for _, reviewer := range mr.Reviewers {
+			if !slices.Contains(reviewersIDs, reviewer.ID) {
+				reviewersIDs = append(reviewersIDs, reviewer.ID)
+			}
+		}
+	}
+
 	assigneesIDs, err := g.getUserIds(ctx, updatedPR.Assignees)
 	if err != nil {
 		return nil, err
@@ -377,7 +469,32 @@ func convertMergeRequest(mr *gitlab.MergeRequest, repoName, ownerName string) pu
 		status:     pullRequestStatus(mr),
 		iid:        mr.IID,
 		webURL:     mr.WebURL,
+		details:    mergeRequestDetails(mr),
+	}
+}
+
+func mergeRequestDetails(mr *gitlab.MergeRequest) scm.PullRequestDetails {
+	details := scm.PullRequestDetails{
+		ID:     strconv.Itoa(mr.ID),
+		Number: mr.IID,
+	}
+	if mr.HeadPipeline != nil {
+		details.Checks = mr.HeadPipeline.Status
+	}
+	// GitLab only lists who has been asked to review, not if they have approved
+	for _, reviewer := range mr.Reviewers {
+		details.Reviews = append(details.Reviews, scm.PullRequestReview{
+			Reviewer: reviewer.Username,
+			State:    "requested",
+		})
+	}
+	if mr.CreatedAt != nil {
+		details.CreatedAt = *mr.CreatedAt
+	}
+	if mr.UpdatedAt != nil {
+		details.UpdatedAt = *mr.UpdatedAt
 	}
+	return details
 }
 
 func (g *Gitlab) getPullRequest(ctx context.Context, branchName string, project *gitlab.Project) (*gitlab.MergeRequest, error) {
@@ -440,14 +557,157 @@ func (g *Gitlab) GetOpenPullRequest(ctx context.Context, repo scm.Repository, br
 	return convertMergeRequest(mrs[0], project.name, project.ownerName), nil
 }
 
+// GetClosedPullRequest gets the latest closed, but not merged, merge request from a branch
+func (g *Gitlab) GetClosedPullRequest(ctx context.Context, repo scm.Repository, branchName string) (scm.PullRequest, error) {
+	project := repo.(repository)
+
+	state := "closed"
+	orderBy := "updated_at"
+	mrs, _, err := g.glClient.MergeRequests.ListProjectMergeRequests(project.pid, &gitlab.ListProjectMergeRequestsOptions{
+		ListOptions: gitlab.ListOptions{
+			PerPage: 1,
+		},
+		SourceBranch: &branchName,
+		State:        &state,
+		OrderBy:      &orderBy,
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(mrs) == 0 {
+		return nil, nil
+	}
+
+	return convertMergeRequest(mrs[0], project.name, project.ownerName), nil
+}
+
+// ReopenPullRequest reopens a closed merge request
+func (g *Gitlab) ReopenPullRequest(ctx context.Context, pullReq scm.PullRequest) (scm.PullRequest, error) {
+	pr := pullReq.(pullRequest)
+
+	stateEvent := "reopen"
+	mr, _, err := g.glClient.MergeRequests.UpdateMergeRequest(pr.targetPID, pr.iid, &gitlab.UpdateMergeRequestOptions{
+		StateEvent: &stateEvent,
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return convertMergeRequest(mr, pr.repoName, pr.ownerName), nil
+}
+
+// FindCampaignPullRequest gets the open merge request to the base branch with the title and a description that contains the campaign marker
+func (g *Gitlab) FindCampaignPullRequest(ctx context.Context, repo scm.Repository, baseBranch, title, marker string) (scm.PullRequest, string, error) {
+	project := repo.(repository)
+
+	state := "opened"
+	for i := 1; ; i++ {
+		mrs, _, err := g.glClient.MergeRequests.ListProjectMergeRequests(project.pid, &gitlab.ListProjectMergeRequestsOptions{
+			ListOptions: gitlab.ListOptions{
+				Page:    i,
+				PerPage: 100,
+			},
+			State:        &state,
+			Search:       &title,
+			TargetBranch: &baseBranch,
+		}, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, "", err
+		}
+		for _, mr := range mrs {
+			if mr.Title == title && mr.TargetBranch == baseBranch && strings.Contains(mr.Description, marker) {
+				return convertMergeRequest(mr, project.name, project.ownerName), mr.SourceBranch, nil
+			}
+		}
+		if len(mrs) != 100 {
+			break
+		}
+	}
+
+	return nil, "", nil
+}
+
+// GetConflictingPullRequests gets all open merge requests, except the one from branchName, that change any of the files
+func (g *Gitlab) GetConflictingPullRequests(ctx context.Context, repo scm.Repository, branchName string, files []string) ([]scm.PullRequest, error) {
+	project := repo.(repository)
+
+	var openMRs []*gitlab.MergeRequest
+	state := "opened"
+	for i := 1; ; i++ {
+		mrs, _, err := g.glClient.MergeRequests.ListProjectMergeRequests(project.pid, &gitlab.ListProjectMergeRequestsOptions{
+			ListOptions: gitlab.ListOptions{
+				Page:    i,
+				PerPage: 100,
+			},
+			State: &state,
+		}, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+		openMRs = append(openMRs, mrs...)
+		if len(mrs) != 100 {
+			break
+		}
+	}
+
+	var conflictingPRs []scm.PullRequest
+	for _, mr := range openMRs {
+		if mr.SourceBranch == branchName {
+			continue
+		}
+
+		var mrFiles []string
+		for i := 1; ; i++ {
+			diffs, _, err := g.glClient.MergeRequests.ListMergeRequestDiffs(project.pid, mr.IID, &gitlab.ListMergeRequestDiffsOptions{
+				ListOptions: gitlab.ListOptions{
+					Page:    i,
+					PerPage: 100,
+				},
+			}, gitlab.WithContext(ctx))
+			if err != nil {
+				return nil, err
+			}
+			for _, diff := range diffs {
+				mrFiles = append(mrFiles, diff.OldPath, diff.NewPath)
+			}
+			if len(diffs) != 100 {
+				break
+			}
+		}
+
+		if scm.Overlaps(mrFiles, files) {
+			conflictingPRs = append(conflictingPRs, convertMergeRequest(mr, project.name, project.ownerName))
+		}
+	}
+
+	return conflictingPRs, nil
+}
+
 // MergePullRequest merges a pull request
 func (g *Gitlab) MergePullRequest(ctx context.Context, pullReq scm.PullRequest) error {
+	return g.mergePullRequest(ctx, pullReq, scm.MergeMessage{})
+}
+
+// MergePullRequestWithMessage merges a merge request with a custom message of the merge, or squashed, commit
+func (g *Gitlab) MergePullRequestWithMessage(ctx context.Context, pullReq scm.PullRequest, message scm.MergeMessage) error {
+	return g.mergePullRequest(ctx, pullReq, message)
+}
+
+func (g *Gitlab) mergePullRequest(ctx context.Context, pullReq scm.PullRequest, message scm.MergeMessage) error {
 	pr := pullReq.(pullRequest)
 
 	shouldRemoveSourceBranch := true
-	_, _, err := g.glClient.MergeRequests.AcceptMergeRequest(pr.targetPID, pr.iid, &gitlab.AcceptMergeRequestOptions{
+	opts := &gitlab.AcceptMergeRequestOptions{
 		ShouldRemoveSourceBranch: &shouldRemoveSourceBranch,
-	}, gitlab.WithContext(ctx))
+	}
+	// GitLab has no separate title, the first line of the message is the title
+	if commitMessage := strings.TrimSpace(message.Title + "\n\n" + message.Body); commitMessage != "" {
+		opts.MergeCommitMessage = &commitMessage
+		opts.SquashCommitMessage = &commitMessage
+	}
+
+	_, _, err := g.glClient.MergeRequests.AcceptMergeRequest(pr.targetPID, pr.iid, opts, gitlab.WithContext(ctx))
 	if err != nil {
 		return err
 	}
@@ -455,15 +715,46 @@ func (g *Gitlab) MergePullRequest(ctx context.Context, pullReq scm.PullRequest)
 	return nil
 }
 
+// EnableAutoMerge makes a merge request be merged once its pipeline succeeds
+func (g *Gitlab) EnableAutoMerge(ctx context.Context, pullReq scm.PullRequest) error {
+	pr := pullReq.(pullRequest)
+
+	shouldRemoveSourceBranch := true
+	mergeWhenPipelineSucceeds := true
+	_, _, err := g.glClient.MergeRequests.AcceptMergeRequest(pr.targetPID, pr.iid, &gitlab.AcceptMergeRequestOptions{
+		ShouldRemoveSourceBranch:  &shouldRemoveSourceBranch,
+		MergeWhenPipelineSucceeds: &mergeWhenPipelineSucceeds,
+	}, gitlab.WithContext(ctx))
+	return err
+}
+
+// UpdatePullRequestBranch rebases the source branch of a merge request on its target branch. The rebase is made in the
+// background, and the merge request is not ready to be merged until it's done
+func (g *Gitlab) UpdatePullRequestBranch(ctx context.Context, pullReq scm.PullRequest) error {
+	pr := pullReq.(pullRequest)
+
+	_, err := g.glClient.MergeRequests.RebaseMergeRequest(pr.targetPID, pr.iid, nil, gitlab.WithContext(ctx))
+	return err
+}
+
 // ClosePullRequest closes a pull request
 func (g *Gitlab) ClosePullRequest(ctx context.Context, pullReq scm.PullRequest) error {
+	return g.closePullRequest(ctx, pullReq, true)
+}
+
+// ClosePullRequestKeepBranch closes a merge request without deleting its branch
+func (g *Gitlab) ClosePullRequestKeepBranch(ctx context.Context, pullReq scm.PullRequest) error {
+	return g.closePullRequest(ctx, pullReq, false)
+}
+
+func (g *Gitlab) closePullRequest(ctx context.Context, pullReq scm.PullRequest, deleteBranch bool) error {
 	pr := pullReq.(pullRequest)
 
 	stateEvent := "close"
 	_, _, err := g.glClient.MergeRequests.UpdateMergeRequest(pr.targetPID, pr.iid, &gitlab.UpdateMergeRequestOptions{
 		StateEvent: &stateEvent,
 	}, gitlab.WithContext(ctx))
-	if err != nil {
+	if err != nil || !deleteBranch {
 		return err
 	}
 
@@ -475,6 +766,248 @@ func (g *Gitlab) ClosePullRequest(ctx context.Context, pullReq scm.PullRequest)
 	return nil
 }
 
+// SetPullRequestDraft marks a merge request as draft, or removes the draft mark
+func (g *Gitlab) SetPullRequestDraft(ctx context.Context, pullReq scm.PullRequest, draft bool) error {
+	pr := pullReq.(pullRequest)
+
+	mr, _, err := g.glClient.MergeRequests.GetMergeRequest(pr.targetPID, pr.iid, nil, gitlab.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	title := strings.TrimPrefix(mr.Title, "Draft: ")
+	if draft {
+		title = "Draft: " + title // See https://docs.gitlab.com/ee/user/project/merge_requests/drafts.html#mark-merge-requests-as-drafts
+	}
+	if title == mr.Title {
+		return nil
+	}
+
+	_, _, err = g.glClient.MergeRequests.UpdateMergeRequest(pr.targetPID, pr.iid, &gitlab.UpdateMergeRequestOptions{
+		Title: &title,
+	}, gitlab.WithContext(ctx))
+	return err
+}
+
+// RepositoryAvailable returns if the project still exists, and is not archived
+func (g *Gitlab) RepositoryAvailable(ctx context.Context, repo scm.Repository) (bool, error) {
+	project := repo.(repository)
+
+	p, resp, err := g.glClient.Projects.GetProject(project.pid, nil, gitlab.WithContext(ctx))
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return !p.Archived, nil
+}
+
+// SetCommitStatus sets a successful status on a commit of a merge request
+func (g *Gitlab) SetCommitStatus(ctx context.Context, pullReq scm.PullRequest, status scm.CommitStatus) error {
+	pr := pullReq.(pullRequest)
+
+	opts := &gitlab.SetCommitStatusOptions{
+		State:       gitlab.Success,
+		Name:        &status.Context,
+		Description: &status.Description,
+	}
+	if status.TargetURL != "" {
+		opts.TargetURL = &status.TargetURL
+	}
+	_, _, err := g.glClient.Commits.SetCommitStatus(pr.sourcePID, status.SHA, opts, gitlab.WithContext(ctx))
+	return err
+}
+
+// TriggerPipeline starts a merge request pipeline, for projects where they are not started automatically, like for bot users
+func (g *Gitlab) TriggerPipeline(ctx context.Context, pullReq scm.PullRequest) (scm.Pipeline, error) {
+	pr := pullReq.(pullRequest)
+
+	pipeline, _, err := g.glClient.MergeRequests.CreateMergeRequestPipeline(pr.targetPID, pr.iid, gitlab.WithContext(ctx))
+	if err != nil {
+		return scm.Pipeline{}, err
+	}
+	return convertPipeline(pipeline.ProjectID, pipeline.ID, pipeline.Status, pipeline.WebURL), nil
+}
+
+// GetPipeline gets the current state of a pipeline started by TriggerPipeline
+func (g *Gitlab) GetPipeline(ctx context.Context, pipeline scm.Pipeline) (scm.Pipeline, error) {
+	var pid, id int
+	if _, err := fmt.Sscanf(pipeline.ID, "%d/%d", &pid, &id); err != nil {
+		return scm.Pipeline{}, fmt.Errorf("invalid pipeline id %q", pipeline.ID)
+	}
+
+	p, _, err := g.glClient.Pipelines.GetPipeline(pid, id, gitlab.WithContext(ctx))
+	if err != nil {
+		return scm.Pipeline{}, err
+	}
+	return convertPipeline(p.ProjectID, p.ID, p.Status, p.WebURL), nil
+}
+
+// convertPipeline converts a pipeline, which is identified by the project it runs in together with its id
+func convertPipeline(pid, id int, status, webURL string) scm.Pipeline {
+	// Pipelines waiting for a manual action are done, since nothing will happen without anyone acting on them
+	done := false
+	switch status {
+	case "success", "failed", "canceled", "skipped", "manual":
+		done = true
+	}
+	return scm.Pipeline{
+		ID:     fmt.Sprintf("%d/%d", pid, id),
+		Status: status,
+		URL:    webURL,
+		Done:   done,
+		Passed: status == "success",
+	}
+}
+
+// PullRequestConstraints returns the limits GitLab puts on merge requests
+func (g *Gitlab) PullRequestConstraints() scm.PullRequestConstraints {
+	return scm.PullRequestConstraints{
+		MaxTitleLength:      255,
+		MaxLabelLength:      255,
+		ForbiddenLabelChars: ",", // Labels are sent as a comma separated list
+	}
+}
+
+// MaxPullRequestBodyLength returns the maximum number of characters in the description of a merge request
+func (g *Gitlab) MaxPullRequestBodyLength() int {
+	return 1048576
+}
+
+// CommentOnPullRequest adds a note to a merge request
+func (g *Gitlab) CommentOnPullRequest(ctx context.Context, pullReq scm.PullRequest, comment string) error {
+	pr := pullReq.(pullRequest)
+
+	_, _, err := g.glClient.Notes.CreateMergeRequestNote(pr.targetPID, pr.iid, &gitlab.CreateMergeRequestNoteOptions{
+		Body: &comment,
+	}, gitlab.WithContext(ctx))
+	return err
+}
+
+// PullRequestComments returns the bodies of all notes on a merge request
+func (g *Gitlab) PullRequestComments(ctx context.Context, pullReq scm.PullRequest) ([]string, error) {
+	pr := pullReq.(pullRequest)
+
+	var comments []string
+	for i := 1; ; i++ {
+		notes, _, err := g.glClient.Notes.ListMergeRequestNotes(pr.targetPID, pr.iid, &gitlab.ListMergeRequestNotesOptions{
+			ListOptions: gitlab.ListOptions{
+				PerPage: 100,
+				Page:    i,
+			},
+		}, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, note := range notes {
+			comments = append(comments, note.Body)
+		}
+
+		if len(notes) < 100 {
+			break
+		}
+	}
+	return comments, nil
+}
+
+// RerequestReviews notifies everyone who has approved the merge request that it has changed and should be reviewed again
+func (g *Gitlab) RerequestReviews(ctx context.Context, pullReq scm.PullRequest) ([]string, error) {
+	pr := pullReq.(pullRequest)
+
+	approvals, _, err := g.glClient.MergeRequests.GetMergeRequestApprovals(pr.targetPID, pr.iid, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	approvers := []string{}
+	mentions := []string{}
+	for _, approver := range approvals.ApprovedBy {
+		if approver.User == nil {
+			continue
+		}
+		approvers = append(approvers, approver.User.Username)
+		mentions = append(mentions, "@"+approver.User.Username)
+	}
+	if len(approvers) == 0 {
+		return nil, nil
+	}
+
+	note := fmt.Sprintf("%s the merge request has been updated since it was approved, please review the new changes.", strings.Join(mentions, " "))
+	_, _, err = g.glClient.Notes.CreateMergeRequestNote(pr.targetPID, pr.iid, &gitlab.CreateMergeRequestNoteOptions{
+		Body: &note,
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return approvers, nil
+}
+
+// ResetApprovals withdraws every approval of the merge request. GitLab only allows bot users to do this
+func (g *Gitlab) ResetApprovals(ctx context.Context, pullReq scm.PullRequest) ([]string, error) {
+	pr := pullReq.(pullRequest)
+
+	approvals, _, err := g.glClient.MergeRequests.GetMergeRequestApprovals(pr.targetPID, pr.iid, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	approvers := []string{}
+	for _, approver := range approvals.ApprovedBy {
+		if approver.User != nil {
+			approvers = append(approvers, approver.User.Username)
+		}
+	}
+	if len(approvers) == 0 {
+		return nil, nil
+	}
+
+	_, err = g.glClient.MergeRequestApprovals.ResetApprovalsOfMergeRequest(pr.targetPID, pr.iid, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return approvers, nil
+}
+
+// UpsertIssue creates an issue with the title in the project, or updates the description of it if an open issue with the same title already exists
+func (g *Gitlab) UpsertIssue(ctx context.Context, repoName string, title string, body string) (string, error) {
+	state := "opened"
+	in := "title"
+	issues, _, err := g.glClient.Issues.ListProjectIssues(repoName, &gitlab.ListProjectIssuesOptions{
+		State:  &state,
+		Search: &title,
+		In:     &in,
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+
+	for _, issue := range issues {
+		if issue.Title != title {
+			continue
+		}
+
+		updated, _, err := g.glClient.Issues.UpdateIssue(repoName, issue.IID, &gitlab.UpdateIssueOptions{
+			Description: &body,
+		}, gitlab.WithContext(ctx))
+		if err != nil {
+			return "", err
+		}
+		return updated.WebURL, nil
+	}
+
+	created, _, err := g.glClient.Issues.CreateIssue(repoName, &gitlab.CreateIssueOptions{
+		Title:       &title,
+		Description: &body,
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	return created.WebURL, nil
+}
+
 // ForkRepository forks a project
 func (g *Gitlab) ForkRepository(ctx context.Context, repo scm.Repository, newOwner string) (scm.Repository, error) {
 	r := repo.(repository)
@@ -524,6 +1057,42 @@ func (g *Gitlab) ForkRepository(ctx context.Context, repo scm.Repository, newOwn
 	return nil, errors.New("time waiting for fork to complete was exceeded")
 }
 
+// DeleteFork deletes the fork a merge request was made from. The fork is not deleted if it has any other branches
+// than the default branch and the branch of the merge request, since it might then be in use by something else
+func (g *Gitlab) DeleteFork(ctx context.Context, pullReq scm.PullRequest) (bool, error) {
+	pr := pullReq.(pullRequest)
+	if pr.sourcePID == 0 || pr.sourcePID == pr.targetPID {
+		return false, nil
+	}
+
+	fork, resp, err := g.glClient.Projects.GetProject(pr.sourcePID, nil, gitlab.WithContext(ctx))
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if fork.ForkedFromProject == nil {
+		return false, nil
+	}
+
+	branches, _, err := g.glClient.Branches.ListBranches(pr.sourcePID, &gitlab.ListBranchesOptions{
+		ListOptions: gitlab.ListOptions{PerPage: 100},
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return false, err
+	}
+	for _, branch := range branches {
+		if branch.Name != fork.DefaultBranch && branch.Name != pr.branchName {
+			return false, nil
+		}
+	}
+
+	if _, err := g.glClient.Projects.DeleteProject(pr.sourcePID, gitlab.WithContext(ctx)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 func (g *Gitlab) getCurrentUser(ctx context.Context) (*gitlab.User, error) {
 	if g.currentUser != nil {
 		return g.currentUser, nil
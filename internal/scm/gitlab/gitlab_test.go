@@ -36,6 +36,19 @@ func TestParseProjectReference(t *testing.T) {
 				Name:      "my-project",
 			},
 		},
+		{
+			name: "encoded subgroup",
+			val:  "my-group/sub%20group/my-project",
+			want: ProjectReference{
+				OwnerName: "my-group/sub group",
+				Name:      "my-project",
+			},
+		},
+		{
+			name:    "empty subgroup",
+			val:     "my-group//my-project",
+			wantErr: true,
+		},
 		{
 			name:    "no-group",
 			val:     "my-project",
@@ -55,3 +68,31 @@ func TestParseProjectReference(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertPipeline(t *testing.T) {
+	tests := []struct {
+		status     string
+		wantDone   bool
+		wantPassed bool
+	}{
+		{status: "running"},
+		{status: "pending"},
+		{status: "success", wantDone: true, wantPassed: true},
+		{status: "failed", wantDone: true},
+		{status: "manual", wantDone: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			got := convertPipeline(12, 34, tt.status, "https://gitlab.com/pipelines/34")
+			if got.ID != "12/34" {
+				t.Errorf("convertPipeline() ID = %v, want 12/34", got.ID)
+			}
+			if got.Done != tt.wantDone {
+				t.Errorf("convertPipeline() Done = %v, want %v", got.Done, tt.wantDone)
+			}
+			if got.Passed != tt.wantPassed {
+				t.Errorf("convertPipeline() Passed = %v, want %v", got.Passed, tt.wantPassed)
+			}
+		})
+	}
+}
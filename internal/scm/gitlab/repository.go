@@ -3,25 +3,23 @@ package gitlab
 import (
 	"fmt"
 	"net/url"
+	"strconv"
 
 	"github.com/xanzy/go-gitlab"
 )
 
 func (g *Gitlab) convertProject(project *gitlab.Project) (repository, error) {
-	var cloneURL string
-	if g.Config.SSHAuth {
-		cloneURL = project.SSHURLToRepo
-	} else {
-		u, err := url.Parse(project.HTTPURLToRepo)
-		if err != nil {
-			return repository{}, err
-		}
-		u.User = url.UserPassword("oauth2", g.token)
-		cloneURL = u.String()
+	u, err := url.Parse(project.HTTPURLToRepo)
+	if err != nil {
+		return repository{}, err
 	}
+	u.User = url.UserPassword("oauth2", g.token)
+
+	cloneURL, fallbackURL := g.Config.CloneProtocol.CloneURLs(u.String(), project.SSHURLToRepo)
 
 	return repository{
 		url:           cloneURL,
+		fallbackURL:   fallbackURL,
 		pid:           project.ID,
 		name:          project.Path,
 		ownerName:     project.Namespace.FullPath,
@@ -43,6 +41,7 @@ func shouldSquash(project *gitlab.Project) bool {
 
 type repository struct {
 	url           string
+	fallbackURL   string
 	pid           int
 	name          string
 	ownerName     string
@@ -50,10 +49,19 @@ type repository struct {
 	shouldSquash  bool
 }
 
+// ID returns the id of the project, which is kept when it's renamed or moved
+func (r repository) ID() string {
+	return strconv.Itoa(r.pid)
+}
+
 func (r repository) CloneURL() string {
 	return r.url
 }
 
+func (r repository) FallbackCloneURL() string {
+	return r.fallbackURL
+}
+
 func (r repository) DefaultBranch() string {
 	return r.defaultBranch
 }
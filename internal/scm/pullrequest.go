@@ -1,8 +1,10 @@
 package scm
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 )
 
 // NewPullRequest is the data needed to create a new pull request
@@ -17,6 +19,8 @@ type NewPullRequest struct {
 	Assignees     []string
 	Draft         bool
 	Labels        []string
+
+	KeepReviewers bool // If set, reviewers of an updated pull request that are not part of Reviewers or TeamReviewers are kept
 }
 
 // PullRequestStatus is the status of a pull request, including statuses of the last commit
@@ -56,6 +60,38 @@ type PullRequest interface {
 	String() string
 }
 
+// PullRequestNotPermittedError is returned when a pull request can not be created on a repository at all, like when
+// pull requests are disabled, or the user is not permitted to create them. Unlike other errors, retrying will not help
+type PullRequestNotPermittedError struct {
+	Err error
+}
+
+func (e *PullRequestNotPermittedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PullRequestNotPermittedError) Unwrap() error {
+	return e.Err
+}
+
+// PullRequestDetails are details about a pull request that some platforms provide, in the same format on all platforms
+type PullRequestDetails struct {
+	ID        string              // The id of the pull request on the platform, which unlike the number is unique across repositories
+	Number    int                 // The number of the pull request in the repository
+	Checks    string              // The combined state of the checks of the last commit, like "success", "pending" or "failure"
+	Reviews   []PullRequestReview // The latest review of every reviewer, and the reviews that are requested
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	UnsuccessfulChecks []string // The checks of the last commit that have not passed, in the format "name: state", like "build: failure"
+}
+
+// PullRequestReview is the latest review of a reviewer
+type PullRequestReview struct {
+	Reviewer string `json:"reviewer"`
+	State    string `json:"state"` // Like "approved", "changes_requested", "commented" or "requested" if the review has not been made
+}
+
 // MergeType is the way a pull request is "merged" into the base branch
 type MergeType int
 
@@ -80,6 +116,33 @@ func ParseMergeType(typ string) (MergeType, error) {
 	return MergeTypeUnknown, fmt.Errorf(`not a valid merge type: "%s"`, typ)
 }
 
+func (mt MergeType) String() string {
+	switch mt {
+	case MergeTypeMerge:
+		return "merge"
+	case MergeTypeRebase:
+		return "rebase"
+	case MergeTypeSquash:
+		return "squash"
+	}
+	return "unknown"
+}
+
+// fallbackMergeTypes is the order merge types are picked in when none of the configured ones is allowed
+var fallbackMergeTypes = []MergeType{MergeTypeMerge, MergeTypeSquash, MergeTypeRebase}
+
+// ChooseMergeType returns the first of the configured merge types that the repository allows. If none of them is
+// allowed, the best merge type that the repository allows is returned instead, and fallback is true
+func ChooseMergeType(configured, allowed []MergeType) (mergeType MergeType, fallback bool, err error) {
+	if mergeTypes := MergeTypeIntersection(configured, allowed); len(mergeTypes) > 0 {
+		return mergeTypes[0], false, nil
+	}
+	if mergeTypes := MergeTypeIntersection(fallbackMergeTypes, allowed); len(mergeTypes) > 0 {
+		return mergeTypes[0], true, nil
+	}
+	return MergeTypeUnknown, false, errors.New("the repository does not allow any merge type")
+}
+
 // MergeTypeIntersection calculates the intersection of two merge type slices,
 // The order of the first slice will be preserved
 func MergeTypeIntersection(mergeTypes1, mergeTypes2 []MergeType) []MergeType {
@@ -93,3 +156,73 @@ func MergeTypeIntersection(mergeTypes1, mergeTypes2 []MergeType) []MergeType {
 	}
 	return res
 }
+
+// PullRequestConstraints are the limits a platform puts on the values of pull requests
+type PullRequestConstraints struct {
+	MaxTitleLength      int    // The maximum number of characters in the title, zero means no limit
+	MaxLabelLength      int    // The maximum number of characters in a label, zero means no limit
+	ForbiddenLabelChars string // Characters that can not be part of a label
+}
+
+// Lint returns a description of every value of the pull request that the platform would reject
+func (c PullRequestConstraints) Lint(pr NewPullRequest) []string {
+	problems := []string{}
+
+	if strings.TrimSpace(pr.Title) == "" {
+		problems = append(problems, "the title is empty")
+	} else if length := len([]rune(pr.Title)); c.MaxTitleLength > 0 && length > c.MaxTitleLength {
+		problems = append(problems, fmt.Sprintf("the title is %d characters long, the maximum is %d", length, c.MaxTitleLength))
+	}
+
+	for _, label := range pr.Labels {
+		if strings.TrimSpace(label) == "" {
+			problems = append(problems, "a label is empty")
+		} else if length := len([]rune(label)); c.MaxLabelLength > 0 && length > c.MaxLabelLength {
+			problems = append(problems, fmt.Sprintf("the label %q is %d characters long, the maximum is %d", label, length, c.MaxLabelLength))
+		}
+		if c.ForbiddenLabelChars != "" && strings.ContainsAny(label, c.ForbiddenLabelChars) {
+			problems = append(problems, fmt.Sprintf("the label %q contains any of the forbidden characters %q", label, c.ForbiddenLabelChars))
+		}
+	}
+
+	return problems
+}
+
+// NewRelease is the data needed to create a release, or only a tag, on the merge commit of a pull request
+type NewRelease struct {
+	TagName string
+	Name    string
+	Notes   string
+	TagOnly bool // If set, only a tag is created, without any release
+}
+
+// MergeMessage is the message of the commit made when a pull request is merged. Empty fields are left to the platform
+type MergeMessage struct {
+	Title string
+	Body  string
+}
+
+// CheckRun is a check, that has already completed successfully, added to the pushed commit of a pull request
+type CheckRun struct {
+	Name    string
+	Title   string
+	Summary string // Markdown
+	SHA     string // The commit the check run is added to
+}
+
+// CommitStatus is a successful status, like the ones set by CI systems, set on the pushed commit of a pull request
+type CommitStatus struct {
+	Context     string // Identifies the status, like "multi-gitter/run"
+	Description string
+	TargetURL   string
+	SHA         string // The commit the status is set on
+}
+
+// Pipeline is a run of the CI pipeline of a pull request
+type Pipeline struct {
+	ID     string // Identifies the pipeline on the platform
+	Status string // The status on the platform, like "running", "success" or "failed"
+	URL    string
+	Done   bool // If the pipeline will not change status anymore, regardless of if it passed
+	Passed bool
+}
@@ -0,0 +1,101 @@
+package scm
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPullRequestConstraintsLint(t *testing.T) {
+	constraints := PullRequestConstraints{
+		MaxTitleLength:      10,
+		MaxLabelLength:      5,
+		ForbiddenLabelChars: ",",
+	}
+
+	tests := []struct {
+		name string
+		pr   NewPullRequest
+		want []string
+	}{
+		{
+			name: "valid",
+			pr:   NewPullRequest{Title: "title", Labels: []string{"a", "b"}},
+			want: []string{},
+		},
+		{
+			name: "empty title",
+			pr:   NewPullRequest{Title: " "},
+			want: []string{"the title is empty"},
+		},
+		{
+			name: "long title",
+			pr:   NewPullRequest{Title: strings.Repeat("å", 11)},
+			want: []string{"the title is 11 characters long, the maximum is 10"},
+		},
+		{
+			name: "invalid labels",
+			pr:   NewPullRequest{Title: "title", Labels: []string{"", "too-long", "a,b"}},
+			want: []string{
+				"a label is empty",
+				`the label "too-long" is 8 characters long, the maximum is 5`,
+				`the label "a,b" contains any of the forbidden characters ","`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := constraints.Lint(tt.pr); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChooseMergeType(t *testing.T) {
+	tests := []struct {
+		name         string
+		configured   []MergeType
+		allowed      []MergeType
+		want         MergeType
+		wantFallback bool
+		wantErr      bool
+	}{
+		{
+			name:       "first configured is allowed",
+			configured: []MergeType{MergeTypeSquash, MergeTypeMerge},
+			allowed:    []MergeType{MergeTypeMerge, MergeTypeSquash},
+			want:       MergeTypeSquash,
+		},
+		{
+			name:       "later configured is allowed",
+			configured: []MergeType{MergeTypeRebase, MergeTypeMerge},
+			allowed:    []MergeType{MergeTypeMerge, MergeTypeSquash},
+			want:       MergeTypeMerge,
+		},
+		{
+			name:         "none configured is allowed",
+			configured:   []MergeType{MergeTypeRebase},
+			allowed:      []MergeType{MergeTypeMerge, MergeTypeSquash},
+			want:         MergeTypeMerge,
+			wantFallback: true,
+		},
+		{
+			name:       "nothing allowed",
+			configured: []MergeType{MergeTypeMerge},
+			allowed:    []MergeType{},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, fallback, err := ChooseMergeType(tt.configured, tt.allowed)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ChooseMergeType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want || fallback != tt.wantFallback {
+				t.Errorf("ChooseMergeType() = %v, %v, want %v, %v", got, fallback, tt.want, tt.wantFallback)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package scm
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// SplitRepositoryReference splits a repository reference, like "owner/repo", into its path segments.
+// Each segment may be URL-encoded, which makes it possible to reference names with a slash ("%2F") or
+// names copied from a URL ("My%20Project"). Names with spaces or unicode characters can also be used as is.
+// Surrounding whitespace is removed, and an error is returned if the number of segments is not in [minParts, maxParts]
+// or if any segment is empty. A maxParts of 0 means that there is no upper limit.
+func SplitRepositoryReference(val string, minParts, maxParts int) ([]string, error) {
+	split := strings.Split(strings.TrimSpace(val), "/")
+	if len(split) < minParts || (maxParts > 0 && len(split) > maxParts) {
+		return nil, fmt.Errorf("could not parse repository reference: %s", val)
+	}
+
+	for i, segment := range split {
+		unescaped, err := url.PathUnescape(strings.TrimSpace(segment))
+		if err != nil {
+			return nil, fmt.Errorf("could not parse repository reference %s: %w", val, err)
+		}
+		if unescaped == "" {
+			return nil, fmt.Errorf("could not parse repository reference: %s", val)
+		}
+		split[i] = unescaped
+	}
+	return split, nil
+}
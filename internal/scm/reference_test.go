@@ -0,0 +1,86 @@
+package scm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitRepositoryReference(t *testing.T) {
+	tests := []struct {
+		name     string
+		val      string
+		minParts int
+		maxParts int
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:     "simple",
+			val:      "owner/repo",
+			minParts: 2,
+			maxParts: 2,
+			want:     []string{"owner", "repo"},
+		},
+		{
+			name:     "spaces",
+			val:      " My Project/My Repo ",
+			minParts: 2,
+			maxParts: 2,
+			want:     []string{"My Project", "My Repo"},
+		},
+		{
+			name:     "url encoded",
+			val:      "My%20Project/a%2Fb",
+			minParts: 2,
+			maxParts: 2,
+			want:     []string{"My Project", "a/b"},
+		},
+		{
+			name:     "unicode",
+			val:      "ägare/förråd",
+			minParts: 2,
+			maxParts: 2,
+			want:     []string{"ägare", "förråd"},
+		},
+		{
+			name:     "nested",
+			val:      "group/subgroup/project",
+			minParts: 2,
+			maxParts: 0,
+			want:     []string{"group", "subgroup", "project"},
+		},
+		{
+			name:     "too many segments",
+			val:      "group/subgroup/project",
+			minParts: 2,
+			maxParts: 2,
+			wantErr:  true,
+		},
+		{
+			name:     "empty segment",
+			val:      "owner/",
+			minParts: 2,
+			maxParts: 2,
+			wantErr:  true,
+		},
+		{
+			name:     "invalid encoding",
+			val:      "owner/repo%zz",
+			minParts: 2,
+			maxParts: 2,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SplitRepositoryReference(tt.val, tt.minParts, tt.maxParts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SplitRepositoryReference() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitRepositoryReference() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
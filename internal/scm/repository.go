@@ -1,5 +1,10 @@
 package scm
 
+import (
+	"fmt"
+	"strings"
+)
+
 // Repository provides all the information needed about a git repository
 type Repository interface {
 	// CloneURL returns the clone address of the repository
@@ -24,3 +29,44 @@ func RepoContainsTopic(repoTopics []string, filterTopics []string) bool {
 
 	return false
 }
+
+// CloneProtocol is the protocol used when cloning repositories
+type CloneProtocol int
+
+// All CloneProtocols
+const (
+	CloneProtocolHTTPS CloneProtocol = iota
+	CloneProtocolSSH
+	CloneProtocolAuto
+)
+
+// ParseCloneProtocol parses a clone protocol
+func ParseCloneProtocol(str string) (CloneProtocol, error) {
+	switch strings.ToLower(str) {
+	case "https":
+		return CloneProtocolHTTPS, nil
+	case "ssh":
+		return CloneProtocolSSH, nil
+	case "auto":
+		return CloneProtocolAuto, nil
+	}
+	return CloneProtocolHTTPS, fmt.Errorf(`not a valid clone protocol: "%s"`, str)
+}
+
+// UsesSSH returns true if the protocol will use ssh for the first clone attempt
+func (p CloneProtocol) UsesSSH() bool {
+	return p == CloneProtocolSSH || p == CloneProtocolAuto
+}
+
+// CloneURLs selects the url that should be used for cloning, and the fallback url that should be used
+// if the first clone attempt fails. The fallback url is empty if no fallback should be made.
+func (p CloneProtocol) CloneURLs(httpsURL, sshURL string) (cloneURL string, fallbackURL string) {
+	switch p {
+	case CloneProtocolSSH:
+		return sshURL, ""
+	case CloneProtocolAuto:
+		return sshURL, httpsURL
+	default:
+		return httpsURL, ""
+	}
+}
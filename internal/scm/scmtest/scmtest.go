@@ -0,0 +1,261 @@
+// Package scmtest contains a test suite of the behavior that every version controller has to conform to, since the
+// rest of multi-gitter relies on it regardless of the platform
+package scmtest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Setup is a version controller to run the suite against
+type Setup struct {
+	VersionController scm.VersionController
+
+	// The full names of all repositories the version controller lists
+	Repositories []string
+
+	// The number of repositories, and pull requests, on each page the platform lists. If set, there has to be more
+	// repositories than fit on one page, so that paging is tested
+	PageSize int
+
+	// A branch that exists, with changes compared to the default branch, in every repository. No pull requests should
+	// have been made from it
+	Branch string
+
+	// The owner repositories are forked to, if forking should be tested
+	ForkOwner string
+
+	// Reads the labels of a pull request from the platform, if label sync should be tested
+	PullRequestLabels func(t *testing.T, pr scm.PullRequest) []string
+}
+
+// Run runs the suite. Every test gets its own version controller from setup, since the tests change its state
+func Run(t *testing.T, setup func(t *testing.T) Setup) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, s Setup)
+	}{
+		{name: "lists every repository once", test: testListsRepositories},
+		{name: "created pull request is open", test: testCreatedPullRequest},
+		{name: "no open pull request from other branches", test: testNoOpenPullRequest},
+		{name: "lists the pull requests of the branch", test: testListsPullRequests},
+		{name: "updated pull request is the same pull request", test: testUpdatedPullRequest},
+		{name: "updated labels replace the earlier labels", test: testSyncedLabels},
+		{name: "closed pull request", test: testClosedPullRequest},
+		{name: "merged pull request", test: testMergedPullRequest},
+		{name: "forking is idempotent", test: testFork},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.test(t, setup(t))
+		})
+	}
+}
+
+func testListsRepositories(t *testing.T, s Setup) {
+	requirePages(t, s)
+
+	repos, err := s.VersionController.GetRepositories(context.Background())
+	require.NoError(t, err)
+
+	names := make([]string, len(repos))
+	for i, repo := range repos {
+		names[i] = repo.FullName()
+	}
+	assert.ElementsMatch(t, s.Repositories, names)
+}
+
+func testCreatedPullRequest(t *testing.T, s Setup) {
+	ctx := context.Background()
+	repo := firstRepository(t, s)
+
+	pr, err := s.VersionController.CreatePullRequest(ctx, repo, repo, newPullRequest(s))
+	require.NoError(t, err)
+	assertOpen(t, pr)
+
+	open, err := s.VersionController.GetOpenPullRequest(ctx, repo, s.Branch)
+	require.NoError(t, err)
+	require.NotNil(t, open, "the created pull request was not found")
+	assert.Equal(t, pr.String(), open.String())
+	assertOpen(t, open)
+}
+
+func testNoOpenPullRequest(t *testing.T, s Setup) {
+	ctx := context.Background()
+	repo := firstRepository(t, s)
+
+	_, err := s.VersionController.CreatePullRequest(ctx, repo, repo, newPullRequest(s))
+	require.NoError(t, err)
+
+	// An interface holding a nil pointer would be mistaken for an existing pull request
+	pr, err := s.VersionController.GetOpenPullRequest(ctx, repo, s.Branch+"-does-not-exist")
+	require.NoError(t, err)
+	assert.True(t, pr == nil, "a pull request was found from a branch without any")
+}
+
+func testListsPullRequests(t *testing.T, s Setup) {
+	requirePages(t, s)
+
+	ctx := context.Background()
+	repos, err := s.VersionController.GetRepositories(ctx)
+	require.NoError(t, err)
+
+	created := make([]string, 0, len(repos))
+	for _, repo := range repos {
+		pr, err := s.VersionController.CreatePullRequest(ctx, repo, repo, newPullRequest(s))
+		require.NoError(t, err)
+		created = append(created, pr.String())
+	}
+
+	prs, err := s.VersionController.GetPullRequests(ctx, s.Branch)
+	require.NoError(t, err)
+	listed := make([]string, len(prs))
+	for i, pr := range prs {
+		listed[i] = pr.String()
+		assertOpen(t, pr)
+	}
+	assert.ElementsMatch(t, created, listed)
+
+	prs, err = s.VersionController.GetPullRequests(ctx, s.Branch+"-does-not-exist")
+	require.NoError(t, err)
+	assert.Empty(t, prs)
+}
+
+func testUpdatedPullRequest(t *testing.T, s Setup) {
+	ctx := context.Background()
+	repo := firstRepository(t, s)
+
+	newPR := newPullRequest(s)
+	pr, err := s.VersionController.CreatePullRequest(ctx, repo, repo, newPR)
+	require.NoError(t, err)
+
+	newPR.Title = "updated title"
+	newPR.Body = "updated body"
+	updated, err := s.VersionController.UpdatePullRequest(ctx, repo, pr, newPR)
+	require.NoError(t, err)
+	assert.Equal(t, pr.String(), updated.String())
+	assertOpen(t, updated)
+}
+
+func testSyncedLabels(t *testing.T, s Setup) {
+	if s.PullRequestLabels == nil {
+		t.Skip("label sync is not tested")
+	}
+	ctx := context.Background()
+	repo := firstRepository(t, s)
+
+	newPR := newPullRequest(s)
+	newPR.Labels = []string{"first", "kept"}
+	pr, err := s.VersionController.CreatePullRequest(ctx, repo, repo, newPR)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"first", "kept"}, s.PullRequestLabels(t, pr))
+
+	// Labels removed from the configuration are removed from the pull request
+	newPR.Labels = []string{"kept", "second"}
+	updated, err := s.VersionController.UpdatePullRequest(ctx, repo, pr, newPR)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"kept", "second"}, s.PullRequestLabels(t, updated))
+}
+
+func testClosedPullRequest(t *testing.T, s Setup) {
+	ctx := context.Background()
+	repo := firstRepository(t, s)
+
+	pr, err := s.VersionController.CreatePullRequest(ctx, repo, repo, newPullRequest(s))
+	require.NoError(t, err)
+	require.NoError(t, s.VersionController.ClosePullRequest(ctx, pr))
+
+	open, err := s.VersionController.GetOpenPullRequest(ctx, repo, s.Branch)
+	require.NoError(t, err)
+	assert.True(t, open == nil, "the closed pull request is still open")
+
+	assert.Equal(t, scm.PullRequestStatusClosed, listedStatus(t, s, pr))
+}
+
+func testMergedPullRequest(t *testing.T, s Setup) {
+	ctx := context.Background()
+	repo := firstRepository(t, s)
+
+	pr, err := s.VersionController.CreatePullRequest(ctx, repo, repo, newPullRequest(s))
+	require.NoError(t, err)
+	require.NoError(t, s.VersionController.MergePullRequest(ctx, pr))
+
+	open, err := s.VersionController.GetOpenPullRequest(ctx, repo, s.Branch)
+	require.NoError(t, err)
+	assert.True(t, open == nil, "the merged pull request is still open")
+
+	assert.Equal(t, scm.PullRequestStatusMerged, listedStatus(t, s, pr))
+}
+
+func testFork(t *testing.T, s Setup) {
+	if s.ForkOwner == "" {
+		t.Skip("forking is not tested")
+	}
+	ctx := context.Background()
+	repo := firstRepository(t, s)
+
+	fork, err := s.VersionController.ForkRepository(ctx, repo, s.ForkOwner)
+	require.NoError(t, err)
+	assert.Equal(t, s.ForkOwner+"/"+repoName(repo), fork.FullName())
+
+	// Runs are repeated, and have to use the fork that was made by the earlier runs
+	again, err := s.VersionController.ForkRepository(ctx, repo, s.ForkOwner)
+	require.NoError(t, err)
+	assert.Equal(t, fork.FullName(), again.FullName())
+}
+
+// requirePages makes sure there are more repositories than fit on one page, if the platform pages
+func requirePages(t *testing.T, s Setup) {
+	t.Helper()
+	if s.PageSize > 0 {
+		require.Greater(t, len(s.Repositories), s.PageSize, "there are too few repositories to test paging")
+	}
+}
+
+func firstRepository(t *testing.T, s Setup) scm.Repository {
+	repos, err := s.VersionController.GetRepositories(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, repos)
+	return repos[0]
+}
+
+func newPullRequest(s Setup) scm.NewPullRequest {
+	return scm.NewPullRequest{
+		Title: "conformance test",
+		Body:  "made by the conformance test suite",
+		Head:  s.Branch,
+	}
+}
+
+// listedStatus returns the status of the pull request when the pull requests of the branch are listed
+func listedStatus(t *testing.T, s Setup, pr scm.PullRequest) scm.PullRequestStatus {
+	prs, err := s.VersionController.GetPullRequests(context.Background(), s.Branch)
+	require.NoError(t, err)
+	for _, listed := range prs {
+		if listed.String() == pr.String() {
+			return listed.Status()
+		}
+	}
+	require.Failf(t, "the pull request was not listed", "%s", pr.String())
+	return scm.PullRequestStatusUnknown
+}
+
+func assertOpen(t *testing.T, pr scm.PullRequest) {
+	t.Helper()
+	assert.Contains(t, []scm.PullRequestStatus{
+		scm.PullRequestStatusPending,
+		scm.PullRequestStatusSuccess,
+		scm.PullRequestStatusError,
+	}, pr.Status(), "the pull request %s is not open", pr.String())
+}
+
+// repoName returns the name of a repository without its owner, which can contain slashes, like GitLab groups
+func repoName(repo scm.Repository) string {
+	fullName := repo.FullName()
+	return fullName[strings.LastIndex(fullName, "/")+1:]
+}
@@ -0,0 +1,71 @@
+package sourcehut
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// makeGraphQLRequest makes a request against the GraphQL API of a sr.ht service (git.sr.ht, lists.sr.ht etc.)
+func (s *SourceHut) makeGraphQLRequest(ctx context.Context, serviceURL string, query string, variables interface{}, res interface{}) error {
+	rawReqData, err := json.Marshal(struct {
+		Query     string      `json:"query"`
+		Variables interface{} `json:"variables"`
+	}{
+		Query:     query,
+		Variables: variables,
+	})
+	if err != nil {
+		return errors.WithMessage(err, "could not marshal graphql request")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(serviceURL, "/")+"/query", bytes.NewBuffer(rawReqData))
+	if err != nil {
+		return errors.WithMessage(err, "could not create graphql request")
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.token))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	resultData := struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}{}
+
+	if err := json.NewDecoder(resp.Body).Decode(&resultData); err != nil {
+		return errors.WithMessagef(err, "could not read graphql response body (status code %d)", resp.StatusCode)
+	}
+
+	if len(resultData.Errors) > 0 {
+		errorsMsgs := make([]string, len(resultData.Errors))
+		for i := range resultData.Errors {
+			errorsMsgs[i] = resultData.Errors[i].Message
+		}
+		return errors.WithMessage(
+			errors.New(strings.Join(errorsMsgs, "\n")),
+			"encountered error during GraphQL query",
+		)
+	}
+
+	if resp.StatusCode >= 400 {
+		return errors.Errorf("could not make SourceHut GraphQL request, status code %d", resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(resultData.Data, res); err != nil {
+		return errors.WithMessage(err, "could not unmarshal graphQL result")
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package sourcehut
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+)
+
+type srhtPatchset struct {
+	ID      int64  `json:"id"`
+	Subject string `json:"subject"`
+	Prefix  string `json:"prefix"`
+	Status  string `json:"status"`
+	Version int    `json:"version"`
+}
+
+// patchsetStatus maps the status of a patchset onto the status of a pull request
+func patchsetStatus(status string) scm.PullRequestStatus {
+	switch status {
+	case "APPLIED":
+		return scm.PullRequestStatusMerged
+	case "REJECTED", "SUPERSEDED":
+		return scm.PullRequestStatusClosed
+	case "APPROVED":
+		return scm.PullRequestStatusSuccess
+	case "PROPOSED":
+		return scm.PullRequestStatusPending
+	case "NEEDS_REVISION":
+		return scm.PullRequestStatusError
+	}
+	return scm.PullRequestStatusUnknown
+}
+
+// pullRequest is a patchset sent to a mailing list
+type pullRequest struct {
+	repoFullName string
+	list         mailingList
+	id           int64
+	webURL       string
+	status       scm.PullRequestStatus
+}
+
+func (pr pullRequest) String() string {
+	return fmt.Sprintf("%s #%d", pr.repoFullName, pr.id)
+}
+
+// RepositoryFullName returns the full name of the repository the pull request is made against
+func (pr pullRequest) RepositoryFullName() string {
+	return pr.repoFullName
+}
+
+func (pr pullRequest) Status() scm.PullRequestStatus {
+	return pr.status
+}
+
+func (pr pullRequest) URL() string {
+	return pr.webURL
+}
+
+// mailingList is a reference to a lists.sr.ht mailing list
+type mailingList struct {
+	ownerName string // The username of the owner, without the tilde
+	name      string
+}
+
+// parseMailingList parses a mailing list in the format "~owner/list"
+func parseMailingList(val string) (mailingList, error) {
+	split := strings.Split(strings.TrimPrefix(val, "~"), "/")
+	if len(split) != 2 || split[0] == "" || split[1] == "" {
+		return mailingList{}, fmt.Errorf("could not parse mailing list: %s", val)
+	}
+	return mailingList{
+		ownerName: split[0],
+		name:      split[1],
+	}, nil
+}
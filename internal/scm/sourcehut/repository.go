@@ -0,0 +1,67 @@
+package sourcehut
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+type srhtRepository struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Owner struct {
+		CanonicalName string `json:"canonicalName"`
+	} `json:"owner"`
+	Head *struct {
+		Name string `json:"name"`
+	} `json:"HEAD"`
+}
+
+func (s *SourceHut) convertRepository(repo srhtRepository) (repository, error) {
+	u, err := url.Parse(s.gitURL)
+	if err != nil {
+		return repository{}, err
+	}
+	u.Path = fmt.Sprintf("/%s/%s", repo.Owner.CanonicalName, repo.Name)
+
+	sshURL := fmt.Sprintf("git@%s:%s/%s", u.Hostname(), repo.Owner.CanonicalName, repo.Name)
+
+	repoURL, fallbackURL := s.CloneProtocol.CloneURLs(u.String(), sshURL)
+
+	defaultBranch := "master"
+	if repo.Head != nil {
+		defaultBranch = strings.TrimPrefix(repo.Head.Name, "refs/heads/")
+	}
+
+	return repository{
+		url:           repoURL,
+		fallbackURL:   fallbackURL,
+		name:          repo.Name,
+		ownerName:     repo.Owner.CanonicalName,
+		defaultBranch: defaultBranch,
+	}, nil
+}
+
+type repository struct {
+	url           string
+	fallbackURL   string
+	name          string
+	ownerName     string // The canonical name of the owner, including the tilde
+	defaultBranch string
+}
+
+func (r repository) CloneURL() string {
+	return r.url
+}
+
+func (r repository) FallbackCloneURL() string {
+	return r.fallbackURL
+}
+
+func (r repository) DefaultBranch() string {
+	return r.defaultBranch
+}
+
+func (r repository) FullName() string {
+	return fmt.Sprintf("%s/%s", r.ownerName, r.name)
+}
@@ -0,0 +1,376 @@
+package sourcehut
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+
+	internalHTTP "github.com/lindell/multi-gitter/internal/http"
+)
+
+const (
+	// DefaultGitURL is the url of the hosted git.sr.ht service
+	DefaultGitURL = "https://git.sr.ht"
+	// DefaultListsURL is the url of the hosted lists.sr.ht service
+	DefaultListsURL = "https://lists.sr.ht"
+)
+
+// errPatchesOnly is returned for operations that require pull requests, since changes are submitted as emailed patches
+var errPatchesOnly = errors.New("changes are submitted as emailed patches on SourceHut")
+
+// New creates a new SourceHut client
+func New(token, gitURL string, repoListing RepositoryListing, mailingList string, cloneProtocol scm.CloneProtocol) (*SourceHut, error) {
+	listsURL := DefaultListsURL
+	if gitURL == "" {
+		gitURL = DefaultGitURL
+	} else {
+		// Self-hosted instances normally host the services as git.example.com and lists.example.com
+		// If the url does not follow that pattern, the services are expected to be hosted on the same url
+		u, err := url.Parse(gitURL)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not parse base-url")
+		}
+		if strings.HasPrefix(u.Host, "git.") {
+			u.Host = "lists." + strings.TrimPrefix(u.Host, "git.")
+		}
+		listsURL = u.String()
+	}
+
+	s := &SourceHut{
+		RepositoryListing: repoListing,
+
+		gitURL:   gitURL,
+		listsURL: listsURL,
+		token:    token,
+		httpClient: &http.Client{
+			Transport: internalHTTP.LoggingRoundTripper{},
+		},
+
+		CloneProtocol: cloneProtocol,
+	}
+
+	if mailingList != "" {
+		list, err := parseMailingList(mailingList)
+		if err != nil {
+			return nil, err
+		}
+		s.mailingList = &list
+	}
+
+	return s, nil
+}
+
+// SourceHut contains SourceHut configuration
+type SourceHut struct {
+	RepositoryListing
+
+	gitURL     string
+	listsURL   string
+	token      string
+	httpClient *http.Client
+
+	// The mailing list patches are sent to. If not set, the "~owner/repo-devel" list of each repository is used
+	mailingList *mailingList
+
+	CloneProtocol scm.CloneProtocol
+}
+
+// RepositoryListing contains information about which repositories that should be fetched
+type RepositoryListing struct {
+	Users        []string
+	Repositories []RepositoryReference
+}
+
+// RepositoryReference contains information to be able to reference a repository
+type RepositoryReference struct {
+	OwnerName string // The username of the owner, without the tilde
+	Name      string
+}
+
+// ParseRepositoryReference parses a repository reference from the format "~ownerName/repoName"
+func ParseRepositoryReference(val string) (RepositoryReference, error) {
+	split, err := scm.SplitRepositoryReference(strings.TrimPrefix(strings.TrimSpace(val), "~"), 2, 2)
+	if err != nil {
+		return RepositoryReference{}, fmt.Errorf(`%w, expected the format "~ownerName/repoName"`, err)
+	}
+	return RepositoryReference{
+		OwnerName: split[0],
+		Name:      split[1],
+	}, nil
+}
+
+const repositoryFields = `id name owner { canonicalName } HEAD { name }`
+
+// GetRepositories fetches repositories from all sources (users/specific repo)
+func (s *SourceHut) GetRepositories(ctx context.Context) ([]scm.Repository, error) {
+	allRepos := []srhtRepository{}
+
+	for _, user := range s.Users {
+		repos, err := s.getUserRepositories(ctx, strings.TrimPrefix(user, "~"))
+		if err != nil {
+			return nil, err
+		}
+		allRepos = append(allRepos, repos...)
+	}
+
+	for _, repoRef := range s.Repositories {
+		repo, err := s.getRepository(ctx, repoRef)
+		if err != nil {
+			return nil, err
+		}
+		allRepos = append(allRepos, repo)
+	}
+
+	seen := map[int64]struct{}{}
+	repos := make([]scm.Repository, 0, len(allRepos))
+	for _, repo := range allRepos {
+		if _, ok := seen[repo.ID]; ok {
+			continue
+		}
+		seen[repo.ID] = struct{}{}
+
+		convertedRepo, err := s.convertRepository(repo)
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, convertedRepo)
+	}
+
+	return repos, nil
+}
+
+func (s *SourceHut) getUserRepositories(ctx context.Context, username string) ([]srhtRepository, error) {
+	query := `query ($username: String!, $cursor: Cursor) {
+		user(username: $username) {
+			repositories(cursor: $cursor) {
+				results { ` + repositoryFields + ` }
+				cursor
+			}
+		}
+	}`
+
+	var allRepos []srhtRepository
+	var cursor *string
+	for {
+		var result struct {
+			User *struct {
+				Repositories struct {
+					Results []srhtRepository `json:"results"`
+					Cursor  *string          `json:"cursor"`
+				} `json:"repositories"`
+			} `json:"user"`
+		}
+		err := s.makeGraphQLRequest(ctx, s.gitURL, query, map[string]interface{}{
+			"username": username,
+			"cursor":   cursor,
+		}, &result)
+		if err != nil {
+			return nil, err
+		}
+		if result.User == nil {
+			return nil, errors.Errorf("could not find user %s", username)
+		}
+
+		allRepos = append(allRepos, result.User.Repositories.Results...)
+
+		cursor = result.User.Repositories.Cursor
+		if cursor == nil {
+			return allRepos, nil
+		}
+	}
+}
+
+func (s *SourceHut) getRepository(ctx context.Context, repoRef RepositoryReference) (srhtRepository, error) {
+	query := `query ($username: String!, $name: String!) {
+		user(username: $username) {
+			repository(name: $name) { ` + repositoryFields + ` }
+		}
+	}`
+
+	var result struct {
+		User *struct {
+			Repository *srhtRepository `json:"repository"`
+		} `json:"user"`
+	}
+	err := s.makeGraphQLRequest(ctx, s.gitURL, query, map[string]interface{}{
+		"username": repoRef.OwnerName,
+		"name":     repoRef.Name,
+	}, &result)
+	if err != nil {
+		return srhtRepository{}, err
+	}
+	if result.User == nil || result.User.Repository == nil {
+		return srhtRepository{}, errors.Errorf("could not find repository ~%s/%s", repoRef.OwnerName, repoRef.Name)
+	}
+
+	return *result.User.Repository, nil
+}
+
+// PatchSubjectPrefix returns the subject prefix of patches, which contain both the repository and the branch name
+// so that the patchset can be found again on the mailing list
+func (s *SourceHut) PatchSubjectPrefix(repo scm.Repository, branchName string) string {
+	r := repo.(repository)
+	return fmt.Sprintf("PATCH %s %s", r.name, branchName)
+}
+
+// patchsetPrefix is the prefix lists.sr.ht parses from the subject of a patch
+func patchsetPrefix(repoName, branchName string) string {
+	return fmt.Sprintf("%s %s", repoName, branchName)
+}
+
+func (s *SourceHut) repoMailingList(r repository) mailingList {
+	if s.mailingList != nil {
+		return *s.mailingList
+	}
+	return mailingList{
+		ownerName: strings.TrimPrefix(r.ownerName, "~"),
+		name:      r.name + "-devel",
+	}
+}
+
+// CreatePullRequest is not supported, since changes are submitted as patches
+func (s *SourceHut) CreatePullRequest(_ context.Context, _ scm.Repository, _ scm.Repository, _ scm.NewPullRequest) (scm.PullRequest, error) {
+	return nil, errPatchesOnly
+}
+
+// UpdatePullRequest is not supported, since changes are submitted as patches
+func (s *SourceHut) UpdatePullRequest(_ context.Context, _ scm.Repository, _ scm.PullRequest, _ scm.NewPullRequest) (scm.PullRequest, error) {
+	return nil, errPatchesOnly
+}
+
+// GetPullRequests gets the latest patchset sent for each repository with a specific branch
+func (s *SourceHut) GetPullRequests(ctx context.Context, branchName string) ([]scm.PullRequest, error) {
+	repos, err := s.GetRepositories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	prs := []scm.PullRequest{}
+	for _, repo := range repos {
+		pr, err := s.getPatchset(ctx, repo.(repository), branchName)
+		if err != nil {
+			return nil, err
+		}
+		if pr != nil {
+			prs = append(prs, *pr)
+		}
+	}
+
+	return prs, nil
+}
+
+// getPatchset gets the latest patchset sent for a repository and branch
+func (s *SourceHut) getPatchset(ctx context.Context, repo repository, branchName string) (*pullRequest, error) {
+	list := s.repoMailingList(repo)
+	query := `query ($username: String!, $list: String!, $cursor: Cursor) {
+		user(username: $username) {
+			list(name: $list) {
+				patches(cursor: $cursor) {
+					results { id subject prefix status version }
+					cursor
+				}
+			}
+		}
+	}`
+
+	prefix := patchsetPrefix(repo.name, branchName)
+	var latest *srhtPatchset
+	var cursor *string
+	for {
+		var result struct {
+			User *struct {
+				List *struct {
+					Patches struct {
+						Results []srhtPatchset `json:"results"`
+						Cursor  *string        `json:"cursor"`
+					} `json:"patches"`
+				} `json:"list"`
+			} `json:"user"`
+		}
+		err := s.makeGraphQLRequest(ctx, s.listsURL, query, map[string]interface{}{
+			"username": list.ownerName,
+			"list":     list.name,
+			"cursor":   cursor,
+		}, &result)
+		if err != nil {
+			return nil, err
+		}
+		if result.User == nil || result.User.List == nil {
+			return nil, errors.Errorf("could not find mailing list ~%s/%s", list.ownerName, list.name)
+		}
+
+		for _, patchset := range result.User.List.Patches.Results {
+			if patchset.Prefix == prefix && (latest == nil || patchset.ID > latest.ID) {
+				patchset := patchset
+				latest = &patchset
+			}
+		}
+
+		cursor = result.User.List.Patches.Cursor
+		if cursor == nil {
+			break
+		}
+	}
+
+	if latest == nil {
+		return nil, nil
+	}
+
+	return &pullRequest{
+		repoFullName: repo.FullName(),
+		list:         list,
+		id:           latest.ID,
+		webURL:       fmt.Sprintf("%s/~%s/%s/patches/%d", strings.TrimSuffix(s.listsURL, "/"), list.ownerName, list.name, latest.ID),
+		status:       patchsetStatus(latest.Status),
+	}, nil
+}
+
+// GetOpenPullRequest gets the latest patchset for a repository that has not yet been applied or rejected
+func (s *SourceHut) GetOpenPullRequest(ctx context.Context, repo scm.Repository, branchName string) (scm.PullRequest, error) {
+	pr, err := s.getPatchset(ctx, repo.(repository), branchName)
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil || pr.status == scm.PullRequestStatusMerged || pr.status == scm.PullRequestStatusClosed {
+		return nil, nil
+	}
+	return *pr, nil
+}
+
+// MergePullRequest is not supported, since patches are applied by the maintainers of the repository
+func (s *SourceHut) MergePullRequest(_ context.Context, _ scm.PullRequest) error {
+	return errors.New("patches can only be applied by the maintainers of the repository")
+}
+
+// ClosePullRequest marks a patchset as rejected. This requires the user to have access to the mailing list
+func (s *SourceHut) ClosePullRequest(ctx context.Context, pullReq scm.PullRequest) error {
+	pr := pullReq.(pullRequest)
+
+	query := `mutation ($id: Int!) {
+		updatePatchset(id: $id, status: REJECTED) { id }
+	}`
+
+	var result map[string]interface{}
+	err := s.makeGraphQLRequest(ctx, s.listsURL, query, map[string]interface{}{
+		"id": pr.id,
+	}, &result)
+	if err != nil {
+		return errors.WithMessagef(err, "could not reject patchset %s", pr)
+	}
+	return nil
+}
+
+// ClosePullRequestKeepBranch marks a patchset as rejected, there is no branch to delete since patches are sent by email
+func (s *SourceHut) ClosePullRequestKeepBranch(ctx context.Context, pullReq scm.PullRequest) error {
+	return s.ClosePullRequest(ctx, pullReq)
+}
+
+// ForkRepository is not supported, since patches can be sent without access to the repository
+func (s *SourceHut) ForkRepository(_ context.Context, _ scm.Repository, _ string) (scm.Repository, error) {
+	return nil, errPatchesOnly
+}
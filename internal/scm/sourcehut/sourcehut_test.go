@@ -0,0 +1,97 @@
+package sourcehut_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/lindell/multi-gitter/internal/scm/sourcehut"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceHut(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/query", r.URL.Path)
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		switch {
+		case strings.Contains(req.Query, "repositories("):
+			assert.Equal(t, "test-user", req.Variables["username"])
+			_, _ = w.Write([]byte(`{"data": {"user": {"repositories": {
+				"results": [
+					{"id": 1, "name": "repo1", "owner": {"canonicalName": "~test-user"}, "HEAD": {"name": "refs/heads/main"}},
+					{"id": 2, "name": "repo2", "owner": {"canonicalName": "~test-user"}, "HEAD": null}
+				],
+				"cursor": null
+			}}}}`))
+		case strings.Contains(req.Query, "patches("):
+			assert.Equal(t, "test-user", req.Variables["username"])
+			if req.Variables["list"] == "repo2-devel" {
+				_, _ = w.Write([]byte(`{"data": {"user": {"list": null}}}`))
+				return
+			}
+			assert.Equal(t, "repo1-devel", req.Variables["list"])
+			_, _ = w.Write([]byte(`{"data": {"user": {"list": {"patches": {
+				"results": [
+					{"id": 10, "subject": "change", "prefix": "repo1 my-branch", "status": "SUPERSEDED", "version": 1},
+					{"id": 12, "subject": "change", "prefix": "repo1 my-branch", "status": "APPLIED", "version": 2},
+					{"id": 11, "subject": "other", "prefix": "repo1 other-branch", "status": "PROPOSED", "version": 1}
+				],
+				"cursor": null
+			}}}}}`))
+		default:
+			t.Errorf("unexpected query: %s", req.Query)
+		}
+	}))
+	defer server.Close()
+
+	s, err := sourcehut.New("test-token", server.URL, sourcehut.RepositoryListing{
+		Users: []string{"~test-user"},
+	}, "", scm.CloneProtocolHTTPS)
+	require.NoError(t, err)
+
+	repos, err := s.GetRepositories(context.Background())
+	require.NoError(t, err)
+	require.Len(t, repos, 2)
+	assert.Equal(t, "~test-user/repo1", repos[0].FullName())
+	assert.Equal(t, "main", repos[0].DefaultBranch())
+	assert.Equal(t, server.URL+"/~test-user/repo1", repos[0].CloneURL())
+	assert.Equal(t, "master", repos[1].DefaultBranch())
+
+	assert.Equal(t, "PATCH repo1 my-branch", s.PatchSubjectPrefix(repos[0], "my-branch"))
+
+	pr, err := s.GetOpenPullRequest(context.Background(), repos[0], "other-branch")
+	require.NoError(t, err)
+	require.NotNil(t, pr)
+	assert.Equal(t, scm.PullRequestStatusPending, pr.Status())
+
+	pr, err = s.GetOpenPullRequest(context.Background(), repos[0], "my-branch")
+	require.NoError(t, err)
+	assert.Nil(t, pr)
+
+	_, err = s.GetPullRequests(context.Background(), "my-branch")
+	assert.EqualError(t, err, "could not find mailing list ~test-user/repo2-devel")
+
+	// With a shared mailing list
+	s, err = sourcehut.New("test-token", server.URL, sourcehut.RepositoryListing{
+		Users: []string{"test-user"},
+	}, "~test-user/repo1-devel", scm.CloneProtocolHTTPS)
+	require.NoError(t, err)
+
+	prs, err := s.GetPullRequests(context.Background(), "my-branch")
+	require.NoError(t, err)
+	require.Len(t, prs, 1)
+	assert.Equal(t, "~test-user/repo1 #12", prs[0].String())
+	assert.Equal(t, scm.PullRequestStatusMerged, prs[0].Status())
+}
@@ -0,0 +1,115 @@
+package static
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/pkg/errors"
+)
+
+// errNotSupported is returned for all operations that requires a platform
+var errNotSupported = errors.New("not supported when no platform is used")
+
+// DefaultBranchFinder finds the branch that HEAD points to in a remote repository
+type DefaultBranchFinder interface {
+	RemoteDefaultBranch(ctx context.Context, cloneURL string) (string, error)
+}
+
+// New creates a new static version controller, that only uses git to interact with a static list of repositories.
+// The default branches are found with the finder, which should connect to the remotes in the same way as the clones do
+func New(cloneURLs []string, finder DefaultBranchFinder) (*Static, error) {
+	repos := make([]repository, len(cloneURLs))
+	for i, cloneURL := range cloneURLs {
+		name, err := repositoryName(cloneURL)
+		if err != nil {
+			return nil, err
+		}
+		repos[i] = repository{
+			url:  cloneURL,
+			name: name,
+		}
+	}
+
+	return &Static{
+		repositories: repos,
+		finder:       finder,
+	}, nil
+}
+
+// Static is a version controller without any platform, only a static list of git repositories
+type Static struct {
+	repositories []repository
+	finder       DefaultBranchFinder
+}
+
+// GetRepositories returns the static list of repositories, with the default branch of each one fetched from the remote
+func (s *Static) GetRepositories(ctx context.Context) ([]scm.Repository, error) {
+	repos := make([]scm.Repository, len(s.repositories))
+	for i, repo := range s.repositories {
+		defaultBranch, err := s.finder.RemoteDefaultBranch(ctx, repo.url)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "could not get the default branch of %s", repo.name)
+		}
+		repo.defaultBranch = defaultBranch
+		repos[i] = repo
+	}
+	return repos, nil
+}
+
+var scpLikeURL = regexp.MustCompile(`^(?:[^@/]+@)?[^:/]+:(.+)$`)
+
+// repositoryName creates a name of the repository, based on the path of the clone url
+func repositoryName(cloneURL string) (string, error) {
+	var path string
+	if u, err := url.Parse(cloneURL); err == nil && u.Scheme != "" {
+		path = u.Path
+	} else if match := scpLikeURL.FindStringSubmatch(cloneURL); match != nil {
+		path = match[1]
+	} else {
+		return "", errors.Errorf(`could not parse the repository url "%s"`, cloneURL)
+	}
+
+	name := strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if name == "" {
+		return "", errors.Errorf(`the repository url "%s" does not contain any path`, cloneURL)
+	}
+	return name, nil
+}
+
+// CreatePullRequest is not supported without a platform
+func (s *Static) CreatePullRequest(_ context.Context, _ scm.Repository, _ scm.Repository, _ scm.NewPullRequest) (scm.PullRequest, error) {
+	return nil, errNotSupported
+}
+
+// UpdatePullRequest is not supported without a platform
+func (s *Static) UpdatePullRequest(_ context.Context, _ scm.Repository, _ scm.PullRequest, _ scm.NewPullRequest) (scm.PullRequest, error) {
+	return nil, errNotSupported
+}
+
+// GetPullRequests is not supported without a platform
+func (s *Static) GetPullRequests(_ context.Context, _ string) ([]scm.PullRequest, error) {
+	return nil, errNotSupported
+}
+
+// GetOpenPullRequest is not supported without a platform
+func (s *Static) GetOpenPullRequest(_ context.Context, _ scm.Repository, _ string) (scm.PullRequest, error) {
+	return nil, errNotSupported
+}
+
+// MergePullRequest is not supported without a platform
+func (s *Static) MergePullRequest(_ context.Context, _ scm.PullRequest) error {
+	return errNotSupported
+}
+
+// ClosePullRequest is not supported without a platform
+func (s *Static) ClosePullRequest(_ context.Context, _ scm.PullRequest) error {
+	return errNotSupported
+}
+
+// ForkRepository is not supported without a platform
+func (s *Static) ForkRepository(_ context.Context, _ scm.Repository, _ string) (scm.Repository, error) {
+	return nil, errNotSupported
+}
@@ -33,3 +33,19 @@ func Map[T any, K any](vals []T, mapping func(T) K) []K {
 	}
 	return newVals
 }
+
+// Overlaps returns true if any value exist in both slices
+func Overlaps[T comparable](s1, s2 []T) bool {
+	s1Lookup := map[T]struct{}{}
+	for _, v := range s1 {
+		s1Lookup[v] = struct{}{}
+	}
+
+	for _, v := range s2 {
+		if _, ok := s1Lookup[v]; ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -54,3 +54,38 @@ func TestDiff(t *testing.T) {
 		})
 	}
 }
+
+func TestOverlaps(t *testing.T) {
+	tests := []struct {
+		name string
+		s1   []string
+		s2   []string
+		want bool
+	}{
+		{
+			name: "overlap",
+			s1:   []string{"a", "b"},
+			s2:   []string{"b", "c"},
+			want: true,
+		},
+		{
+			name: "no overlap",
+			s1:   []string{"a", "b"},
+			s2:   []string{"c", "d"},
+			want: false,
+		},
+		{
+			name: "empty",
+			s1:   []string{"a", "b"},
+			s2:   nil,
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Overlaps(tt.s1, tt.s2); got != tt.want {
+				t.Errorf("Overlaps() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
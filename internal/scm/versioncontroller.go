@@ -0,0 +1,15 @@
+package scm
+
+import "context"
+
+// VersionController fetches repositories, and manages pull requests on a platform
+type VersionController interface {
+	GetRepositories(ctx context.Context) ([]Repository, error)
+	CreatePullRequest(ctx context.Context, repo Repository, prRepo Repository, newPR NewPullRequest) (PullRequest, error)
+	UpdatePullRequest(ctx context.Context, repo Repository, pullReq PullRequest, updatedPR NewPullRequest) (PullRequest, error)
+	GetPullRequests(ctx context.Context, branchName string) ([]PullRequest, error)
+	GetOpenPullRequest(ctx context.Context, repo Repository, branchName string) (PullRequest, error)
+	MergePullRequest(ctx context.Context, pr PullRequest) error
+	ClosePullRequest(ctx context.Context, pr PullRequest) error
+	ForkRepository(ctx context.Context, repo Repository, newOwner string) (Repository, error)
+}
@@ -0,0 +1,164 @@
+package tokensource
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	internalhttp "github.com/lindell/multi-gitter/internal/http"
+	"github.com/pkg/errors"
+)
+
+// The time AWS Secrets Manager has to respond
+const awsTimeout = 10 * time.Second
+
+// readAWSSecretsManager reads a secret in AWS Secrets Manager, from a value in the format "//SECRET-ID" or
+// "//SECRET-ID#KEY", where KEY selects a key of a secret stored as a JSON object. The credentials and region are read
+// from the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN and AWS_REGION environment variables, like the AWS CLI
+func readAWSSecretsManager(value string) ([]byte, error) {
+	secretID, key, hasKey := strings.Cut(strings.TrimPrefix(value, "//"), "#")
+	if !strings.HasPrefix(value, "//") || secretID == "" || (hasKey && key == "") {
+		return nil, errors.New(`expected the format "aws-secretsmanager://SECRET-ID" or "aws-secretsmanager://SECRET-ID#KEY"`)
+	}
+
+	accessKeyID := os.Getenv("AWS_ACCESS_KEY_ID")
+	secretAccessKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if accessKeyID == "" || secretAccessKey == "" {
+		return nil, errors.New("the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables have to be set")
+	}
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if region == "" {
+		return nil, errors.New("the AWS_REGION environment variable has to be set")
+	}
+
+	endpoint := os.Getenv("AWS_ENDPOINT_URL_SECRETS_MANAGER")
+	if endpoint == "" {
+		endpoint = os.Getenv("AWS_ENDPOINT_URL")
+	}
+	if endpoint == "" {
+		endpoint = "https://secretsmanager." + region + ".amazonaws.com"
+	}
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse the AWS endpoint")
+	}
+
+	body, err := json.Marshal(map[string]string{"SecretId": secretID})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, endpointURL.JoinPath("/").String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	if sessionToken := os.Getenv("AWS_SESSION_TOKEN"); sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", sessionToken)
+	}
+	signAWSRequest(req, body, accessKeyID, secretAccessKey, region, "secretsmanager", time.Now())
+
+	client := http.Client{
+		Timeout:   awsTimeout,
+		Transport: internalhttp.LoggingRoundTripper{Sensitive: true},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("aws secrets manager responded with the status %s", resp.Status)
+	}
+
+	var secret struct {
+		SecretString *string `json:"SecretString"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxTokenSize)).Decode(&secret); err != nil {
+		return nil, errors.Wrap(err, "could not parse the secret")
+	}
+	if secret.SecretString == nil {
+		return nil, errors.New("the secret has no string value")
+	}
+	if !hasKey {
+		return []byte(*secret.SecretString), nil
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal([]byte(*secret.SecretString), &data); err != nil {
+		return nil, errors.Wrap(err, "the secret is not a JSON object")
+	}
+	token, ok := data[key].(string)
+	if !ok {
+		return nil, errors.Errorf("the secret has no key %q", key)
+	}
+	return []byte(token), nil
+}
+
+// signAWSRequest signs a request with AWS Signature Version 4, over the host and all headers already set on it
+func signAWSRequest(req *http.Request, body []byte, accessKeyID, secretAccessKey, region, service string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(req.Header.Get(name))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		hexSHA256(body),
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hexSHA256([]byte(canonicalRequest))
+
+	key := []byte("AWS4" + secretAccessKey)
+	for _, part := range []string{date, region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+accessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,115 @@
+package tokensource
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	internalhttp "github.com/lindell/multi-gitter/internal/http"
+	"github.com/pkg/errors"
+)
+
+// The time Azure has to respond, to each of the requests for an access token and for the secret
+const azureTimeout = 10 * time.Second
+
+// The URL of a key vault, from its name. Replaced in tests
+var azureKeyVaultURL = func(vault string) string {
+	return "https://" + vault + ".vault.azure.net"
+}
+
+// readAzureKeyVault reads a secret in Azure Key Vault, from a value in the format "//VAULT/SECRET". The vault is
+// authenticated against with the client credentials of a service principal, read from the AZURE_TENANT_ID,
+// AZURE_CLIENT_ID and AZURE_CLIENT_SECRET environment variables, like the Azure SDKs
+func readAzureKeyVault(value string) ([]byte, error) {
+	vault, secretName, ok := strings.Cut(strings.TrimPrefix(value, "//"), "/")
+	if !strings.HasPrefix(value, "//") || !ok || vault == "" || secretName == "" || strings.Contains(secretName, "/") {
+		return nil, errors.New(`expected the format "azure-keyvault://VAULT/SECRET"`)
+	}
+
+	client := &http.Client{
+		Timeout:   azureTimeout,
+		Transport: internalhttp.LoggingRoundTripper{Sensitive: true},
+	}
+
+	accessToken, err := azureAccessToken(client)
+	if err != nil {
+		return nil, err
+	}
+
+	secretURL, err := url.JoinPath(azureKeyVaultURL(vault), "secrets", secretName)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodGet, secretURL+"?api-version=7.4", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("azure key vault responded with the status %s", resp.Status)
+	}
+
+	var secret struct {
+		Value *string `json:"value"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxTokenSize)).Decode(&secret); err != nil {
+		return nil, errors.Wrap(err, "could not parse the secret")
+	}
+	if secret.Value == nil {
+		return nil, errors.New("the secret has no value")
+	}
+	return []byte(*secret.Value), nil
+}
+
+// azureAccessToken gets an access token for Azure Key Vault with the client credentials of a service principal
+func azureAccessToken(client *http.Client) (string, error) {
+	tenantID := os.Getenv("AZURE_TENANT_ID")
+	clientID := os.Getenv("AZURE_CLIENT_ID")
+	clientSecret := os.Getenv("AZURE_CLIENT_SECRET")
+	if tenantID == "" || clientID == "" || clientSecret == "" {
+		return "", errors.New("the AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_CLIENT_SECRET environment variables have to be set")
+	}
+	authorityHost := os.Getenv("AZURE_AUTHORITY_HOST")
+	if authorityHost == "" {
+		authorityHost = "https://login.microsoftonline.com"
+	}
+
+	tokenURL, err := url.JoinPath(authorityHost, tenantID, "oauth2", "v2.0", "token")
+	if err != nil {
+		return "", errors.Wrap(err, "could not parse AZURE_AUTHORITY_HOST")
+	}
+	resp, err := client.PostForm(tokenURL, url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {clientID},
+		"client_secret": {clientSecret},
+		"scope":         {"https://vault.azure.net/.default"},
+	})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("azure responded to the request for an access token with the status %s", resp.Status)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxTokenSize)).Decode(&token); err != nil {
+		return "", errors.Wrap(err, "could not parse the access token")
+	}
+	if token.AccessToken == "" {
+		return "", errors.New("azure responded without an access token")
+	}
+	return token.AccessToken, nil
+}
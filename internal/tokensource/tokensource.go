@@ -0,0 +1,85 @@
+// Package tokensource reads tokens from sources that, unlike environment variables, can not be read by other processes
+// of the same user, like the file descriptors inherited from a secret manager, or from the secret manager itself
+package tokensource
+
+import (
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The most that is read from a source, no token is anywhere near this long
+const maxTokenSize = 64 * 1024
+
+// The time a unix socket has to send the token
+const socketTimeout = 10 * time.Second
+
+// Read reads a token from a source in the format "fd:N", for a file descriptor inherited from the parent process, or
+// "unix:PATH", for a unix socket that sends the token to whoever connects, "vault://PATH#KEY", for a key of a secret in
+// HashiCorp Vault, "aws-secretsmanager://SECRET-ID#KEY", for a secret in AWS Secrets Manager, where "#KEY" is optional, or
+// "azure-keyvault://VAULT/SECRET", for a secret in Azure Key Vault. Surrounding whitespace is not part of the token.
+// A source is read once, so a secret that is rotated after that is not picked up
+func Read(source string) (string, error) {
+	kind, value, _ := strings.Cut(source, ":")
+
+	var data []byte
+	var err error
+	switch kind {
+	case "fd":
+		data, err = readFileDescriptor(value)
+	case "unix":
+		data, err = readSocket(value)
+	case "vault":
+		data, err = readVault(value)
+	case "aws-secretsmanager":
+		data, err = readAWSSecretsManager(value)
+	case "azure-keyvault":
+		data, err = readAzureKeyVault(value)
+	default:
+		return "", errors.Errorf(`unknown token source %q, expected the format "fd:N", "unix:PATH", "vault://PATH#KEY", `+
+			`"aws-secretsmanager://SECRET-ID#KEY" or "azure-keyvault://VAULT/SECRET"`, source)
+	}
+	if err != nil {
+		return "", errors.WithMessagef(err, "could not read the token from %s", source)
+	}
+
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", errors.Errorf("no token was read from %s", source)
+	}
+	return token, nil
+}
+
+func readFileDescriptor(value string) ([]byte, error) {
+	fd, err := strconv.ParseUint(value, 10, 0)
+	if err != nil {
+		return nil, errors.Errorf("%q is not a file descriptor", value)
+	}
+
+	file := os.NewFile(uintptr(fd), "token")
+	if file == nil {
+		return nil, errors.Errorf("%d is not a valid file descriptor", fd)
+	}
+	// The descriptor is closed after it's read, so that the token can't be read again from it
+	defer file.Close()
+
+	return io.ReadAll(io.LimitReader(file, maxTokenSize))
+}
+
+func readSocket(path string) ([]byte, error) {
+	conn, err := net.DialTimeout("unix", path, socketTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(socketTimeout)); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(io.LimitReader(conn, maxTokenSize))
+}
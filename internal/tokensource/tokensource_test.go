@@ -0,0 +1,210 @@
+package tokensource
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFileDescriptor(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+	_, err = writer.WriteString("my-token\n")
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	token, err := Read(fmt.Sprintf("fd:%d", reader.Fd()))
+	// The descriptor is already closed by Read, this keeps the reader from closing it again when it's garbage collected
+	_ = reader.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "my-token", token)
+}
+
+func TestReadSocket(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "token.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		_, _ = conn.Write([]byte("my-token"))
+		conn.Close()
+	}()
+
+	token, err := Read("unix:" + socketPath)
+	require.NoError(t, err)
+	assert.Equal(t, "my-token", token)
+}
+
+func TestReadVault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "vault-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/multi-gitter":
+			_, _ = w.Write([]byte(`{"data": {"data": {"token": "my-token"}, "metadata": {"version": 1}}}`))
+		case "/v1/kv/multi-gitter":
+			_, _ = w.Write([]byte(`{"data": {"token": "my-v1-token"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("VAULT_ADDR", server.URL)
+	t.Setenv("VAULT_TOKEN", "vault-token")
+
+	token, err := Read("vault://secret/data/multi-gitter#token")
+	require.NoError(t, err)
+	assert.Equal(t, "my-token", token)
+
+	token, err = Read("vault://kv/multi-gitter#token")
+	require.NoError(t, err)
+	assert.Equal(t, "my-v1-token", token)
+
+	_, err = Read("vault://kv/multi-gitter#other")
+	assert.EqualError(t, err, `could not read the token from vault://kv/multi-gitter#other: the secret has no key "other"`)
+
+	_, err = Read("vault://kv/missing#token")
+	assert.EqualError(t, err, `could not read the token from vault://kv/missing#token: vault responded with the status 404 Not Found`)
+
+	_, err = Read("vault://kv/multi-gitter")
+	assert.EqualError(t, err, `could not read the token from vault://kv/multi-gitter: expected the format "vault://PATH#KEY"`)
+
+	t.Setenv("VAULT_TOKEN", "")
+	_, err = Read("vault://kv/multi-gitter#token")
+	assert.EqualError(t, err, `could not read the token from vault://kv/multi-gitter#token: the VAULT_TOKEN environment variable has to be set`)
+}
+
+func TestReadAWSSecretsManager(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") ||
+			!strings.Contains(r.Header.Get("Authorization"), "/eu-north-1/secretsmanager/aws4_request") ||
+			r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var req struct {
+			SecretID string `json:"SecretId"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		switch req.SecretID {
+		case "multi-gitter/token":
+			_, _ = w.Write([]byte(`{"SecretString": "my-token"}`))
+		case "multi-gitter/tokens":
+			_, _ = w.Write([]byte(`{"SecretString": "{\"github\": \"my-github-token\"}"}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("AWS_ENDPOINT_URL_SECRETS_MANAGER", server.URL)
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_REGION", "eu-north-1")
+
+	token, err := Read("aws-secretsmanager://multi-gitter/token")
+	require.NoError(t, err)
+	assert.Equal(t, "my-token", token)
+
+	token, err = Read("aws-secretsmanager://multi-gitter/tokens#github")
+	require.NoError(t, err)
+	assert.Equal(t, "my-github-token", token)
+
+	_, err = Read("aws-secretsmanager://multi-gitter/tokens#gitlab")
+	assert.EqualError(t, err, `could not read the token from aws-secretsmanager://multi-gitter/tokens#gitlab: the secret has no key "gitlab"`)
+
+	_, err = Read("aws-secretsmanager://missing")
+	assert.EqualError(t, err, `could not read the token from aws-secretsmanager://missing: aws secrets manager responded with the status 400 Bad Request`)
+
+	t.Setenv("AWS_REGION", "")
+	_, err = Read("aws-secretsmanager://multi-gitter/token")
+	assert.EqualError(t, err, `could not read the token from aws-secretsmanager://multi-gitter/token: the AWS_REGION environment variable has to be set`)
+}
+
+// TestSignAWSRequest tests the signature against the "get-vanilla" case of the AWS Signature Version 4 test suite
+func TestSignAWSRequest(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	require.NoError(t, err)
+	signAWSRequest(req, nil, "AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "us-east-1", "service",
+		time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+
+		"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		req.Header.Get("Authorization"))
+}
+
+func TestReadAzureKeyVault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/tenant/oauth2/v2.0/token":
+			if r.PostFormValue("client_id") != "client" || r.PostFormValue("client_secret") != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token": "access-token"}`))
+		case "/my-vault/secrets/multi-gitter":
+			if r.Header.Get("Authorization") != "Bearer access-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"value": "my-token"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	defaultKeyVaultURL := azureKeyVaultURL
+	defer func() { azureKeyVaultURL = defaultKeyVaultURL }()
+	azureKeyVaultURL = func(vault string) string { return server.URL + "/" + vault }
+	t.Setenv("AZURE_AUTHORITY_HOST", server.URL)
+	t.Setenv("AZURE_TENANT_ID", "tenant")
+	t.Setenv("AZURE_CLIENT_ID", "client")
+	t.Setenv("AZURE_CLIENT_SECRET", "secret")
+
+	token, err := Read("azure-keyvault://my-vault/multi-gitter")
+	require.NoError(t, err)
+	assert.Equal(t, "my-token", token)
+
+	_, err = Read("azure-keyvault://my-vault/missing")
+	assert.EqualError(t, err, `could not read the token from azure-keyvault://my-vault/missing: azure key vault responded with the status 404 Not Found`)
+
+	_, err = Read("azure-keyvault://my-vault")
+	assert.EqualError(t, err, `could not read the token from azure-keyvault://my-vault: expected the format "azure-keyvault://VAULT/SECRET"`)
+
+	t.Setenv("AZURE_CLIENT_SECRET", "wrong")
+	_, err = Read("azure-keyvault://my-vault/multi-gitter")
+	assert.EqualError(t, err, `could not read the token from azure-keyvault://my-vault/multi-gitter: azure responded to the request for an access token with the status 401 Unauthorized`)
+}
+
+func TestReadErrors(t *testing.T) {
+	_, err := Read("env:TOKEN")
+	assert.EqualError(t, err, `unknown token source "env:TOKEN", expected the format "fd:N", "unix:PATH", "vault://PATH#KEY", `+
+		`"aws-secretsmanager://SECRET-ID#KEY" or "azure-keyvault://VAULT/SECRET"`)
+
+	_, err = Read("fd:abc")
+	assert.EqualError(t, err, `could not read the token from fd:abc: "abc" is not a file descriptor`)
+
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	source := fmt.Sprintf("fd:%d", reader.Fd())
+	_, err = Read(source)
+	_ = reader.Close()
+	assert.EqualError(t, err, "no token was read from "+source)
+}
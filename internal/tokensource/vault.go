@@ -0,0 +1,82 @@
+package tokensource
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	internalhttp "github.com/lindell/multi-gitter/internal/http"
+	"github.com/pkg/errors"
+)
+
+// The time HashiCorp Vault has to respond
+const vaultTimeout = 10 * time.Second
+
+// readVault reads the key of a secret in HashiCorp Vault, from a value in the format "//PATH#KEY". The address of Vault,
+// and the token to authenticate with, is read from the VAULT_ADDR and VAULT_TOKEN environment variables, like the Vault CLI
+func readVault(value string) ([]byte, error) {
+	path, key, ok := strings.Cut(strings.TrimPrefix(value, "//"), "#")
+	if !strings.HasPrefix(value, "//") || !ok || path == "" || key == "" {
+		return nil, errors.New(`expected the format "vault://PATH#KEY"`)
+	}
+
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		return nil, errors.New("the VAULT_ADDR environment variable has to be set")
+	}
+	vaultToken := os.Getenv("VAULT_TOKEN")
+	if vaultToken == "" {
+		return nil, errors.New("the VAULT_TOKEN environment variable has to be set")
+	}
+
+	secretURL, err := url.JoinPath(addr, "v1", path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse VAULT_ADDR")
+	}
+	req, err := http.NewRequest(http.MethodGet, secretURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", vaultToken)
+	if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	client := http.Client{
+		Timeout:   vaultTimeout,
+		Transport: internalhttp.LoggingRoundTripper{Sensitive: true},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("vault responded with the status %s", resp.Status)
+	}
+
+	var secret struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxTokenSize)).Decode(&secret); err != nil {
+		return nil, errors.Wrap(err, "could not parse the secret")
+	}
+
+	// Secrets in a version 2 key/value engine are nested in another data object, together with their metadata
+	data := secret.Data
+	if nested, ok := data["data"].(map[string]any); ok {
+		if _, hasMetadata := data["metadata"]; hasMetadata {
+			data = nested
+		}
+	}
+
+	token, ok := data[key].(string)
+	if !ok {
+		return nil, errors.Errorf("the secret has no key %q", key)
+	}
+	return []byte(token), nil
+}
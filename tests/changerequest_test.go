@@ -0,0 +1,141 @@
+package tests
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/internal/git"
+	"github.com/lindell/multi-gitter/internal/git/gogit"
+	"github.com/lindell/multi-gitter/internal/multigitter"
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// changeRequestServer responds that CHG0001 is the only valid change request
+func changeRequestServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Basic dXNlcjpwYXNz", r.Header.Get("Authorization"))
+		if r.URL.Path != "/change_request/CHG0001" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+// TestChangeRequestRun tests that a run in a production organization requires a valid change request
+func TestChangeRequestRun(t *testing.T) {
+	server := changeRequestServer(t)
+	defer server.Close()
+
+	scriptPath, err := filepath.Abs(changerBinaryPath)
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		name          string
+		org           string
+		changeRequest string
+		expectErr     string
+	}{
+		{name: "not production", org: "dev", changeRequest: ""},
+		{name: "missing", org: "prod", changeRequest: "", expectErr: "a change request is required to make changes in production organizations, like prod/should-change"},
+		{name: "invalid", org: "prod", changeRequest: "CHG0002", expectErr: "the change request CHG0002 is not valid, the validation responded with status code 404"},
+		{name: "valid", org: "prod", changeRequest: "CHG0001"},
+		{name: "missing in subgroup", org: "prod-x/sub", changeRequest: "", expectErr: "a change request is required to make changes in production organizations, like prod-x/sub/should-change"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			vcMock := &vcmock.VersionController{}
+			defer vcMock.Clean()
+			vcMock.AddRepository(createRepo(t, test.org, "should-change", "i like apples"))
+
+			gate, err := multigitter.NewChangeRequestGate(
+				[]string{"prod*"},
+				test.changeRequest,
+				server.URL+"/change_request/{{.ChangeRequest}}",
+				[]string{"Authorization: Basic dXNlcjpwYXNz"},
+			)
+			require.NoError(t, err)
+
+			runner := &multigitter.Runner{
+				VersionController: vcMock,
+				ScriptPath:        scriptPath,
+				FeatureBranch:     "custom-branch-name",
+				Output:            io.Discard,
+				CommitMessage:     "custom message",
+				PullRequestTitle:  "custom message",
+				CommitAuthor:      &git.CommitAuthor{Name: "Test Author", Email: "test@example.com"},
+				Concurrent:        1,
+				ChangeRequestGate: gate,
+				CreateGit: func(dir string) multigitter.Git {
+					return &gogit.Git{Directory: dir}
+				},
+			}
+
+			err = runner.Run(context.Background())
+			if test.expectErr != "" {
+				assert.EqualError(t, err, test.expectErr)
+				assert.Len(t, vcMock.PullRequests, 0)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, vcMock.PullRequests, 1)
+			}
+		})
+	}
+}
+
+// TestChangeRequestMerge tests that merging in a production organization requires a valid change request
+func TestChangeRequestMerge(t *testing.T) {
+	server := changeRequestServer(t)
+	defer server.Close()
+
+	vcMock := &vcmock.VersionController{}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir, err := os.MkdirTemp(os.TempDir(), "multi-git-test-change-request-")
+	defer os.RemoveAll(tmpDir)
+	require.NoError(t, err)
+
+	repo := createRepo(t, "prod", "should-merge", "i like apples")
+	vcMock.AddRepository(repo)
+	vcMock.PullRequests = append(vcMock.PullRequests, vcmock.PullRequest{
+		PRStatus:   scm.PullRequestStatusSuccess,
+		PRNumber:   1,
+		Repository: repo,
+		NewPullRequest: scm.NewPullRequest{
+			Head: "custom-branch-name",
+		},
+	})
+
+	merge := func(changeRequest string) error {
+		command := cmd.RootCmd()
+		command.SetArgs([]string{
+			"merge",
+			"--log-file", filepath.Join(tmpDir, "merge-log.txt"),
+			"-B", "custom-branch-name",
+			"--production-org", "prod",
+			"--change-request", changeRequest,
+			"--change-request-url", server.URL + "/change_request/{{.ChangeRequest}}",
+			"--change-request-header", "Authorization: Basic dXNlcjpwYXNz",
+		})
+		return command.Execute()
+	}
+
+	err = merge("CHG0002")
+	assert.EqualError(t, err, "the change request CHG0002 is not valid, the validation responded with status code 404")
+	assert.Equal(t, scm.PullRequestStatusSuccess, vcMock.PullRequests[0].PRStatus)
+
+	err = merge("CHG0001")
+	require.NoError(t, err)
+	assert.Equal(t, scm.PullRequestStatusMerged, vcMock.PullRequests[0].PRStatus)
+
+	logData, err := os.ReadFile(filepath.Join(tmpDir, "merge-log.txt"))
+	require.NoError(t, err)
+	assert.Contains(t, string(logData), "Change request CHG0001 approves to merge pull requests in 1 production repositories")
+}
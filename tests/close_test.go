@@ -0,0 +1,68 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCloseKeepBranch tests that the branches of closed pull requests are deleted, unless they should be kept
+func TestCloseKeepBranch(t *testing.T) {
+	tests := []struct {
+		name       string
+		keepBranch bool
+	}{
+		{name: "delete branch", keepBranch: false},
+		{name: "keep branch", keepBranch: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			vcMock := &vcmock.VersionController{}
+			defer vcMock.Clean()
+			cmd.OverrideVersionController = vcMock
+
+			tmpDir, err := os.MkdirTemp(os.TempDir(), "multi-git-test-close-")
+			defer os.RemoveAll(tmpDir)
+			require.NoError(t, err)
+
+			repo := createRepo(t, "owner", "should-change", "i like apples")
+			vcMock.AddRepository(repo)
+
+			command := cmd.RootCmd()
+			command.SetArgs([]string{
+				"run",
+				"--log-file", filepath.Join(tmpDir, "run-log.txt"),
+				"--output", filepath.Join(tmpDir, "out.txt"),
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				changerBinaryPath,
+			})
+			require.NoError(t, command.Execute())
+			require.True(t, branchExist(t, repo.Path, "custom-branch-name"))
+
+			args := []string{
+				"close",
+				"--log-file", filepath.Join(tmpDir, "close-log.txt"),
+				"-B", "custom-branch-name",
+			}
+			if test.keepBranch {
+				args = append(args, "--keep-branch")
+			}
+			command = cmd.RootCmd()
+			command.SetArgs(args)
+			require.NoError(t, command.Execute())
+
+			require.Len(t, vcMock.PullRequests, 1)
+			assert.Equal(t, scm.PullRequestStatusClosed, vcMock.PullRequests[0].PRStatus)
+			assert.Equal(t, test.keepBranch, branchExist(t, repo.Path, "custom-branch-name"))
+		})
+	}
+}
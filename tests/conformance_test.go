@@ -0,0 +1,47 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/lindell/multi-gitter/internal/scm/scmtest"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestVersionControllerConformance tests that the mock version controller, that the other tests rely on, behaves like the
+// real platforms
+func TestVersionControllerConformance(t *testing.T) {
+	scmtest.Run(t, func(t *testing.T) scmtest.Setup {
+		vcMock := &vcmock.VersionController{PageSize: 2}
+		t.Cleanup(vcMock.Clean)
+
+		names := []string{"first", "second", "third"}
+		fullNames := make([]string, len(names))
+		for i, name := range names {
+			repo := createRepo(t, "owner", name, "i like apples")
+			changeBranch(t, repo.Path, "conformance-branch", true)
+			changeTestFile(t, repo.Path, "i like bananas", "test change")
+			changeBranch(t, repo.Path, "master", false)
+			vcMock.AddRepository(repo)
+			fullNames[i] = repo.FullName()
+		}
+
+		return scmtest.Setup{
+			VersionController: vcMock,
+			Repositories:      fullNames,
+			PageSize:          vcMock.PageSize,
+			Branch:            "conformance-branch",
+			ForkOwner:         "fork-owner",
+			PullRequestLabels: func(t *testing.T, pr scm.PullRequest) []string {
+				for _, p := range vcMock.PullRequests {
+					if p.String() == pr.String() {
+						return p.Labels
+					}
+				}
+				require.Failf(t, "the pull request does not exist", "%s", pr.String())
+				return nil
+			},
+		}
+	})
+}
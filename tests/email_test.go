@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startSMTPServer starts a minimal SMTP server that sends every received message on the returned channel
+func startSMTPServer(t *testing.T) (string, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	messages := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSMTP(conn, messages)
+		}
+	}()
+	return listener.Addr().String(), messages
+}
+
+func serveSMTP(conn net.Conn, messages chan<- string) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		switch command := strings.ToUpper(strings.Fields(line)[0]); command {
+		case "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			messages <- data.String()
+			reply("250 ok")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+// TestEmailDigest tests that a digest with the results of a run is emailed
+func TestEmailDigest(t *testing.T) {
+	smtpServer, messages := startSMTPServer(t)
+
+	vcMock := &vcmock.VersionController{}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir, err := os.MkdirTemp(os.TempDir(), "multi-git-test-email-")
+	defer os.RemoveAll(tmpDir)
+	require.NoError(t, err)
+
+	vcMock.AddRepository(createRepo(t, "owner", "should-change", "i like apples"))
+
+	command := cmd.RootCmd()
+	command.SetArgs([]string{
+		"run",
+		"--log-file", filepath.Join(tmpDir, "log.txt"),
+		"--output", filepath.Join(tmpDir, "out.txt"),
+		"--author-name", "Test Author",
+		"--author-email", "test@example.com",
+		"-B", "custom-branch-name",
+		"-m", "custom message",
+		"--smtp-server", smtpServer,
+		"--email-from", "multi-gitter@example.com",
+		"--email-to", "team@example.com",
+		changerBinaryPath,
+	})
+	require.NoError(t, command.Execute())
+
+	require.Len(t, messages, 1)
+	msg := <-messages
+	assert.Contains(t, msg, "To: team@example.com\r\n")
+	assert.Contains(t, msg, "Subject: multi-gitter run of custom-branch-name\r\n")
+	assert.Contains(t, msg, "\r\n\r\nRepositories with a successful run:\r\n  owner/should-change #1\r\n")
+}
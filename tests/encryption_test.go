@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/internal/encryption"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEncryptedOutput tests that the output of a run is encrypted, and can be read with the decrypt command
+func TestEncryptedOutput(t *testing.T) {
+	vcMock := &vcmock.VersionController{}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir, err := os.MkdirTemp(os.TempDir(), "multi-git-test-encryption-")
+	defer os.RemoveAll(tmpDir)
+	require.NoError(t, err)
+
+	vcMock.AddRepository(createRepo(t, "owner", "should-change", "i like apples"))
+
+	keyFile := filepath.Join(tmpDir, "key.txt")
+	require.NoError(t, os.WriteFile(keyFile, []byte("secret passphrase\n"), 0o600))
+	outFile := filepath.Join(tmpDir, "out.txt")
+
+	command := cmd.RootCmd()
+	command.SetArgs([]string{
+		"run",
+		"--log-file", filepath.Join(tmpDir, "log.txt"),
+		"--output", outFile,
+		"--encryption-key-file", keyFile,
+		"--author-name", "Test Author",
+		"--author-email", "test@example.com",
+		"-B", "custom-branch-name",
+		"-m", "custom message",
+		changerBinaryPath,
+	})
+	require.NoError(t, command.Execute())
+
+	encrypted, err := os.ReadFile(outFile)
+	require.NoError(t, err)
+	assert.True(t, encryption.IsEncrypted(encrypted))
+	assert.NotContains(t, string(encrypted), "owner/should-change")
+
+	decryptedFile := filepath.Join(tmpDir, "decrypted.txt")
+	command = cmd.RootCmd()
+	command.SetArgs([]string{
+		"decrypt",
+		"--output", decryptedFile,
+		"--encryption-key-file", keyFile,
+		outFile,
+	})
+	require.NoError(t, command.Execute())
+
+	decrypted, err := os.ReadFile(decryptedFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(decrypted), "Repositories with a successful run:\n  owner/should-change #1\n")
+}
@@ -0,0 +1,131 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testEvent struct {
+	SpecVersion string `json:"specversion"`
+	Type        string `json:"type"`
+	Subject     string `json:"subject"`
+	Data        struct {
+		Repository  string `json:"repository"`
+		Error       string `json:"error"`
+		Reason      string `json:"reason"`
+		PullRequest *struct {
+			Name string `json:"name"`
+		} `json:"pullRequest"`
+	} `json:"data"`
+}
+
+// TestEventWebhook tests that a CloudEvent is sent for the outcome of every repository
+func TestEventWebhook(t *testing.T) {
+	var lock sync.Mutex
+	var events []testEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/cloudevents+json", r.Header.Get("Content-Type"))
+
+		var event testEvent
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+
+		lock.Lock()
+		events = append(events, event)
+		lock.Unlock()
+	}))
+	defer server.Close()
+
+	vcMock := &vcmock.VersionController{}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir, err := os.MkdirTemp(os.TempDir(), "multi-git-test-events-")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	workingDir, err := os.Getwd()
+	assert.NoError(t, err)
+
+	vcMock.AddRepository(
+		createRepo(t, "owner", "should-change", "i like apples"),
+		createRepo(t, "owner", "should-not-change", "i like oranges"),
+	)
+
+	command := cmd.RootCmd()
+	command.SetArgs([]string{
+		"run",
+		"--log-file", filepath.Join(tmpDir, "log.txt"),
+		"--output", filepath.Join(tmpDir, "out.txt"),
+		"--author-name", "Test Author",
+		"--author-email", "test@example.com",
+		"-m", "test",
+		"--event-webhook-url", server.URL,
+		normalizePath(filepath.Join(workingDir, changerBinaryPath)),
+	})
+	err = command.Execute()
+	assert.NoError(t, err)
+
+	require.Len(t, events, 2)
+	sort.Slice(events, func(i, j int) bool { return events[i].Subject < events[j].Subject })
+
+	assert.Equal(t, "1.0", events[0].SpecVersion)
+	assert.Equal(t, "com.github.lindell.multi-gitter.repository.succeeded", events[0].Type)
+	assert.Equal(t, "owner/should-change", events[0].Data.Repository)
+	require.NotNil(t, events[0].Data.PullRequest)
+	assert.Equal(t, "owner/should-change #1", events[0].Data.PullRequest.Name)
+
+	assert.Equal(t, "com.github.lindell.multi-gitter.repository.skipped", events[1].Type)
+	assert.Equal(t, "owner/should-not-change", events[1].Data.Repository)
+	assert.Empty(t, events[1].Data.Error)
+	assert.Equal(t, "no data was changed", events[1].Data.Reason)
+	assert.Nil(t, events[1].Data.PullRequest)
+}
+
+// TestEventWebhookReadOnly tests that no events are sent in read-only mode
+func TestEventWebhookReadOnly(t *testing.T) {
+	var lock sync.Mutex
+	sent := 0
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		lock.Lock()
+		sent++
+		lock.Unlock()
+	}))
+	defer server.Close()
+
+	vcMock := &vcmock.VersionController{}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir := t.TempDir()
+	vcMock.AddRepository(createRepo(t, "owner", "should-change", "i like apples"))
+
+	command := cmd.RootCmd()
+	command.SetArgs([]string{
+		"run",
+		"--log-file", filepath.Join(tmpDir, "log.txt"),
+		"--output", filepath.Join(tmpDir, "out.txt"),
+		"--author-name", "Test Author",
+		"--author-email", "test@example.com",
+		"-m", "test",
+		"--read-only",
+		"--event-webhook-url", server.URL,
+		changerBinaryPath,
+	})
+	require.NoError(t, command.Execute())
+
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Zero(t, sent)
+	assert.Contains(t, readFile(t, tmpDir, "log.txt"), "No events will be sent to the event webhook since multi-gitter is in read-only mode")
+}
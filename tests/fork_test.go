@@ -0,0 +1,60 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDeleteForks tests that the forks pull requests were made from can be deleted when the pull requests are closed
+func TestDeleteForks(t *testing.T) {
+	vcMock := &vcmock.VersionController{}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir, err := os.MkdirTemp(os.TempDir(), "multi-git-test-fork-")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	repo := createRepo(t, "owner", "should-change", "i like apples")
+	vcMock.AddRepository(repo)
+	forkPath := repo.Path + "-forked-default-owner"
+
+	command := cmd.RootCmd()
+	command.SetArgs([]string{
+		"run",
+		"--log-file", filepath.Join(tmpDir, "run-log.txt"),
+		"--output", filepath.Join(tmpDir, "out.txt"),
+		"--author-name", "Test Author",
+		"--author-email", "test@example.com",
+		"-B", "custom-branch-name",
+		"-m", "custom message",
+		"--fork",
+		changerBinaryPath,
+	})
+	err = command.Execute()
+	require.NoError(t, err)
+	require.Len(t, vcMock.PullRequests, 1)
+	assert.DirExists(t, forkPath)
+
+	closeLogFile := filepath.Join(tmpDir, "close-log.txt")
+	command = cmd.RootCmd()
+	command.SetArgs([]string{
+		"close",
+		"--log-file", closeLogFile,
+		"-B", "custom-branch-name",
+		"--delete-forks",
+	})
+	err = command.Execute()
+	require.NoError(t, err)
+
+	closeLogData, err := os.ReadFile(closeLogFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(closeLogData), `Deleted fork" pr="owner/should-change #1"`)
+	assert.NoDirExists(t, forkPath)
+}
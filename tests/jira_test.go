@@ -0,0 +1,135 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeJira is a minimal Jira that keeps track of issues by their label
+type fakeJira struct {
+	lock        sync.Mutex
+	issues      map[string]string // Issue keys by label
+	transitions map[string]string // The transitions made, by issue key
+}
+
+func (j *fakeJira) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/search":
+		issues := []map[string]string{}
+		for label, key := range j.issues {
+			if strings.Contains(r.URL.Query().Get("jql"), fmt.Sprintf("labels = %q", label)) {
+				issues = append(issues, map[string]string{"key": key})
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"issues": issues})
+	case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue":
+		var body struct {
+			Fields struct {
+				Labels []string `json:"labels"`
+			} `json:"fields"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		key := fmt.Sprintf("PROJ-%d", len(j.issues)+1)
+		j.issues[body.Fields.Labels[0]] = key
+		_ = json.NewEncoder(w).Encode(map[string]string{"key": key})
+	case strings.HasSuffix(r.URL.Path, "/transitions"):
+		key := strings.Split(r.URL.Path, "/")[5]
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`{"transitions":[{"id":"11","name":"In Progress"},{"id":"31","name":"Done"}]}`))
+			return
+		}
+		var body struct {
+			Transition struct {
+				ID string `json:"id"`
+			} `json:"transition"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		j.transitions[key] = body.Transition.ID
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// TestJira tests that a Jira issue is created and referenced by the pull requests, and transitioned when they are merged
+func TestJira(t *testing.T) {
+	jira := &fakeJira{
+		issues:      map[string]string{},
+		transitions: map[string]string{},
+	}
+	server := httptest.NewServer(jira)
+	defer server.Close()
+
+	vcMock := &vcmock.VersionController{}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir, err := os.MkdirTemp(os.TempDir(), "multi-git-test-jira-")
+	defer os.RemoveAll(tmpDir)
+	require.NoError(t, err)
+
+	workingDir, err := os.Getwd()
+	require.NoError(t, err)
+
+	vcMock.AddRepository(
+		createRepo(t, "owner", "should-change-1", "i like apples"),
+		createRepo(t, "owner", "should-change-2", "i like apples"),
+	)
+
+	command := cmd.RootCmd()
+	command.SetArgs([]string{
+		"run",
+		"--log-file", filepath.Join(tmpDir, "log.txt"),
+		"--output", filepath.Join(tmpDir, "out.txt"),
+		"--author-name", "Test Author",
+		"--author-email", "test@example.com",
+		"-B", "custom-branch-name",
+		"-m", "custom message",
+		"--jira-url", server.URL,
+		"--jira-project", "PROJ",
+		"--jira-token", "token",
+		normalizePath(filepath.Join(workingDir, changerBinaryPath)),
+	})
+	require.NoError(t, command.Execute())
+
+	assert.Equal(t, map[string]string{"multi-gitter:custom-branch-name": "PROJ-1"}, jira.issues)
+	require.Len(t, vcMock.PullRequests, 2)
+	for _, pr := range vcMock.PullRequests {
+		assert.Equal(t, "PROJ-1 custom message", pr.Title)
+		assert.Equal(t, "Jira issue: [PROJ-1]("+server.URL+"/browse/PROJ-1)", pr.Body)
+	}
+
+	for i := range vcMock.PullRequests {
+		vcMock.PullRequests[i].PRStatus = scm.PullRequestStatusSuccess
+	}
+
+	command = cmd.RootCmd()
+	command.SetArgs([]string{
+		"merge",
+		"--log-file", filepath.Join(tmpDir, "merge-log.txt"),
+		"-B", "custom-branch-name",
+		"--jira-url", server.URL,
+		"--jira-project", "PROJ",
+		"--jira-token", "token",
+		"--jira-transition", "done",
+	})
+	require.NoError(t, command.Execute())
+
+	assert.Equal(t, map[string]string{"PROJ-1": "31"}, jira.transitions)
+}
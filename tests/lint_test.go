@@ -0,0 +1,47 @@
+package tests
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/lindell/multi-gitter/internal/multigitter"
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLint(t *testing.T) {
+	vcMock := &vcmock.VersionController{
+		Constraints: scm.PullRequestConstraints{
+			MaxTitleLength:      10,
+			ForbiddenLabelChars: ",",
+		},
+	}
+	defer vcMock.Clean()
+
+	vcMock.AddRepository(createRepo(t, "owner", "long-title", "i like apples"))
+	vcMock.AddRepository(createRepo(t, "owner", "short-title", "i like apples"))
+
+	runner := &multigitter.Runner{
+		VersionController: vcMock,
+		ScriptPath:        changerBinaryPath,
+		FeatureBranch:     "feature..branch",
+		Output:            io.Discard,
+		CommitMessage:     "a title that is too long",
+		PullRequestTitle:  "a title that is too long",
+		Labels:            []string{"a,b"},
+		PullRequestOverrides: map[string]multigitter.PullRequestOverride{
+			"owner/short-title": {Title: "short"},
+		},
+		Concurrent: 1,
+	}
+
+	err := runner.Run(context.Background())
+	assert.EqualError(t, err, `the platform would reject the changes:
+  branch "feature..branch": the name contains ".."
+  owner/long-title: the title is 24 characters long, the maximum is 10
+  owner/long-title: the label "a,b" contains any of the forbidden characters ","
+  owner/short-title: the label "a,b" contains any of the forbidden characters ","`)
+	assert.Len(t, vcMock.PullRequests, 0)
+}
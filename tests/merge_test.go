@@ -0,0 +1,316 @@
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMergeGroups tests that a merge group is not merged until all pull requests of the previous groups are merged
+func TestMergeGroups(t *testing.T) {
+	vcMock := &vcmock.VersionController{}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir, err := os.MkdirTemp(os.TempDir(), "multi-git-test-merge-")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	libRepo := createRepo(t, "owner", "lib", "i like apples")
+	appRepo := createRepo(t, "owner", "app", "i like apples")
+	otherRepo := createRepo(t, "owner", "other", "i like apples")
+	vcMock.AddRepository(libRepo, appRepo, otherRepo)
+	for i, repo := range []vcmock.Repository{otherRepo, appRepo, libRepo} {
+		vcMock.PullRequests = append(vcMock.PullRequests, vcmock.PullRequest{
+			PRStatus:   scm.PullRequestStatusSuccess,
+			PRNumber:   i + 1,
+			Repository: repo,
+			NewPullRequest: scm.NewPullRequest{
+				Head: "custom-branch-name",
+			},
+		})
+	}
+	vcMock.SetPRStatus("lib", "custom-branch-name", scm.PullRequestStatusPending)
+
+	merge := func() error {
+		command := cmd.RootCmd()
+		command.SetArgs([]string{
+			"merge",
+			"--log-file", filepath.Join(tmpDir, "merge-log.txt"),
+			"-B", "custom-branch-name",
+			"--merge-group", "owner/lib",
+			"--merge-group", "owner/app*",
+		})
+		return command.Execute()
+	}
+
+	err = merge()
+	assert.EqualError(t, err, "not all pull requests of merge group 1 could be merged, the remaining groups were not merged")
+	for _, pr := range vcMock.PullRequests {
+		assert.NotEqual(t, scm.PullRequestStatusMerged, pr.PRStatus, pr.String())
+	}
+
+	vcMock.SetPRStatus("lib", "custom-branch-name", scm.PullRequestStatusSuccess)
+
+	err = merge()
+	require.NoError(t, err)
+	for _, pr := range vcMock.PullRequests {
+		assert.Equal(t, scm.PullRequestStatusMerged, pr.PRStatus, pr.String())
+	}
+}
+
+// TestMergeVerify tests that merging is aborted when the verification of a merged pull request fails
+func TestMergeVerify(t *testing.T) {
+	vcMock := &vcmock.VersionController{}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir, err := os.MkdirTemp(os.TempDir(), "multi-git-test-merge-")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	workingDir, err := os.Getwd()
+	assert.NoError(t, err)
+
+	brokenRepo := createRepo(t, "owner", "broken", "i like apples")
+	otherRepo := createRepo(t, "owner", "other", "i like apples")
+	vcMock.AddRepository(brokenRepo, otherRepo)
+	for i, repo := range vcMock.Repositories {
+		vcMock.PullRequests = append(vcMock.PullRequests, vcmock.PullRequest{
+			PRStatus:   scm.PullRequestStatusSuccess,
+			PRNumber:   i + 1,
+			Repository: repo,
+			NewPullRequest: scm.NewPullRequest{
+				Head: "custom-branch-name",
+			},
+		})
+	}
+
+	command := cmd.RootCmd()
+	command.SetArgs([]string{
+		"merge",
+		"--log-file", filepath.Join(tmpDir, "merge-log.txt"),
+		"-B", "custom-branch-name",
+		"--verify-command", fmt.Sprintf("go run %s -fail owner/broken", normalizePath(filepath.Join(workingDir, "scripts/verifier/main.go"))),
+	})
+	err = command.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "verification of owner/broken #1 failed, the remaining pull requests were not merged")
+	assert.Contains(t, err.Error(), "owner/broken is broken")
+
+	assert.Equal(t, scm.PullRequestStatusMerged, vcMock.PullRequests[0].PRStatus)
+	assert.Equal(t, scm.PullRequestStatusSuccess, vcMock.PullRequests[1].PRStatus)
+}
+
+// TestMergeRelease tests that a release is created for each merged pull request
+func TestMergeRelease(t *testing.T) {
+	vcMock := &vcmock.VersionController{}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir, err := os.MkdirTemp(os.TempDir(), "multi-git-test-merge-")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	mergedRepo := createRepo(t, "owner", "merged", "i like apples")
+	pendingRepo := createRepo(t, "owner", "pending", "i like apples")
+	vcMock.AddRepository(mergedRepo, pendingRepo)
+	vcMock.PullRequests = []vcmock.PullRequest{
+		{
+			PRStatus:       scm.PullRequestStatusSuccess,
+			PRNumber:       1,
+			Repository:     mergedRepo,
+			NewPullRequest: scm.NewPullRequest{Head: "custom-branch-name"},
+		},
+		{
+			PRStatus:       scm.PullRequestStatusPending,
+			PRNumber:       2,
+			Repository:     pendingRepo,
+			NewPullRequest: scm.NewPullRequest{Head: "custom-branch-name"},
+		},
+	}
+
+	command := cmd.RootCmd()
+	command.SetArgs([]string{
+		"merge",
+		"--log-file", filepath.Join(tmpDir, "merge-log.txt"),
+		"-B", "custom-branch-name",
+		"--release-tag", "v1.0.0",
+		"--release-notes", "Merged {{.PullRequest}} in {{.Repository}} from {{.Branch}}",
+	})
+	err = command.Execute()
+	require.NoError(t, err)
+
+	require.Len(t, vcMock.Releases, 1)
+	assert.Equal(t, vcmock.Release{
+		RepoName: "owner/merged",
+		NewRelease: scm.NewRelease{
+			TagName: "v1.0.0",
+			Name:    "v1.0.0",
+			Notes:   "Merged owner/merged #1 in owner/merged from custom-branch-name",
+		},
+	}, vcMock.Releases[0])
+}
+
+// TestMergeMessage tests that the message of merge commits can be made from templates
+func TestMergeMessage(t *testing.T) {
+	vcMock := &vcmock.VersionController{}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir, err := os.MkdirTemp(os.TempDir(), "multi-git-test-merge-")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	repo := createRepo(t, "owner", "repo", "i like apples")
+	vcMock.AddRepository(repo)
+	vcMock.PullRequests = []vcmock.PullRequest{
+		{
+			PRStatus:       scm.PullRequestStatusSuccess,
+			PRNumber:       1,
+			Repository:     repo,
+			NewPullRequest: scm.NewPullRequest{Head: "custom-branch-name"},
+		},
+	}
+
+	command := cmd.RootCmd()
+	command.SetArgs([]string{
+		"merge",
+		"--log-file", filepath.Join(tmpDir, "merge-log.txt"),
+		"-B", "custom-branch-name",
+		"--merge-title", "Merge {{.Branch}} into {{.Repository}}",
+		"--merge-message", "Merged {{.PullRequest}}",
+	})
+	err = command.Execute()
+	require.NoError(t, err)
+
+	assert.Equal(t, scm.PullRequestStatusMerged, vcMock.PullRequests[0].PRStatus)
+	assert.Equal(t, scm.MergeMessage{
+		Title: "Merge custom-branch-name into owner/repo",
+		Body:  "Merged owner/repo #1",
+	}, vcMock.PullRequests[0].MergeMessage)
+}
+
+// TestMergeWait tests that pending pull requests are waited for, and merged once they are ready
+func TestMergeWait(t *testing.T) {
+	vcMock := &vcmock.VersionController{}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir, err := os.MkdirTemp(os.TempDir(), "multi-git-test-merge-")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	repo := createRepo(t, "owner", "repo", "i like apples")
+	vcMock.AddRepository(repo)
+	vcMock.PullRequests = []vcmock.PullRequest{
+		{
+			PRStatus:       scm.PullRequestStatusPending,
+			PRNumber:       1,
+			Repository:     repo,
+			NewPullRequest: scm.NewPullRequest{Head: "custom-branch-name"},
+		},
+	}
+
+	// The checks pass while the merge command is waiting
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		vcMock.SetPRStatus("repo", "custom-branch-name", scm.PullRequestStatusSuccess)
+	}()
+
+	logFile := filepath.Join(tmpDir, "merge-log.txt")
+	command := cmd.RootCmd()
+	command.SetArgs([]string{
+		"merge",
+		"--log-file", logFile,
+		"-B", "custom-branch-name",
+		"--wait", "1m",
+	})
+	err = command.Execute()
+	require.NoError(t, err)
+
+	logData, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(logData), "Waiting for 1 pending pull requests")
+	assert.Equal(t, scm.PullRequestStatusMerged, vcMock.PullRequests[0].PRStatus)
+}
+
+// TestMergeRetryConflicts tests that pull requests that can not be merged get their branch updated, and are merged again
+func TestMergeRetryConflicts(t *testing.T) {
+	vcMock := &vcmock.VersionController{}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir, err := os.MkdirTemp(os.TempDir(), "multi-git-test-merge-")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	repo1 := createRepo(t, "owner", "repo1", "i like apples")
+	repo2 := createRepo(t, "owner", "repo2", "i like apples")
+	repo3 := createRepo(t, "owner", "repo3", "i like apples")
+	vcMock.AddRepository(repo1)
+	vcMock.AddRepository(repo2)
+	vcMock.AddRepository(repo3)
+	vcMock.PullRequests = []vcmock.PullRequest{
+		{
+			PRStatus:       scm.PullRequestStatusSuccess,
+			PRNumber:       1,
+			Repository:     repo1,
+			NewPullRequest: scm.NewPullRequest{Head: "custom-branch-name"},
+		},
+		{
+			PRStatus:       scm.PullRequestStatusSuccess,
+			PRNumber:       2,
+			Repository:     repo2,
+			Conflicting:    true,
+			NewPullRequest: scm.NewPullRequest{Head: "custom-branch-name"},
+		},
+		{
+			PRStatus:       scm.PullRequestStatusSuccess,
+			PRNumber:       3,
+			Repository:     repo3,
+			Conflicting:    true,
+			Unupdatable:    true,
+			NewPullRequest: scm.NewPullRequest{Head: "custom-branch-name"},
+		},
+	}
+
+	logFile := filepath.Join(tmpDir, "merge-log.txt")
+	command := cmd.RootCmd()
+	command.SetArgs([]string{
+		"merge",
+		"--log-file", logFile,
+		"-B", "custom-branch-name",
+		"--wait", "1m",
+		"--retry-conflicts",
+	})
+	err = command.Execute()
+	require.NoError(t, err)
+
+	logData, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(logData), "Could not merge, updating the branch with the base branch to retry: the pull request has conflicts")
+	assert.Contains(t, string(logData), "Retrying merge")
+	assert.Contains(t, string(logData), "the branch could not be updated to retry the merge (the branch conflicts with the base branch): the pull request has conflicts")
+	assert.Equal(t, scm.PullRequestStatusMerged, vcMock.PullRequests[0].PRStatus)
+	assert.Equal(t, scm.PullRequestStatusMerged, vcMock.PullRequests[1].PRStatus)
+	assert.Equal(t, scm.PullRequestStatusSuccess, vcMock.PullRequests[2].PRStatus)
+
+	command = cmd.RootCmd()
+	command.SetArgs([]string{
+		"merge",
+		"--log-file", logFile,
+		"-B", "custom-branch-name",
+		"--retry-conflicts",
+	})
+	err = command.Execute()
+	assert.EqualError(t, err, "--wait has to be set to retry conflicting merges")
+}
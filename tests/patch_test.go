@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// patchVersionController is a mock of a platform where changes are submitted as patches
+type patchVersionController struct {
+	*vcmock.VersionController
+}
+
+func (vc patchVersionController) PatchSubjectPrefix(repo scm.Repository, branchName string) string {
+	return "PATCH " + repo.(vcmock.Repository).RepoName + " " + branchName
+}
+
+// TestPatch tests that changes are written as patches on platforms where changes are submitted as patches
+func TestPatch(t *testing.T) {
+	for _, gitBackend := range gitBackends {
+		t.Run(string(gitBackend), func(t *testing.T) {
+			vcMock := &vcmock.VersionController{}
+			defer vcMock.Clean()
+			cmd.OverrideVersionController = patchVersionController{vcMock}
+			defer func() { cmd.OverrideVersionController = nil }()
+
+			tmpDir, err := os.MkdirTemp(os.TempDir(), "multi-git-test-patch-")
+			defer os.RemoveAll(tmpDir)
+			assert.NoError(t, err)
+
+			workingDir, err := os.Getwd()
+			assert.NoError(t, err)
+
+			changeRepo := createRepo(t, "owner", "should-change", "i like apples")
+			vcMock.AddRepository(changeRepo)
+
+			patchDir := filepath.Join(tmpDir, "patches")
+
+			command := cmd.RootCmd()
+			command.SetArgs([]string{
+				"run",
+				"--log-file", filepath.Join(tmpDir, "log.txt"),
+				"--output", filepath.Join(tmpDir, "out.txt"),
+				"--git-type", string(gitBackend),
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message\n\nbody text",
+				"--patch-dir", patchDir,
+				normalizePath(filepath.Join(workingDir, changerBinaryPath)),
+			})
+			err = command.Execute()
+			assert.NoError(t, err)
+
+			assert.Len(t, vcMock.PullRequests, 0)
+
+			patch, err := os.ReadFile(filepath.Join(patchDir, "owner", "should-change.patch"))
+			require.NoError(t, err)
+			assert.Contains(t, string(patch), "From: Test Author <test@example.com>\n")
+			assert.Contains(t, string(patch), "Subject: [PATCH should-change custom-branch-name] custom message\n")
+			assert.Contains(t, string(patch), "\nbody text\n---\n")
+			assert.Contains(t, string(patch), "\n-i like apples\n")
+			assert.Contains(t, string(patch), "\n+i like bananas\n")
+
+			// The feature branch should never have been pushed
+			assert.False(t, branchExist(t, changeRepo.Path, "custom-branch-name"))
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTriggerPipeline tests that pipelines are triggered and waited for, and that their status is part of the status output
+func TestTriggerPipeline(t *testing.T) {
+	vcMock := &vcmock.VersionController{}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir, err := os.MkdirTemp(os.TempDir(), "multi-git-test-pipeline-")
+	defer os.RemoveAll(tmpDir)
+	require.NoError(t, err)
+
+	vcMock.AddRepository(createRepo(t, "owner", "passing", "i like apples"))
+	failing := createRepo(t, "owner", "failing", "i like apples")
+	failing.PipelineStatus = "failed"
+	vcMock.AddRepository(failing)
+
+	outFile := filepath.Join(tmpDir, "out.txt")
+	command := cmd.RootCmd()
+	command.SetArgs([]string{
+		"run",
+		"--output", outFile,
+		"--author-name", "Test Author",
+		"--author-email", "test@example.com",
+		"-B", "custom-branch-name",
+		"-m", "custom message",
+		"--trigger-pipeline",
+		"--wait-for-pipeline", "1m",
+		changerBinaryPath,
+	})
+	require.NoError(t, command.Execute())
+
+	out, err := os.ReadFile(outFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "Repositories with a successful run:\n  owner/passing #")
+	assert.Contains(t, string(out), "The pipeline ended with the status failed:\n  owner/failing #")
+
+	command = cmd.RootCmd()
+	command.SetArgs([]string{
+		"status",
+		"--output", outFile,
+		"-B", "custom-branch-name",
+	})
+	require.NoError(t, command.Execute())
+
+	out, err = os.ReadFile(outFile)
+	require.NoError(t, err)
+	assert.Regexp(t, `owner/passing #\d: Pending \(checks: success\)\n`, string(out))
+	assert.Regexp(t, `owner/failing #\d: Pending \(checks: failed\)\n`, string(out))
+}
@@ -0,0 +1,225 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/internal/multigitter"
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPlan tests that a plan can be made without changing anything, and that a saved plan limits a later run
+func TestPlan(t *testing.T) {
+	vcMock := &vcmock.VersionController{}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir, err := os.MkdirTemp(os.TempDir(), "multi-git-test-plan-")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	vcMock.AddRepository(createRepo(t, "owner", "already-changed", "i like apples"))
+	vcMock.AddRepository(createRepo(t, "owner", "should-change", "i like apples"))
+
+	command := cmd.RootCmd()
+	command.SetArgs([]string{
+		"run",
+		"--log-file", filepath.Join(tmpDir, "first-run-log.txt"),
+		"--output", filepath.Join(tmpDir, "first-run-out.txt"),
+		"--author-name", "Test Author",
+		"--author-email", "test@example.com",
+		"-B", "custom-branch-name",
+		"-m", "custom message",
+		"--repo-include", "already-changed",
+		changerBinaryPath,
+	})
+	require.NoError(t, command.Execute())
+	require.Len(t, vcMock.PullRequests, 1)
+
+	planFile := filepath.Join(tmpDir, "plan.json")
+	planOutFile := filepath.Join(tmpDir, "plan-out.txt")
+	command = cmd.RootCmd()
+	command.SetArgs([]string{
+		"plan",
+		"--log-file", filepath.Join(tmpDir, "plan-log.txt"),
+		"--output", planOutFile,
+		"-B", "custom-branch-name",
+		"-m", "custom message",
+		"--labels", "automated",
+		"--plan-file", planFile,
+	})
+	require.NoError(t, command.Execute())
+	require.Len(t, vcMock.PullRequests, 1)
+
+	plan, err := multigitter.ReadPlan(planFile, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "custom-branch-name", plan.FeatureBranch)
+	require.Len(t, plan.Repositories, 2)
+	assert.Equal(t, "owner/already-changed #1", plan.Repositories[0].ExistingPullRequest)
+	assert.NotEmpty(t, plan.Repositories[0].Blockers)
+	assert.Equal(t, "master", plan.Repositories[1].BaseBranch)
+	assert.Equal(t, "custom message", plan.Repositories[1].Title)
+	assert.Equal(t, []string{"automated"}, plan.Repositories[1].Labels)
+	assert.Empty(t, plan.Repositories[1].Blockers)
+	require.Len(t, plan.RunnableRepositories(), 1)
+	assert.Equal(t, "owner/should-change", plan.RunnableRepositories()[0].Name)
+
+	planOut, err := os.ReadFile(planOutFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(planOut), `Branch "custom-branch-name" will be used on 1 of 2 repositories`)
+	assert.Contains(t, string(planOut), "Blocked: a pull request already exists")
+
+	runLogFile := filepath.Join(tmpDir, "run-log.txt")
+	command = cmd.RootCmd()
+	command.SetArgs([]string{
+		"run",
+		"--log-file", runLogFile,
+		"--output", filepath.Join(tmpDir, "run-out.txt"),
+		"--author-name", "Test Author",
+		"--author-email", "test@example.com",
+		"-B", "custom-branch-name",
+		"-m", "custom message",
+		"--from-plan", planFile,
+		changerBinaryPath,
+	})
+	require.NoError(t, command.Execute())
+	require.Len(t, vcMock.PullRequests, 2)
+	assert.Equal(t, "owner/should-change #2", vcMock.PullRequests[1].String())
+
+	runLogData, err := os.ReadFile(runLogFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(runLogData), "Skipping owner/already-changed since it is not part of the plan")
+}
+
+// TestPlanRenamedRepository tests that a repository that is renamed after a plan is made is still used by the run
+func TestPlanRenamedRepository(t *testing.T) {
+	vcMock := &vcmock.VersionController{}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir, err := os.MkdirTemp(os.TempDir(), "multi-git-test-plan-renamed-")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	repo := createRepo(t, "owner", "old-name", "i like apples")
+	repo.RepoID = "1234"
+	vcMock.AddRepository(repo)
+
+	planFile := filepath.Join(tmpDir, "plan.json")
+	command := cmd.RootCmd()
+	command.SetArgs([]string{
+		"plan",
+		"--log-file", filepath.Join(tmpDir, "plan-log.txt"),
+		"--output", filepath.Join(tmpDir, "plan-out.txt"),
+		"-B", "custom-branch-name",
+		"-m", "custom message",
+		"--plan-file", planFile,
+	})
+	require.NoError(t, command.Execute())
+
+	vcMock.Repositories[0].OwnerName = "new-owner"
+	vcMock.Repositories[0].RepoName = "new-name"
+
+	runLogFile := filepath.Join(tmpDir, "run-log.txt")
+	command = cmd.RootCmd()
+	command.SetArgs([]string{
+		"run",
+		"--log-file", runLogFile,
+		"--output", filepath.Join(tmpDir, "run-out.txt"),
+		"--author-name", "Test Author",
+		"--author-email", "test@example.com",
+		"-B", "custom-branch-name",
+		"-m", "custom message",
+		"--from-plan", planFile,
+		changerBinaryPath,
+	})
+	require.NoError(t, command.Execute())
+	require.Len(t, vcMock.PullRequests, 1)
+	assert.Equal(t, "new-owner/new-name #1", vcMock.PullRequests[0].String())
+
+	runLogData, err := os.ReadFile(runLogFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(runLogData), "owner/old-name has been renamed or moved to new-owner/new-name since the plan was made")
+}
+
+// TestPlanRenamedRepositoryStatus tests that the status and close commands follow repositories that are renamed after
+// a plan is made, and that repositories without an id are not mistaken for each other
+func TestPlanRenamedRepositoryStatus(t *testing.T) {
+	vcMock := &vcmock.VersionController{}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir, err := os.MkdirTemp(os.TempDir(), "multi-git-test-plan-renamed-status-")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	renamed := createRepo(t, "owner", "old-name", "i like apples")
+	renamed.RepoID = "1234"
+	vcMock.AddRepository(renamed)
+	removed := createRepo(t, "owner", "removed", "i like apples")
+	removed.RepoID = "0"
+	vcMock.AddRepository(removed)
+
+	planFile := filepath.Join(tmpDir, "plan.json")
+	command := cmd.RootCmd()
+	command.SetArgs([]string{
+		"plan",
+		"--log-file", filepath.Join(tmpDir, "plan-log.txt"),
+		"--output", filepath.Join(tmpDir, "plan-out.txt"),
+		"-B", "custom-branch-name",
+		"-m", "custom message",
+		"--plan-file", planFile,
+	})
+	require.NoError(t, command.Execute())
+
+	vcMock.Repositories[0].OwnerName = "new-owner"
+	vcMock.Repositories[0].RepoName = "new-name"
+	unplanned := createRepo(t, "owner", "unplanned", "i like apples")
+	unplanned.RepoID = "0"
+	vcMock.Repositories[1] = unplanned
+	vcMock.PullRequests = []vcmock.PullRequest{
+		{
+			PRStatus:       scm.PullRequestStatusPending,
+			PRNumber:       1,
+			Repository:     vcMock.Repositories[0],
+			NewPullRequest: scm.NewPullRequest{Head: "custom-branch-name"},
+		},
+		{
+			PRStatus:       scm.PullRequestStatusPending,
+			PRNumber:       2,
+			Repository:     unplanned,
+			NewPullRequest: scm.NewPullRequest{Head: "custom-branch-name"},
+		},
+	}
+
+	statusOutFile := filepath.Join(tmpDir, "status-out.txt")
+	command = cmd.RootCmd()
+	command.SetArgs([]string{
+		"status",
+		"--log-file", filepath.Join(tmpDir, "status-log.txt"),
+		"--output", statusOutFile,
+		"-B", "custom-branch-name",
+		"--from-plan", planFile,
+	})
+	require.NoError(t, command.Execute())
+
+	statusOut, err := os.ReadFile(statusOutFile)
+	require.NoError(t, err)
+	assert.Equal(t, "new-owner/new-name #1: Pending\n", string(statusOut))
+
+	command = cmd.RootCmd()
+	command.SetArgs([]string{
+		"close",
+		"--log-file", filepath.Join(tmpDir, "close-log.txt"),
+		"-B", "custom-branch-name",
+		"--from-plan", planFile,
+	})
+	require.NoError(t, command.Execute())
+	assert.Equal(t, scm.PullRequestStatusClosed, vcMock.PullRequests[0].PRStatus)
+	assert.Equal(t, scm.PullRequestStatusPending, vcMock.PullRequests[1].PRStatus)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/lindell/multi-gitter/cmd"
@@ -55,3 +56,81 @@ func TestPrint(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "I LIKE APPLES\nI LIKE MY APPLE\nI LIKE ORANGES\n", string(errOutData))
 }
+
+func TestPrintEnvironment(t *testing.T) {
+	vcMock := &vcmock.VersionController{}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	t.Setenv("MULTI_GITTER_SECRET", "secret")
+	t.Setenv("MULTI_GITTER_FORWARDED", "forwarded")
+	t.Setenv("HTTPS_PROXY", "http://proxy.example.com")
+
+	tmpDir, err := os.MkdirTemp(os.TempDir(), "multi-git-test-run-")
+	assert.NoError(t, err)
+
+	workingDir, err := os.Getwd()
+	assert.NoError(t, err)
+
+	vcMock.AddRepository(createRepo(t, "owner", "test-1", "i like apples"))
+
+	runLogFile := filepath.Join(tmpDir, "print-log.txt")
+	outFile := filepath.Join(tmpDir, "out.txt")
+
+	command := cmd.RootCmd()
+	command.SetArgs([]string{
+		"print",
+		"--log-file", filepath.ToSlash(runLogFile),
+		"--output", filepath.ToSlash(outFile),
+		"--env", "MULTI_GITTER_FORWARDED",
+		"--env", "MULTI_GITTER_SET=set",
+		fmt.Sprintf(`%s -env MULTI_GITTER_SECRET,MULTI_GITTER_FORWARDED,MULTI_GITTER_SET,HTTPS_PROXY,REPOSITORY`,
+			normalizePath(filepath.Join(workingDir, printerBinaryPath))),
+	})
+	err = command.Execute()
+	assert.NoError(t, err)
+
+	outData, err := os.ReadFile(outFile)
+	require.NoError(t, err)
+	assert.Equal(t, `MULTI_GITTER_SECRET=
+MULTI_GITTER_FORWARDED=forwarded
+MULTI_GITTER_SET=set
+HTTPS_PROXY=http://proxy.example.com
+REPOSITORY=owner/test-1
+`, string(outData))
+}
+
+// TestPrintStatic tests that the default branch of repositories without a platform is found with the configured git type
+func TestPrintStatic(t *testing.T) {
+	cmd.OverrideVersionController = nil
+
+	workingDir, err := os.Getwd()
+	assert.NoError(t, err)
+
+	for _, gitType := range []string{"go", "cmd"} {
+		t.Run(gitType, func(t *testing.T) {
+			repo := createRepo(t, "owner", "test-1", "i like apples")
+			changeBranch(t, repo.Path, "main", true)
+			changeTestFile(t, repo.Path, "i like main", "main commit")
+
+			outFile := filepath.Join(t.TempDir(), "out.txt")
+
+			command := cmd.RootCmd()
+			command.SetArgs([]string{
+				"print",
+				"--platform", "none",
+				"--git-type", gitType,
+				"--repo-url", "file:///" + strings.TrimPrefix(filepath.ToSlash(repo.Path), "/"),
+				"--log-file", filepath.ToSlash(filepath.Join(t.TempDir(), "log.txt")),
+				"--output", filepath.ToSlash(outFile),
+				normalizePath(filepath.Join(workingDir, printerBinaryPath)),
+			})
+			err := command.Execute()
+			require.NoError(t, err)
+
+			outData, err := os.ReadFile(outFile)
+			require.NoError(t, err)
+			assert.Equal(t, "i like main\n", string(outData))
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/lindell/multi-gitter/internal/git"
+	"github.com/lindell/multi-gitter/internal/git/gogit"
+	"github.com/lindell/multi-gitter/internal/multigitter"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgress(t *testing.T) {
+	vcMock := &vcmock.VersionController{}
+	defer vcMock.Clean()
+
+	vcMock.AddRepository(createRepo(t, "owner", "should-change-1", "i like apples"))
+	vcMock.AddRepository(createRepo(t, "owner", "should-change-2", "i like apples"))
+	vcMock.AddRepository(createRepo(t, "owner", "no-change", "i like oranges"))
+
+	scriptPath, err := filepath.Abs(changerBinaryPath)
+	require.NoError(t, err)
+
+	progress := &bytes.Buffer{}
+	runner := &multigitter.Runner{
+		VersionController: vcMock,
+		ScriptPath:        scriptPath,
+		FeatureBranch:     "custom-branch-name",
+		Output:            io.Discard,
+		CommitMessage:     "custom message",
+		PullRequestTitle:  "custom message",
+		CommitAuthor:      &git.CommitAuthor{Name: "Test Author", Email: "test@example.com"},
+		Concurrent:        2,
+		Progress:          progress,
+		CreateGit: func(dir string) multigitter.Git {
+			return &gogit.Git{Directory: dir}
+		},
+	}
+
+	err = runner.Run(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, vcMock.PullRequests, 2)
+	assert.Contains(t, progress.String(), "3/3 repositories done\n")
+}
@@ -97,6 +97,16 @@ func branchExist(t *testing.T, path string, branchName string) bool {
 	return true
 }
 
+func branchCommit(t *testing.T, path string, branchName string) string {
+	repo, err := git.PlainOpen(path)
+	require.NoError(t, err)
+
+	ref, err := repo.Reference(plumbing.NewBranchReferenceName(branchName), false)
+	require.NoError(t, err)
+
+	return ref.Hash().String()
+}
+
 func changeTestFile(t *testing.T, basePath string, content string, commitMessage string) {
 	repo, err := git.PlainOpen(basePath)
 	require.NoError(t, err)
@@ -122,6 +132,17 @@ func changeTestFile(t *testing.T, basePath string, content string, commitMessage
 	require.NoError(t, err)
 }
 
+func addTag(t *testing.T, basePath string, tagName string) {
+	repo, err := git.PlainOpen(basePath)
+	require.NoError(t, err)
+
+	head, err := repo.Head()
+	require.NoError(t, err)
+
+	_, err = repo.CreateTag(tagName, head.Hash(), nil)
+	require.NoError(t, err)
+}
+
 func addFile(t *testing.T, basePath string, fn string, content string, commitMessage string) {
 	repo, err := git.PlainOpen(basePath)
 	require.NoError(t, err)
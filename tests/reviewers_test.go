@@ -0,0 +1,40 @@
+package tests
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/lindell/multi-gitter/internal/multigitter"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMissingReviewers(t *testing.T) {
+	vcMock := &vcmock.VersionController{
+		Users: []string{"existing-user", "org/existing-team"},
+	}
+	defer vcMock.Clean()
+
+	vcMock.AddRepository(createRepo(t, "owner", "should-change", "i like apples"))
+
+	runner := &multigitter.Runner{
+		VersionController: vcMock,
+		ScriptPath:        changerBinaryPath,
+		FeatureBranch:     "custom-branch-name",
+		Output:            io.Discard,
+		CommitMessage:     "custom message",
+		PullRequestTitle:  "custom message",
+		Reviewers:         []string{"existing-user", "missing-user"},
+		TeamReviewers:     []string{"org/existing-team", "org/missing-team"},
+		Assignees:         []string{"missing-assignee"},
+		Concurrent:        1,
+	}
+
+	err := runner.Run(context.Background())
+	assert.EqualError(t, err, `could not find:
+  reviewer "missing-user"
+  assignee "missing-assignee"
+  team reviewer "org/missing-team"`)
+	assert.Len(t, vcMock.PullRequests, 0)
+}
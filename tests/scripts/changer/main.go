@@ -11,6 +11,7 @@ const fileName = "test.txt"
 
 func main() {
 	duration := flag.String("sleep", "", "Time to sleep before running the script")
+	pullRequest := flag.String("pull-request", "", "Pull request metadata to write to the file in PULL_REQUEST_FILE")
 	flag.Parse()
 
 	if *duration != "" {
@@ -23,6 +24,13 @@ func main() {
 		panic(err)
 	}
 
+	if *pullRequest != "" {
+		err = os.WriteFile(os.Getenv("PULL_REQUEST_FILE"), []byte(*pullRequest), 0600)
+		if err != nil {
+			panic(err)
+		}
+	}
+
 	replaced := bytes.ReplaceAll(data, []byte("apple"), []byte("banana"))
 
 	err = os.WriteFile(fileName, replaced, 0600)
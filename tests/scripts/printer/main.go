@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 	"strings"
@@ -9,6 +10,16 @@ import (
 const fileName = "test.txt"
 
 func main() {
+	envNames := flag.String("env", "", "Comma separated environment variables that should be printed instead of the file content")
+	flag.Parse()
+
+	if *envNames != "" {
+		for _, name := range strings.Split(*envNames, ",") {
+			fmt.Printf("%s=%s\n", name, os.Getenv(name))
+		}
+		return
+	}
+
 	data, err := os.ReadFile(fileName)
 	if err != nil {
 		panic(err)
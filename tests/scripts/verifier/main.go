@@ -0,0 +1,19 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	fail := flag.String("fail", "", "The repository the verification should fail for")
+	exitCode := flag.Int("exit-code", 1, "The exit code used when the verification fails")
+	flag.Parse()
+
+	repo := os.Getenv("REPOSITORY")
+	if repo == *fail {
+		fmt.Printf("%s is broken\n", repo)
+		os.Exit(*exitCode)
+	}
+}
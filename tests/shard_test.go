@@ -0,0 +1,56 @@
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestShard tests that every repository is changed by exactly one of the shards of a run
+func TestShard(t *testing.T) {
+	vcMock := &vcmock.VersionController{}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir, err := os.MkdirTemp(os.TempDir(), "multi-git-test-shard-")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	for i := 0; i < 6; i++ {
+		vcMock.AddRepository(createRepo(t, "owner", fmt.Sprintf("should-change-%d", i), "i like apples"))
+	}
+
+	prCounts := []int{}
+	for _, shard := range []string{"1/2", "2/2"} {
+		command := cmd.RootCmd()
+		command.SetArgs([]string{
+			"run",
+			"--log-file", filepath.Join(tmpDir, "log.txt"),
+			"--output", filepath.Join(tmpDir, "out.txt"),
+			"--author-name", "Test Author",
+			"--author-email", "test@example.com",
+			"-B", "custom-branch-name",
+			"-m", "custom message",
+			"--shard", shard,
+			changerBinaryPath,
+		})
+		require.NoError(t, command.Execute())
+		prCounts = append(prCounts, len(vcMock.PullRequests))
+	}
+
+	assert.Len(t, vcMock.PullRequests, 6)
+	assert.Greater(t, prCounts[0], 0)
+	assert.Less(t, prCounts[0], 6)
+
+	changed := map[string]bool{}
+	for _, pr := range vcMock.PullRequests {
+		assert.False(t, changed[pr.Repository.FullName()], "%s was changed by both shards", pr.Repository.FullName())
+		changed[pr.Repository.FullName()] = true
+	}
+}
@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/lindell/multi-gitter/internal/git/gogit"
+	"github.com/lindell/multi-gitter/internal/multigitter"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestScriptSkip tests that a script can skip a repository with an exit code, without it being a failure
+func TestScriptSkip(t *testing.T) {
+	tests := []struct {
+		name        string
+		exitCode    string
+		expectedErr string
+		expectedOut string
+	}{
+		{
+			name:        "skipped",
+			exitCode:    "3",
+			expectedOut: "Skipped by the script:\n  owner/not-applicable\n",
+		},
+		{
+			name:        "failed",
+			exitCode:    "4",
+			expectedErr: "the run failed on 1 of 2 repositories",
+			expectedOut: "Exit status 4:\n  owner/not-applicable\n",
+		},
+	}
+
+	scriptPath, err := filepath.Abs(verifierBinaryPath)
+	require.NoError(t, err)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			vcMock := &vcmock.VersionController{}
+			defer vcMock.Clean()
+
+			vcMock.AddRepository(createRepo(t, "owner", "not-applicable", "i like apples"))
+			vcMock.AddRepository(createRepo(t, "owner", "no-change", "i like apples"))
+
+			out := &bytes.Buffer{}
+			runner := &multigitter.Runner{
+				VersionController: vcMock,
+				ScriptPath:        scriptPath,
+				Arguments:         []string{"-fail", "owner/not-applicable", "-exit-code", test.exitCode},
+				FeatureBranch:     "skip-branch",
+				Output:            out,
+				CommitMessage:     "skip message",
+				PullRequestTitle:  "skip message",
+				Concurrent:        1,
+				CreateGit: func(dir string) multigitter.Git {
+					return &gogit.Git{Directory: dir}
+				},
+				SkipExitCode: 3,
+				FailOnError:  true,
+			}
+
+			err := runner.Run(context.Background())
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.expectedErr)
+			}
+			assert.Contains(t, out.String(), test.expectedOut)
+			assert.Contains(t, out.String(), "No data was changed:\n  owner/no-change\n")
+			assert.Len(t, vcMock.PullRequests, 0)
+		})
+	}
+}
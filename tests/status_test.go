@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStatusJSON tests that the statuses of pull requests can be written as json, with the details of each pull request
+func TestStatusJSON(t *testing.T) {
+	vcMock := &vcmock.VersionController{}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir, err := os.MkdirTemp(os.TempDir(), "multi-git-test-status-")
+	defer os.RemoveAll(tmpDir)
+	require.NoError(t, err)
+
+	repo := createRepo(t, "owner", "has-url", "i like apples")
+	vcMock.AddRepository(repo)
+	vcMock.PullRequests = append(vcMock.PullRequests, vcmock.PullRequest{
+		PRStatus:     scm.PullRequestStatusPending,
+		PRNumber:     1,
+		Repository:   repo,
+		ApprovedBy:   []string{"alice"},
+		Checks:       "pending",
+		Unsuccessful: []string{"test: in_progress"},
+		NewPullRequest: scm.NewPullRequest{
+			Head:      "custom-branch-name",
+			Reviewers: []string{"alice", "bob"},
+		},
+	})
+
+	outFile := filepath.Join(tmpDir, "out.json")
+	command := cmd.RootCmd()
+	command.SetArgs([]string{
+		"status",
+		"--output", outFile,
+		"--output-format", "json",
+		"-B", "custom-branch-name",
+	})
+	require.NoError(t, command.Execute())
+
+	out, err := os.ReadFile(outFile)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{
+			"platform": "github",
+			"repository": "owner/has-url",
+			"name": "owner/has-url #1",
+			"id": "owner/has-url/1",
+			"number": 1,
+			"url": "https://github.com/owner/has-url/pull/1",
+			"state": "pending",
+			"checks": "pending",
+			"unsuccessfulChecks": ["test: in_progress"],
+			"reviews": [
+				{"reviewer": "alice", "state": "approved"},
+				{"reviewer": "bob", "state": "requested"}
+			]
+		}
+	]`, string(out))
+}
+
+// TestStatusFormats tests the formats that the statuses of pull requests can be written in
+func TestStatusFormats(t *testing.T) {
+	vcMock := &vcmock.VersionController{}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir, err := os.MkdirTemp(os.TempDir(), "multi-git-test-status-")
+	defer os.RemoveAll(tmpDir)
+	require.NoError(t, err)
+
+	for i, repoName := range []string{"has-url", "no-url"} {
+		repo := createRepo(t, "owner", repoName, "i like apples")
+		vcMock.AddRepository(repo)
+		vcMock.PullRequests = append(vcMock.PullRequests, vcmock.PullRequest{
+			PRStatus:   scm.PullRequestStatusSuccess,
+			PRNumber:   i + 1,
+			Repository: repo,
+			NewPullRequest: scm.NewPullRequest{
+				Head:      "custom-branch-name",
+				Reviewers: []string{"bob"},
+			},
+		})
+	}
+
+	templateFile := filepath.Join(tmpDir, "template.txt")
+	require.NoError(t, os.WriteFile(templateFile, []byte(`{{range .}}{{.Repository}} is {{.State}}
+{{end}}`), 0o600))
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{
+			format: "csv",
+			expected: `platform,repository,name,team,id,number,url,state,checks,reviews,createdAt,updatedAt
+github,owner/has-url,owner/has-url #1,,owner/has-url/1,1,https://github.com/owner/has-url/pull/1,success,,bob:requested,,
+github,owner/no-url,owner/no-url #2,,owner/no-url/2,2,,success,,bob:requested,,
+`,
+		},
+		{
+			format: "markdown",
+			expected: `| Pull request | Team | State | Checks | Reviews |
+| --- | --- | --- | --- | --- |
+| [owner/has-url #1](https://github.com/owner/has-url/pull/1) |  | success |  | bob:requested |
+| owner/no-url #2 |  | success |  | bob:requested |
+`,
+		},
+		{
+			format: "gotemplate=" + templateFile,
+			expected: `owner/has-url is success
+owner/no-url is success
+`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			outFile := filepath.Join(tmpDir, "out.txt")
+			command := cmd.RootCmd()
+			command.SetArgs([]string{
+				"status",
+				"--output", outFile,
+				"--output-format", test.format,
+				"-B", "custom-branch-name",
+			})
+			require.NoError(t, command.Execute())
+
+			out, err := os.ReadFile(outFile)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, string(out))
+		})
+	}
+}
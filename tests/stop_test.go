@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lindell/multi-gitter/internal/git/gogit"
+	"github.com/lindell/multi-gitter/internal/multigitter"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStopAfterFailures tests that no more repositories are started once the run has failed too many times,
+// and that the remaining repositories are saved so that the run can be continued
+func TestStopAfterFailures(t *testing.T) {
+	vcMock := &vcmock.VersionController{}
+	defer vcMock.Clean()
+
+	vcMock.AddRepository(createRepo(t, "owner", "should-fail", "i like apples"))
+	vcMock.AddRepository(createRepo(t, "owner", "never-run-1", "i like apples"))
+	vcMock.AddRepository(createRepo(t, "owner", "never-run-2", "i like apples"))
+
+	tmpDir, err := os.MkdirTemp(os.TempDir(), "multi-git-test-stop-")
+	defer os.RemoveAll(tmpDir)
+	require.NoError(t, err)
+
+	scriptPath, err := filepath.Abs(verifierBinaryPath)
+	require.NoError(t, err)
+
+	checkpointPath := filepath.Join(tmpDir, "checkpoint.json")
+	runner := &multigitter.Runner{
+		VersionController: vcMock,
+		ScriptPath:        scriptPath,
+		Arguments:         []string{"-fail", "owner/should-fail"},
+		FeatureBranch:     "stop-branch",
+		Output:            io.Discard,
+		CommitMessage:     "stop message",
+		PullRequestTitle:  "stop message",
+		Concurrent:        1,
+		StopAfterFailures: 1,
+		CheckpointPath:    checkpointPath,
+		CreateGit: func(dir string) multigitter.Git {
+			return &gogit.Git{Directory: dir}
+		},
+	}
+
+	err = runner.Run(context.Background())
+	assert.EqualError(t, err, "the run was stopped early since it failed on 1 repositories")
+
+	plan, err := multigitter.ReadPlan(checkpointPath, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "stop-branch", plan.FeatureBranch)
+	require.Len(t, plan.Repositories, 2)
+	assert.Equal(t, "owner/never-run-1", plan.Repositories[0].Name)
+	assert.Equal(t, "owner/never-run-2", plan.Repositories[1].Name)
+	assert.Equal(t, "master", plan.Repositories[0].BaseBranch)
+}
@@ -1090,6 +1090,32 @@ Repositories with a successful run:
 			},
 		},
 
+		{
+			name: "size labels",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "should-change", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--labels", "label1",
+				"--size-labels",
+				"--size-label-thresholds", "1,2,3,4,5",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Equal(t, []string{"label1", "size/M"}, vcMock.PullRequests[0].Labels)
+			},
+		},
+
 		{
 			name: "remove files",
 			vcCreate: func(t *testing.T) *vcmock.VersionController {
@@ -1256,6 +1282,74 @@ Repositories with a successful run:
 			},
 		},
 
+		{
+			name: "keep failed clones",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "should-fail", "i like apples"),
+						createRepo(t, "owner", "no-change", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "keep-failed-branch-name",
+				"-m", "keep failed message",
+				"--clone-dir", filepath.Join(os.TempDir(), "keep-failed-test"),
+				"--keep-failed-clones",
+				fmt.Sprintf("go run %s -fail owner/should-fail", normalizePath(filepath.Join(workingDir, "scripts/verifier/main.go"))),
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				cloneDir := filepath.Join(os.TempDir(), "keep-failed-test")
+				defer os.RemoveAll(cloneDir)
+
+				assert.Len(t, vcMock.PullRequests, 0)
+				roots, err := filepath.Glob(filepath.Join(cloneDir, "multi-gitter-*"))
+				require.NoError(t, err)
+				require.Len(t, roots, 1)
+				assert.FileExists(t, filepath.Join(roots[0], "keep-failed-branch-name", "owner", "should-fail", "test.txt"))
+				assert.NoDirExists(t, filepath.Join(roots[0], "keep-failed-branch-name", "owner", "no-change"))
+				assert.Contains(t, runData.logOut, "The clone was kept at")
+
+				keptDir := filepath.Join(roots[0], "keep-failed-branch-name", "owner", "should-fail")
+				assert.Contains(t, readFile(t, filepath.Dir(keptDir), "should-fail.log"), "owner/should-fail is broken")
+				assert.Contains(t, runData.out, "Kept clones of the failed repositories:\n  owner/should-fail: "+keptDir)
+			},
+		},
+
+		{
+			name: "script output limit",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "should-fail", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--script-output-limit", "10",
+				"--script-output-dir", filepath.Join(os.TempDir(), "script-output-test"),
+				fmt.Sprintf("go run %s -fail owner/should-fail", normalizePath(filepath.Join(workingDir, "scripts/verifier/main.go"))),
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				outputDir := filepath.Join(os.TempDir(), "script-output-test")
+				defer os.RemoveAll(outputDir)
+
+				assert.Contains(t, runData.logOut, "Script output: owner")
+				assert.Contains(t, runData.logOut, "bytes left out")
+				assert.NotContains(t, runData.logOut, "should-fail is broken")
+				assert.Contains(t, readFile(t, filepath.Join(outputDir, "owner"), "should-fail.log"), "owner/should-fail is broken")
+			},
+		},
+
 		{
 			name: "fork conflicts with pushOnly",
 			vcCreate: func(t *testing.T) *vcmock.VersionController {
@@ -1325,6 +1419,2090 @@ Repositories with a successful run:
 				assert.Equal(t, runData.out, "Repositories with a successful run:\n  owner/example-repository #0\n")
 			},
 		},
+
+		{
+			name: "close obsolete pull request",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				repo1 := createRepo(t, "owner", "obsolete-pr", "i like oranges")
+				repo2 := createRepo(t, "owner", "no-pr", "i like oranges")
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						repo1,
+						repo2,
+					},
+					PullRequests: []vcmock.PullRequest{
+						{
+							PRStatus:   scm.PullRequestStatusSuccess,
+							PRNumber:   42,
+							Repository: repo1,
+							NewPullRequest: scm.NewPullRequest{
+								Title: "original title",
+								Head:  "custom-branch-name",
+							},
+						},
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--close-obsolete",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Equal(t, scm.PullRequestStatusClosed, vcMock.PullRequests[0].PRStatus)
+				assert.Contains(t, runData.logOut, "Closing obsolete pull request")
+				assert.Contains(t, runData.out, `No data was changed and the obsolete pull request was closed:
+  owner/obsolete-pr #42
+`)
+				assert.Contains(t, runData.out, `No data was changed:
+  owner/no-pr
+`)
+			},
+		},
+
+		{
+			name: "close obsolete pull request with dry run",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				repo := createRepo(t, "owner", "obsolete-pr", "i like oranges")
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{repo},
+					PullRequests: []vcmock.PullRequest{
+						{
+							PRStatus:   scm.PullRequestStatusSuccess,
+							PRNumber:   42,
+							Repository: repo,
+							NewPullRequest: scm.NewPullRequest{
+								Title: "original title",
+								Head:  "custom-branch-name",
+							},
+						},
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--close-obsolete",
+				"--dry-run",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Equal(t, scm.PullRequestStatusSuccess, vcMock.PullRequests[0].PRStatus)
+				assert.Contains(t, runData.logOut, "Skipping closing obsolete pull request because of dry run")
+				assert.Contains(t, runData.out, `No data was changed and the obsolete pull request would be closed, but was not because of dry run:
+  owner/obsolete-pr #42
+`)
+				assert.NotContains(t, runData.out, "obsolete pull request was closed")
+			},
+		},
+
+		{
+			name: "pull request overrides",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "overridden", "i like apples"),
+						createRepo(t, "owner", "body-overridden", "i like apples"),
+						createRepo(t, "owner", "not-overridden", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--labels", "default-label",
+				"--pr-overrides-dir", "testdata/pr-overrides",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 3)
+
+				prs := map[string]vcmock.PullRequest{}
+				for _, pr := range vcMock.PullRequests {
+					prs[pr.RepoName] = pr
+				}
+
+				assert.Equal(t, "special title", prs["overridden"].Title)
+				assert.Equal(t, "This repository needs some special attention.", prs["overridden"].Body)
+				assert.Equal(t, []string{"special-label"}, prs["overridden"].Labels)
+
+				assert.Equal(t, "custom message", prs["body-overridden"].Title)
+				assert.Equal(t, "Only the body is special here.", prs["body-overridden"].Body)
+				assert.Equal(t, []string{"default-label"}, prs["body-overridden"].Labels)
+
+				assert.Equal(t, "custom message", prs["not-overridden"].Title)
+				assert.Equal(t, "", prs["not-overridden"].Body)
+			},
+		},
+
+		{
+			name: "skip conflicting pull requests",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				repo1 := createRepo(t, "owner", "conflicting", "i like apples")
+				repo2 := createRepo(t, "owner", "not-conflicting", "i like apples")
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						repo1,
+						repo2,
+					},
+					PullRequests: []vcmock.PullRequest{
+						{
+							PRStatus:   scm.PullRequestStatusSuccess,
+							PRNumber:   42,
+							Repository: repo1,
+							Files:      []string{fileName},
+							NewPullRequest: scm.NewPullRequest{
+								Title: "human change",
+								Head:  "human-branch",
+							},
+						},
+						{
+							PRStatus:   scm.PullRequestStatusSuccess,
+							PRNumber:   43,
+							Repository: repo2,
+							Files:      []string{"other-file.txt"},
+							NewPullRequest: scm.NewPullRequest{
+								Title: "human change",
+								Head:  "human-branch",
+							},
+						},
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--skip-conflicting-prs",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 3)
+				assert.Equal(t, "not-conflicting", vcMock.PullRequests[2].RepoName)
+				assert.Equal(t, `Skipped since another open pull request changes the same files:
+  owner/conflicting #42
+Repositories with a successful run:
+  owner/not-conflicting #1
+`, runData.out)
+			},
+		},
+
+		{
+			name: "fallback clone url",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				repo := createRepo(t, "owner", "should-change", "i like apples")
+				repo.BrokenCloneURL = true
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						repo,
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Contains(t, runData.logOut, "Could not clone using file, falling back to file")
+				assert.Contains(t, runData.logOut, "Cloned using file")
+
+				changeBranch(t, vcMock.Repositories[0].Path, "custom-branch-name", false)
+				assert.Equal(t, "i like bananas", readTestFile(t, vcMock.Repositories[0].Path))
+			},
+		},
+
+		{
+			name: "pr diff summary",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "should-change", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message\nbody text",
+				"--pr-diff-summary",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				body := vcMock.PullRequests[0].Body
+				assert.True(t, strings.HasPrefix(body, "body text\n\n<details>"))
+				assert.Contains(t, body, "1 files changed, 1 insertions(+), 1 deletions(-)")
+				assert.Contains(t, body, "**./**\n- `test.txt` (+1 -1)")
+				assert.Contains(t, body, "-i like apples")
+				assert.Contains(t, body, "+i like bananas")
+			},
+		},
+
+		{
+			name: "tracking issue",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "should-change", "i like apples"),
+						createRepo(t, "owner", "has-url", "i like apples"),
+						createRepo(t, "owner", "should-not-change", "i like oranges"),
+					},
+					Issues: []vcmock.Issue{
+						{RepoName: "owner/tracking", Title: "multi-gitter: other-branch", Body: "other"},
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--tracking-issue", "owner/tracking",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 2)
+				require.Len(t, vcMock.Issues, 2)
+				assert.Equal(t, "other", vcMock.Issues[0].Body)
+
+				issue := vcMock.Issues[1]
+				assert.Equal(t, "owner/tracking", issue.RepoName)
+				assert.Equal(t, "multi-gitter: custom-branch-name", issue.Title)
+				assert.Contains(t, issue.Body, "**2 pull requests**: 2 Pending")
+				assert.Contains(t, issue.Body, "| [owner/has-url #2](https://github.com/owner/has-url/pull/1) | Pending |")
+				assert.Contains(t, issue.Body, "| owner/should-change #1 | Pending |")
+				assert.NotContains(t, issue.Body, "should-not-change")
+				assert.Contains(t, runData.logOut, "Updated tracking issue https://example.com/owner/tracking/issues/2")
+			},
+		},
+
+		{
+			name: "issue fallback",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				repo := createRepo(t, "owner", "no-prs", "i like apples")
+				repo.PullRequestsDisabled = true
+				archived := createRepo(t, "owner", "archived", "i like apples")
+				archived.Archived = true
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						repo,
+						archived,
+						createRepo(t, "owner", "should-change", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message\nbody text",
+				"--issue-fallback",
+				"--fail-on-error",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Equal(t, "owner/should-change", vcMock.PullRequests[0].Repository.FullName())
+
+				require.Len(t, vcMock.Issues, 1)
+				issue := vcMock.Issues[0]
+				assert.Equal(t, "owner/no-prs", issue.RepoName)
+				assert.Equal(t, "custom message", issue.Title)
+				assert.True(t, strings.HasPrefix(issue.Body, "body text\n\nA pull request with these changes could not be created (pull requests are disabled)."))
+				assert.Contains(t, issue.Body, "```diff\ndiff --git a/test.txt b/test.txt")
+				assert.Contains(t, issue.Body, "\n-i like apples\n")
+				assert.Contains(t, issue.Body, "\n+i like bananas\n")
+
+				assert.Contains(t, runData.out, "Could not create a pull request, an issue with the changes was created instead:\n  owner/no-prs\n")
+				assert.Contains(t, runData.out, "The repository became unavailable during the run, like by being archived:\n  owner/archived\n")
+				assert.Contains(t, runData.out, "Repositories with a successful run:\n  owner/should-change #1\n")
+			},
+		},
+
+		{
+			name: "report required checks",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				repo := createRepo(t, "owner", "with-checks", "i like apples")
+				repo.RequiredStatusChecks = []string{"build", "lint"}
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						repo,
+						createRepo(t, "owner", "without-checks", "i like apples"),
+						createRepo(t, "owner", "should-not-change", "i like oranges"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--dry-run",
+				"--report-required-checks",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 0)
+				assert.Equal(t, `No data was changed:
+  owner/should-not-change
+Repositories with a successful run:
+  owner/with-checks #0
+  owner/without-checks #0
+Required status checks on the base branch:
+  owner/with-checks: build, lint
+  owner/without-checks: none
+`, runData.out)
+			},
+		},
+
+		{
+			name: "rerequest review",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				repo := createRepo(t, "owner", "existing-pr", "i like apples")
+				changeBranch(t, repo.Path, "custom-branch-name", true)
+				changeTestFile(t, repo.Path, "i like apple", "test change")
+				changeBranch(t, repo.Path, "master", false)
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{repo},
+					PullRequests: []vcmock.PullRequest{
+						{
+							PRStatus:   scm.PullRequestStatusSuccess,
+							PRNumber:   42,
+							Repository: repo,
+							NewPullRequest: scm.NewPullRequest{
+								Title: "original title",
+								Head:  "custom-branch-name",
+							},
+							ApprovedBy: []string{"approver1", "approver2"},
+						},
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--conflict-strategy", "replace",
+				"--rerequest-review",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Equal(t, []string{"approver1", "approver2"}, vcMock.PullRequests[0].Reviewers)
+				assert.Empty(t, vcMock.PullRequests[0].ApprovedBy)
+				assert.Contains(t, runData.logOut, "Re-requested review from approver1, approver2")
+			},
+		},
+
+		{
+			name: "reset approvals",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				repo := createRepo(t, "owner", "existing-pr", "i like apples")
+				changeBranch(t, repo.Path, "custom-branch-name", true)
+				changeTestFile(t, repo.Path, "i like apple", "test change")
+				changeBranch(t, repo.Path, "master", false)
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{repo},
+					PullRequests: []vcmock.PullRequest{
+						{
+							PRStatus:   scm.PullRequestStatusSuccess,
+							PRNumber:   42,
+							Repository: repo,
+							NewPullRequest: scm.NewPullRequest{
+								Title: "original title",
+								Head:  "custom-branch-name",
+							},
+							ApprovedBy: []string{"approver1"},
+						},
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--conflict-strategy", "replace",
+				"--reset-approvals",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Empty(t, vcMock.PullRequests[0].ApprovedBy)
+				assert.Empty(t, vcMock.PullRequests[0].Reviewers)
+				assert.Contains(t, runData.logOut, "Reset the approvals of approver1")
+			},
+		},
+
+		{
+			name: "adopt pull request",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				repo := createRepo(t, "owner", "external-pr", "i like apples")
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{repo},
+					PullRequests: []vcmock.PullRequest{
+						{
+							PRStatus:   scm.PullRequestStatusPending,
+							PRNumber:   42,
+							Repository: repo,
+							NewPullRequest: scm.NewPullRequest{
+								Title: "hand made title",
+								Head:  "custom-branch-name",
+							},
+						},
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--pr-title", "new title",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Equal(t, "new title", vcMock.PullRequests[0].Title)
+				assert.Contains(t, runData.logOut, "Adopting the open pull request owner/external-pr #42")
+			},
+		},
+
+		{
+			name: "keep pull request from existing branch",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				repo := createRepo(t, "owner", "external-pr", "i like apples")
+				changeBranch(t, repo.Path, "custom-branch-name", true)
+				changeTestFile(t, repo.Path, "i like pears", "hand made change")
+				changeBranch(t, repo.Path, "master", false)
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{repo},
+					PullRequests: []vcmock.PullRequest{
+						{
+							PRStatus:   scm.PullRequestStatusPending,
+							PRNumber:   42,
+							Repository: repo,
+							NewPullRequest: scm.NewPullRequest{
+								Title: "hand made title",
+								Head:  "custom-branch-name",
+							},
+						},
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--pr-title", "new title",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Equal(t, "hand made title", vcMock.PullRequests[0].Title)
+				changeBranch(t, vcMock.Repositories[0].Path, "custom-branch-name", false)
+				assert.Equal(t, "i like pears", readTestFile(t, vcMock.Repositories[0].Path))
+				assert.NotContains(t, runData.logOut, "Adopting the open pull request")
+				assert.Contains(t, runData.logOut, "Skip creating pull requests since one is already open")
+			},
+		},
+
+		{
+			name: "pull request from existing branch without pushed changes",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				repo := createRepo(t, "owner", "existing-branch", "i like apples")
+				changeBranch(t, repo.Path, "custom-branch-name", true)
+				changeTestFile(t, repo.Path, "i like pears", "hand made change")
+				changeBranch(t, repo.Path, "master", false)
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{repo},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--check-run", "multi-gitter",
+				"--pr-status", "multi-gitter",
+				"--pr-summary-comment",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Empty(t, vcMock.PullRequests[0].CheckRuns)
+				assert.Empty(t, vcMock.PullRequests[0].Statuses)
+				assert.Empty(t, vcMock.PullRequests[0].Comments)
+				changeBranch(t, vcMock.Repositories[0].Path, "custom-branch-name", false)
+				assert.Equal(t, "i like pears", readTestFile(t, vcMock.Repositories[0].Path))
+			},
+		},
+
+		{
+			name: "skip adoption",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				repo := createRepo(t, "owner", "external-pr", "i like apples")
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{repo},
+					PullRequests: []vcmock.PullRequest{
+						{
+							PRStatus:   scm.PullRequestStatusPending,
+							PRNumber:   42,
+							Repository: repo,
+							NewPullRequest: scm.NewPullRequest{
+								Title: "hand made title",
+								Head:  "custom-branch-name",
+							},
+						},
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--pr-title", "new title",
+				"--skip-adoption",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 2)
+				assert.Equal(t, "hand made title", vcMock.PullRequests[0].Title)
+				assert.NotContains(t, runData.logOut, "Adopting")
+			},
+		},
+
+		{
+			name: "reopen closed pull request",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				repo := createRepo(t, "owner", "closed-pr", "i like apples")
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{repo},
+					PullRequests: []vcmock.PullRequest{
+						{
+							PRStatus:   scm.PullRequestStatusClosed,
+							PRNumber:   42,
+							Repository: repo,
+							NewPullRequest: scm.NewPullRequest{
+								Title: "original title",
+								Head:  "custom-branch-name",
+							},
+						},
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--pr-title", "new title",
+				"--reopen-closed",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Equal(t, scm.PullRequestStatusPending, vcMock.PullRequests[0].PRStatus)
+				assert.Equal(t, "new title", vcMock.PullRequests[0].Title)
+				assert.Contains(t, runData.logOut, "Reopened the closed pull request owner/closed-pr #42")
+			},
+		},
+
+		{
+			name: "reopen closed pull request that can not be reopened",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				repo := createRepo(t, "owner", "closed-pr", "i like apples")
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{repo},
+					PullRequests: []vcmock.PullRequest{
+						{
+							PRStatus:     scm.PullRequestStatusClosed,
+							PRNumber:     42,
+							Unreopenable: true,
+							Repository:   repo,
+							NewPullRequest: scm.NewPullRequest{
+								Title: "original title",
+								Head:  "custom-branch-name",
+							},
+						},
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--pr-title", "new title",
+				"--reopen-closed",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 2)
+				assert.Equal(t, scm.PullRequestStatusClosed, vcMock.PullRequests[0].PRStatus)
+				assert.Equal(t, "new title", vcMock.PullRequests[1].Title)
+				assert.Contains(t, runData.logOut, "Could not reopen owner/closed-pr #42, creating a new pull request instead")
+			},
+		},
+
+		{
+			name: "retarget pull request from removed base branch",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				repo := createRepo(t, "owner", "existing-pr", "i like apples")
+				changeBranch(t, repo.Path, "custom-branch-name", true)
+				changeTestFile(t, repo.Path, "i like apple", "test change")
+				changeBranch(t, repo.Path, "master", false)
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{repo},
+					PullRequests: []vcmock.PullRequest{
+						{
+							PRStatus:   scm.PullRequestStatusSuccess,
+							PRNumber:   42,
+							Repository: repo,
+							NewPullRequest: scm.NewPullRequest{
+								Title: "original title",
+								Head:  "custom-branch-name",
+								Base:  "removed-branch",
+							},
+						},
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Equal(t, "master", vcMock.PullRequests[0].Base)
+				assert.Equal(t, "original title", vcMock.PullRequests[0].Title)
+				assert.Contains(t, runData.logOut, "Retargeted the pull request from the removed branch removed-branch to master")
+			},
+		},
+
+		{
+			name: "retarget unchanged pull request from removed base branch",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				repo := createRepo(t, "owner", "existing-pr", "i like oranges")
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{repo},
+					PullRequests: []vcmock.PullRequest{
+						{
+							PRStatus:   scm.PullRequestStatusSuccess,
+							PRNumber:   42,
+							Repository: repo,
+							NewPullRequest: scm.NewPullRequest{
+								Title: "original title",
+								Head:  "custom-branch-name",
+								Base:  "removed-branch",
+							},
+						},
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Equal(t, "master", vcMock.PullRequests[0].Base)
+				assert.Contains(t, runData.logOut, "Retargeted the pull request from the removed branch removed-branch to master")
+				assert.Equal(t, "No data was changed:\n  owner/existing-pr\n", runData.out)
+			},
+		},
+
+		{
+			name: "skip retarget",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				repo := createRepo(t, "owner", "existing-pr", "i like oranges")
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{repo},
+					PullRequests: []vcmock.PullRequest{
+						{
+							PRStatus:   scm.PullRequestStatusSuccess,
+							PRNumber:   42,
+							Repository: repo,
+							NewPullRequest: scm.NewPullRequest{
+								Title: "original title",
+								Head:  "custom-branch-name",
+								Base:  "removed-branch",
+							},
+						},
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--skip-retarget",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Equal(t, "removed-branch", vcMock.PullRequests[0].Base)
+				assert.NotContains(t, runData.logOut, "Retargeted")
+			},
+		},
+
+		{
+			name: "keep reviewers",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				repo := createRepo(t, "owner", "existing-pr", "i like apples")
+				changeBranch(t, repo.Path, "custom-branch-name", true)
+				changeTestFile(t, repo.Path, "i like apple", "test change")
+				changeBranch(t, repo.Path, "master", false)
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{repo},
+					PullRequests: []vcmock.PullRequest{
+						{
+							PRStatus:   scm.PullRequestStatusSuccess,
+							PRNumber:   42,
+							Repository: repo,
+							NewPullRequest: scm.NewPullRequest{
+								Title:     "original title",
+								Head:      "custom-branch-name",
+								Reviewers: []string{"reviewer1", "manually-added"},
+							},
+						},
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--conflict-strategy", "replace",
+				"--reviewers", "reviewer1,reviewer2",
+				"--keep-reviewers",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Equal(t, []string{"reviewer1", "manually-added", "reviewer2"}, vcMock.PullRequests[0].Reviewers)
+			},
+		},
+
+		{
+			name:        "git ssh command and proxy",
+			gitBackends: []gitBackend{gitBackendCmd},
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "should-change", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--git-ssh-command", "ssh -o BatchMode=yes",
+				"--git-proxy", "http://127.0.0.1:3128",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Equal(t, "custom-branch-name", vcMock.PullRequests[0].Head)
+			},
+		},
+
+		{
+			name:        "git proxy",
+			gitBackends: []gitBackend{gitBackendGo},
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "should-change", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--git-proxy", "http://127.0.0.1:3128",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+			},
+		},
+
+		{
+			name:        "git ssh command with go git",
+			gitBackends: []gitBackend{gitBackendGo},
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "should-change", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--git-ssh-command", "ssh -J jump-host",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 0)
+				assert.Contains(t, runData.cmdOut, "git-ssh-command can only be used with the cmd git type")
+			},
+			expectErr: true,
+		},
+
+		{
+			name: "read only",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "should-change", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--read-only",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 0)
+				assert.Contains(t, runData.logOut, "the push to origin was blocked since multi-gitter is in read-only mode")
+				assert.False(t, branchExist(t, vcMock.Repositories[0].Path, "custom-branch-name"))
+			},
+		},
+
+		{
+			name: "teams",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				codeOwnersRepo := createRepo(t, "owner", "api", "i like apples")
+				addFile(t, codeOwnersRepo.Path, "CODEOWNERS", "* @org/platform\ndocs/ @org/docs\n", "add codeowners")
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "web-app", "i like apples"),
+						createRepo(t, "owner", "web-legacy", "i like apples"),
+						codeOwnersRepo,
+						createRepo(t, "owner", "no-owner", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--team-mapping", "testdata/team-mapping",
+				"--teams-from-codeowners",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 4)
+				assert.Contains(t, runData.out, `Repositories by team:
+  @org/frontend:
+    owner/web-app #1
+  @org/legacy:
+    owner/web-legacy #2
+  @org/platform:
+    owner/api #3
+  No team:
+    owner/no-owner #4
+`)
+			},
+		},
+
+		{
+			name: "base ref",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				repo := createRepo(t, "owner", "should-change", "i like apples")
+				addTag(t, repo.Path, "release-should-change")
+				changeTestFile(t, repo.Path, "i like apples and pears", "unreleased change")
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{repo},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--base-ref", "release-{{.Name}}",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Equal(t, "master", vcMock.PullRequests[0].Base)
+				assert.Contains(t, runData.logOut, "Making the changes on top of release-should-change")
+
+				changeBranch(t, vcMock.Repositories[0].Path, "custom-branch-name", false)
+				assert.Equal(t, "i like bananas", readTestFile(t, vcMock.Repositories[0].Path))
+			},
+		},
+
+		{
+			name: "check reviewer ownership",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				ownedRepo := createRepo(t, "owner", "owned", "i like apples")
+				addFile(t, ownedRepo.Path, "CODEOWNERS", "* @org/platform\n/test.txt @alice\n", "add codeowners")
+				unownedRepo := createRepo(t, "owner", "unowned", "i like apples")
+				addFile(t, unownedRepo.Path, "CODEOWNERS", "*.txt @bob @org/docs\n", "add codeowners")
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						ownedRepo,
+						unownedRepo,
+						createRepo(t, "owner", "no-codeowners", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--reviewers", "alice",
+				"--check-reviewer-ownership",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 3)
+				assert.Contains(t, runData.logOut, `msg="None of the reviewers own 1 of the changed files: test.txt (owned by @bob @org/docs)" repo=owner/unowned`)
+				assert.Equal(t, 1, strings.Count(runData.logOut, "None of the reviewers own"))
+			},
+		},
+
+		{
+			name: "check team reviewer ownership without organization",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				ownedRepo := createRepo(t, "org", "owned", "i like apples")
+				addFile(t, ownedRepo.Path, "CODEOWNERS", "* @org/platform\n", "add codeowners")
+				unownedRepo := createRepo(t, "other-org", "unowned", "i like apples")
+				addFile(t, unownedRepo.Path, "CODEOWNERS", "* @org/platform\n", "add codeowners")
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						ownedRepo,
+						unownedRepo,
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--team-reviewers", "platform",
+				"--check-reviewer-ownership",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 2)
+				assert.Contains(t, runData.logOut, `msg="None of the reviewers own 1 of the changed files: test.txt (owned by @org/platform)" repo=other-org/unowned`)
+				assert.Equal(t, 1, strings.Count(runData.logOut, "None of the reviewers own"))
+			},
+		},
+
+		{
+			name: "check reviewer ownership of files with spaces",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				repo := createRepo(t, "owner", "unowned", "i like apples")
+				addFile(t, repo.Path, "CODEOWNERS", "*.md @org/docs\n", "add codeowners")
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{repo},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--reviewers", "alice",
+				"--check-reviewer-ownership",
+				fmt.Sprintf(`go run %s -filenames "release notes.md" -data test`, normalizePath(filepath.Join(workingDir, "scripts/adder/main.go"))),
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Contains(t, runData.logOut, `None of the reviewers own 1 of the changed files: release notes.md (owned by @org/docs)`)
+			},
+		},
+
+		{
+			name: "split diff",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "should-change", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--split-diff-lines", "1",
+				fmt.Sprintf("go run %s -filenames api/main.go,web/index.js,README.md -data test", normalizePath(filepath.Join(workingDir, "scripts/adder/main.go"))),
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 3)
+				assert.Equal(t, "custom-branch-name-root", vcMock.PullRequests[0].Head)
+				assert.Equal(t, "custom message (1/3)", vcMock.PullRequests[0].Title)
+				assert.Equal(t, "custom-branch-name-api", vcMock.PullRequests[1].Head)
+				assert.Equal(t, "custom message (2/3)", vcMock.PullRequests[1].Title)
+				assert.Equal(t, "custom-branch-name-web", vcMock.PullRequests[2].Head)
+				assert.Equal(t, `The changes were split by top-level directory into 3 pull requests, on these branches:
+- custom-branch-name-root
+- custom-branch-name-api
+- custom-branch-name-web (this pull request)`, vcMock.PullRequests[2].Body)
+
+				changeBranch(t, vcMock.Repositories[0].Path, "custom-branch-name-api", false)
+				assert.True(t, fileExist(t, vcMock.Repositories[0].Path, "api/main.go"))
+				assert.False(t, fileExist(t, vcMock.Repositories[0].Path, "web/index.js"))
+				assert.False(t, fileExist(t, vcMock.Repositories[0].Path, "README.md"))
+			},
+		},
+
+		{
+			name: "split diff with non-ASCII file names",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "should-change", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--split-diff-lines", "1",
+				fmt.Sprintf("go run %s -filenames api/café.txt,web/index.js -data test", normalizePath(filepath.Join(workingDir, "scripts/adder/main.go"))),
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 2)
+				assert.Equal(t, "custom-branch-name-api", vcMock.PullRequests[0].Head)
+				assert.Equal(t, "custom-branch-name-web", vcMock.PullRequests[1].Head)
+
+				changeBranch(t, vcMock.Repositories[0].Path, "custom-branch-name-api", false)
+				assert.True(t, fileExist(t, vcMock.Repositories[0].Path, "api/café.txt"))
+				assert.False(t, fileExist(t, vcMock.Repositories[0].Path, "web/index.js"))
+			},
+		},
+
+		{
+			name: "paths",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "should-change", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--paths", "/deploy/",
+				fmt.Sprintf("go run %s -filenames deploy/app.yaml,web/index.js,README.md -data test", normalizePath(filepath.Join(workingDir, "scripts/adder/main.go"))),
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Equal(t, "deploy: custom message", vcMock.PullRequests[0].Title)
+				assert.Equal(t, "The changes are limited to `deploy`.", vcMock.PullRequests[0].Body)
+
+				changeBranch(t, vcMock.Repositories[0].Path, "custom-branch-name", false)
+				assert.True(t, fileExist(t, vcMock.Repositories[0].Path, "deploy/app.yaml"))
+				assert.False(t, fileExist(t, vcMock.Repositories[0].Path, "web/index.js"))
+				assert.False(t, fileExist(t, vcMock.Repositories[0].Path, "README.md"))
+				assert.Equal(t, "i like apples", readTestFile(t, vcMock.Repositories[0].Path))
+			},
+		},
+
+		{
+			name: "paths with renamed file",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				repo := createRepo(t, "owner", "should-change", "i like apples")
+				require.NoError(t, os.MkdirAll(filepath.Join(repo.Path, "deploy"), 0700))
+				addFile(t, repo.Path, "deploy/old.yaml", "data", "add deploy file")
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{repo},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--paths", "deploy",
+				"git mv deploy/old.yaml deploy/new.yaml",
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+
+				changeBranch(t, vcMock.Repositories[0].Path, "custom-branch-name", false)
+				assert.True(t, fileExist(t, vcMock.Repositories[0].Path, "deploy/new.yaml"))
+				assert.False(t, fileExist(t, vcMock.Repositories[0].Path, "deploy/old.yaml"))
+			},
+		},
+
+		{
+			name: "paths without changes within them",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "should-not-change", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--paths", "deploy",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 0)
+				assert.Contains(t, runData.out, "No data was changed:\n  owner/should-not-change\n")
+			},
+		},
+
+		{
+			name: "check run",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "should-change", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--campaign", "apples",
+				"--check-run", "multi-gitter",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				require.Len(t, vcMock.PullRequests[0].CheckRuns, 1)
+				checkRun := vcMock.PullRequests[0].CheckRuns[0]
+				assert.Equal(t, "multi-gitter", checkRun.Name)
+				assert.Equal(t, branchCommit(t, vcMock.Repositories[0].Path, "custom-branch-name"), checkRun.SHA)
+				assert.Contains(t, checkRun.Summary, "**Campaign:** apples\n**Branch:** custom-branch-name\n")
+				assert.Regexp(t, `\*\*Script hash:\*\* sha256:[0-9a-f]{64}\n`, checkRun.Summary)
+				assert.Contains(t, runData.logOut, `Created the check run \"multi-gitter\"`)
+			},
+		},
+
+		{
+			name: "commit status",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "should-change", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--pr-status", "multi-gitter/run",
+				"--pr-status-url", "https://example.com/campaigns/apples",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Equal(t, []scm.CommitStatus{{
+					Context:     "multi-gitter/run",
+					Description: "Changed by multi-gitter",
+					TargetURL:   "https://example.com/campaigns/apples",
+					SHA:         branchCommit(t, vcMock.Repositories[0].Path, "custom-branch-name"),
+				}}, vcMock.PullRequests[0].Statuses)
+				assert.Contains(t, runData.logOut, `Set the status \"multi-gitter/run\"`)
+			},
+		},
+
+		{
+			name: "summary comment",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "should-change", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--pr-summary-comment",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				require.Len(t, vcMock.PullRequests[0].Comments, 1)
+				comment := vcMock.PullRequests[0].Comments[0]
+				assert.Contains(t, comment, "The changes were made by multi-gitter (version ")
+				assert.Contains(t, comment, "- Script: `"+filepath.Base(changerBinaryPath)+"`\n")
+				assert.Contains(t, comment, "- 1 files changed, 1 insertions(+), 1 deletions(-)\n")
+			},
+		},
+
+		{
+			name: "repository archived during the run",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				archived := createRepo(t, "owner", "archived", "i like apples")
+				archived.Archived = true
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "should-change", "i like apples"),
+						archived,
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--fail-on-error",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Contains(t, runData.out, "The repository became unavailable during the run, like by being archived:\n  owner/archived\n")
+			},
+		},
+
+		{
+			name: "stop after failures when every repository was started",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "no-change", "i like apples"),
+						createRepo(t, "owner", "fail", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--stop-after-failures", "1",
+				fmt.Sprintf("go run %s -fail owner/fail", normalizePath(filepath.Join(workingDir, "scripts/verifier/main.go"))),
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				assert.Contains(t, runData.out, "No data was changed:\n  owner/no-change\n")
+			},
+		},
+
+		{
+			name: "report slowest",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "first", "i like apples"),
+						createRepo(t, "owner", "second", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--report-slowest", "1",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				assert.Regexp(t, `Slowest repositories:\n  owner/(first|second): \S+ \(cloning \S+, running script \S+, publishing \S+\)\n$`, runData.out)
+			},
+		},
+
+		{
+			name: "pull request metadata from the script",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "should-change", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--pr-body", "custom body",
+				"--labels", "automated",
+				"--reviewers", "alice",
+				changerBinaryPath + ` -pull-request '{"labels": ["go", "automated"], "reviewers": ["bob"], "titleSuffix": "(go)", "body": "Go was detected."}'`,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Equal(t, "custom message (go)", vcMock.PullRequests[0].Title)
+				assert.Equal(t, "custom body\n\nGo was detected.", vcMock.PullRequests[0].Body)
+				assert.Equal(t, []string{"automated", "go"}, vcMock.PullRequests[0].Labels)
+				assert.Equal(t, []string{"alice", "bob"}, vcMock.PullRequests[0].Reviewers)
+			},
+		},
+
+		{
+			name: "invalid pull request metadata from the script",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "should-change", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				changerBinaryPath + ` -pull-request '{"label": "go"}'`,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 0)
+				assert.Contains(t, runData.out, "Could not parse the pull request file written by the script")
+			},
+		},
+
+		{
+			name: "pr title prefix and suffix",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "should-change", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--platform", "github",
+				"--pr-title-prefix", "[automated]",
+				"--pr-title-prefix", "gitlab=Draft:",
+				"--pr-title-suffix", "github=(JIRA-123)",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Equal(t, "[automated] custom message (JIRA-123)", vcMock.PullRequests[0].Title)
+			},
+		},
+
+		{
+			name: "base branch rules",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				gitflowRepo := createRepo(t, "owner", "gitflow", "i like apples")
+				changeBranch(t, gitflowRepo.Path, "develop", true)
+				changeBranch(t, gitflowRepo.Path, "master", false)
+				legacyRepo := createRepo(t, "owner", "legacy", "i like apples")
+				changeBranch(t, legacyRepo.Path, "develop", true)
+				changeBranch(t, legacyRepo.Path, "release", true)
+				changeBranch(t, legacyRepo.Path, "master", false)
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						gitflowRepo,
+						legacyRepo,
+						createRepo(t, "owner", "trunk", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--base-branch-rule", "^owner/legacy$:release",
+				"--base-branch-rule", "develop",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 3)
+				bases := map[string]string{}
+				for _, pr := range vcMock.PullRequests {
+					bases[pr.RepoName] = pr.Base
+				}
+				assert.Equal(t, map[string]string{
+					"gitflow": "develop",
+					"legacy":  "release",
+					"trunk":   "master",
+				}, bases)
+			},
+		},
+
+		{
+			name: "backport branches",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				maintainedRepo := createRepo(t, "owner", "maintained", "i like apples")
+				changeBranch(t, maintainedRepo.Path, "release/1", true)
+				changeBranch(t, maintainedRepo.Path, "master", false)
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						maintainedRepo,
+						createRepo(t, "owner", "trunk", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--backport-branch", "release/1",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 3)
+				prs := []string{}
+				for _, pr := range vcMock.PullRequests {
+					prs = append(prs, pr.RepoName+": "+pr.Head+" -> "+pr.Base)
+				}
+				assert.ElementsMatch(t, []string{
+					"maintained: custom-branch-name -> master",
+					"trunk: custom-branch-name -> master",
+					"maintained: custom-branch-name-release/1 -> release/1",
+				}, prs)
+				assert.Contains(t, runData.logOut, "Backporting the changes to release/1, with the branch custom-branch-name-release/1")
+
+				changeBranch(t, vcMock.Repositories[0].Path, "custom-branch-name-release/1", false)
+				assert.Equal(t, "i like bananas", readTestFile(t, vcMock.Repositories[0].Path))
+			},
+		},
+
+		{
+			name: "backport branch pattern",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				maintainedRepo := createRepo(t, "owner", "maintained", "i like apples")
+				changeBranch(t, maintainedRepo.Path, "release/1", true)
+				changeBranch(t, maintainedRepo.Path, "release/2", true)
+				changeBranch(t, maintainedRepo.Path, "release/old/2", true)
+				changeBranch(t, maintainedRepo.Path, "master", false)
+
+				oldRepo := createRepo(t, "owner", "old", "i like apples")
+				changeBranch(t, oldRepo.Path, "release/1", true)
+				changeBranch(t, oldRepo.Path, "master", false)
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						maintainedRepo,
+						oldRepo,
+						createRepo(t, "owner", "trunk", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--backport-branch", "release/*",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				prs := []string{}
+				for _, pr := range vcMock.PullRequests {
+					prs = append(prs, pr.RepoName+": "+pr.Head+" -> "+pr.Base)
+				}
+				assert.ElementsMatch(t, []string{
+					"maintained: custom-branch-name -> master",
+					"old: custom-branch-name -> master",
+					"trunk: custom-branch-name -> master",
+					"maintained: custom-branch-name-release/1 -> release/1",
+					"old: custom-branch-name-release/1 -> release/1",
+					"maintained: custom-branch-name-release/2 -> release/2",
+				}, prs)
+				assert.Contains(t, runData.logOut, "Backporting the changes to release/1, with the branch custom-branch-name-release/1")
+				assert.Contains(t, runData.logOut, "Backporting the changes to release/2, with the branch custom-branch-name-release/2")
+			},
+		},
+
+		{
+			name: "backport branches in a campaign",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				repo := createRepo(t, "owner", "maintained", "i like apples")
+				changeBranch(t, repo.Path, "release/1", true)
+				changeTestFile(t, repo.Path, "i like old apples", "release change")
+				changeBranch(t, repo.Path, "master", false)
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{repo},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--campaign", "test-campaign",
+				"--conflict-strategy", "replace",
+				"--backport-branch", "release/1",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 2)
+				prs := []string{}
+				for _, pr := range vcMock.PullRequests {
+					prs = append(prs, pr.Head+" -> "+pr.Base)
+				}
+				assert.ElementsMatch(t, []string{
+					"custom-branch-name -> master",
+					"custom-branch-name-release/1 -> release/1",
+				}, prs)
+				assert.NotContains(t, runData.logOut, "of the same campaign on the branch")
+
+				changeBranch(t, vcMock.Repositories[0].Path, "custom-branch-name", false)
+				assert.Equal(t, "i like bananas", readTestFile(t, vcMock.Repositories[0].Path))
+				changeBranch(t, vcMock.Repositories[0].Path, "custom-branch-name-release/1", false)
+				assert.Equal(t, "i like old bananas", readTestFile(t, vcMock.Repositories[0].Path))
+			},
+		},
+
+		{
+			name: "reviewer limit ignores skipped pull requests",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				existing := createRepo(t, "owner", "existing-pr", "i like apples")
+				changeBranch(t, existing.Path, "custom-branch-name", true)
+				changeTestFile(t, existing.Path, "i like apple", "test change")
+				changeBranch(t, existing.Path, "master", false)
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						existing,
+						createRepo(t, "owner", "new-pr", "i like apples"),
+					},
+					PullRequests: []vcmock.PullRequest{
+						{
+							PRStatus:   scm.PullRequestStatusPending,
+							PRNumber:   42,
+							Repository: existing,
+							NewPullRequest: scm.NewPullRequest{
+								Head: "custom-branch-name",
+							},
+						},
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--reviewers", "alice",
+				"--max-prs-per-reviewer", "1",
+				"--skip-adoption",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 2)
+				assert.Empty(t, vcMock.PullRequests[0].Reviewers)
+				assert.Equal(t, []string{"alice"}, vcMock.PullRequests[1].Reviewers)
+			},
+		},
+
+		{
+			name: "backport branches share the reviewer limit",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				repo := createRepo(t, "owner", "maintained", "i like apples")
+				changeBranch(t, repo.Path, "release/1", true)
+				changeBranch(t, repo.Path, "master", false)
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{repo},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--reviewers", "alice",
+				"--max-prs-per-reviewer", "1",
+				"--backport-branch", "release/1",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 2)
+				reviewed := 0
+				for _, pr := range vcMock.PullRequests {
+					reviewed += len(pr.Reviewers)
+				}
+				assert.Equal(t, 1, reviewed)
+			},
+		},
+
+		{
+			name: "campaign on renamed branch",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				repo := createRepo(t, "owner", "existing-pr", "i like apples")
+				changeBranch(t, repo.Path, "old-branch-name", true)
+				changeTestFile(t, repo.Path, "i like apple", "test change")
+				changeBranch(t, repo.Path, "master", false)
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{repo},
+					PullRequests: []vcmock.PullRequest{
+						{
+							PRStatus:   scm.PullRequestStatusSuccess,
+							PRNumber:   42,
+							Repository: repo,
+							NewPullRequest: scm.NewPullRequest{
+								Title: "custom message",
+								Body:  "<!-- multi-gitter campaign: test-campaign -->\nold body",
+								Head:  "old-branch-name",
+								Base:  "master",
+							},
+						},
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "new-branch-name",
+				"-m", "custom message",
+				"--campaign", "test-campaign",
+				"--conflict-strategy", "replace",
+				"--labels", "campaign",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Equal(t, 42, vcMock.PullRequests[0].PRNumber)
+				assert.Contains(t, vcMock.PullRequests[0].Body, "<!-- multi-gitter campaign: test-campaign -->")
+				assert.Equal(t, []string{"campaign"}, vcMock.PullRequests[0].Labels)
+				assert.Contains(t, runData.logOut, "of the same campaign on the branch old-branch-name")
+
+				changeBranch(t, vcMock.Repositories[0].Path, "old-branch-name", false)
+				assert.Equal(t, "i like bananas", readTestFile(t, vcMock.Repositories[0].Path))
+			},
+		},
+
+		{
+			name: "campaign on renamed branch with skip conflict strategy",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				repo := createRepo(t, "owner", "existing-pr", "i like apples")
+				changeBranch(t, repo.Path, "old-branch-name", true)
+				changeTestFile(t, repo.Path, "i like apple", "test change")
+				changeBranch(t, repo.Path, "master", false)
+
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{repo},
+					PullRequests: []vcmock.PullRequest{
+						{
+							PRStatus:   scm.PullRequestStatusSuccess,
+							PRNumber:   42,
+							Repository: repo,
+							NewPullRequest: scm.NewPullRequest{
+								Title: "custom message",
+								Body:  "<!-- multi-gitter campaign: test-campaign -->\nold body",
+								Head:  "old-branch-name",
+								Base:  "master",
+							},
+						},
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "new-branch-name",
+				"-m", "custom message",
+				"--campaign", "test-campaign",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Contains(t, vcMock.PullRequests[0].Body, "old body")
+				assert.Contains(t, runData.logOut, "skipping since the branch already exists")
+
+				changeBranch(t, vcMock.Repositories[0].Path, "old-branch-name", false)
+				assert.Equal(t, "i like apple", readTestFile(t, vcMock.Repositories[0].Path))
+			},
+		},
+
+		{
+			name: "max prs per reviewer",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "should-change-1", "i like apples"),
+						createRepo(t, "owner", "should-change-2", "i like apples"),
+						createRepo(t, "owner", "should-change-3", "i like apples"),
+						createRepo(t, "owner", "should-change-4", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--reviewers", "reviewer1,reviewer2,reviewer3",
+				"--max-reviewers", "1",
+				"--max-prs-per-reviewer", "1",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 4)
+				reviewers := []string{}
+				for _, pr := range vcMock.PullRequests {
+					reviewers = append(reviewers, pr.Reviewers...)
+				}
+				assert.ElementsMatch(t, []string{"reviewer1", "reviewer2", "reviewer3"}, reviewers)
+				assert.Contains(t, runData.logOut, "Only 0 of 1 reviewers could be added")
+			},
+		},
+
+		{
+			name: "reviewer limit ignores failed pull requests",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				archived := createRepo(t, "owner", "archived", "i like apples")
+				archived.Archived = true
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						archived,
+						createRepo(t, "owner", "should-change", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--reviewers", "alice",
+				"--max-prs-per-reviewer", "1",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Equal(t, "owner/should-change", vcMock.PullRequests[0].Repository.FullName())
+				assert.Equal(t, []string{"alice"}, vcMock.PullRequests[0].Reviewers)
+			},
+		},
+
+		{
+			name: "reviewer limit counts reviewers of the script",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "should-change-1", "i like apples"),
+						createRepo(t, "owner", "should-change-2", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--reviewers", "alice,bob",
+				"--max-reviewers", "1",
+				"--max-prs-per-reviewer", "1",
+				changerBinaryPath + ` -pull-request '{"reviewers": ["alice", "bob"]}'`,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 2)
+				assert.Contains(t, runData.logOut, "Only 0 of 1 reviewers could be added")
+			},
+		},
+
+		{
+			name: "lenient reviewers",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "should-change", "i like apples"),
+					},
+					Users: []string{"existing-user"},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--reviewers", "existing-user,missing-user",
+				"--lenient-reviewers",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Equal(t, []string{"existing-user"}, vcMock.PullRequests[0].Reviewers)
+				assert.Contains(t, runData.logOut, `Could not find reviewer \"missing-user\", they will not be added to any pull request`)
+			},
+		},
+
+		{
+			name: "disk budget",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "should-change-1", "i like apples"),
+						createRepo(t, "owner", "should-change-2", "i like apples"),
+						createRepo(t, "owner", "should-change-3", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--concurrent", "3",
+				"--disk-budget", "1",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				assert.Len(t, vcMock.PullRequests, 3)
+				// The size of the clones is not known until the first clone is measured, so the others wait for it
+				assert.Contains(t, runData.logOut, "Waiting for other clones to be removed")
+			},
+		},
+
+		{
+			name: "min free disk",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "should-change", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--min-free-disk", "1000000000",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				assert.Len(t, vcMock.PullRequests, 0)
+				assert.Contains(t, runData.out, "MB is required")
+			},
+		},
+
+		{
+			name: "fault injection",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "should-change", "i like apples"),
+					},
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message",
+				"--fault-inject", "1",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 0)
+				assert.Equal(t, `Could not push changes: push rejected, fault injected by multi-gitter:
+  owner/should-change
+`, runData.out)
+			},
+		},
+
+		{
+			name: "truncated pr body",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{
+						createRepo(t, "owner", "should-change", "i like apples"),
+					},
+					MaxBodyLength: 20,
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message\nbody text that is far too long",
+				"--pr-body-truncation-marker", "...",
+				"--pr-full-body-comment",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Equal(t, "body text that is...", vcMock.PullRequests[0].Body)
+				assert.Equal(t, []string{"body text that is fa", "r too long"}, vcMock.PullRequests[0].Comments)
+				assert.Contains(t, runData.logOut, "The pull request body exceeds the length limit of the platform and was truncated")
+			},
+		},
+
+		{
+			name: "truncated pr body already commented",
+			vcCreate: func(t *testing.T) *vcmock.VersionController {
+				repo := createRepo(t, "owner", "should-change", "i like apples")
+				return &vcmock.VersionController{
+					Repositories: []vcmock.Repository{repo},
+					PullRequests: []vcmock.PullRequest{
+						{
+							PRStatus:   scm.PullRequestStatusPending,
+							PRNumber:   42,
+							Repository: repo,
+							NewPullRequest: scm.NewPullRequest{
+								Title: "custom message",
+								Body:  "body text that is...",
+								Head:  "custom-branch-name",
+							},
+							Comments: []string{"body text that is fa", "r too long"},
+						},
+					},
+					MaxBodyLength: 20,
+				}
+			},
+			args: []string{
+				"run",
+				"--author-name", "Test Author",
+				"--author-email", "test@example.com",
+				"-B", "custom-branch-name",
+				"-m", "custom message\nbody text that is far too long",
+				"--pr-body-truncation-marker", "...",
+				"--pr-full-body-comment",
+				changerBinaryPath,
+			},
+			verify: func(t *testing.T, vcMock *vcmock.VersionController, runData runData) {
+				require.Len(t, vcMock.PullRequests, 1)
+				assert.Equal(t, []string{"body text that is fa", "r too long"}, vcMock.PullRequests[0].Comments)
+				assert.Contains(t, runData.logOut, "The full pull request body is already commented")
+			},
+		},
 	}
 
 	for _, gitBackend := range gitBackends {
@@ -0,0 +1,58 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStatusTeams tests that the statuses of pull requests are grouped by the teams that own the repositories
+func TestStatusTeams(t *testing.T) {
+	vcMock := &vcmock.VersionController{}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir, err := os.MkdirTemp(os.TempDir(), "multi-git-test-teams-")
+	defer os.RemoveAll(tmpDir)
+	require.NoError(t, err)
+
+	for i, repoName := range []string{"no-owner", "web-app", "web-legacy", "web-site"} {
+		repo := createRepo(t, "owner", repoName, "i like apples")
+		vcMock.AddRepository(repo)
+		vcMock.PullRequests = append(vcMock.PullRequests, vcmock.PullRequest{
+			PRStatus:   scm.PullRequestStatusPending,
+			PRNumber:   i + 1,
+			Repository: repo,
+			NewPullRequest: scm.NewPullRequest{
+				Head: "custom-branch-name",
+			},
+		})
+	}
+
+	outFile := filepath.Join(tmpDir, "out.txt")
+	command := cmd.RootCmd()
+	command.SetArgs([]string{
+		"status",
+		"--output", outFile,
+		"-B", "custom-branch-name",
+		"--team-mapping", "testdata/team-mapping",
+	})
+	require.NoError(t, command.Execute())
+
+	out, err := os.ReadFile(outFile)
+	require.NoError(t, err)
+	assert.Equal(t, `@org/frontend:
+  owner/web-app #2: Pending
+  owner/web-site #4: Pending
+@org/legacy:
+  owner/web-legacy #3: Pending
+No team:
+  owner/no-owner #1: Pending
+`, string(out))
+}
@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTokenSource tests that a token from a source that can only be read once, like a file descriptor, is used by the
+// platform, even though the token is needed both to censor the logs and to create the platform
+func TestTokenSource(t *testing.T) {
+	cmd.OverrideVersionController = nil
+
+	var lock sync.Mutex
+	tokens := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		tokens = append(tokens, r.URL.Query().Get("access_token"))
+		lock.Unlock()
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+	_, err = writer.WriteString("fd-token\n")
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	tmpDir := t.TempDir()
+	command := cmd.RootCmd()
+	command.SetArgs([]string{
+		"status",
+		"--platform", "gitee",
+		"--base-url", server.URL,
+		"--org", "test-org",
+		"--token-source", fmt.Sprintf("fd:%d", reader.Fd()),
+		"--log-file", filepath.Join(tmpDir, "log.txt"),
+		"--output", filepath.Join(tmpDir, "out.txt"),
+		"-B", "custom-branch-name",
+	})
+	err = command.Execute()
+	// The descriptor is already closed when the token is read, this keeps the reader from closing it again
+	_ = reader.Close()
+	require.NoError(t, err)
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.NotEmpty(t, tokens)
+	for _, token := range tokens {
+		assert.Equal(t, "fd-token", token)
+	}
+}
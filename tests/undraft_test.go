@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lindell/multi-gitter/cmd"
+	"github.com/lindell/multi-gitter/internal/scm"
+	"github.com/lindell/multi-gitter/tests/vcmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUndraft tests that draft pull requests can be marked as ready for review, and converted back to drafts
+func TestUndraft(t *testing.T) {
+	vcMock := &vcmock.VersionController{}
+	defer vcMock.Clean()
+	cmd.OverrideVersionController = vcMock
+
+	tmpDir, err := os.MkdirTemp(os.TempDir(), "multi-git-test-undraft-")
+	defer os.RemoveAll(tmpDir)
+	assert.NoError(t, err)
+
+	openRepo := createRepo(t, "owner", "open", "i like apples")
+	mergedRepo := createRepo(t, "owner", "merged", "i like apples")
+	vcMock.AddRepository(openRepo, mergedRepo)
+	vcMock.PullRequests = []vcmock.PullRequest{
+		{
+			PRStatus:   scm.PullRequestStatusPending,
+			PRNumber:   1,
+			Repository: openRepo,
+			NewPullRequest: scm.NewPullRequest{
+				Head:  "custom-branch-name",
+				Draft: true,
+			},
+		},
+		{
+			PRStatus:   scm.PullRequestStatusMerged,
+			PRNumber:   2,
+			Repository: mergedRepo,
+			NewPullRequest: scm.NewPullRequest{
+				Head:  "custom-branch-name",
+				Draft: true,
+			},
+		},
+	}
+
+	undraftLogFile := filepath.Join(tmpDir, "undraft-log.txt")
+
+	command := cmd.RootCmd()
+	command.SetArgs([]string{
+		"undraft",
+		"--log-file", undraftLogFile,
+		"-B", "custom-branch-name",
+	})
+	err = command.Execute()
+	assert.NoError(t, err)
+
+	undraftLogData, err := os.ReadFile(undraftLogFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(undraftLogData), "Marking 1 pull requests as ready for review")
+	assert.False(t, vcMock.PullRequests[0].Draft)
+	assert.True(t, vcMock.PullRequests[1].Draft)
+
+	toDraftLogFile := filepath.Join(tmpDir, "to-draft-log.txt")
+
+	command = cmd.RootCmd()
+	command.SetArgs([]string{
+		"undraft",
+		"--log-file", toDraftLogFile,
+		"-B", "custom-branch-name",
+		"--to-draft",
+	})
+	err = command.Execute()
+	assert.NoError(t, err)
+
+	toDraftLogData, err := os.ReadFile(toDraftLogFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(toDraftLogData), "Converting 1 pull requests to draft")
+	assert.True(t, vcMock.PullRequests[0].Draft)
+
+	autoMergeLogFile := filepath.Join(tmpDir, "auto-merge-log.txt")
+
+	command = cmd.RootCmd()
+	command.SetArgs([]string{
+		"undraft",
+		"--log-file", autoMergeLogFile,
+		"-B", "custom-branch-name",
+		"--auto-merge",
+	})
+	err = command.Execute()
+	assert.NoError(t, err)
+
+	autoMergeLogData, err := os.ReadFile(autoMergeLogFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(autoMergeLogData), "Enabling auto-merge")
+	assert.False(t, vcMock.PullRequests[0].Draft)
+	assert.True(t, vcMock.PullRequests[0].AutoMerge)
+	assert.False(t, vcMock.PullRequests[1].AutoMerge)
+}
@@ -7,9 +7,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 
 	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/lindell/multi-gitter/internal/scm"
 	"github.com/pkg/errors"
 )
@@ -19,22 +23,51 @@ type VersionController struct {
 	PRNumber     int
 	Repositories []Repository
 	PullRequests []PullRequest
+	Issues       []Issue
+	Releases     []Release
+
+	MaxBodyLength int                        // The maximum length of pull request bodies, zero means no limit
+	Constraints   scm.PullRequestConstraints // The limits on the values of pull requests
+	Users         []string                   // The users and teams that exist, if nil everyone exists
+	PageSize      int                        // If set, repositories and pull requests are listed in pages of this size, like the platforms do
 
 	prLock sync.RWMutex
 }
 
 // GetRepositories returns mock repositories
 func (vc *VersionController) GetRepositories(_ context.Context) ([]scm.Repository, error) {
-	ret := make([]scm.Repository, len(vc.Repositories))
-	for i := range vc.Repositories {
-		ret[i] = vc.Repositories[i]
+	ret := make([]scm.Repository, 0, len(vc.Repositories))
+	for i := 0; ; i++ {
+		repos := page(vc.Repositories, i, vc.PageSize)
+		for _, repo := range repos {
+			ret = append(ret, repo)
+		}
+		if vc.PageSize <= 0 || len(repos) < vc.PageSize {
+			break
+		}
 	}
 	return ret, nil
 }
 
+// page returns the items on a page, the first page has index 0. If the page size is not set, everything is on one page
+func page[T any](items []T, index int, size int) []T {
+	if size <= 0 {
+		return items
+	}
+	start := min(index*size, len(items))
+	end := min(start+size, len(items))
+	return items[start:end]
+}
+
 // CreatePullRequest stores a mock pull request
-func (vc *VersionController) CreatePullRequest(_ context.Context, repo scm.Repository, _ scm.Repository, newPR scm.NewPullRequest) (scm.PullRequest, error) {
+func (vc *VersionController) CreatePullRequest(_ context.Context, repo scm.Repository, prRepo scm.Repository, newPR scm.NewPullRequest) (scm.PullRequest, error) {
 	repository := repo.(Repository)
+	if repository.PullRequestsDisabled {
+		return nil, &scm.PullRequestNotPermittedError{Err: errors.New("pull requests are disabled")}
+	}
+	if repository.Archived {
+		return nil, errors.New("the repository is archived")
+	}
 
 	vc.prLock.Lock()
 	defer vc.prLock.Unlock()
@@ -46,6 +79,10 @@ func (vc *VersionController) CreatePullRequest(_ context.Context, repo scm.Repos
 		Repository:     repository,
 		NewPullRequest: newPR,
 	}
+	if prRepo.FullName() != repository.FullName() {
+		fork := prRepo.(Repository)
+		pr.Fork = &fork
+	}
 	vc.PullRequests = append(vc.PullRequests, pr)
 
 	return pr, nil
@@ -62,8 +99,13 @@ func (vc *VersionController) UpdatePullRequest(_ context.Context, _ scm.Reposito
 		if vc.PullRequests[i].PRNumber == pullRequest.PRNumber && vc.PullRequests[i].Repository.FullName() == pullRequest.Repository.FullName() {
 			vc.PullRequests[i].Title = updatedPR.Title
 			vc.PullRequests[i].Body = updatedPR.Body
-			vc.PullRequests[i].Reviewers = updatedPR.Reviewers
-			vc.PullRequests[i].TeamReviewers = updatedPR.TeamReviewers
+			if updatedPR.KeepReviewers {
+				vc.PullRequests[i].Reviewers = appendMissing(vc.PullRequests[i].Reviewers, updatedPR.Reviewers)
+				vc.PullRequests[i].TeamReviewers = appendMissing(vc.PullRequests[i].TeamReviewers, updatedPR.TeamReviewers)
+			} else {
+				vc.PullRequests[i].Reviewers = updatedPR.Reviewers
+				vc.PullRequests[i].TeamReviewers = updatedPR.TeamReviewers
+			}
 			vc.PullRequests[i].Assignees = updatedPR.Assignees
 			vc.PullRequests[i].Labels = updatedPR.Labels
 			return vc.PullRequests[i], nil
@@ -77,11 +119,22 @@ func (vc *VersionController) GetPullRequests(_ context.Context, branchName strin
 	vc.prLock.RLock()
 	defer vc.prLock.RUnlock()
 
-	ret := make([]scm.PullRequest, 0, len(vc.PullRequests))
+	matching := make([]PullRequest, 0, len(vc.PullRequests))
 	for _, pr := range vc.PullRequests {
 		if pr.NewPullRequest.Head == branchName {
+			matching = append(matching, pr)
+		}
+	}
+
+	ret := make([]scm.PullRequest, 0, len(matching))
+	for i := 0; ; i++ {
+		prs := page(matching, i, vc.PageSize)
+		for _, pr := range prs {
 			ret = append(ret, pr)
 		}
+		if vc.PageSize <= 0 || len(prs) < vc.PageSize {
+			break
+		}
 	}
 	return ret, nil
 }
@@ -101,10 +154,95 @@ func (vc *VersionController) GetOpenPullRequest(_ context.Context, repo scm.Repo
 	return nil, nil
 }
 
+// GetConflictingPullRequests gets mock open pull requests that change any of the files
+func (vc *VersionController) GetConflictingPullRequests(_ context.Context, repo scm.Repository, branchName string, files []string) ([]scm.PullRequest, error) {
+	vc.prLock.RLock()
+	defer vc.prLock.RUnlock()
+
+	r := repo.(Repository)
+
+	ret := []scm.PullRequest{}
+	for _, pr := range vc.PullRequests {
+		if r.OwnerName == pr.OwnerName && r.RepoName == pr.RepoName && pr.NewPullRequest.Head != branchName &&
+			openPullRequest(pr) && scm.Overlaps(pr.Files, files) {
+			ret = append(ret, pr)
+		}
+	}
+	return ret, nil
+}
+
+// FindCampaignPullRequest gets the mock open pull request to the base branch with the title that contains the campaign marker
+func (vc *VersionController) FindCampaignPullRequest(_ context.Context, repo scm.Repository, baseBranch, title, marker string) (scm.PullRequest, string, error) {
+	vc.prLock.RLock()
+	defer vc.prLock.RUnlock()
+
+	r := repo.(Repository)
+
+	for _, pr := range vc.PullRequests {
+		if r.OwnerName == pr.OwnerName && r.RepoName == pr.RepoName && openPullRequest(pr) &&
+			pr.Base == baseBranch && pr.Title == title && strings.Contains(pr.Body, marker) {
+			return pr, pr.Head, nil
+		}
+	}
+	return nil, "", nil
+}
+
 func openPullRequest(pr PullRequest) bool {
 	return pr.PRStatus == scm.PullRequestStatusSuccess || pr.PRStatus == scm.PullRequestStatusPending
 }
 
+// MergePullRequestWithMessage sets the status of a mock pull request to merged, and stores the message of the merge commit
+func (vc *VersionController) MergePullRequestWithMessage(ctx context.Context, pr scm.PullRequest, message scm.MergeMessage) error {
+	if err := vc.MergePullRequest(ctx, pr); err != nil {
+		return err
+	}
+
+	vc.prLock.Lock()
+	defer vc.prLock.Unlock()
+
+	pullRequest := pr.(PullRequest)
+	for i := range vc.PullRequests {
+		if vc.PullRequests[i].Repository.FullName() == pullRequest.Repository.FullName() {
+			vc.PullRequests[i].MergeMessage = message
+		}
+	}
+	return nil
+}
+
+// GetClosedPullRequest gets the latest closed pull request from a branch
+func (vc *VersionController) GetClosedPullRequest(_ context.Context, repo scm.Repository, branchName string) (scm.PullRequest, error) {
+	vc.prLock.RLock()
+	defer vc.prLock.RUnlock()
+
+	r := repo.(Repository)
+
+	for i := len(vc.PullRequests) - 1; i >= 0; i-- {
+		pr := vc.PullRequests[i]
+		if r.OwnerName == pr.OwnerName && r.RepoName == pr.RepoName && pr.NewPullRequest.Head == branchName && pr.PRStatus == scm.PullRequestStatusClosed {
+			return pr, nil
+		}
+	}
+	return nil, nil
+}
+
+// ReopenPullRequest sets the status of a mock pull request to pending
+func (vc *VersionController) ReopenPullRequest(_ context.Context, pr scm.PullRequest) (scm.PullRequest, error) {
+	vc.prLock.Lock()
+	defer vc.prLock.Unlock()
+
+	pullRequest := pr.(PullRequest)
+	for i := range vc.PullRequests {
+		if vc.PullRequests[i].PRNumber == pullRequest.PRNumber && vc.PullRequests[i].Repository.FullName() == pullRequest.Repository.FullName() {
+			if vc.PullRequests[i].Unreopenable {
+				return nil, errors.New("the pull request can not be reopened")
+			}
+			vc.PullRequests[i].PRStatus = scm.PullRequestStatusPending
+			return vc.PullRequests[i], nil
+		}
+	}
+	return nil, errors.New("could not find pull request")
+}
+
 // MergePullRequest sets the status of a mock pull requests to merged
 func (vc *VersionController) MergePullRequest(_ context.Context, pr scm.PullRequest) error {
 	vc.prLock.Lock()
@@ -113,6 +251,9 @@ func (vc *VersionController) MergePullRequest(_ context.Context, pr scm.PullRequ
 	pullRequest := pr.(PullRequest)
 	for i := range vc.PullRequests {
 		if vc.PullRequests[i].Repository.FullName() == pullRequest.Repository.FullName() {
+			if vc.PullRequests[i].Conflicting {
+				return errors.New("the pull request has conflicts")
+			}
 			vc.PullRequests[i].PRStatus = scm.PullRequestStatusMerged
 			return nil
 		}
@@ -120,8 +261,48 @@ func (vc *VersionController) MergePullRequest(_ context.Context, pr scm.PullRequ
 	return errors.New("could not find pull request")
 }
 
+// UpdatePullRequestBranch resolves the conflicts of a mock pull request, unless it can't be updated
+func (vc *VersionController) UpdatePullRequestBranch(_ context.Context, pr scm.PullRequest) error {
+	vc.prLock.Lock()
+	defer vc.prLock.Unlock()
+
+	pullRequest := pr.(PullRequest)
+	for i := range vc.PullRequests {
+		if vc.PullRequests[i].Repository.FullName() == pullRequest.Repository.FullName() {
+			if vc.PullRequests[i].Unupdatable {
+				return errors.New("the branch conflicts with the base branch")
+			}
+			vc.PullRequests[i].Conflicting = false
+			return nil
+		}
+	}
+	return errors.New("could not find pull request")
+}
+
 // ClosePullRequest sets the status of a mock pull requests to closed
-func (vc *VersionController) ClosePullRequest(_ context.Context, pr scm.PullRequest) error {
+func (vc *VersionController) ClosePullRequest(ctx context.Context, pr scm.PullRequest) error {
+	if err := vc.ClosePullRequestKeepBranch(ctx, pr); err != nil {
+		return err
+	}
+
+	pullRequest := pr.(PullRequest)
+	path := pullRequest.Repository.Path
+	if pullRequest.Fork != nil {
+		path = pullRequest.Fork.Path
+	}
+	gitRepo, err := git.PlainOpen(path)
+	if err != nil {
+		return err
+	}
+	err = gitRepo.Storer.RemoveReference(plumbing.NewBranchReferenceName(pullRequest.Head))
+	if err != nil {
+		return errors.Wrap(err, "could not delete the branch")
+	}
+	return nil
+}
+
+// ClosePullRequestKeepBranch closes a mock pull request without deleting its branch
+func (vc *VersionController) ClosePullRequestKeepBranch(_ context.Context, pr scm.PullRequest) error {
 	vc.prLock.Lock()
 	defer vc.prLock.Unlock()
 
@@ -135,6 +316,249 @@ func (vc *VersionController) ClosePullRequest(_ context.Context, pr scm.PullRequ
 	return errors.New("could not find pull request")
 }
 
+// DeleteFork deletes the fork a mock pull request was made from
+func (vc *VersionController) DeleteFork(_ context.Context, pr scm.PullRequest) (bool, error) {
+	fork := pr.(PullRequest).Fork
+	if fork == nil {
+		return false, nil
+	}
+	if err := os.RemoveAll(fork.Path); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CreateRelease stores a mock release of a pull request
+func (vc *VersionController) CreateRelease(_ context.Context, pr scm.PullRequest, release scm.NewRelease) error {
+	vc.prLock.Lock()
+	defer vc.prLock.Unlock()
+
+	vc.Releases = append(vc.Releases, Release{
+		RepoName:   pr.(PullRequest).Repository.FullName(),
+		NewRelease: release,
+	})
+	return nil
+}
+
+// SetPullRequestDraft sets the draft state of a mock pull request
+func (vc *VersionController) SetPullRequestDraft(_ context.Context, pr scm.PullRequest, draft bool) error {
+	vc.prLock.Lock()
+	defer vc.prLock.Unlock()
+
+	pullRequest := pr.(PullRequest)
+	for i := range vc.PullRequests {
+		if vc.PullRequests[i].Repository.FullName() == pullRequest.Repository.FullName() {
+			vc.PullRequests[i].Draft = draft
+			return nil
+		}
+	}
+	return errors.New("could not find pull request")
+}
+
+// EnableAutoMerge sets a mock pull request to be merged automatically
+func (vc *VersionController) EnableAutoMerge(_ context.Context, pr scm.PullRequest) error {
+	vc.prLock.Lock()
+	defer vc.prLock.Unlock()
+
+	pullRequest := pr.(PullRequest)
+	for i := range vc.PullRequests {
+		if vc.PullRequests[i].Repository.FullName() == pullRequest.Repository.FullName() {
+			vc.PullRequests[i].AutoMerge = true
+			return nil
+		}
+	}
+	return errors.New("could not find pull request")
+}
+
+// RerequestReviews requests a new review from everyone who approved a mock pull request
+func (vc *VersionController) RerequestReviews(_ context.Context, pr scm.PullRequest) ([]string, error) {
+	vc.prLock.Lock()
+	defer vc.prLock.Unlock()
+
+	pullRequest := pr.(PullRequest)
+	for i := range vc.PullRequests {
+		if vc.PullRequests[i].PRNumber == pullRequest.PRNumber && vc.PullRequests[i].Repository.FullName() == pullRequest.Repository.FullName() {
+			approvers := vc.PullRequests[i].ApprovedBy
+			vc.PullRequests[i].ApprovedBy = nil
+			vc.PullRequests[i].Reviewers = append(vc.PullRequests[i].Reviewers, approvers...)
+			return approvers, nil
+		}
+	}
+	return nil, errors.New("could not find pull request")
+}
+
+// ResetApprovals withdraws the approvals of a mock pull request
+func (vc *VersionController) ResetApprovals(_ context.Context, pr scm.PullRequest) ([]string, error) {
+	vc.prLock.Lock()
+	defer vc.prLock.Unlock()
+
+	pullRequest := pr.(PullRequest)
+	for i := range vc.PullRequests {
+		if vc.PullRequests[i].PRNumber == pullRequest.PRNumber && vc.PullRequests[i].Repository.FullName() == pullRequest.Repository.FullName() {
+			approvers := vc.PullRequests[i].ApprovedBy
+			vc.PullRequests[i].ApprovedBy = nil
+			return approvers, nil
+		}
+	}
+	return nil, errors.New("could not find pull request")
+}
+
+// GetRequiredStatusChecks gets the mock required status checks of a repository
+func (vc *VersionController) GetRequiredStatusChecks(_ context.Context, repo scm.Repository, _ string) ([]string, error) {
+	return repo.(Repository).RequiredStatusChecks, nil
+}
+
+// UserExists checks if a mock user exists
+func (vc *VersionController) UserExists(_ context.Context, username string) (bool, error) {
+	return vc.exists(username), nil
+}
+
+// TeamExists checks if a mock team exists
+func (vc *VersionController) TeamExists(_ context.Context, team string) (bool, error) {
+	return vc.exists(team), nil
+}
+
+func (vc *VersionController) exists(name string) bool {
+	if vc.Users == nil {
+		return true
+	}
+	for _, user := range vc.Users {
+		if user == name {
+			return true
+		}
+	}
+	return false
+}
+
+// PullRequestConstraints returns the limits on the values of mock pull requests
+func (vc *VersionController) PullRequestConstraints() scm.PullRequestConstraints {
+	return vc.Constraints
+}
+
+// MaxPullRequestBodyLength returns the maximum length of mock pull request bodies
+func (vc *VersionController) MaxPullRequestBodyLength() int {
+	return vc.MaxBodyLength
+}
+
+// CommentOnPullRequest adds a comment to a mock pull request
+func (vc *VersionController) CommentOnPullRequest(_ context.Context, pr scm.PullRequest, comment string) error {
+	vc.prLock.Lock()
+	defer vc.prLock.Unlock()
+
+	pullRequest := pr.(PullRequest)
+	for i := range vc.PullRequests {
+		if vc.PullRequests[i].PRNumber == pullRequest.PRNumber && vc.PullRequests[i].Repository.FullName() == pullRequest.Repository.FullName() {
+			vc.PullRequests[i].Comments = append(vc.PullRequests[i].Comments, comment)
+			return nil
+		}
+	}
+	return errors.New("could not find pull request")
+}
+
+// PullRequestComments returns the comments of a mock pull request
+func (vc *VersionController) PullRequestComments(_ context.Context, pr scm.PullRequest) ([]string, error) {
+	vc.prLock.Lock()
+	defer vc.prLock.Unlock()
+
+	pullRequest := pr.(PullRequest)
+	for _, p := range vc.PullRequests {
+		if p.PRNumber == pullRequest.PRNumber && p.Repository.FullName() == pullRequest.Repository.FullName() {
+			return append([]string{}, p.Comments...), nil
+		}
+	}
+	return nil, errors.New("could not find pull request")
+}
+
+// CreateCheckRun adds a check run to a mock pull request
+func (vc *VersionController) CreateCheckRun(_ context.Context, pr scm.PullRequest, checkRun scm.CheckRun) error {
+	vc.prLock.Lock()
+	defer vc.prLock.Unlock()
+
+	pullRequest := pr.(PullRequest)
+	for i := range vc.PullRequests {
+		if vc.PullRequests[i].PRNumber == pullRequest.PRNumber && vc.PullRequests[i].Repository.FullName() == pullRequest.Repository.FullName() {
+			vc.PullRequests[i].CheckRuns = append(vc.PullRequests[i].CheckRuns, checkRun)
+			return nil
+		}
+	}
+	return errors.New("could not find pull request")
+}
+
+// SetCommitStatus adds a status to a mock pull request
+func (vc *VersionController) SetCommitStatus(_ context.Context, pr scm.PullRequest, status scm.CommitStatus) error {
+	vc.prLock.Lock()
+	defer vc.prLock.Unlock()
+
+	pullRequest := pr.(PullRequest)
+	for i := range vc.PullRequests {
+		if vc.PullRequests[i].PRNumber == pullRequest.PRNumber && vc.PullRequests[i].Repository.FullName() == pullRequest.Repository.FullName() {
+			vc.PullRequests[i].Statuses = append(vc.PullRequests[i].Statuses, status)
+			return nil
+		}
+	}
+	return errors.New("could not find pull request")
+}
+
+// TriggerPipeline starts a mock pipeline, which is finished the first time its status is checked
+func (vc *VersionController) TriggerPipeline(_ context.Context, pr scm.PullRequest) (scm.Pipeline, error) {
+	vc.prLock.Lock()
+	defer vc.prLock.Unlock()
+
+	pullRequest := pr.(PullRequest)
+	for i := range vc.PullRequests {
+		if vc.PullRequests[i].PRNumber == pullRequest.PRNumber && vc.PullRequests[i].Repository.FullName() == pullRequest.Repository.FullName() {
+			vc.PullRequests[i].Checks = "running"
+			return scm.Pipeline{
+				ID:     strconv.Itoa(i),
+				Status: "running",
+				URL:    fmt.Sprintf("https://example.com/%s/pipelines/%d", pullRequest.Repository.FullName(), pullRequest.PRNumber),
+			}, nil
+		}
+	}
+	return scm.Pipeline{}, errors.New("could not find pull request")
+}
+
+// GetPipeline finishes a mock pipeline with the pipeline status of the repository of its pull request
+func (vc *VersionController) GetPipeline(_ context.Context, pipeline scm.Pipeline) (scm.Pipeline, error) {
+	vc.prLock.Lock()
+	defer vc.prLock.Unlock()
+
+	i, err := strconv.Atoi(pipeline.ID)
+	if err != nil || i >= len(vc.PullRequests) {
+		return scm.Pipeline{}, errors.New("could not find pipeline")
+	}
+
+	status := vc.PullRequests[i].PipelineStatus
+	if status == "" {
+		status = "success"
+	}
+	vc.PullRequests[i].Checks = status
+	pipeline.Status = status
+	pipeline.Done = true
+	pipeline.Passed = status == "success"
+	return pipeline, nil
+}
+
+// UpsertIssue creates a mock issue, or updates the body of an existing one with the same title
+func (vc *VersionController) UpsertIssue(_ context.Context, repoName string, title string, body string) (string, error) {
+	vc.prLock.Lock()
+	defer vc.prLock.Unlock()
+
+	for i := range vc.Issues {
+		if vc.Issues[i].RepoName == repoName && vc.Issues[i].Title == title {
+			vc.Issues[i].Body = body
+			return fmt.Sprintf("https://example.com/%s/issues/%d", repoName, i+1), nil
+		}
+	}
+
+	vc.Issues = append(vc.Issues, Issue{
+		RepoName: repoName,
+		Title:    title,
+		Body:     body,
+	})
+	return fmt.Sprintf("https://example.com/%s/issues/%d", repoName, len(vc.Issues)), nil
+}
+
 // AddRepository adds a repository to the mock
 func (vc *VersionController) AddRepository(repo ...Repository) {
 	vc.Repositories = append(vc.Repositories, repo...)
@@ -176,6 +600,16 @@ func (vc *VersionController) ForkRepository(ctx context.Context, repo scm.Reposi
 	}
 
 	newPath := fmt.Sprintf("%s-forked-%s", r.Path, newOwner)
+	fork := Repository{
+		OwnerName: newOwner,
+		RepoName:  r.RepoName,
+		Path:      newPath,
+	}
+
+	// Like on the platforms, an existing fork is used instead of a new one being made
+	if _, err := os.Stat(newPath); err == nil {
+		return fork, nil
+	}
 
 	_, err := git.PlainCloneContext(ctx, newPath, false, &git.CloneOptions{
 		URL: fmt.Sprintf(`file://%s`, filepath.ToSlash(r.Path)),
@@ -184,11 +618,83 @@ func (vc *VersionController) ForkRepository(ctx context.Context, repo scm.Reposi
 		return nil, err
 	}
 
-	return Repository{
-		OwnerName: newOwner,
-		RepoName:  r.RepoName,
-		Path:      newPath,
-	}, nil
+	return fork, nil
+}
+
+// RepositoryAvailable returns if the mock repository exists, and is not archived
+func (vc *VersionController) RepositoryAvailable(_ context.Context, repo scm.Repository) (bool, error) {
+	for _, r := range vc.Repositories {
+		if r.FullName() == repo.FullName() {
+			return !r.Archived, nil
+		}
+	}
+	return false, nil
+}
+
+// GetPullRequestBase gets the base branch of a mock pull request
+func (vc *VersionController) GetPullRequestBase(_ context.Context, pr scm.PullRequest) (string, error) {
+	vc.prLock.RLock()
+	defer vc.prLock.RUnlock()
+
+	pullRequest := pr.(PullRequest)
+	for _, p := range vc.PullRequests {
+		if p.PRNumber == pullRequest.PRNumber && p.Repository.FullName() == pullRequest.Repository.FullName() {
+			return p.Base, nil
+		}
+	}
+	return "", errors.New("could not find pull request")
+}
+
+// RetargetPullRequest changes the base branch of a mock pull request
+func (vc *VersionController) RetargetPullRequest(_ context.Context, pr scm.PullRequest, base string) error {
+	vc.prLock.Lock()
+	defer vc.prLock.Unlock()
+
+	pullRequest := pr.(PullRequest)
+	for i := range vc.PullRequests {
+		if vc.PullRequests[i].PRNumber == pullRequest.PRNumber && vc.PullRequests[i].Repository.FullName() == pullRequest.Repository.FullName() {
+			vc.PullRequests[i].Base = base
+			return nil
+		}
+	}
+	return errors.New("could not find pull request")
+}
+
+// BranchExists checks if a branch exists in the repository on disk
+func (vc *VersionController) BranchExists(_ context.Context, repo scm.Repository, branchName string) (bool, error) {
+	r := repo.(Repository)
+
+	gitRepo, err := git.PlainOpen(r.Path)
+	if err != nil {
+		return false, err
+	}
+	_, err = gitRepo.Reference(plumbing.NewBranchReferenceName(branchName), false)
+	if err == plumbing.ErrReferenceNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetBranches gets the names of all branches in the repository on disk
+func (vc *VersionController) GetBranches(_ context.Context, repo scm.Repository) ([]string, error) {
+	r := repo.(Repository)
+
+	gitRepo, err := git.PlainOpen(r.Path)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := gitRepo.Branches()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		names = append(names, ref.Name().Short())
+		return nil
+	})
+	return names, err
 }
 
 // Clean cleans up the data on disk that exist within the version controller mock
@@ -200,9 +706,22 @@ func (vc *VersionController) Clean() {
 
 // PullRequest is a mock pr
 type PullRequest struct {
-	PRStatus scm.PullRequestStatus
-	PRNumber int
-	Merged   bool
+	PRStatus     scm.PullRequestStatus
+	PRNumber     int
+	Merged       bool
+	AutoMerge    bool
+	MergeMessage scm.MergeMessage // The message of the merge commit, if a custom one was used
+	Files        []string         // The files changed by the pull request
+	Comments     []string
+	CheckRuns    []scm.CheckRun
+	Statuses     []scm.CommitStatus
+	Checks       string      // The state of the checks of the last commit, set to the status of triggered pipelines
+	Unsuccessful []string    // The checks of the last commit that have not passed, in the format "name: state"
+	ApprovedBy   []string    // The users that has approved the pull request
+	Fork         *Repository // The fork the pull request was made from, if any
+	Unreopenable bool        // If reopening the pull request fails, like when its branch has been re-created
+	Conflicting  bool        // If merging the pull request fails until its branch is updated
+	Unupdatable  bool        // If updating the branch of the pull request fails, like when it conflicts with the base branch
 
 	Repository
 	scm.NewPullRequest
@@ -218,6 +737,30 @@ func (pr PullRequest) String() string {
 	return fmt.Sprintf("%s #%d", pr.Repository.FullName(), pr.PRNumber)
 }
 
+// RepositoryFullName returns the full name of the repository the pull request is made against
+func (pr PullRequest) RepositoryFullName() string {
+	return pr.Repository.FullName()
+}
+
+// Details returns the details of the pr, where reviewers that has not approved are requested reviewers
+func (pr PullRequest) Details() scm.PullRequestDetails {
+	details := scm.PullRequestDetails{
+		ID:     fmt.Sprintf("%s/%d", pr.Repository.FullName(), pr.PRNumber),
+		Number: pr.PRNumber,
+		Checks: pr.Checks,
+
+		UnsuccessfulChecks: pr.Unsuccessful,
+	}
+	for _, reviewer := range pr.Reviewers {
+		state := "requested"
+		if slices.Contains(pr.ApprovedBy, reviewer) {
+			state = "approved"
+		}
+		details.Reviews = append(details.Reviews, scm.PullRequestReview{Reviewer: reviewer, State: state})
+	}
+	return details
+}
+
 func (pr PullRequest) URL() string {
 	if pr.Repository.RepoName == "has-url" {
 		return "https://github.com/owner/has-url/pull/1"
@@ -226,23 +769,59 @@ func (pr PullRequest) URL() string {
 	return ""
 }
 
+// Issue is a mock issue
+type Issue struct {
+	RepoName string // The full name of the repository
+	Title    string
+	Body     string
+}
+
+// Release is a mock release
+type Release struct {
+	RepoName string // The full name of the repository
+	scm.NewRelease
+}
+
 // Repository is a mock repository
 type Repository struct {
 	OwnerName string
 	RepoName  string
 	Path      string
+	RepoID    string // The id that is kept when the repository is renamed or moved, if any
+
+	BrokenCloneURL       bool     // If set, the clone url does not work, and the fallback clone url has to be used
+	PullRequestsDisabled bool     // If set, no pull requests can be created on the repository
+	RequiredStatusChecks []string // The status checks required to pass on the base branch
+	PipelineStatus       string   // The status triggered pipelines of pull requests finish with, "success" if not set
+	Archived             bool     // If set, the repository is listed, but archived as if it happened after it was listed
 }
 
 // CloneURL return the URL (filepath) of the repository on disk
 func (r Repository) CloneURL() string {
+	if r.BrokenCloneURL {
+		return fmt.Sprintf(`file://%s`, filepath.ToSlash(r.Path+"-does-not-exist"))
+	}
 	return fmt.Sprintf(`file://%s`, filepath.ToSlash(r.Path))
 }
 
+// FallbackCloneURL return the URL (filepath) of the repository on disk if the clone url is broken
+func (r Repository) FallbackCloneURL() string {
+	if r.BrokenCloneURL {
+		return fmt.Sprintf(`file://%s`, filepath.ToSlash(r.Path))
+	}
+	return ""
+}
+
 // DefaultBranch returns "master"
 func (r Repository) DefaultBranch() string {
 	return "master"
 }
 
+// ID returns the id of the mock repo
+func (r Repository) ID() string {
+	return r.RepoID
+}
+
 // FullName returns the name of the mock repo
 func (r Repository) FullName() string {
 	return fmt.Sprintf("%s/%s", r.OwnerName, r.RepoName)
@@ -257,3 +836,13 @@ func (r Repository) Owner() string {
 func (r Repository) Delete() {
 	os.RemoveAll(r.Path)
 }
+
+// appendMissing appends the values that are not already in the slice
+func appendMissing(slice []string, values []string) []string {
+	for _, value := range values {
+		if !slices.Contains(slice, value) {
+			slice = append(slice, value)
+		}
+	}
+	return slice
+}